@@ -0,0 +1,60 @@
+package edamame
+
+import (
+	"context"
+	"fmt"
+)
+
+// AccessMode classifies a statement as reading or writing the table.
+type AccessMode string
+
+const (
+	AccessRead  AccessMode = "read"
+	AccessWrite AccessMode = "write"
+)
+
+// StatementAccess returns the access mode of stmt: queries, selects and aggregates
+// read, and updates and deletes write.
+func StatementAccess(stmt Statement) AccessMode {
+	switch stmt.(type) {
+	case UpdateStatement, DeleteStatement:
+		return AccessWrite
+	default:
+		return AccessRead
+	}
+}
+
+// readOnlyKey marks a context as restricted to reads.
+type readOnlyKey struct{}
+
+// WithReadOnly returns a context in which the executor's write methods fail before
+// building SQL. Wrap the request context of a read-only API surface with it to share
+// one executor with an admin surface that may write.
+func WithReadOnly(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readOnlyKey{}, true)
+}
+
+// SetReadOnly enables or disables rejecting writes on every context. With it enabled,
+// the update, delete, insert, upsert, restore, truncate and materialized view refresh
+// methods, including their batch and Tx variants, return an error before building SQL.
+// Queries, selects, aggregates and ExecClaimNext, which only locks, still run.
+//
+// Example:
+//
+//	exec.SetReadOnly(true)
+//	_, err := exec.ExecDelete(ctx, DeleteByID, params)
+//	// edamame: statement "delete-by-id" denied: executor is read-only
+func (e *Executor[T]) SetReadOnly(enabled bool) {
+	e.readOnly.Store(enabled)
+}
+
+// checkWrite returns an error naming what when writes are disabled for ctx.
+func (e *Executor[T]) checkWrite(ctx context.Context, what string) error {
+	if e.readOnly.Load() {
+		return fmt.Errorf("edamame: %s denied: executor is read-only", what)
+	}
+	if ro, _ := ctx.Value(readOnlyKey{}).(bool); ro {
+		return fmt.Errorf("edamame: %s denied: context is read-only", what)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package edamame
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestSetReadOnly(t *testing.T) {
+	db := &recordingDB{}
+	exec, err := New[User](db, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ctx := context.Background()
+	del := NewDeleteStatement("delete", "Delete by ID", DeleteSpec{Where: []ConditionSpec{{Field: "id", Operator: "=", Param: "id"}}})
+	all := NewQueryStatement("all", "All users", QuerySpec{})
+
+	exec.SetReadOnly(true)
+	_, err = exec.ExecDelete(ctx, del, map[string]any{"id": 1})
+	if err == nil || err.Error() != `edamame: statement "delete" denied: executor is read-only` {
+		t.Errorf("expected a read-only error, got %v", err)
+	}
+	if _, err := exec.ExecInsert(ctx, &User{Email: "a@example.com"}); err == nil || !strings.Contains(err.Error(), "ExecInsert denied") {
+		t.Errorf("expected insert to be denied, got %v", err)
+	}
+	var audit struct {
+		ID int `db:"id"`
+	}
+	if err := ExecInsertReturningInto(ctx, exec, &User{Email: "a@example.com"}, []string{"id"}, &audit); err == nil || !strings.Contains(err.Error(), "ExecInsertReturningInto denied") {
+		t.Errorf("expected returning insert to be denied, got %v", err)
+	}
+	if db.count() != 0 {
+		t.Errorf("expected no SQL to reach the database, got %v", db.queries)
+	}
+
+	_, err = exec.ExecQuery(ctx, all, nil)
+	if err == nil || strings.Contains(err.Error(), "denied") || db.count() != 1 {
+		t.Errorf("expected the query to run, got %v", err)
+	}
+
+	exec.SetReadOnly(false)
+	_, err = exec.ExecDelete(WithReadOnly(ctx), del, map[string]any{"id": 1})
+	if err == nil || !strings.Contains(err.Error(), "denied: context is read-only") {
+		t.Errorf("expected a read-only context error, got %v", err)
+	}
+	err = ExecInsertReturningInto(WithReadOnly(ctx), exec, &User{Email: "a@example.com"}, []string{"id"}, &struct {
+		ID int `db:"id"`
+	}{})
+	if err == nil || !strings.Contains(err.Error(), "ExecInsertReturningInto denied: context is read-only") {
+		t.Errorf("expected a read-only context error for the returning insert, got %v", err)
+	}
+	if _, err := exec.ExecDelete(ctx, del, map[string]any{"id": 1}); err != nil && strings.Contains(err.Error(), "denied") {
+		t.Errorf("expected the delete to be allowed, got %v", err)
+	}
+}
+
+func TestStatementAccess(t *testing.T) {
+	if StatementAccess(NewQueryStatement("q", "", QuerySpec{})) != AccessRead ||
+		StatementAccess(NewAggregateStatement("a", "", AggCount, AggregateSpec{})) != AccessRead ||
+		StatementAccess(NewUpdateStatement("u", "", UpdateSpec{})) != AccessWrite ||
+		StatementAccess(NewDeleteStatement("d", "", DeleteSpec{})) != AccessWrite {
+		t.Error("unexpected access modes")
+	}
+}
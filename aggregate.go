@@ -0,0 +1,174 @@
+package edamame
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ExecAggregateInt executes an aggregate statement and returns the result as int64.
+// Intended for COUNT and SUM over integer columns. A NULL result returns 0.
+func (e *Executor[T]) ExecAggregateInt(ctx context.Context, stmt AggregateStatement, params map[string]any) (int64, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	return execAggregateScalar[T, int64](withRead(ctx), e, e.execer(), stmt, params)
+}
+
+// ExecAggregateIntTx executes an aggregate statement within a transaction and returns the result as int64.
+func (e *Executor[T]) ExecAggregateIntTx(ctx context.Context, tx *sqlx.Tx, stmt AggregateStatement, params map[string]any) (int64, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	return execAggregateScalar[T, int64](ctx, e, e.execerFor(tx), stmt, params)
+}
+
+// ExecAggregateScalar executes an aggregate statement and scans the result into R.
+// Use it for MIN/MAX to get the field's native type, such as time.Time.
+// A NULL result (no matching rows) returns the zero value of R.
+//
+// Example:
+//
+//	latest, err := edamame.ExecAggregateScalar[Event, time.Time](ctx, exec, maxCreatedAt, nil)
+func ExecAggregateScalar[T, R any](ctx context.Context, e *Executor[T], stmt AggregateStatement, params map[string]any) (R, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	return execAggregateScalar[T, R](withRead(ctx), e, e.execer(), stmt, params)
+}
+
+// ExecAggregateScalarTx executes an aggregate statement within a transaction and scans the result into R.
+func ExecAggregateScalarTx[T, R any](ctx context.Context, e *Executor[T], tx *sqlx.Tx, stmt AggregateStatement, params map[string]any) (R, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	return execAggregateScalar[T, R](ctx, e, e.execerFor(tx), stmt, params)
+}
+
+// execAggregateScalar renders the aggregate through soy and scans the single result column into R.
+func execAggregateScalar[T, R any](ctx context.Context, e *Executor[T], execer sqlx.ExtContext, stmt AggregateStatement, params map[string]any) (R, error) {
+	var zero R
+	if err := checkUngrouped(stmt); err != nil {
+		return zero, err
+	}
+	params, err := e.prepareParams(stmt, params)
+	if err != nil {
+		return zero, err
+	}
+	ctx = withStatement(ctx, stmt.name, "aggregate")
+
+	sql, err := e.renderAggregate(stmt)
+	if err != nil {
+		return zero, fmt.Errorf("edamame: failed to render %s: %w", stmt.fn, err)
+	}
+
+	e.emitSQL(ctx, stmt.name, "aggregate", sql, params)
+
+	rows, err := sqlx.NamedQueryContext(ctx, execer, sql, params)
+	if err != nil {
+		return zero, fmt.Errorf("edamame: %s query failed: %w", stmt.fn, err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return zero, fmt.Errorf("edamame: %s query failed: %w", stmt.fn, err)
+		}
+		return zero, fmt.Errorf("edamame: %s query returned no rows", stmt.fn)
+	}
+
+	var value *R
+	if err := rows.Scan(&value); err != nil {
+		return zero, fmt.Errorf("edamame: failed to scan %s result: %w", stmt.fn, err)
+	}
+	if value == nil {
+		return zero, nil
+	}
+	return *value, nil
+}
+
+// ExecCountByGroup counts rows per distinct value of groupField, optionally filtered by where.
+// It renders "SELECT groupField, COUNT(*) ... GROUP BY groupField" and keys the result by
+// each group value's string form. A NULL group value is keyed by the empty string.
+// Params are prepared as for a query statement named "count-by-" + groupField, and
+// where may hold any condition a query statement's Where accepts.
+//
+// Example:
+//
+//	counts, err := exec.ExecCountByGroup(ctx, "status", nil, nil)
+//	// map[string]int64{"active": 12, "pending": 3}
+func (e *Executor[T]) ExecCountByGroup(ctx context.Context, groupField string, where []ConditionSpec, params map[string]any) (map[string]int64, error) {
+	ctx, cancel := e.withTimeout(ctx, "")
+	defer cancel()
+	return e.execCountByGroup(withRead(ctx), e.execer(), groupField, where, params)
+}
+
+// ExecCountByGroupTx counts rows per distinct value of groupField within a transaction.
+func (e *Executor[T]) ExecCountByGroupTx(ctx context.Context, tx *sqlx.Tx, groupField string, where []ConditionSpec, params map[string]any) (map[string]int64, error) {
+	ctx, cancel := e.withTimeout(ctx, "")
+	defer cancel()
+	return e.execCountByGroup(ctx, e.execerFor(tx), groupField, where, params)
+}
+
+// execCountByGroup renders the grouped count through soy and scans each (group, count) row.
+func (e *Executor[T]) execCountByGroup(ctx context.Context, execer sqlx.ExtContext, groupField string, where []ConditionSpec, params map[string]any) (map[string]int64, error) {
+	name := "count-by-" + groupField
+	spec := QuerySpec{
+		Fields:      []string{groupField},
+		SelectExprs: []SelectExprSpec{{Func: "count_star", Alias: "edamame_count"}},
+		Where:       where,
+		GroupBy:     []string{groupField},
+	}
+	params, err := e.prepareParams(NewQueryStatement(name, "Row count by "+groupField, spec), params)
+	if err != nil {
+		return nil, err
+	}
+	ctx = withStatement(ctx, name, "query")
+
+	q, err := e.queryFromSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	result, err := q.Render()
+	if err != nil {
+		return nil, fmt.Errorf("edamame: failed to render count by %s: %w", groupField, err)
+	}
+	sql, binds, err := e.finalizeSQL(result.SQL, e.queryRewrites(spec))
+	if err != nil {
+		return nil, err
+	}
+	params = mergeParams(params, binds)
+
+	e.emitSQL(ctx, name, "query", sql, params)
+
+	rows, err := sqlx.NamedQueryContext(ctx, execer, sql, params)
+	if err != nil {
+		return nil, fmt.Errorf("edamame: count by %s failed: %w", groupField, err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int64)
+	for rows.Next() {
+		var group any
+		var count int64
+		if err := rows.Scan(&group, &count); err != nil {
+			return nil, fmt.Errorf("edamame: failed to scan count by %s: %w", groupField, err)
+		}
+		counts[groupKey(group)] += count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("edamame: count by %s failed: %w", groupField, err)
+	}
+	return counts, nil
+}
+
+// groupKey returns the string form of a scanned group value.
+func groupKey(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,94 @@
+package edamame
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestExecCountByGroup_SQL(t *testing.T) {
+	db := &recordingDB{}
+	factory, err := New[User](db, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	where := []ConditionSpec{{Field: "age", Operator: ">=", Param: "min_age"}}
+	_, err = factory.ExecCountByGroup(context.Background(), "name", where, map[string]any{"min_age": 18})
+	if !errors.Is(err, errRecorded) {
+		t.Fatalf("expected recorded error, got %v", err)
+	}
+
+	sql := db.queries[0]
+	for _, want := range []string{`SELECT "name", COUNT(*) AS "edamame_count"`, `WHERE "age" >= $1`, `GROUP BY "name"`} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("expected %q in SQL, got: %s", want, sql)
+		}
+	}
+}
+
+func TestExecCountByGroup_Rewrites(t *testing.T) {
+	db := &recordingDB{}
+	factory, err := New[User](db, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ctx := context.Background()
+
+	where := []ConditionSpec{
+		{Field: "email", Match: "ends_with", Param: "domain"},
+		{Field: "age", Operator: "IS DISTINCT FROM", Param: "age"},
+		{Exists: true, Subquery: &QuerySpec{Where: []ConditionSpec{{Field: "name", Operator: "=", Param: "name"}}},
+			Correlate: []CorrelationSpec{{Outer: "email", Inner: "email"}}},
+	}
+	if _, err := factory.ExecCountByGroup(ctx, "name", where, map[string]any{"domain": "example.com"}); err == nil || errors.Is(err, errRecorded) {
+		t.Fatalf("expected a missing param error before reaching the database, got %v", err)
+	}
+
+	params := map[string]any{"domain": "example.com", "age": 30, "sub_name": "Alice"}
+	if _, err := factory.ExecCountByGroup(ctx, "name", where, params); !errors.Is(err, errRecorded) {
+		t.Fatalf("expected recorded error, got %v", err)
+	}
+	sql := db.queries[0]
+	for _, want := range []string{`"email" LIKE $1`, `"age" IS DISTINCT FROM $2`, `EXISTS (SELECT 1 FROM "users" AS edamame_sub`} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("expected %q in SQL, got: %s", want, sql)
+		}
+	}
+	if strings.Contains(sql, existsParamPrefix) {
+		t.Errorf("expected the EXISTS placeholder to be replaced, got: %s", sql)
+	}
+}
+
+func TestExecCountByGroup_UnknownField(t *testing.T) {
+	factory, err := New[User](&recordingDB{}, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if _, err := factory.ExecCountByGroup(context.Background(), "nope", nil, nil); err == nil {
+		t.Error("expected error for unknown group field")
+	}
+}
+
+func TestGroupKey(t *testing.T) {
+	tests := []struct {
+		in   any
+		want string
+	}{
+		{nil, ""},
+		{[]byte("active"), "active"},
+		{"pending", "pending"},
+		{int64(30), "30"},
+		{true, "true"},
+		{time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), "2025-01-02 00:00:00 +0000 UTC"},
+	}
+	for _, tt := range tests {
+		if got := groupKey(tt.in); got != tt.want {
+			t.Errorf("groupKey(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package edamame
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// errFieldAliasesUnsupported is returned by execution paths that run soy's SQL
+// unmodified and so cannot apply field aliases.
+var errFieldAliasesUnsupported = errors.New("edamame: field aliases are not supported by this method")
+
+// validateFieldAliases checks that every aliased column is a selected field and that
+// every alias is a valid identifier; aliases are spliced into the rendered SQL rather
+// than passed through soy.
+func (e *Executor[T]) validateFieldAliases(fields []string, aliases map[string]string) error {
+	for col, alias := range aliases {
+		found := false
+		for _, f := range fields {
+			if f == col {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("aliased field %q must be listed in Fields", col)
+		}
+		if _, err := e.soy.Instance().TryP(alias); err != nil {
+			return fmt.Errorf("invalid alias %q for field %q: %w", alias, col, err)
+		}
+	}
+	return nil
+}
+
+// aliasedColumns returns columns with each aliased column replaced by its alias.
+func aliasedColumns(columns []string, aliases map[string]string) []string {
+	if len(aliases) == 0 {
+		return columns
+	}
+	renamed := make([]string, len(columns))
+	for i, col := range columns {
+		if alias, ok := aliases[col]; ok {
+			col = alias
+		}
+		renamed[i] = col
+	}
+	return renamed
+}
+
+// rewriteFieldAliases appends "AS alias" to each aliased column in the rendered SELECT list.
+// soy only aliases function expressions, so plain fields are rendered bare and edited here:
+// the list runs from SELECT (past any DISTINCT or DISTINCT ON prefix) to the top-level FROM,
+// and is split on top-level commas so expression arguments are never matched.
+func rewriteFieldAliases(sql string, aliases map[string]string) (string, error) {
+	if len(aliases) == 0 {
+		return sql, nil
+	}
+	if !strings.HasPrefix(sql, "SELECT ") {
+		return "", fmt.Errorf("edamame: rendered SQL does not start with SELECT")
+	}
+
+	start := len("SELECT ")
+	if strings.HasPrefix(sql[start:], "DISTINCT ON (") {
+		end := topLevelIndex(sql, start+len("DISTINCT ON ("), ") ")
+		if end < 0 {
+			return "", fmt.Errorf("edamame: unterminated DISTINCT ON in rendered SQL")
+		}
+		start = end + len(") ")
+	} else if strings.HasPrefix(sql[start:], "DISTINCT ") {
+		start += len("DISTINCT ")
+	}
+	end := topLevelIndex(sql, start, " FROM ")
+	if end < 0 {
+		return "", fmt.Errorf("edamame: rendered SQL has no FROM clause")
+	}
+
+	items := splitTopLevel(sql[start:end])
+	applied := 0
+	for i, item := range items {
+		if len(item) < 2 || (item[0] != '"' && item[0] != '`') || item[len(item)-1] != item[0] {
+			continue
+		}
+		alias, ok := aliases[item[1:len(item)-1]]
+		if !ok {
+			continue
+		}
+		quote := item[:1]
+		items[i] = item + " AS " + quote + alias + quote
+		applied++
+	}
+	if applied != len(aliases) {
+		return "", fmt.Errorf("edamame: rendered SELECT list has %d aliased fields, expected %d", applied, len(aliases))
+	}
+
+	return sql[:start] + strings.Join(items, ", ") + sql[end:], nil
+}
+
+// topLevelIndex returns the index of the first occurrence of sep in sql at or after
+// from that is outside parentheses and quotes, or -1.
+func topLevelIndex(sql string, from int, sep string) int {
+	depth := 0
+	var quote byte
+	for i := from; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '`' || c == '\'':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			if depth == 0 && strings.HasPrefix(sql[i:], sep) {
+				return i
+			}
+			depth--
+		case depth == 0 && strings.HasPrefix(sql[i:], sep):
+			return i
+		}
+	}
+	return -1
+}
+
+// splitTopLevel splits a rendered SELECT list on ", " outside parentheses and quotes.
+func splitTopLevel(list string) []string {
+	var items []string
+	for {
+		i := topLevelIndex(list, 0, ", ")
+		if i < 0 {
+			return append(items, list)
+		}
+		items = append(items, list[:i])
+		list = list[i+len(", "):]
+	}
+}
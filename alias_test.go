@@ -0,0 +1,102 @@
+package edamame
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/mariadb"
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestFieldAliases_Render(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	stmt := NewQueryStatement("contacts", "User contacts", QuerySpec{
+		Fields:       []string{"id", "email"},
+		FieldAliases: map[string]string{"email": "contact"},
+		SelectExprs:  []SelectExprSpec{{Func: "upper", Field: "email", Alias: "email_upper"}},
+	})
+
+	sql, err := factory.RenderQuery(stmt)
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+	want := `SELECT "id", "email" AS "contact", UPPER("email") AS "email_upper" FROM "users"`
+	if sql != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, sql)
+	}
+
+	columns, err := factory.OutputColumns(stmt)
+	if err != nil {
+		t.Fatalf("OutputColumns() failed: %v", err)
+	}
+	if strings.Join(columns, ",") != "id,contact,email_upper" {
+		t.Errorf("unexpected output columns: %v", columns)
+	}
+}
+
+func TestFieldAliases_RenderSelectMariaDB(t *testing.T) {
+	factory, err := New[User](nil, "users", mariadb.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	stmt := NewSelectStatement("contact", "User contact", SelectSpec{
+		Fields:       []string{"Email"},
+		FieldAliases: map[string]string{"Email": "contact"},
+		Distinct:     true,
+	})
+
+	sql, err := factory.RenderSelect(stmt)
+	if err != nil {
+		t.Fatalf("RenderSelect() failed: %v", err)
+	}
+	if !strings.HasPrefix(sql, "SELECT DISTINCT `email` AS `contact` FROM `users`") {
+		t.Errorf("unexpected SQL: %s", sql)
+	}
+}
+
+func TestRewriteFieldAliases(t *testing.T) {
+	sql, err := rewriteFieldAliases(`SELECT DISTINCT ON ("email") "email", CONCAT("name", "email") AS "label" FROM "users"`, map[string]string{"email": "contact"})
+	if err != nil {
+		t.Fatalf("rewriteFieldAliases() failed: %v", err)
+	}
+	want := `SELECT DISTINCT ON ("email") "email" AS "contact", CONCAT("name", "email") AS "label" FROM "users"`
+	if sql != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, sql)
+	}
+}
+
+func TestFieldAliases_Invalid(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	notSelected := NewQueryStatement("not-selected", "Alias for an unselected field", QuerySpec{
+		Fields:       []string{"id"},
+		FieldAliases: map[string]string{"email": "contact"},
+	})
+	if _, err := factory.RenderQuery(notSelected); err == nil {
+		t.Error("expected error for alias of a field not in Fields")
+	}
+
+	badAlias := NewSelectStatement("bad-alias", "Alias with invalid name", SelectSpec{
+		Fields:       []string{"email"},
+		FieldAliases: map[string]string{"email": `contact"; DROP TABLE users`},
+	})
+	if _, err := factory.RenderSelect(badAlias); err == nil {
+		t.Error("expected error for invalid alias")
+	}
+
+	compound := CompoundQuerySpec{
+		Base:     QuerySpec{Fields: []string{"email"}, FieldAliases: map[string]string{"email": "contact"}},
+		Operands: []SetOperandSpec{{Operation: "union", Query: QuerySpec{Fields: []string{"email"}}}},
+	}
+	if _, err := factory.RenderCompound(compound); err == nil {
+		t.Error("RenderCompound() should reject field aliases")
+	}
+}
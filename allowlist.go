@@ -0,0 +1,185 @@
+package edamame
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// SetQueryableFields restricts the fields statements may reference to fields, for
+// executors running specs built from untrusted input such as LLM output. Every field
+// a statement filters, groups, orders, selects or aggregates by must be in the list:
+// condition Field and RightField, including fragments, groups and EXISTS correlations;
+// Fields, FieldAliases, SelectExprs, GroupBy, DistinctOn, OrderBy, Having and HavingAgg;
+// and an aggregate's Field. Update Set fields and the default projection of every
+// column are not restricted.
+//
+// A statement referencing another field fails to render, Prepare and every Exec method
+// with an error naming the field, before any SQL is built. Fields are resolved like
+// spec fields, so Go field names and mapped names are accepted. Calling it with no
+// fields removes the allowlist.
+//
+// Example:
+//
+//	if err := exec.SetQueryableFields("id", "name", "created_at"); err != nil {
+//	    return err
+//	}
+func (e *Executor[T]) SetQueryableFields(fields ...string) error {
+	var allowed map[string]bool
+	if len(fields) > 0 {
+		allowed = make(map[string]bool, len(fields))
+		for _, field := range fields {
+			col := e.column(field)
+			if _, ok := e.columns[col]; !ok {
+				return fmt.Errorf("edamame: unknown queryable field %q", field)
+			}
+			allowed[col] = true
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.queryable = allowed
+	return nil
+}
+
+// checkQueryable returns an error for the first of fields outside the allowlist set
+// by SetQueryableFields. Without an allowlist every field passes.
+func (e *Executor[T]) checkQueryable(fields []string) error {
+	e.mu.RLock()
+	allowed := e.queryable
+	e.mu.RUnlock()
+	if allowed == nil {
+		return nil
+	}
+	for _, field := range fields {
+		if field == "" || field == "*" {
+			continue
+		}
+		if !allowed[e.column(field)] {
+			return fmt.Errorf("field %q is not queryable", field)
+		}
+	}
+	return nil
+}
+
+// hasQueryable reports whether an allowlist is set.
+func (e *Executor[T]) hasQueryable() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.queryable != nil
+}
+
+// checkStatementFields checks the fields stmt references against the allowlist and,
+// in strict mode, against the schema.
+func (e *Executor[T]) checkStatementFields(stmt Statement) error {
+	strict := e.strictFields.Load()
+	if !strict && !e.hasQueryable() {
+		return nil
+	}
+	fields := e.statementFields(stmt)
+	if strict {
+		if err := e.checkKnownFields(append(fields, statementSetFields(stmt)...)); err != nil {
+			return err
+		}
+	}
+	return e.checkQueryable(fields)
+}
+
+// statementFields returns the fields stmt filters, groups, orders, selects or
+// aggregates by.
+func (e *Executor[T]) statementFields(stmt Statement) []string {
+	switch s := stmt.(type) {
+	case QueryStatement:
+		return e.queryFields(s.spec)
+	case SelectStatement:
+		return e.queryFields(selectFieldSpec(s.spec))
+	case UpdateStatement:
+		return e.conditionFields(s.spec.Where, nil)
+	case DeleteStatement:
+		return e.conditionFields(s.spec.Where, nil)
+	case AggregateStatement:
+		fields := append(e.conditionFields(s.spec.Where, nil), s.spec.Field)
+		return append(fields, s.spec.GroupBy...)
+	}
+	return nil
+}
+
+// checkQueryFields checks the fields spec references against the allowlist.
+func (e *Executor[T]) checkQueryFields(spec QuerySpec) error {
+	if !e.hasQueryable() {
+		return nil
+	}
+	return e.checkQueryable(e.queryFields(spec))
+}
+
+// selectFieldSpec returns a QuerySpec holding the field references of spec, for queryFields.
+func selectFieldSpec(spec SelectSpec) QuerySpec {
+	return QuerySpec{
+		Fields: spec.Fields, FieldAliases: spec.FieldAliases, SelectExprs: spec.SelectExprs,
+		Where: spec.Where, OrderBy: spec.OrderBy, GroupBy: spec.GroupBy, Having: spec.Having,
+		HavingAgg: spec.HavingAgg, DistinctOn: spec.DistinctOn,
+	}
+}
+
+// queryFields returns the fields spec references. OuterWhere is skipped, as it
+// filters on the aliases of fields checked here.
+func (e *Executor[T]) queryFields(spec QuerySpec) []string {
+	fields := append([]string(nil), spec.Fields...)
+	fields = append(fields, slices.Sorted(maps.Keys(spec.FieldAliases))...)
+	for _, expr := range spec.SelectExprs {
+		fields = append(fields, expr.Field)
+		fields = append(fields, expr.Fields...)
+		if expr.Filter != nil {
+			fields = e.conditionFields([]ConditionSpec{*expr.Filter}, fields)
+		}
+	}
+	fields = e.conditionFields(spec.Where, fields)
+	fields = e.conditionFields(spec.Having, fields)
+	fields = append(fields, orderFields(spec.OrderBy)...)
+	fields = append(fields, spec.GroupBy...)
+	fields = append(fields, spec.DistinctOn...)
+	for _, h := range spec.HavingAgg {
+		fields = append(fields, h.Field)
+	}
+	return fields
+}
+
+// conditionFields appends the fields conditions reference to fields, expanding
+// fragments and recursing into groups. EXISTS conditions and quantified subquery
+// comparisons contribute their outer fields, and their subquery's fields when it
+// selects from this table.
+func (e *Executor[T]) conditionFields(conditions []ConditionSpec, fields []string) []string {
+	for _, c := range e.expandFragments(conditions) {
+		switch {
+		case c.IsGroup():
+			fields = e.conditionFields(c.Group, fields)
+		case c.IsSubquery():
+			fields = append(fields, c.Field)
+			for _, corr := range c.Correlate {
+				fields = append(fields, corr.Outer)
+			}
+			if c.From == "" && c.Subquery != nil {
+				fields = append(fields, c.Subquery.Fields...)
+				fields = e.conditionFields(c.Subquery.Where, fields)
+			}
+		case c.IsFullText():
+			fields = append(fields, c.FullText.Fields...)
+		default:
+			fields = append(fields, c.Field, c.RightField)
+		}
+	}
+	return fields
+}
+
+// orderFields returns the fields orderBy sorts by.
+func orderFields(orderBy []OrderBySpec) []string {
+	var fields []string
+	for _, o := range orderBy {
+		fields = append(fields, o.Field)
+		for _, term := range o.Terms {
+			fields = append(fields, term.Field)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,79 @@
+package edamame
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestSetQueryableFields(t *testing.T) {
+	db := &recordingDB{}
+	exec, err := New[User](db, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if err := exec.SetQueryableFields("id", "Name", "age"); err != nil {
+		t.Fatalf("SetQueryableFields() failed: %v", err)
+	}
+
+	allowed := NewQueryStatement("allowed", "", QuerySpec{
+		Where:   []ConditionSpec{{Field: "age", Operator: ">=", Param: "min_age"}},
+		OrderBy: []OrderBySpec{{Field: "name", Direction: "asc"}},
+	})
+	if _, err := exec.RenderQuery(allowed); err != nil {
+		t.Errorf("expected allowed fields to render, got %v", err)
+	}
+
+	tests := []struct {
+		name string
+		stmt Statement
+	}{
+		{"where", NewQueryStatement("where", "", QuerySpec{Where: []ConditionSpec{{Field: "email", Operator: "=", Param: "email"}}})},
+		{"right field", NewQueryStatement("right", "", QuerySpec{Where: []ConditionSpec{{Field: "name", Operator: "=", RightField: "email"}}})},
+		{"group", NewQueryStatement("group", "", QuerySpec{Where: []ConditionSpec{{Logic: "OR", Group: []ConditionSpec{
+			{Field: "age", Operator: ">", Param: "age"},
+			{Field: "email", Operator: "=", Param: "email"},
+		}}}})},
+		{"order by", NewQueryStatement("order", "", QuerySpec{OrderBy: []OrderBySpec{{Field: "email", Direction: "asc"}}})},
+		{"fields", NewSelectStatement("fields", "", SelectSpec{Fields: []string{"id", "email"}})},
+		{"group by", NewAggregateStatement("group-by", "", AggCount, AggregateSpec{GroupBy: []string{"email"}})},
+		{"aggregate field", NewAggregateStatement("sum", "", AggSum, AggregateSpec{Field: "email"})},
+		{"delete where", NewDeleteStatement("delete", "", DeleteSpec{Where: []ConditionSpec{{Field: "email", Operator: "=", Param: "email"}}})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := exec.Prepare(tt.stmt); err == nil || !strings.Contains(err.Error(), `field "email" is not queryable`) {
+				t.Errorf("expected Prepare to reject email, got %v", err)
+			}
+		})
+	}
+
+	byEmail := NewQueryStatement("by-email", "", QuerySpec{Where: []ConditionSpec{{Field: "email", Operator: "=", Param: "email"}}})
+	if _, err := exec.ExecQuery(context.Background(), byEmail, map[string]any{"email": "a@example.com"}); err == nil || !strings.Contains(err.Error(), "not queryable") {
+		t.Errorf("expected ExecQuery to reject email, got %v", err)
+	}
+	if db.count() != 0 {
+		t.Errorf("expected no SQL to reach the database, got %v", db.queries)
+	}
+
+	_, err = exec.RenderCompound(CompoundQuerySpec{
+		Base:     QuerySpec{Fields: []string{"id"}},
+		Operands: []SetOperandSpec{{Operation: "union", Query: QuerySpec{Fields: []string{"id"}}}},
+		OrderBy:  []OrderBySpec{{Field: "email", Direction: "asc"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "not queryable") {
+		t.Errorf("expected the compound ORDER BY to be rejected, got %v", err)
+	}
+
+	if err := exec.SetQueryableFields("missing"); err == nil {
+		t.Error("expected an unknown field to be rejected")
+	}
+	if err := exec.SetQueryableFields(); err != nil {
+		t.Fatalf("SetQueryableFields() failed: %v", err)
+	}
+	if err := exec.Prepare(byEmail); err != nil {
+		t.Errorf("expected no allowlist after clearing, got %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package edamame
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/mariadb"
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestRenderBulkUpdate(t *testing.T) {
+	exec, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	sql, params, err := exec.renderBulkUpdate([]RowUpdate{
+		{Key: 1, Set: map[string]any{"name": "Alice", "age": 30}},
+		{Key: 2, Set: map[string]any{"name": "Bob", "age": nil}},
+	})
+	if err != nil {
+		t.Fatalf("renderBulkUpdate() failed: %v", err)
+	}
+	want := `UPDATE "users" SET "age" = edamame_v."age", "name" = edamame_v."name" FROM (VALUES ` +
+		`(CAST(:edamame_bulk_0_0 AS integer), CAST(:edamame_bulk_0_1 AS integer), CAST(:edamame_bulk_0_2 AS text)), ` +
+		`(CAST(:edamame_bulk_1_0 AS integer), CAST(:edamame_bulk_1_1 AS integer), CAST(:edamame_bulk_1_2 AS text))) ` +
+		`AS edamame_v("id", "age", "name") WHERE "users"."id" = edamame_v."id"`
+	if sql != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, sql)
+	}
+	if params["edamame_bulk_1_0"] != 2 || params["edamame_bulk_0_2"] != "Alice" || params["edamame_bulk_1_1"] != nil {
+		t.Errorf("unexpected params: %v", params)
+	}
+}
+
+func TestRenderBulkUpdate_Errors(t *testing.T) {
+	exec, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	tests := map[string][]RowUpdate{
+		"no fields":        {{Key: 1}},
+		"primary key":      {{Key: 1, Set: map[string]any{"id": 2}}},
+		"different fields": {{Key: 1, Set: map[string]any{"name": "a"}}, {Key: 2, Set: map[string]any{"email": "b"}}},
+		"unknown field":    {{Key: 1, Set: map[string]any{"nickname": "a"}}},
+	}
+	for name, updates := range tests {
+		if _, _, err := exec.renderBulkUpdate(updates); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	maria, err := New[User](nil, "users", mariadb.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	_, _, err = maria.renderBulkUpdate([]RowUpdate{{Key: 1, Set: map[string]any{"name": "a"}}})
+	if err == nil || !strings.Contains(err.Error(), "postgres") {
+		t.Errorf("expected postgres-only error, got %v", err)
+	}
+}
@@ -0,0 +1,349 @@
+package edamame
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Catalog is the serializable form of a set of statements, for teams that keep their
+// statement definitions as JSON rather than Go. Each definition holds the arguments of
+// the matching statement constructor; params are derived from the spec as usual.
+//
+// Example document:
+//
+//	{
+//	  "queries": [
+//	    {"name": "adults", "description": "Users at or above an age",
+//	     "spec": {"where": [{"field": "age", "operator": ">=", "param": "min_age"}]}}
+//	  ],
+//	  "aggregates": [
+//	    {"name": "count-all", "description": "Count all users", "func": "COUNT", "spec": {}}
+//	  ]
+//	}
+type Catalog struct {
+	Queries    []CatalogQuery     `json:"queries,omitempty"`
+	Selects    []CatalogSelect    `json:"selects,omitempty"`
+	Updates    []CatalogUpdate    `json:"updates,omitempty"`
+	Deletes    []CatalogDelete    `json:"deletes,omitempty"`
+	Aggregates []CatalogAggregate `json:"aggregates,omitempty"`
+}
+
+// CatalogQuery defines a QueryStatement.
+type CatalogQuery struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Spec        QuerySpec `json:"spec"`
+	Tags        []string  `json:"tags,omitempty"`
+}
+
+// CatalogSelect defines a SelectStatement.
+type CatalogSelect struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Spec        SelectSpec `json:"spec"`
+	Tags        []string   `json:"tags,omitempty"`
+}
+
+// CatalogUpdate defines an UpdateStatement.
+type CatalogUpdate struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Spec        UpdateSpec `json:"spec"`
+	Tags        []string   `json:"tags,omitempty"`
+}
+
+// CatalogDelete defines a DeleteStatement.
+type CatalogDelete struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Spec        DeleteSpec `json:"spec"`
+	Tags        []string   `json:"tags,omitempty"`
+}
+
+// CatalogAggregate defines an AggregateStatement.
+type CatalogAggregate struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	Func        AggregateFunc `json:"func"`
+	Spec        AggregateSpec `json:"spec"`
+	Tags        []string      `json:"tags,omitempty"`
+}
+
+// ExportCatalog serializes stmts as an indented Catalog document that ParseCatalog
+// reloads into statements rendering the same SQL. Each array is sorted by statement
+// name, so the output is stable for diffing in version control. Duplicate names are
+// reported as ParseCatalog reports them, and statement types a catalog cannot hold,
+// such as compound queries, return an error.
+//
+// Example:
+//
+//	doc, err := edamame.ExportCatalog(Adults, ByEmail, CountAll)
+//	err = os.WriteFile("statements/users.json", doc, 0o644)
+func ExportCatalog(stmts ...Statement) ([]byte, error) {
+	c, err := NewCatalog(stmts...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.Statements(); err != nil {
+		return nil, err
+	}
+	doc, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("edamame: failed to encode catalog: %w", err)
+	}
+	return append(doc, '\n'), nil
+}
+
+// NewCatalog returns the Catalog defining stmts, each array sorted by statement name.
+func NewCatalog(stmts ...Statement) (Catalog, error) {
+	var c Catalog
+	for _, stmt := range stmts {
+		switch s := stmt.(type) {
+		case QueryStatement:
+			c.Queries = append(c.Queries, CatalogQuery{Name: s.name, Description: s.description, Spec: s.spec, Tags: s.tags})
+		case SelectStatement:
+			c.Selects = append(c.Selects, CatalogSelect{Name: s.name, Description: s.description, Spec: s.spec, Tags: s.tags})
+		case UpdateStatement:
+			c.Updates = append(c.Updates, CatalogUpdate{Name: s.name, Description: s.description, Spec: s.spec, Tags: s.tags})
+		case DeleteStatement:
+			c.Deletes = append(c.Deletes, CatalogDelete{Name: s.name, Description: s.description, Spec: s.spec, Tags: s.tags})
+		case AggregateStatement:
+			c.Aggregates = append(c.Aggregates, CatalogAggregate{Name: s.name, Description: s.description, Func: s.fn, Spec: s.spec, Tags: s.tags})
+		default:
+			return Catalog{}, fmt.Errorf("edamame: cannot export statement type %T", stmt)
+		}
+	}
+	sort.SliceStable(c.Queries, func(i, j int) bool { return c.Queries[i].Name < c.Queries[j].Name })
+	sort.SliceStable(c.Selects, func(i, j int) bool { return c.Selects[i].Name < c.Selects[j].Name })
+	sort.SliceStable(c.Updates, func(i, j int) bool { return c.Updates[i].Name < c.Updates[j].Name })
+	sort.SliceStable(c.Deletes, func(i, j int) bool { return c.Deletes[i].Name < c.Deletes[j].Name })
+	sort.SliceStable(c.Aggregates, func(i, j int) bool { return c.Aggregates[i].Name < c.Aggregates[j].Name })
+	return c, nil
+}
+
+// ParseCatalog decodes a Catalog document from r and builds its statements, keyed by
+// name. Unknown keys are rejected so a misspelled spec field fails instead of being
+// dropped. Every statement needs a unique, non-empty name; duplicates are reported
+// together in one error. The statements are not checked against a schema; use
+// Executor.LoadCatalog for that.
+func ParseCatalog(r io.Reader) (map[string]Statement, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	var c Catalog
+	if err := dec.Decode(&c); err != nil {
+		return nil, fmt.Errorf("edamame: failed to decode catalog: %w", err)
+	}
+	return c.Statements()
+}
+
+// Statements builds the catalog's statements, keyed by name.
+func (c Catalog) Statements() (map[string]Statement, error) {
+	var stmts []Statement
+	for _, d := range c.Queries {
+		stmts = append(stmts, NewQueryStatement(d.Name, d.Description, d.Spec, d.Tags...))
+	}
+	for _, d := range c.Selects {
+		stmts = append(stmts, NewSelectStatement(d.Name, d.Description, d.Spec, d.Tags...))
+	}
+	for _, d := range c.Updates {
+		stmts = append(stmts, NewUpdateStatement(d.Name, d.Description, d.Spec, d.Tags...))
+	}
+	for _, d := range c.Deletes {
+		stmts = append(stmts, NewDeleteStatement(d.Name, d.Description, d.Spec, d.Tags...))
+	}
+	for _, d := range c.Aggregates {
+		if !isAggregateFunc(d.Func) {
+			return nil, fmt.Errorf("edamame: aggregate %q has invalid func %q", d.Name, d.Func)
+		}
+		stmts = append(stmts, NewAggregateStatement(d.Name, d.Description, d.Func, d.Spec, d.Tags...))
+	}
+
+	byName := make(map[string]Statement, len(stmts))
+	var duplicates []string
+	for _, stmt := range stmts {
+		if stmt.Name() == "" {
+			return nil, fmt.Errorf("edamame: catalog statement has no name")
+		}
+		if _, ok := byName[stmt.Name()]; ok {
+			duplicates = append(duplicates, stmt.Name())
+			continue
+		}
+		byName[stmt.Name()] = stmt
+	}
+	if len(duplicates) > 0 {
+		sort.Strings(duplicates)
+		return nil, fmt.Errorf("edamame: duplicate statement names in catalog: %s", strings.Join(duplicates, ", "))
+	}
+	return byName, nil
+}
+
+// LoadCatalog decodes a Catalog document from r with ParseCatalog and prepares every
+// statement against this executor's schema, so an invalid definition fails at startup
+// rather than on first use. The statements are returned keyed by name.
+//
+// Example:
+//
+//	f, err := os.Open("statements/users.json")
+//	...
+//	stmts, err := exec.LoadCatalog(f)
+//	users, err := exec.ExecQuery(ctx, stmts["adults"].(edamame.QueryStatement), params)
+func (e *Executor[T]) LoadCatalog(r io.Reader) (map[string]Statement, error) {
+	stmts, err := ParseCatalog(r)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(stmts))
+	for name := range stmts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := e.Prepare(stmts[name]); err != nil {
+			return nil, err
+		}
+	}
+	return stmts, nil
+}
+
+// RenameQuery renames the catalog's query oldName to newName, keeping its description,
+// spec and tags, so a versioned name such as "find-active-v2" changes in place. It
+// returns an error if oldName is not a query or newName names any catalog statement;
+// the other Rename methods behave the same way.
+func (c *Catalog) RenameQuery(oldName, newName string) error {
+	return renameDefinition(c, c.Queries, "query", oldName, newName, func(d *CatalogQuery) *string { return &d.Name })
+}
+
+// RenameSelect renames the catalog's select oldName to newName.
+func (c *Catalog) RenameSelect(oldName, newName string) error {
+	return renameDefinition(c, c.Selects, "select", oldName, newName, func(d *CatalogSelect) *string { return &d.Name })
+}
+
+// RenameUpdate renames the catalog's update oldName to newName.
+func (c *Catalog) RenameUpdate(oldName, newName string) error {
+	return renameDefinition(c, c.Updates, "update", oldName, newName, func(d *CatalogUpdate) *string { return &d.Name })
+}
+
+// RenameDelete renames the catalog's delete oldName to newName.
+func (c *Catalog) RenameDelete(oldName, newName string) error {
+	return renameDefinition(c, c.Deletes, "delete", oldName, newName, func(d *CatalogDelete) *string { return &d.Name })
+}
+
+// RenameAggregate renames the catalog's aggregate oldName to newName.
+func (c *Catalog) RenameAggregate(oldName, newName string) error {
+	return renameDefinition(c, c.Aggregates, "aggregate", oldName, newName, func(d *CatalogAggregate) *string { return &d.Name })
+}
+
+// renameDefinition renames the definition oldName in defs, one of c's arrays, to
+// newName. The array keeps its order, so a catalog from NewCatalog may no longer be
+// sorted by name.
+func renameDefinition[D any](c *Catalog, defs []D, typ, oldName, newName string, name func(*D) *string) error {
+	if newName == "" {
+		return fmt.Errorf("edamame: cannot rename %s %q to an empty name", typ, oldName)
+	}
+	if c.hasName(newName) {
+		return fmt.Errorf("edamame: cannot rename %s %q: catalog already has a statement named %q", typ, oldName, newName)
+	}
+	for i := range defs {
+		if n := name(&defs[i]); *n == oldName {
+			*n = newName
+			return nil
+		}
+	}
+	return fmt.Errorf("edamame: catalog has no %s named %q", typ, oldName)
+}
+
+// hasName reports whether any of the catalog's statements is named name.
+func (c *Catalog) hasName(name string) bool {
+	return slices.ContainsFunc(c.Queries, func(d CatalogQuery) bool { return d.Name == name }) ||
+		slices.ContainsFunc(c.Selects, func(d CatalogSelect) bool { return d.Name == name }) ||
+		slices.ContainsFunc(c.Updates, func(d CatalogUpdate) bool { return d.Name == name }) ||
+		slices.ContainsFunc(c.Deletes, func(d CatalogDelete) bool { return d.Name == name }) ||
+		slices.ContainsFunc(c.Aggregates, func(d CatalogAggregate) bool { return d.Name == name })
+}
+
+// CatalogRef names a catalog statement and its type: "query", "select", "update",
+// "delete" or "aggregate".
+type CatalogRef struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// QueriesByTag returns the names of the catalog's queries tagged tag, sorted.
+// Tags match exactly and case-sensitively; the other ByTag methods match the same way.
+func (c Catalog) QueriesByTag(tag string) []string {
+	return namesByTag(c.Queries, tag, func(d CatalogQuery) (string, []string) { return d.Name, d.Tags })
+}
+
+// SelectsByTag returns the names of the catalog's selects tagged tag, sorted.
+func (c Catalog) SelectsByTag(tag string) []string {
+	return namesByTag(c.Selects, tag, func(d CatalogSelect) (string, []string) { return d.Name, d.Tags })
+}
+
+// UpdatesByTag returns the names of the catalog's updates tagged tag, sorted.
+func (c Catalog) UpdatesByTag(tag string) []string {
+	return namesByTag(c.Updates, tag, func(d CatalogUpdate) (string, []string) { return d.Name, d.Tags })
+}
+
+// DeletesByTag returns the names of the catalog's deletes tagged tag, sorted.
+func (c Catalog) DeletesByTag(tag string) []string {
+	return namesByTag(c.Deletes, tag, func(d CatalogDelete) (string, []string) { return d.Name, d.Tags })
+}
+
+// AggregatesByTag returns the names of the catalog's aggregates tagged tag, sorted.
+func (c Catalog) AggregatesByTag(tag string) []string {
+	return namesByTag(c.Aggregates, tag, func(d CatalogAggregate) (string, []string) { return d.Name, d.Tags })
+}
+
+// FindByTag returns the catalog's statements of every type tagged tag, sorted by
+// name and then type, such as for building a tool menu scoped to one tag.
+//
+// Example:
+//
+//	for _, ref := range catalog.FindByTag("admin") {
+//	    fmt.Println(ref.Type, ref.Name)
+//	}
+func (c Catalog) FindByTag(tag string) []CatalogRef {
+	refs := make([]CatalogRef, 0)
+	for _, group := range []struct {
+		typ   string
+		names []string
+	}{
+		{"aggregate", c.AggregatesByTag(tag)},
+		{"delete", c.DeletesByTag(tag)},
+		{"query", c.QueriesByTag(tag)},
+		{"select", c.SelectsByTag(tag)},
+		{"update", c.UpdatesByTag(tag)},
+	} {
+		for _, name := range group.names {
+			refs = append(refs, CatalogRef{Name: name, Type: group.typ})
+		}
+	}
+	sort.SliceStable(refs, func(i, j int) bool { return refs[i].Name < refs[j].Name })
+	return refs
+}
+
+// namesByTag returns the sorted names of the definitions in defs tagged tag.
+func namesByTag[D any](defs []D, tag string, nameTags func(D) (string, []string)) []string {
+	names := make([]string, 0)
+	for _, d := range defs {
+		name, tags := nameTags(d)
+		if slices.Contains(tags, tag) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isAggregateFunc reports whether fn is one of the AggregateFunc constants.
+func isAggregateFunc(fn AggregateFunc) bool {
+	switch fn {
+	case AggCount, AggSum, AggAvg, AggMin, AggMax, AggStdDev, AggStdDevPop, AggVariance, AggVariancePop:
+		return true
+	}
+	return false
+}
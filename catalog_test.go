@@ -0,0 +1,239 @@
+package edamame
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+const testCatalog = `{
+  "queries": [
+    {"name": "adults", "description": "Users at or above an age",
+     "spec": {"where": [{"field": "age", "operator": ">=", "param": "min_age"}]}, "tags": ["users"]}
+  ],
+  "selects": [
+    {"name": "by-email", "spec": {"where": [{"field": "email", "operator": "=", "param": "email"}]}}
+  ],
+  "updates": [
+    {"name": "rename", "spec": {"set": {"name": "new_name"}, "where": [{"field": "id", "operator": "=", "param": "id"}]}}
+  ],
+  "deletes": [
+    {"name": "delete-by-id", "spec": {"where": [{"field": "id", "operator": "=", "param": "id"}]}}
+  ],
+  "aggregates": [
+    {"name": "count-all", "func": "COUNT", "spec": {}}
+  ]
+}`
+
+func TestParseCatalog(t *testing.T) {
+	stmts, err := ParseCatalog(strings.NewReader(testCatalog))
+	if err != nil {
+		t.Fatalf("ParseCatalog() failed: %v", err)
+	}
+	if len(stmts) != 5 {
+		t.Fatalf("expected 5 statements, got %d", len(stmts))
+	}
+	adults, ok := stmts["adults"].(QueryStatement)
+	if !ok {
+		t.Fatalf("expected adults to be a QueryStatement, got %T", stmts["adults"])
+	}
+	if adults.Description() != "Users at or above an age" || len(adults.Tags()) != 1 {
+		t.Errorf("unexpected metadata: %q %v", adults.Description(), adults.Tags())
+	}
+	if params := adults.Params(); len(params) != 1 || params[0].Name != "min_age" {
+		t.Errorf("expected derived param min_age, got %+v", params)
+	}
+	if agg, ok := stmts["count-all"].(AggregateStatement); !ok || agg.Func() != AggCount {
+		t.Errorf("expected a COUNT aggregate, got %#v", stmts["count-all"])
+	}
+}
+
+func TestParseCatalog_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{"duplicates", `{"queries": [{"name": "a", "spec": {}}, {"name": "b", "spec": {}}],
+			"deletes": [{"name": "b", "spec": {}}], "aggregates": [{"name": "a", "func": "COUNT", "spec": {}}]}`,
+			"duplicate statement names in catalog: a, b"},
+		{"unknown field", `{"queries": [{"name": "a", "spec": {"wher": []}}]}`, "unknown field"},
+		{"no name", `{"queries": [{"spec": {}}]}`, "has no name"},
+		{"func", `{"aggregates": [{"name": "a", "func": "MEDIAN", "spec": {}}]}`, `invalid func "MEDIAN"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseCatalog(strings.NewReader(tt.doc))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestLoadCatalog(t *testing.T) {
+	exec, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if _, err := exec.LoadCatalog(strings.NewReader(testCatalog)); err != nil {
+		t.Fatalf("LoadCatalog() failed: %v", err)
+	}
+
+	_, err = exec.LoadCatalog(strings.NewReader(`{"queries": [{"name": "bad", "spec": {"where": [{"field": "nope", "operator": "=", "param": "x"}]}}]}`))
+	if err == nil || !strings.Contains(err.Error(), `"bad"`) {
+		t.Errorf("expected a prepare error naming the statement, got %v", err)
+	}
+}
+
+func TestExportCatalog_RoundTrip(t *testing.T) {
+	exec, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	limit := 10
+	stmts := []Statement{
+		NewQueryStatement("search", "Search users", QuerySpec{
+			Fields: []string{"id", "name"},
+			Where: []ConditionSpec{
+				{Logic: "OR", Group: []ConditionSpec{
+					{Field: "name", Match: "contains", Param: "q"},
+					{Field: "email", Operator: "=", Param: "email"},
+				}},
+				{Field: "age", Between: true, LowParam: "lo", HighParam: "hi"},
+			},
+			OrderBy:       []OrderBySpec{{Field: "name", Direction: "desc", Nulls: "last"}},
+			Limit:         &limit,
+			ParamDefaults: map[string]any{"lo": 0},
+		}, "search"),
+		NewQueryStatement("adults", "Adults", QuerySpec{Where: []ConditionSpec{{Field: "age", Operator: ">=", Param: "min_age"}}}),
+		NewSelectStatement("by-email", "By email", SelectSpec{Where: []ConditionSpec{{Field: "email", Operator: "=", Param: "email"}}}),
+		NewUpdateStatement("rename", "Rename", UpdateSpec{Set: map[string]string{"name": "name"}, Where: []ConditionSpec{{Field: "id", Operator: "=", Param: "id"}}}),
+		NewDeleteStatement("purge", "Purge", DeleteSpec{Where: []ConditionSpec{{Field: "age", IsNull: true}}}),
+		NewAggregateStatement("avg-age", "Average age", AggAvg, AggregateSpec{Field: "age", GroupBy: []string{"name"}}),
+	}
+
+	doc, err := ExportCatalog(stmts...)
+	if err != nil {
+		t.Fatalf("ExportCatalog() failed: %v", err)
+	}
+	if strings.Index(string(doc), `"adults"`) > strings.Index(string(doc), `"search"`) {
+		t.Error("expected queries sorted by name")
+	}
+	again, err := ExportCatalog(stmts[1], stmts[0], stmts[5], stmts[4], stmts[3], stmts[2])
+	if err != nil {
+		t.Fatalf("ExportCatalog() failed: %v", err)
+	}
+	if string(again) != string(doc) {
+		t.Error("expected the export to be independent of statement order")
+	}
+
+	loaded, err := exec.LoadCatalog(strings.NewReader(string(doc)))
+	if err != nil {
+		t.Fatalf("LoadCatalog() failed: %v", err)
+	}
+	for _, stmt := range stmts {
+		want, err := exec.RenderStatement(stmt)
+		if err != nil {
+			t.Fatalf("RenderStatement(%s) failed: %v", stmt.Name(), err)
+		}
+		got, err := exec.RenderStatement(loaded[stmt.Name()])
+		if err != nil {
+			t.Fatalf("RenderStatement(%s) after reload failed: %v", stmt.Name(), err)
+		}
+		if got != want {
+			t.Errorf("%s: expected %s, got %s", stmt.Name(), want, got)
+		}
+		if len(loaded[stmt.Name()].Params()) != len(stmt.Params()) {
+			t.Errorf("%s: expected params %+v, got %+v", stmt.Name(), stmt.Params(), loaded[stmt.Name()].Params())
+		}
+	}
+
+	if _, err := ExportCatalog(stmts[0], stmts[0]); err == nil || !strings.Contains(err.Error(), "duplicate") {
+		t.Errorf("expected a duplicate name error, got %v", err)
+	}
+}
+
+func TestCatalog_ByTag(t *testing.T) {
+	byID := []ConditionSpec{{Field: "id", Operator: "=", Param: "id"}}
+	c, err := NewCatalog(
+		NewQueryStatement("search", "", QuerySpec{}, "users", "admin"),
+		NewQueryStatement("adults", "", QuerySpec{}, "users"),
+		NewQueryStatement("audit", "", QuerySpec{}, "Users"),
+		NewSelectStatement("user", "", SelectSpec{Where: byID}, "users"),
+		NewUpdateStatement("rename", "", UpdateSpec{Set: map[string]string{"name": "name"}, Where: byID}, "admin"),
+		NewDeleteStatement("user", "", DeleteSpec{Where: byID}, "admin", "users"),
+		NewAggregateStatement("count", "", AggCount, AggregateSpec{}),
+	)
+	if err != nil {
+		t.Fatalf("NewCatalog() failed: %v", err)
+	}
+
+	if got := c.QueriesByTag("users"); strings.Join(got, ",") != "adults,search" {
+		t.Errorf("expected adults,search, got %v", got)
+	}
+	if got := c.UpdatesByTag("users"); len(got) != 0 {
+		t.Errorf("expected no updates, got %v", got)
+	}
+	if got := c.AggregatesByTag(""); len(got) != 0 {
+		t.Errorf("expected an untagged statement not to match the empty tag, got %v", got)
+	}
+
+	want := []CatalogRef{{"rename", "update"}, {"search", "query"}, {"user", "delete"}}
+	if got := c.FindByTag("admin"); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	want = []CatalogRef{{"adults", "query"}, {"search", "query"}, {"user", "delete"}, {"user", "select"}}
+	if got := c.FindByTag("users"); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got := c.FindByTag("missing"); got == nil || len(got) != 0 {
+		t.Errorf("expected an empty result, got %#v", got)
+	}
+}
+
+func TestCatalog_Rename(t *testing.T) {
+	c, err := NewCatalog(
+		NewQueryStatement("find-active", "Active users", QuerySpec{Where: []ConditionSpec{{Field: "age", Operator: ">=", Param: "min_age"}}}, "users"),
+		NewAggregateStatement("count", "", AggCount, AggregateSpec{}),
+	)
+	if err != nil {
+		t.Fatalf("NewCatalog() failed: %v", err)
+	}
+
+	if err := c.RenameQuery("find-active", "find-active-v2"); err != nil {
+		t.Fatalf("RenameQuery() failed: %v", err)
+	}
+	stmts, err := c.Statements()
+	if err != nil {
+		t.Fatalf("Statements() failed: %v", err)
+	}
+	renamed, ok := stmts["find-active-v2"].(QueryStatement)
+	if !ok || stmts["find-active"] != nil {
+		t.Fatalf("expected only the new name, got %v", stmts)
+	}
+	if renamed.Description() != "Active users" || len(renamed.Params()) != 1 || renamed.Tags()[0] != "users" {
+		t.Errorf("expected the definition to be kept, got %+v", renamed)
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"missing", c.RenameQuery("find-active", "find-active-v3"), `no query named "find-active"`},
+		{"wrong type", c.RenameSelect("count", "count-v2"), `no select named "count"`},
+		{"taken", c.RenameQuery("find-active-v2", "count"), `already has a statement named "count"`},
+		{"empty", c.RenameAggregate("count", ""), "empty name"},
+	}
+	for _, tt := range tests {
+		if tt.err == nil || !strings.Contains(tt.err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.want, tt.err)
+		}
+	}
+	if len(c.Aggregates) != 1 || c.Aggregates[0].Name != "count" {
+		t.Errorf("expected failed renames to leave the catalog unchanged, got %+v", c.Aggregates)
+	}
+}
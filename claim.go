@@ -0,0 +1,80 @@
+package edamame
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ExecClaimNext claims the first row matching a query statement for the rest of tx.
+// The statement is run as "... LIMIT 1 FOR UPDATE SKIP LOCKED", so concurrent workers
+// each lock a different row instead of waiting on one another. The statement's Where
+// and OrderBy select which row is next; its Limit, Offset params and ForLocking are
+// replaced. Returns nil when no unlocked row matches.
+//
+// A claim only holds while tx is open, so a transaction is required: update the
+// claimed row and commit, or roll back to release it.
+//
+// Example:
+//
+//	var NextJob = edamame.NewQueryStatement("next-job", "Oldest pending job", edamame.QuerySpec{
+//	    Where:   []edamame.ConditionSpec{{Field: "status", Operator: "=", Param: "status"}},
+//	    OrderBy: []edamame.OrderBySpec{{Field: "created_at", Direction: "asc"}},
+//	})
+//
+//	job, err := exec.ExecClaimNext(ctx, tx, NextJob, map[string]any{"status": "pending"})
+func (e *Executor[T]) ExecClaimNext(ctx context.Context, tx *sqlx.Tx, stmt QueryStatement, params map[string]any) (*T, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	if tx == nil {
+		return nil, fmt.Errorf("edamame: ExecClaimNext requires a transaction")
+	}
+	params, err := e.prepareParams(stmt, params)
+	if err != nil {
+		return nil, err
+	}
+	sql, binds, err := e.renderClaim(stmt)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = withStatement(ctx, stmt.name, "query")
+	params = mergeParams(params, binds)
+	e.emitSQL(ctx, stmt.name, "query", sql, params)
+	records, err := execRenderedQuery[T](ctx, e.execerFor(tx), sql, params)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	if err := e.assertResults(records[0]); err != nil {
+		return nil, err
+	}
+	return records[0], nil
+}
+
+// renderClaim renders stmt limited to one row and locked with FOR UPDATE SKIP LOCKED.
+// soy renders FOR UPDATE but has no SKIP LOCKED, so it is appended to the rendered SQL.
+func (e *Executor[T]) renderClaim(stmt QueryStatement) (string, map[string]any, error) {
+	one := 1
+	spec := stmt.spec
+	spec.Limit = &one
+	spec.LimitParam = ""
+	spec.ForLocking = lockModeUpdate
+
+	q, err := e.queryFromSpec(spec)
+	if err != nil {
+		return "", nil, err
+	}
+	result, err := q.Render()
+	if err != nil {
+		return "", nil, err
+	}
+	if !strings.HasSuffix(result.SQL, " FOR UPDATE") {
+		return "", nil, fmt.Errorf("edamame: rendered SQL does not end with FOR UPDATE")
+	}
+	return e.finalizeSQL(result.SQL+" SKIP LOCKED", e.queryRewrites(spec))
+}
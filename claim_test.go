@@ -0,0 +1,40 @@
+package edamame
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+var nextByAge = NewQueryStatement("next-by-age", "Youngest user at or above an age", QuerySpec{
+	Where:   []ConditionSpec{{Field: "age", Operator: ">=", Param: "min_age"}},
+	OrderBy: []OrderBySpec{{Field: "age", Direction: "asc"}},
+	Limit:   intPtr(10),
+})
+
+func TestRenderClaim(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	sql, _, err := factory.renderClaim(nextByAge)
+	if err != nil {
+		t.Fatalf("renderClaim() failed: %v", err)
+	}
+	if !strings.HasSuffix(sql, `ORDER BY "age" ASC LIMIT 1 FOR UPDATE SKIP LOCKED`) {
+		t.Errorf("unexpected claim SQL: %s", sql)
+	}
+}
+
+func TestExecClaimNext_RequiresTx(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if _, err := factory.ExecClaimNext(context.Background(), nil, nextByAge, nil); err == nil {
+		t.Error("expected error without a transaction")
+	}
+}
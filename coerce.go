@@ -0,0 +1,168 @@
+package edamame
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// SetParamCoercion enables or disables converting string param values to the type
+// they bind to before a statement executes. HTTP handlers receive query string
+// values as strings, which the driver cannot bind to integer, numeric, boolean or
+// timestamp columns; with coercion enabled such a map can be passed as is.
+//
+// A param's target type is its ParamSpec Type when that names one ("integer",
+// "numeric", "boolean" or "timestamp"), otherwise the Go type of the struct field
+// the param is compared with or assigned to. Timestamps are parsed as RFC 3339 or
+// as a "2006-01-02" date. Values that are not strings, params with no known type
+// and list params are left unchanged. A string that does not parse fails the call
+// before it reaches the database.
+//
+// Example:
+//
+//	exec.SetParamCoercion(true)
+//	users, err := exec.ExecQuery(ctx, Adults, map[string]any{"min_age": r.URL.Query().Get("min_age")})
+func (e *Executor[T]) SetParamCoercion(enabled bool) {
+	e.paramCoercion.Store(enabled)
+}
+
+// Param types a string value can be coerced to.
+const (
+	coerceInteger   = "integer"
+	coerceNumeric   = "numeric"
+	coerceBoolean   = "boolean"
+	coerceTimestamp = "timestamp"
+)
+
+// coerceParams returns params with each string value converted to the type of its
+// param in specs. The caller's map is not modified; params is returned as is when
+// nothing is converted.
+func (e *Executor[T]) coerceParams(stmt Statement, specs []ParamSpec, params map[string]any) (map[string]any, error) {
+	var fields map[string]reflect.Type
+	var coerced map[string]any
+	for _, p := range specs {
+		s, ok := params[p.Name].(string)
+		if !ok || p.Type == "array" {
+			continue
+		}
+		typ := p.Type
+		if !isCoercible(typ) {
+			if fields == nil {
+				fields = e.paramFieldTypes(stmt)
+			}
+			typ = coercionType(fields[p.Name])
+		}
+		if typ == "" {
+			continue
+		}
+		v, err := coerceString(s, typ)
+		if err != nil {
+			return nil, fmt.Errorf("edamame: param %q for statement %q: cannot coerce %q to %s: %w", p.Name, stmt.Name(), s, typ, err)
+		}
+		if coerced == nil {
+			coerced = maps.Clone(params)
+		}
+		coerced[p.Name] = v
+	}
+	if coerced == nil {
+		return params, nil
+	}
+	return coerced, nil
+}
+
+// isCoercible reports whether typ is a ParamSpec Type coerceString converts to.
+func isCoercible(typ string) bool {
+	switch typ {
+	case coerceInteger, coerceNumeric, coerceBoolean, coerceTimestamp:
+		return true
+	}
+	return false
+}
+
+// coerceString parses s as typ.
+func coerceString(s, typ string) (any, error) {
+	switch typ {
+	case coerceInteger:
+		return strconv.ParseInt(s, 10, 64)
+	case coerceNumeric:
+		return strconv.ParseFloat(s, 64)
+	case coerceBoolean:
+		return strconv.ParseBool(s)
+	case coerceTimestamp:
+		if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+			return t, nil
+		}
+		return time.Parse(time.DateOnly, s)
+	}
+	return s, nil
+}
+
+// coercionType returns the param type a struct field of type t binds as, or "" if
+// string values need no conversion.
+func coercionType(t reflect.Type) string {
+	if t == nil {
+		return ""
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == reflect.TypeFor[time.Time]() {
+		return coerceTimestamp
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return coerceInteger
+	case reflect.Float32, reflect.Float64:
+		return coerceNumeric
+	case reflect.Bool:
+		return coerceBoolean
+	}
+	return ""
+}
+
+// paramFieldTypes maps the params stmt compares with or assigns to a column of T to
+// that column's struct field type. A param bound to several columns takes the first.
+func (e *Executor[T]) paramFieldTypes(stmt Statement) map[string]reflect.Type {
+	where, _ := statementWhere(stmt)
+	columns := make(map[string]string)
+	collectParamColumns(e.mapConditions(where), columns)
+	if s, ok := stmt.(UpdateStatement); ok {
+		for col, param := range e.columnKeys(s.spec.Set) {
+			if _, seen := columns[param]; !seen {
+				columns[param] = col
+			}
+		}
+	}
+
+	model := reflect.TypeFor[T]()
+	types := make(map[string]reflect.Type, len(columns))
+	for param, col := range columns {
+		if index, ok := e.columns[col]; ok {
+			types[param] = model.FieldByIndex(index).Type
+		}
+	}
+	return types
+}
+
+// collectParamColumns records the column each param in conditions is compared with,
+// recursing into groups. List params are skipped, as they bind slices, and so are
+// JSONB path params, which bind values inside the column rather than the column's type.
+func collectParamColumns(conditions []ConditionSpec, columns map[string]string) {
+	for _, c := range conditions {
+		if c.IsGroup() {
+			collectParamColumns(c.Group, columns)
+			continue
+		}
+		if c.IsList() || c.IsJSONPath() {
+			continue
+		}
+		for _, param := range []string{c.Param, c.LowParam, c.HighParam} {
+			if _, seen := columns[param]; param != "" && !seen {
+				columns[param] = c.Field
+			}
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package edamame
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+type Feature struct {
+	ID        int       `db:"id" type:"integer" constraints:"primarykey"`
+	Enabled   bool      `db:"enabled" type:"boolean"`
+	Weight    float64   `db:"weight" type:"numeric"`
+	ReleaseAt time.Time `db:"release_at" type:"timestamptz"`
+}
+
+func TestPrepareParams_Coercion(t *testing.T) {
+	exec, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	stmt := NewQueryStatement("adults", "Adults", QuerySpec{
+		Where:      []ConditionSpec{{Field: "age", Operator: ">=", Param: "min_age"}, {Field: "name", Operator: "=", Param: "name"}},
+		LimitParam: "limit",
+	})
+	input := map[string]any{"min_age": "25", "name": "42", "limit": "10"}
+
+	params, err := exec.prepareParams(stmt, input)
+	if err != nil {
+		t.Fatalf("prepareParams() failed: %v", err)
+	}
+	if params["min_age"] != "25" {
+		t.Errorf("expected no coercion by default, got %#v", params["min_age"])
+	}
+
+	exec.SetParamCoercion(true)
+	params, err = exec.prepareParams(stmt, input)
+	if err != nil {
+		t.Fatalf("prepareParams() failed: %v", err)
+	}
+	if params["min_age"] != int64(25) || params["limit"] != int64(10) {
+		t.Errorf("expected integer params, got %#v and %#v", params["min_age"], params["limit"])
+	}
+	if params["name"] != "42" {
+		t.Errorf("expected text param to stay a string, got %#v", params["name"])
+	}
+	if input["min_age"] != "25" {
+		t.Error("expected the caller's map to be left unmodified")
+	}
+
+	_, err = exec.prepareParams(stmt, map[string]any{"min_age": "abc", "name": "x"})
+	if err == nil || !strings.Contains(err.Error(), `cannot coerce "abc" to integer`) {
+		t.Errorf("expected a coercion error, got %v", err)
+	}
+}
+
+func TestPrepareParams_CoercionFieldTypes(t *testing.T) {
+	exec, err := New[Feature](nil, "features", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	exec.SetParamCoercion(true)
+	stmt := NewUpdateStatement("toggle", "Toggle a feature", UpdateSpec{
+		Set: map[string]string{"enabled": "enabled", "weight": "weight"},
+		Where: []ConditionSpec{{Field: "release_at", Between: true, LowParam: "from", HighParam: "to"},
+			{Field: "id", In: true, Param: "ids"}},
+	})
+
+	params, err := exec.prepareParams(stmt, map[string]any{
+		"enabled": "true", "weight": "0.5", "from": "2024-01-01", "to": "2024-06-30T12:00:00Z", "ids": "1,2",
+	})
+	if err != nil {
+		t.Fatalf("prepareParams() failed: %v", err)
+	}
+	if params["enabled"] != true || params["weight"] != 0.5 {
+		t.Errorf("expected bool and float params, got %#v and %#v", params["enabled"], params["weight"])
+	}
+	from, ok := params["from"].(time.Time)
+	if !ok || !from.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected a date param, got %#v", params["from"])
+	}
+	if _, ok := params["to"].(time.Time); !ok {
+		t.Errorf("expected a timestamp param, got %#v", params["to"])
+	}
+	if params["ids"] != "1,2" {
+		t.Errorf("expected list param to be left unchanged, got %#v", params["ids"])
+	}
+
+	if _, err := exec.prepareParams(stmt, map[string]any{"enabled": "maybe", "weight": "1", "from": "2024-01-01", "to": "2024-01-02", "ids": nil}); err == nil {
+		t.Error("expected an error for an invalid boolean")
+	}
+}
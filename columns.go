@@ -0,0 +1,206 @@
+package edamame
+
+// SetColumnMapper sets the function that translates field references in specs
+// to db column names. References that already name a db column of T are used
+// as-is; any other name is passed to fn. By default, Go struct field names are
+// mapped to their `db` tag, so "CreatedAt" resolves to "created_at". Passing nil
+// restores the default.
+//
+// Mapping applies to every field reference a spec carries: selected fields,
+// conditions, ordering, grouping, select expressions, aliased fields, SET and conflict columns.
+// Parameter names and aliases are never mapped.
+func (e *Executor[T]) SetColumnMapper(fn func(field string) string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.mapper = fn
+}
+
+// fieldColumns maps each Go field name of T to its db column.
+func (e *Executor[T]) fieldColumns() map[string]string {
+	fields := e.soy.Metadata().Fields
+	columns := make(map[string]string, len(fields))
+	for _, f := range fields {
+		col := f.Tags["db"]
+		if col == "" || col == "-" {
+			continue
+		}
+		columns[f.Name] = col
+	}
+	return columns
+}
+
+// column resolves a spec field reference to a db column name.
+func (e *Executor[T]) column(field string) string {
+	if field == "" {
+		return field
+	}
+	if _, ok := e.columns[field]; ok {
+		return field
+	}
+
+	e.mu.RLock()
+	mapper := e.mapper
+	e.mu.RUnlock()
+
+	if mapper != nil {
+		return mapper(field)
+	}
+	if col, ok := e.goFields[field]; ok {
+		return col
+	}
+	return field
+}
+
+// columnList resolves each field reference in fields.
+func (e *Executor[T]) columnList(fields []string) []string {
+	if fields == nil {
+		return nil
+	}
+	mapped := make([]string, len(fields))
+	for i, f := range fields {
+		mapped[i] = e.column(f)
+	}
+	return mapped
+}
+
+// columnKeys resolves the keys of a column -> param map, such as UpdateSpec.Set.
+func (e *Executor[T]) columnKeys(set map[string]string) map[string]string {
+	if set == nil {
+		return nil
+	}
+	mapped := make(map[string]string, len(set))
+	for field, param := range set {
+		mapped[e.column(field)] = param
+	}
+	return mapped
+}
+
+// mapConditions resolves the field references in conditions, recursing into groups
+// and inlining condition fragments.
+func (e *Executor[T]) mapConditions(conditions []ConditionSpec) []ConditionSpec {
+	if conditions == nil {
+		return nil
+	}
+	conditions = e.expandFragments(conditions)
+	mapped := make([]ConditionSpec, len(conditions))
+	for i, c := range conditions {
+		c.Field = e.column(c.Field)
+		c.RightField = e.column(c.RightField)
+		c.Group = e.mapConditions(c.Group)
+		if c.IsMatch() {
+			// Match conditions bind the wrapped pattern derived by bindMatchParams.
+			c.Operator, c.Param = c.matchOperator(), c.matchParam()
+		}
+		mapped[i] = c
+	}
+	return mapped
+}
+
+// mapOrderBy resolves the field references in orderBy.
+func (e *Executor[T]) mapOrderBy(orderBy []OrderBySpec) []OrderBySpec {
+	if orderBy == nil {
+		return nil
+	}
+	mapped := make([]OrderBySpec, len(orderBy))
+	for i, o := range orderBy {
+		o.Field = e.column(o.Field)
+		if o.Terms != nil {
+			terms := make([]OrderTermSpec, len(o.Terms))
+			for k, term := range o.Terms {
+				term.Field = e.column(term.Field)
+				terms[k] = term
+			}
+			o.Terms = terms
+		}
+		mapped[i] = o
+	}
+	return mapped
+}
+
+// mapHavingAgg resolves the field references in aggregate HAVING conditions.
+func (e *Executor[T]) mapHavingAgg(having []HavingAggSpec) []HavingAggSpec {
+	if having == nil {
+		return nil
+	}
+	mapped := make([]HavingAggSpec, len(having))
+	for i, h := range having {
+		h.Field = e.column(h.Field)
+		mapped[i] = h
+	}
+	return mapped
+}
+
+// mapSelectExprs resolves the field references in select expressions.
+func (e *Executor[T]) mapSelectExprs(exprs []SelectExprSpec) []SelectExprSpec {
+	if exprs == nil {
+		return nil
+	}
+	mapped := make([]SelectExprSpec, len(exprs))
+	for i, x := range exprs {
+		x.Field = e.column(x.Field)
+		x.Fields = e.columnList(x.Fields)
+		if x.Filter != nil {
+			filter := e.mapConditions([]ConditionSpec{*x.Filter})[0]
+			x.Filter = &filter
+		}
+		mapped[i] = x
+	}
+	return mapped
+}
+
+// mapQuerySpec returns spec with its field references resolved to columns.
+func (e *Executor[T]) mapQuerySpec(spec QuerySpec) QuerySpec {
+	spec.Fields = e.columnList(spec.Fields)
+	spec.FieldAliases = e.columnKeys(spec.FieldAliases)
+	spec.SelectExprs = e.mapSelectExprs(spec.SelectExprs)
+	spec.Where = e.mapConditions(spec.Where)
+	spec.OrderBy = e.mapOrderBy(spec.OrderBy)
+	spec.GroupBy = e.columnList(spec.GroupBy)
+	spec.Having = e.mapConditions(spec.Having)
+	spec.HavingAgg = e.mapHavingAgg(spec.HavingAgg)
+	spec.DistinctOn = e.columnList(spec.DistinctOn)
+	return spec
+}
+
+// mapSelectSpec returns spec with its field references resolved to columns.
+func (e *Executor[T]) mapSelectSpec(spec SelectSpec) SelectSpec {
+	spec.Fields = e.columnList(spec.Fields)
+	spec.FieldAliases = e.columnKeys(spec.FieldAliases)
+	spec.SelectExprs = e.mapSelectExprs(spec.SelectExprs)
+	spec.Where = e.mapConditions(spec.Where)
+	spec.OrderBy = e.mapOrderBy(spec.OrderBy)
+	spec.GroupBy = e.columnList(spec.GroupBy)
+	spec.Having = e.mapConditions(spec.Having)
+	spec.HavingAgg = e.mapHavingAgg(spec.HavingAgg)
+	spec.DistinctOn = e.columnList(spec.DistinctOn)
+	return spec
+}
+
+// mapUpdateSpec returns spec with its field references resolved to columns.
+func (e *Executor[T]) mapUpdateSpec(spec UpdateSpec) UpdateSpec {
+	spec.Set = e.columnKeys(spec.Set)
+	spec.SetExpr = e.columnKeys(spec.SetExpr)
+	spec.Where = e.mapConditions(spec.Where)
+	return spec
+}
+
+// mapDeleteSpec returns spec with its field references resolved to columns.
+func (e *Executor[T]) mapDeleteSpec(spec DeleteSpec) DeleteSpec {
+	spec.Where = e.mapConditions(spec.Where)
+	return spec
+}
+
+// mapAggregateSpec returns spec with its field references resolved to columns.
+func (e *Executor[T]) mapAggregateSpec(spec AggregateSpec) AggregateSpec {
+	spec.Field = e.column(spec.Field)
+	spec.Where = e.mapConditions(spec.Where)
+	spec.GroupBy = e.columnList(spec.GroupBy)
+	return spec
+}
+
+// mapCreateSpec returns spec with its field references resolved to columns.
+func (e *Executor[T]) mapCreateSpec(spec CreateSpec) CreateSpec {
+	spec.OnConflict = e.columnList(spec.OnConflict)
+	spec.ConflictSet = e.columnKeys(spec.ConflictSet)
+	return spec
+}
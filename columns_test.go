@@ -0,0 +1,64 @@
+package edamame
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestColumnMapper_GoFieldNames(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	stmt := NewQueryStatement("by-email-go", "Users by email using Go field names", QuerySpec{
+		Fields:  []string{"ID", "Email"},
+		Where:   []ConditionSpec{{Field: "Email", Operator: "=", Param: "email"}},
+		OrderBy: []OrderBySpec{{Field: "Name", Direction: "asc"}},
+	})
+
+	sql, err := factory.RenderQuery(stmt)
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+	for _, want := range []string{`SELECT "id", "email"`, `WHERE "email" = :email`, `ORDER BY "name" ASC`} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("expected %q in SQL, got: %s", want, sql)
+		}
+	}
+}
+
+func TestColumnMapper_Custom(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	factory.SetColumnMapper(func(field string) string {
+		return strings.TrimPrefix(field, "user_")
+	})
+
+	update := NewUpdateStatement("rename-mapped", "Rename user with prefixed fields", UpdateSpec{
+		Set:   map[string]string{"user_name": "name"},
+		Where: []ConditionSpec{{Field: "user_id", Operator: "=", Param: "id"}},
+	})
+	sql, err := factory.RenderUpdate(update)
+	if err != nil {
+		t.Fatalf("RenderUpdate() failed: %v", err)
+	}
+	if !strings.Contains(sql, `SET "name" = :name`) || !strings.Contains(sql, `WHERE "id" = :id`) {
+		t.Errorf("expected mapped columns, got: %s", sql)
+	}
+
+	// Known columns bypass the mapper.
+	if got := factory.column("email"); got != "email" {
+		t.Errorf("column(email) = %q, want email", got)
+	}
+
+	// nil restores the db tag default.
+	factory.SetColumnMapper(nil)
+	if got := factory.column("Email"); got != "email" {
+		t.Errorf("column(Email) = %q, want email", got)
+	}
+}
@@ -0,0 +1,68 @@
+package edamame
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// statementTagKey carries the statement being executed for SQL comments.
+type statementTagKey struct{}
+
+// statementTag identifies the statement behind a database call.
+type statementTag struct {
+	name      string
+	queryType string
+}
+
+// withStatement records the statement executing under ctx.
+func withStatement(ctx context.Context, name, queryType string) context.Context {
+	return context.WithValue(ctx, statementTagKey{}, statementTag{name: name, queryType: queryType})
+}
+
+// SetSQLComments appends a sqlcommenter-style comment identifying the table and
+// statement to the SQL the executor runs and renders, for example:
+//
+//	SELECT ... /*framework='edamame',statement='by-age',table='users',type='query'*/
+//
+// pg_stat_statements and APM tools can use it to attribute database load to statements.
+// The comment is appended rather than prepended so index hints stay the leading comment.
+// Statements run on the executor's database are tagged, as are query, select, cursor and
+// typed aggregate/insert calls within a transaction; the remaining Tx variants run soy's
+// SQL on the transaction directly and are not.
+func (e *Executor[T]) SetSQLComments(enabled bool) {
+	e.sqlComments.Store(enabled)
+}
+
+// annotateSQL appends the statement comment for ctx when SQL comments are enabled.
+func (e *Executor[T]) annotateSQL(ctx context.Context, sql string) string {
+	if !e.sqlComments.Load() {
+		return sql
+	}
+	tag, _ := ctx.Value(statementTagKey{}).(statementTag)
+	return sql + " " + sqlComment(e.TableName(), tag)
+}
+
+// sqlComment formats a sqlcommenter comment: sorted key='value' pairs with URL-encoded values.
+func sqlComment(table string, tag statementTag) string {
+	pairs := []string{"framework=" + commentValue("edamame")}
+	if tag.name != "" {
+		pairs = append(pairs, "statement="+commentValue(tag.name))
+	}
+	pairs = append(pairs, "table="+commentValue(table))
+	if tag.queryType != "" {
+		pairs = append(pairs, "type="+commentValue(tag.queryType))
+	}
+	return "/*" + strings.Join(pairs, ",") + "*/"
+}
+
+// commentValue URL-encodes v and quotes it. Escaping ':' as well keeps the comment
+// free of anything sqlx could read as a named parameter.
+func commentValue(v string) string {
+	return "'" + strings.ReplaceAll(url.PathEscape(v), ":", "%3A") + "'"
+}
+
+// annotateRendered applies annotateSQL to SQL returned by the Render methods.
+func (e *Executor[T]) annotateRendered(sql, name, queryType string) string {
+	return e.annotateSQL(withStatement(context.Background(), name, queryType), sql)
+}
@@ -0,0 +1,79 @@
+package edamame
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestSetSQLComments_Render(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	plain, err := factory.RenderQuery(queryAll)
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+	if strings.Contains(plain, "/*") {
+		t.Errorf("expected no comment while disabled, got: %s", plain)
+	}
+
+	factory.SetSQLComments(true)
+
+	sql, err := factory.RenderQuery(queryAll)
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+	want := plain + ` /*framework='edamame',statement='query-all',table='users',type='query'*/`
+	if sql != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, sql)
+	}
+
+	hinted, err := factory.RenderQuery(hintedByEmail)
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+	if !strings.HasPrefix(hinted, "/*+ ") || !strings.HasSuffix(hinted, "type='query'*/") {
+		t.Errorf("index hint should lead and the tag comment trail, got: %s", hinted)
+	}
+}
+
+func TestSQLComment_Escaping(t *testing.T) {
+	comment := sqlComment("users", statementTag{name: "it's */ DROP:x", queryType: "delete"})
+	want := `/*framework='edamame',statement='it%27s%20%2A%2F%20DROP%3Ax',table='users',type='delete'*/`
+	if comment != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, comment)
+	}
+	if strings.Count(comment, "*/") != 1 {
+		t.Errorf("comment must close exactly once: %s", comment)
+	}
+}
+
+func TestSetSQLComments_Exec(t *testing.T) {
+	db := &recordingDB{}
+	factory, err := New[User](db, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	factory.SetSQLComments(true)
+
+	ctx := context.Background()
+	_, _ = factory.ExecQuery(ctx, queryAll, nil)
+	_, _ = factory.ExecDelete(ctx, NewDeleteStatement("purge", "Purge", DeleteSpec{
+		Where: []ConditionSpec{{Field: "id", Operator: "=", Param: "id"}},
+	}), map[string]any{"id": 1})
+
+	if db.count() != 2 {
+		t.Fatalf("expected 2 statements, got %d", db.count())
+	}
+	if !strings.HasSuffix(db.queries[0], `/*framework='edamame',statement='query-all',table='users',type='query'*/`) {
+		t.Errorf("query not tagged: %s", db.queries[0])
+	}
+	if !strings.HasSuffix(db.queries[1], `/*framework='edamame',statement='purge',table='users',type='delete'*/`) {
+		t.Errorf("delete not tagged: %s", db.queries[1])
+	}
+}
@@ -0,0 +1,98 @@
+package edamame
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// maxConditionGroupDepth is the group nesting ConditionsFromJSON accepts. The
+// builder renders a group's simple conditions only, so a group inside a group
+// would be dropped silently.
+const maxConditionGroupDepth = 1
+
+// jsonConditionOperators lists the operators ConditionsFromJSON accepts: comparison,
+// pattern, membership, null and null-safe comparison operators. Regex, array and
+// vector operators are left to statements defined in code.
+var jsonConditionOperators = map[string]bool{
+	"=": true, "!=": true, ">": true, ">=": true, "<": true, "<=": true,
+	"LIKE": true, "NOT LIKE": true, "ILIKE": true, "NOT ILIKE": true,
+	opIn: true, opNotIn: true, opIsNull: true, opIsNotNull: true,
+	opIsDistinctFrom: true, opIsNotDistinctFrom: true,
+}
+
+// ConditionsFromJSON decodes a JSON array of conditions from untrusted input, such
+// as an API filter, and validates it against T, so the result can be attached to
+// an ad-hoc QuerySpec or SelectSpec. It rejects:
+//
+//   - unknown JSON keys and trailing data;
+//   - fields that are not db columns of T, naming the closest column, and fields
+//     outside SetQueryableFields when it is set;
+//   - operators other than comparison, LIKE and ILIKE, IN and NOT IN, IS NULL and
+//     IS DISTINCT FROM forms;
+//   - EXISTS subqueries and quantified subquery comparisons, which reach beyond T's table;
+//   - groups without an AND or OR logic, and groups nested inside groups;
+//   - anything else the statement would fail to build with, such as an unknown fragment.
+//
+// Example:
+//
+//	where, err := exec.ConditionsFromJSON(body)
+//	if err != nil {
+//	    return err // 400 Bad Request
+//	}
+//	users, err := exec.ExecQuery(ctx, edamame.NewQueryStatement("search", "Filtered users", edamame.QuerySpec{Where: where}), params)
+func (e *Executor[T]) ConditionsFromJSON(data []byte) ([]ConditionSpec, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var conditions []ConditionSpec
+	if err := dec.Decode(&conditions); err != nil {
+		return nil, fmt.Errorf("edamame: invalid conditions: %w", err)
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("edamame: invalid conditions: unexpected data after the array")
+	}
+
+	if err := checkJSONConditions(conditions, 0); err != nil {
+		return nil, fmt.Errorf("edamame: invalid conditions: %w", err)
+	}
+	if err := e.checkKnownFields(e.conditionFields(conditions, nil)); err != nil {
+		return nil, fmt.Errorf("edamame: invalid conditions: %w", err)
+	}
+	q, err := e.queryFromSpec(QuerySpec{Where: conditions})
+	if err == nil {
+		_, err = q.Render()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("edamame: invalid conditions: %w", err)
+	}
+	return conditions, nil
+}
+
+// checkJSONConditions checks the shape of conditions nested depth groups deep.
+func checkJSONConditions(conditions []ConditionSpec, depth int) error {
+	for _, c := range conditions {
+		switch {
+		case c.IsExists():
+			return fmt.Errorf("EXISTS conditions are not allowed")
+		case c.IsQuantifiedSubquery():
+			return fmt.Errorf("subquery comparisons are not allowed")
+		case len(c.Group) > 0:
+			if !strings.EqualFold(c.Logic, "AND") && !strings.EqualFold(c.Logic, logicOR) {
+				return fmt.Errorf("condition group logic must be AND or OR, got %q", c.Logic)
+			}
+			if depth >= maxConditionGroupDepth {
+				return fmt.Errorf("condition groups nest deeper than %d level", maxConditionGroupDepth)
+			}
+			if err := checkJSONConditions(c.Group, depth+1); err != nil {
+				return err
+			}
+		case c.IsFragment(), c.IsFullText():
+		case c.Field == "":
+			return fmt.Errorf("condition requires a field")
+		case c.Operator != "" && !jsonConditionOperators[strings.ToUpper(c.Operator)]:
+			return fmt.Errorf("operator %q is not allowed on field %q", c.Operator, c.Field)
+		}
+	}
+	return nil
+}
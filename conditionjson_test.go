@@ -0,0 +1,64 @@
+package edamame
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestConditionsFromJSON(t *testing.T) {
+	exec, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	where, err := exec.ConditionsFromJSON([]byte(`[
+		{"field": "age", "operator": ">=", "param": "min_age"},
+		{"field": "name", "operator": "IS DISTINCT FROM", "param": "name"},
+		{"logic": "OR", "group": [
+			{"field": "name", "match": "contains", "param": "q"},
+			{"field": "email", "is_null": true, "operator": "IS NULL"}
+		]}
+	]`))
+	if err != nil {
+		t.Fatalf("ConditionsFromJSON() failed: %v", err)
+	}
+	if len(where) != 3 || len(where[2].Group) != 2 {
+		t.Fatalf("unexpected conditions: %+v", where)
+	}
+	if _, err := exec.RenderQuery(NewQueryStatement("search", "", QuerySpec{Where: where})); err != nil {
+		t.Errorf("expected the conditions to render, got %v", err)
+	}
+
+	tests := []struct {
+		name string
+		json string
+		want string
+	}{
+		{"unknown field", `[{"field": "nmae", "operator": "=", "param": "name"}]`, `unknown field "nmae" (did you mean "name"?)`},
+		{"too deep", `[{"logic": "AND", "group": [{"logic": "OR", "group": [{"field": "age", "operator": "=", "param": "age"}]}]}]`, "nest deeper than 1 level"},
+		{"operator", `[{"field": "name", "operator": "~", "param": "pattern"}]`, `operator "~" is not allowed`},
+		{"exists", `[{"exists": true, "correlate": [{"outer": "id", "inner": "id"}]}]`, "EXISTS conditions are not allowed"},
+		{"group logic", `[{"logic": "XOR", "group": [{"field": "age", "operator": "=", "param": "age"}]}]`, "logic must be AND or OR"},
+		{"missing field", `[{"operator": "=", "param": "age"}]`, "requires a field"},
+		{"unknown key", `[{"field": "age", "operator": "=", "param": "age", "raw": "1=1"}]`, `unknown field "raw"`},
+		{"trailing data", `[] []`, "unexpected data"},
+		{"not an array", `{"field": "age"}`, "cannot unmarshal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := exec.ConditionsFromJSON([]byte(tt.json))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if err := exec.SetQueryableFields("age"); err != nil {
+		t.Fatalf("SetQueryableFields() failed: %v", err)
+	}
+	if _, err := exec.ConditionsFromJSON([]byte(`[{"field": "email", "operator": "=", "param": "email"}]`)); err == nil || !strings.Contains(err.Error(), "not queryable") {
+		t.Errorf("expected the allowlist to apply, got %v", err)
+	}
+}
@@ -16,8 +16,12 @@ const (
 	lockModeShare         = "share"
 	lockModeKeyShare      = "key_share"
 	logicOR               = "OR"
+	opIn                  = "IN"
+	opNotIn               = "NOT IN"
 	opIsNull              = "IS NULL"
 	opIsNotNull           = "IS NOT NULL"
+	opIsDistinctFrom      = "IS DISTINCT FROM"
+	opIsNotDistinctFrom   = "IS NOT DISTINCT FROM"
 	selectExprCount       = "count"
 )
 
@@ -29,7 +33,16 @@ func (c ConditionSpec) toCondition() soy.Condition {
 		}
 		return soy.Null(c.Field)
 	}
-	return soy.C(c.Field, c.Operator, c.Param)
+	if c.IsBetween() {
+		return soy.Between(c.Field, c.LowParam, c.HighParam)
+	}
+	if c.IsNotBetween() {
+		return soy.NotBetween(c.Field, c.LowParam, c.HighParam)
+	}
+	if c.IsIn() || c.IsNotIn() {
+		return soy.C(c.Field, c.listOperator(), c.Param)
+	}
+	return soy.C(c.Field, c.operator(), c.Param)
 }
 
 // toConditions converts a slice of ConditionSpecs to soy.Conditions.
@@ -47,12 +60,51 @@ func toConditions(specs []ConditionSpec) []soy.Condition {
 // queryFromSpec builds a soy.Query from a QuerySpec.
 // Returns an error if the spec contains invalid values.
 func (e *Executor[T]) queryFromSpec(spec QuerySpec) (*soy.Query[T], error) {
+	if err := e.checkQueryFields(spec); err != nil {
+		return nil, err
+	}
+	if err := e.checkSpecFragments(spec.Where, spec.Having); err != nil {
+		return nil, err
+	}
+	if err := checkExists(spec.Where, spec.Having, spec.OuterWhere); err != nil {
+		return nil, err
+	}
+	if err := e.checkMatch(spec.Where, spec.Having, spec.OuterWhere); err != nil {
+		return nil, err
+	}
+	if err := e.checkQuantifiers(spec.Where, spec.Having, spec.OuterWhere); err != nil {
+		return nil, err
+	}
+	if err := e.checkJSONPaths(spec.Where, spec.Having, spec.OuterWhere); err != nil {
+		return nil, err
+	}
+	if err := e.checkFullText(spec.Where, spec.Having, spec.OuterWhere); err != nil {
+		return nil, err
+	}
+	if err := checkRowExprs(spec.SelectExprs); err != nil {
+		return nil, err
+	}
+	spec = e.mapQuerySpec(spec)
+	// Null-safe comparisons render as = or != until rewriteDistinctFrom restores them,
+	// quantified comparisons without ANY or ALL until rewriteQuantified adds it, JSONB
+	// path conditions as comparisons on their field until rewriteJSONPaths restores them,
+	// and full-text and EXISTS conditions as placeholder comparisons until rewriteFullText
+	// and rewriteExists replace them.
+	standIn := e.schemaColumns()[0]
+	spec.Where = withExistsPlaceholders(withFullTextPlaceholders(withJSONPathPlaceholders(withQuantifierPlaceholders(withDistinctFromPlaceholders(spec.Where))), standIn), standIn)
+
 	q := e.soy.Query()
 
-	// Add fields if specified
+	// Add fields if specified; aliases are appended by rewriteFieldAliases
 	if len(spec.Fields) > 0 {
 		q = q.Fields(spec.Fields...)
 	}
+	if err := e.validateFieldAliases(spec.Fields, spec.FieldAliases); err != nil {
+		return nil, err
+	}
+	if spec.MaxResults < 0 {
+		return nil, fmt.Errorf("max results must not be negative, got %d", spec.MaxResults)
+	}
 
 	// Add select expressions if specified
 	for i := range spec.SelectExprs {
@@ -63,10 +115,24 @@ func (e *Executor[T]) queryFromSpec(spec QuerySpec) (*soy.Query[T], error) {
 	for i := range spec.Where {
 		q = applyConditionToQuery(q, spec.Where[i])
 	}
+	if col := e.softDeleteColumn(opQuery); col != "" && !spec.WithTrashed {
+		q = q.WhereNull(col)
+	}
 
 	// Add ORDER BY clauses
 	for _, orderBy := range spec.OrderBy {
 		switch {
+		case orderBy.IsWeighted():
+			// One placeholder per term, collapsed into the weighted sum by rewriteCustomOrdering.
+			if err := e.validateOrderTerms(orderBy.Terms); err != nil {
+				return nil, err
+			}
+			for _, term := range orderBy.Terms {
+				q = q.OrderBy(term.Field, orderBy.Direction)
+			}
+		case orderBy.IsCase():
+			// Placeholder replaced with a CASE expression by rewriteCustomOrdering.
+			q = q.OrderBy(orderBy.Field, orderBy.Direction)
 		case orderBy.IsExpression():
 			q = q.OrderByExpr(orderBy.Field, orderBy.Operator, orderBy.Param, orderBy.Direction)
 		case orderBy.HasNulls():
@@ -75,17 +141,18 @@ func (e *Executor[T]) queryFromSpec(spec QuerySpec) (*soy.Query[T], error) {
 			q = q.OrderBy(orderBy.Field, orderBy.Direction)
 		}
 	}
+	if pk := e.tieBreakerColumn(spec.OrderBy, spec.GroupBy); pk != "" {
+		q = q.OrderBy(pk, "asc")
+	}
 
 	// Add GROUP BY if specified
 	if len(spec.GroupBy) > 0 {
 		q = q.GroupBy(spec.GroupBy...)
 	}
 
-	// Add HAVING conditions (simple field-based)
-	for i := range spec.Having {
-		if !spec.Having[i].IsGroup() {
-			q = q.Having(spec.Having[i].Field, spec.Having[i].Operator, spec.Having[i].Param)
-		}
+	// Add HAVING conditions; complex ones render as placeholders restored by rewriteHaving
+	for _, h := range withHavingPlaceholders(spec.Having) {
+		q = q.Having(h.Field, h.Operator, h.Param)
 	}
 
 	// Add HAVING aggregate conditions
@@ -126,6 +193,17 @@ func (e *Executor[T]) queryFromSpec(spec QuerySpec) (*soy.Query[T], error) {
 	return q, nil
 }
 
+// validateOrderTerms checks that weighted ORDER BY coefficient params are valid
+// param names; they are spliced into the rendered SQL rather than passed through soy.
+func (e *Executor[T]) validateOrderTerms(terms []OrderTermSpec) error {
+	for _, term := range terms {
+		if _, err := e.soy.Instance().TryP(term.Param); err != nil {
+			return fmt.Errorf("invalid order term param %q: %w", term.Param, err)
+		}
+	}
+	return nil
+}
+
 // applyConditionToQuery applies a ConditionSpec to a Query builder.
 // Handles simple conditions, condition groups (AND/OR), BETWEEN, and field comparisons.
 func applyConditionToQuery[T any](q *soy.Query[T], cond ConditionSpec) *soy.Query[T] {
@@ -145,6 +223,11 @@ func applyConditionToQuery[T any](q *soy.Query[T], cond ConditionSpec) *soy.Quer
 		return q.WhereNotBetween(cond.Field, cond.LowParam, cond.HighParam)
 	}
 
+	// IN / NOT IN conditions; soy rejects an empty Param
+	if cond.IsIn() || cond.IsNotIn() {
+		return q.Where(cond.Field, cond.listOperator(), cond.Param)
+	}
+
 	// Field-to-field comparison
 	if cond.IsFieldComparison() {
 		return q.WhereFields(cond.Field, cond.Operator, cond.RightField)
@@ -159,7 +242,7 @@ func applyConditionToQuery[T any](q *soy.Query[T], cond ConditionSpec) *soy.Quer
 	}
 
 	// Simple field-operator-param condition
-	return q.Where(cond.Field, cond.Operator, cond.Param)
+	return q.Where(cond.Field, cond.operator(), cond.Param)
 }
 
 // applySelectExprToQuery applies a SelectExprSpec to a Query builder.
@@ -190,6 +273,9 @@ func applySelectExprToQuery[T any](q *soy.Query[T], expr SelectExprSpec) *soy.Qu
 		}
 	case "concat":
 		return q.SelectConcat(expr.Alias, expr.Fields...)
+	case selectExprRowMin, selectExprRowMax:
+		// Rendered as concat until rewriteRowExprs replaces it
+		return q.SelectConcat(expr.Alias, expr.Fields...)
 
 	// Math functions
 	case "abs":
@@ -291,12 +377,48 @@ func applyForLocking[T any](q *soy.Query[T], forLocking string) (*soy.Query[T],
 // selectFromSpec builds a soy.Select from a SelectSpec.
 // Returns an error if the spec contains invalid values.
 func (e *Executor[T]) selectFromSpec(spec SelectSpec) (*soy.Select[T], error) {
+	if err := e.checkQueryFields(selectFieldSpec(spec)); err != nil {
+		return nil, err
+	}
+	if err := e.checkSpecFragments(spec.Where, spec.Having); err != nil {
+		return nil, err
+	}
+	if err := checkExists(spec.Where, spec.Having, spec.OuterWhere); err != nil {
+		return nil, err
+	}
+	if err := e.checkMatch(spec.Where, spec.Having, spec.OuterWhere); err != nil {
+		return nil, err
+	}
+	if err := e.checkQuantifiers(spec.Where, spec.Having, spec.OuterWhere); err != nil {
+		return nil, err
+	}
+	if err := e.checkJSONPaths(spec.Where, spec.Having, spec.OuterWhere); err != nil {
+		return nil, err
+	}
+	if err := e.checkFullText(spec.Where, spec.Having, spec.OuterWhere); err != nil {
+		return nil, err
+	}
+	if err := checkRowExprs(spec.SelectExprs); err != nil {
+		return nil, err
+	}
+	spec = e.mapSelectSpec(spec)
+	// Null-safe comparisons render as = or != until rewriteDistinctFrom restores them,
+	// quantified comparisons without ANY or ALL until rewriteQuantified adds it, JSONB
+	// path conditions as comparisons on their field until rewriteJSONPaths restores them,
+	// and full-text and EXISTS conditions as placeholder comparisons until rewriteFullText
+	// and rewriteExists replace them.
+	standIn := e.schemaColumns()[0]
+	spec.Where = withExistsPlaceholders(withFullTextPlaceholders(withJSONPathPlaceholders(withQuantifierPlaceholders(withDistinctFromPlaceholders(spec.Where))), standIn), standIn)
+
 	s := e.soy.Select()
 
-	// Add fields if specified
+	// Add fields if specified; aliases are appended by rewriteFieldAliases
 	if len(spec.Fields) > 0 {
 		s = s.Fields(spec.Fields...)
 	}
+	if err := e.validateFieldAliases(spec.Fields, spec.FieldAliases); err != nil {
+		return nil, err
+	}
 
 	// Add select expressions if specified
 	for i := range spec.SelectExprs {
@@ -307,10 +429,24 @@ func (e *Executor[T]) selectFromSpec(spec SelectSpec) (*soy.Select[T], error) {
 	for i := range spec.Where {
 		s = applyConditionToSelect(s, spec.Where[i])
 	}
+	if col := e.softDeleteColumn(opSelect); col != "" && !spec.WithTrashed {
+		s = s.WhereNull(col)
+	}
 
 	// Add ORDER BY clauses
 	for _, orderBy := range spec.OrderBy {
 		switch {
+		case orderBy.IsWeighted():
+			// One placeholder per term, collapsed into the weighted sum by rewriteCustomOrdering.
+			if err := e.validateOrderTerms(orderBy.Terms); err != nil {
+				return nil, err
+			}
+			for _, term := range orderBy.Terms {
+				s = s.OrderBy(term.Field, orderBy.Direction)
+			}
+		case orderBy.IsCase():
+			// Placeholder replaced with a CASE expression by rewriteCustomOrdering.
+			s = s.OrderBy(orderBy.Field, orderBy.Direction)
 		case orderBy.IsExpression():
 			s = s.OrderByExpr(orderBy.Field, orderBy.Operator, orderBy.Param, orderBy.Direction)
 		case orderBy.HasNulls():
@@ -319,17 +455,18 @@ func (e *Executor[T]) selectFromSpec(spec SelectSpec) (*soy.Select[T], error) {
 			s = s.OrderBy(orderBy.Field, orderBy.Direction)
 		}
 	}
+	if pk := e.tieBreakerColumn(spec.OrderBy, spec.GroupBy); pk != "" {
+		s = s.OrderBy(pk, "asc")
+	}
 
 	// Add GROUP BY if specified
 	if len(spec.GroupBy) > 0 {
 		s = s.GroupBy(spec.GroupBy...)
 	}
 
-	// Add HAVING conditions (simple field-based)
-	for i := range spec.Having {
-		if !spec.Having[i].IsGroup() {
-			s = s.Having(spec.Having[i].Field, spec.Having[i].Operator, spec.Having[i].Param)
-		}
+	// Add HAVING conditions; complex ones render as placeholders restored by rewriteHaving
+	for _, h := range withHavingPlaceholders(spec.Having) {
+		s = s.Having(h.Field, h.Operator, h.Param)
 	}
 
 	// Add HAVING aggregate conditions
@@ -389,6 +526,11 @@ func applyConditionToSelect[T any](s *soy.Select[T], cond ConditionSpec) *soy.Se
 		return s.WhereNotBetween(cond.Field, cond.LowParam, cond.HighParam)
 	}
 
+	// IN / NOT IN conditions; soy rejects an empty Param
+	if cond.IsIn() || cond.IsNotIn() {
+		return s.Where(cond.Field, cond.listOperator(), cond.Param)
+	}
+
 	// Field-to-field comparison
 	if cond.IsFieldComparison() {
 		return s.WhereFields(cond.Field, cond.Operator, cond.RightField)
@@ -403,7 +545,7 @@ func applyConditionToSelect[T any](s *soy.Select[T], cond ConditionSpec) *soy.Se
 	}
 
 	// Simple field-operator-param condition
-	return s.Where(cond.Field, cond.Operator, cond.Param)
+	return s.Where(cond.Field, cond.operator(), cond.Param)
 }
 
 // applySelectExprToSelect applies a SelectExprSpec to a Select builder.
@@ -434,6 +576,9 @@ func applySelectExprToSelect[T any](s *soy.Select[T], expr SelectExprSpec) *soy.
 		}
 	case "concat":
 		return s.SelectConcat(expr.Alias, expr.Fields...)
+	case selectExprRowMin, selectExprRowMax:
+		// Rendered as concat until rewriteRowExprs replaces it
+		return s.SelectConcat(expr.Alias, expr.Fields...)
 
 	// Math functions
 	case "abs":
@@ -532,8 +677,55 @@ func applyForLockingToSelect[T any](s *soy.Select[T], forLocking string) (*soy.S
 	}
 }
 
+// checkSoyConditions rejects the WHERE conditions soy cannot render in the statements
+// it builds directly: updates, deletes and ungrouped aggregates. Query and select
+// statements, and grouped aggregates rendered as queries, rewrite these conditions
+// into soy's SQL instead. Match conditions are rejected there only where the dialect
+// needs an ESCAPE clause or lacks ILIKE.
+func (e *Executor[T]) checkSoyConditions(stmt Statement) error {
+	var where []ConditionSpec
+	switch s := stmt.(type) {
+	case UpdateStatement:
+		where = s.spec.Where
+	case DeleteStatement:
+		where = s.spec.Where
+	case AggregateStatement:
+		if len(s.spec.GroupBy) > 0 {
+			return nil
+		}
+		where = s.spec.Where
+	default:
+		return nil
+	}
+	var form string
+	switch {
+	case hasDistinctFrom(where):
+		form = "IS DISTINCT FROM comparisons"
+	case hasQuantified(where):
+		form = "ANY and ALL comparisons"
+	case hasJSONPath(where):
+		form = "JSONB path conditions"
+	case hasFullText(where):
+		form = "full-text conditions"
+	case hasQuantifiedSubquery(where):
+		form = "ANY and ALL subquery comparisons"
+	case hasExists(where):
+		form = "EXISTS conditions"
+	case e.likeEscapes() && hasMatch(e.expandFragments(where)):
+		form = "match conditions on SQLite and SQL Server"
+	default:
+		if err := e.checkMatchILike(collectMatches(e.expandFragments(where), nil)); err != nil {
+			return fmt.Errorf("edamame: statement %q: %w", stmt.Name(), err)
+		}
+		return nil
+	}
+	return fmt.Errorf("edamame: statement %q: %s are only supported in query and select statements", stmt.Name(), form)
+}
+
 // modifyFromSpec builds a soy.Update from an UpdateSpec.
 func (e *Executor[T]) modifyFromSpec(spec UpdateSpec) *soy.Update[T] {
+	spec = e.mapUpdateSpec(spec)
+
 	u := e.soy.Modify()
 
 	// Add SET clauses
@@ -541,10 +733,19 @@ func (e *Executor[T]) modifyFromSpec(spec UpdateSpec) *soy.Update[T] {
 		u = u.Set(field, param)
 	}
 
+	// SET expressions render as placeholder params, replaced by rewriteSetExprs
+	for field := range spec.SetExpr {
+		u = u.Set(field, setExprParam(field))
+	}
+
 	// Add WHERE conditions
 	for i := range spec.Where {
 		u = applyConditionToUpdate(u, spec.Where[i])
 	}
+	// Only alongside a WHERE, so soy's guard against unconditional updates still applies.
+	if col := e.softDeleteColumn(opUpdate); col != "" && len(spec.Where) > 0 {
+		u = u.WhereNull(col)
+	}
 
 	return u
 }
@@ -569,6 +770,11 @@ func applyConditionToUpdate[T any](u *soy.Update[T], cond ConditionSpec) *soy.Up
 		return u.WhereNotBetween(cond.Field, cond.LowParam, cond.HighParam)
 	}
 
+	// IN / NOT IN conditions; soy rejects an empty Param
+	if cond.IsIn() || cond.IsNotIn() {
+		return u.Where(cond.Field, cond.listOperator(), cond.Param)
+	}
+
 	// NULL conditions
 	if cond.IsNull {
 		if cond.Operator == opIsNull {
@@ -578,17 +784,23 @@ func applyConditionToUpdate[T any](u *soy.Update[T], cond ConditionSpec) *soy.Up
 	}
 
 	// Simple field-operator-param condition
-	return u.Where(cond.Field, cond.Operator, cond.Param)
+	return u.Where(cond.Field, cond.operator(), cond.Param)
 }
 
 // removeFromSpec builds a soy.Delete from a DeleteSpec.
 func (e *Executor[T]) removeFromSpec(spec DeleteSpec) *soy.Delete[T] {
+	spec = e.mapDeleteSpec(spec)
+
 	d := e.soy.Remove()
 
 	// Add WHERE conditions
 	for i := range spec.Where {
 		d = applyConditionToDelete(d, spec.Where[i])
 	}
+	// Only alongside a WHERE, so soy's guard against unconditional deletes still applies.
+	if col := e.softDeleteColumn(opDelete); col != "" && len(spec.Where) > 0 {
+		d = d.WhereNull(col)
+	}
 
 	return d
 }
@@ -612,6 +824,11 @@ func applyConditionToDelete[T any](d *soy.Delete[T], cond ConditionSpec) *soy.De
 		return d.WhereNotBetween(cond.Field, cond.LowParam, cond.HighParam)
 	}
 
+	// IN / NOT IN conditions; soy rejects an empty Param
+	if cond.IsIn() || cond.IsNotIn() {
+		return d.Where(cond.Field, cond.listOperator(), cond.Param)
+	}
+
 	// Field-to-field comparison
 	if cond.IsFieldComparison() {
 		return d.WhereFields(cond.Field, cond.Operator, cond.RightField)
@@ -626,11 +843,13 @@ func applyConditionToDelete[T any](d *soy.Delete[T], cond ConditionSpec) *soy.De
 	}
 
 	// Simple field-operator-param condition
-	return d.Where(cond.Field, cond.Operator, cond.Param)
+	return d.Where(cond.Field, cond.operator(), cond.Param)
 }
 
 // countFromSpec builds a soy.Aggregate (COUNT) from an AggregateSpec.
 func (e *Executor[T]) countFromSpec(spec AggregateSpec) *soy.Aggregate[T] {
+	spec = e.mapAggregateSpec(spec)
+
 	agg := e.soy.Count()
 
 	// Add WHERE conditions
@@ -638,11 +857,13 @@ func (e *Executor[T]) countFromSpec(spec AggregateSpec) *soy.Aggregate[T] {
 		agg = applyConditionToAggregate(agg, spec.Where[i])
 	}
 
-	return agg
+	return e.excludeSoftDeletedFromAggregate(agg, spec)
 }
 
 // sumFromSpec builds a soy.Aggregate (SUM) from an AggregateSpec.
 func (e *Executor[T]) sumFromSpec(spec AggregateSpec) *soy.Aggregate[T] {
+	spec = e.mapAggregateSpec(spec)
+
 	agg := e.soy.Sum(spec.Field)
 
 	// Add WHERE conditions
@@ -650,11 +871,13 @@ func (e *Executor[T]) sumFromSpec(spec AggregateSpec) *soy.Aggregate[T] {
 		agg = applyConditionToAggregate(agg, spec.Where[i])
 	}
 
-	return agg
+	return e.excludeSoftDeletedFromAggregate(agg, spec)
 }
 
 // avgFromSpec builds a soy.Aggregate (AVG) from an AggregateSpec.
 func (e *Executor[T]) avgFromSpec(spec AggregateSpec) *soy.Aggregate[T] {
+	spec = e.mapAggregateSpec(spec)
+
 	agg := e.soy.Avg(spec.Field)
 
 	// Add WHERE conditions
@@ -662,11 +885,13 @@ func (e *Executor[T]) avgFromSpec(spec AggregateSpec) *soy.Aggregate[T] {
 		agg = applyConditionToAggregate(agg, spec.Where[i])
 	}
 
-	return agg
+	return e.excludeSoftDeletedFromAggregate(agg, spec)
 }
 
 // minFromSpec builds a soy.Aggregate (MIN) from an AggregateSpec.
 func (e *Executor[T]) minFromSpec(spec AggregateSpec) *soy.Aggregate[T] {
+	spec = e.mapAggregateSpec(spec)
+
 	agg := e.soy.Min(spec.Field)
 
 	// Add WHERE conditions
@@ -674,11 +899,13 @@ func (e *Executor[T]) minFromSpec(spec AggregateSpec) *soy.Aggregate[T] {
 		agg = applyConditionToAggregate(agg, spec.Where[i])
 	}
 
-	return agg
+	return e.excludeSoftDeletedFromAggregate(agg, spec)
 }
 
 // maxFromSpec builds a soy.Aggregate (MAX) from an AggregateSpec.
 func (e *Executor[T]) maxFromSpec(spec AggregateSpec) *soy.Aggregate[T] {
+	spec = e.mapAggregateSpec(spec)
+
 	agg := e.soy.Max(spec.Field)
 
 	// Add WHERE conditions
@@ -686,7 +913,43 @@ func (e *Executor[T]) maxFromSpec(spec AggregateSpec) *soy.Aggregate[T] {
 		agg = applyConditionToAggregate(agg, spec.Where[i])
 	}
 
-	return agg
+	return e.excludeSoftDeletedFromAggregate(agg, spec)
+}
+
+// groupedAggregateFuncs maps aggregate functions to the select expression that computes
+// them per group. Statistical functions render as AVG; renderGroupedAggregate replaces the call.
+var groupedAggregateFuncs = map[AggregateFunc]string{
+	AggCount:       "count_star",
+	AggSum:         "sum",
+	AggAvg:         "avg",
+	AggMin:         "min",
+	AggMax:         "max",
+	AggStdDev:      "avg",
+	AggStdDevPop:   "avg",
+	AggVariance:    "avg",
+	AggVariancePop: "avg",
+}
+
+// groupedAggregateSpec returns the query spec selecting the GroupBy fields and fn over
+// spec.Field, grouped and ordered by the GroupBy fields. soy's Aggregate has no GROUP BY,
+// so the aggregate is selected as an expression of a query.
+func groupedAggregateSpec(fn AggregateFunc, spec AggregateSpec) QuerySpec {
+	expr, ok := groupedAggregateFuncs[fn]
+	if !ok {
+		expr = "count_star"
+	}
+	orderBy := make([]OrderBySpec, len(spec.GroupBy))
+	for i, field := range spec.GroupBy {
+		orderBy[i] = OrderBySpec{Field: field, Direction: "asc"}
+	}
+	return QuerySpec{
+		Fields:      spec.GroupBy,
+		SelectExprs: []SelectExprSpec{{Func: expr, Field: spec.Field, Alias: groupValueAlias}},
+		Where:       spec.Where,
+		OrderBy:     orderBy,
+		GroupBy:     spec.GroupBy,
+		WithTrashed: spec.WithTrashed,
+	}
 }
 
 // applyConditionToAggregate applies a ConditionSpec to an Aggregate builder.
@@ -708,6 +971,11 @@ func applyConditionToAggregate[T any](agg *soy.Aggregate[T], cond ConditionSpec)
 		return agg.WhereNotBetween(cond.Field, cond.LowParam, cond.HighParam)
 	}
 
+	// IN / NOT IN conditions; soy rejects an empty Param
+	if cond.IsIn() || cond.IsNotIn() {
+		return agg.Where(cond.Field, cond.listOperator(), cond.Param)
+	}
+
 	// Field-to-field comparison
 	if cond.IsFieldComparison() {
 		return agg.WhereFields(cond.Field, cond.Operator, cond.RightField)
@@ -722,12 +990,14 @@ func applyConditionToAggregate[T any](agg *soy.Aggregate[T], cond ConditionSpec)
 	}
 
 	// Simple field-operator-param condition
-	return agg.Where(cond.Field, cond.Operator, cond.Param)
+	return agg.Where(cond.Field, cond.operator(), cond.Param)
 }
 
 // insertFromSpec builds a soy.Create from a CreateSpec.
 // Returns an error if an invalid conflict action is specified.
 func (e *Executor[T]) insertFromSpec(spec CreateSpec) (*soy.Create[T], error) {
+	spec = e.mapCreateSpec(spec)
+
 	create := e.soy.Insert()
 
 	// If no conflict handling, return as-is
@@ -757,6 +1027,24 @@ func (e *Executor[T]) insertFromSpec(spec CreateSpec) (*soy.Create[T], error) {
 
 // compoundFromSpec builds a soy.Compound from a CompoundQuerySpec.
 func (e *Executor[T]) compoundFromSpec(spec CompoundQuerySpec) (*soy.Compound[T], error) {
+	if e.hasQueryable() {
+		if err := e.checkQueryable(orderFields(spec.OrderBy)); err != nil {
+			return nil, err
+		}
+	}
+	// Rewrites are applied to rendered SQL, which compound queries bypass
+	if hasCustomOrdering(spec.OrderBy) {
+		return nil, errCustomOrderingUnsupported
+	}
+	if err := e.queryRewrites(spec.Base).unsupported(); err != nil {
+		return nil, err
+	}
+	for _, operand := range spec.Operands {
+		if err := e.queryRewrites(operand.Query).unsupported(); err != nil {
+			return nil, err
+		}
+	}
+
 	// Build base query
 	base, err := e.queryFromSpec(spec.Base)
 	if err != nil {
@@ -821,7 +1109,7 @@ func (e *Executor[T]) compoundFromSpec(spec CompoundQuerySpec) (*soy.Compound[T]
 
 	// Add ORDER BY clauses
 	for _, orderBy := range spec.OrderBy {
-		compound = compound.OrderBy(orderBy.Field, orderBy.Direction)
+		compound = compound.OrderBy(e.column(orderBy.Field), orderBy.Direction)
 	}
 
 	// Add LIMIT if specified
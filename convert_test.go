@@ -4,6 +4,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/zoobzio/astql"
+	"github.com/zoobzio/astql/pkg/mariadb"
 	"github.com/zoobzio/astql/pkg/postgres"
 )
 
@@ -184,6 +186,38 @@ func TestQueryFromSpecWithConditionGroups(t *testing.T) {
 	}
 }
 
+func TestQueryFromSpecWithBetweenInGroup(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	spec := QuerySpec{
+		Where: []ConditionSpec{
+			{
+				Logic: "OR",
+				Group: []ConditionSpec{
+					{Field: "age", Between: true, LowParam: "min_age", HighParam: "max_age"},
+					{Field: "id", NotBetween: true, LowParam: "low_id", HighParam: "high_id"},
+				},
+			},
+		},
+	}
+
+	builder, err := factory.queryFromSpec(spec)
+	if err != nil {
+		t.Fatalf("queryFromSpec() failed: %v", err)
+	}
+	result, err := builder.Render()
+	if err != nil {
+		t.Fatalf("Render() failed: %v", err)
+	}
+
+	if !strings.Contains(result.SQL, `"age" BETWEEN :min_age AND :max_age OR "id" NOT BETWEEN :low_id AND :high_id`) {
+		t.Errorf("expected BETWEEN conditions in group, got: %s", result.SQL)
+	}
+}
+
 func TestSelectFromSpec(t *testing.T) {
 	factory, err := New[User](nil, "users", postgres.New())
 	if err != nil {
@@ -531,6 +565,63 @@ func TestBetweenConditions(t *testing.T) {
 	}
 }
 
+func TestInConditions(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	stmt := NewQueryStatement("by-names", "Users with any of the names", QuerySpec{
+		Where: []ConditionSpec{
+			{Field: "name", In: true, Param: "names"},
+			{Logic: "OR", Group: []ConditionSpec{
+				{Field: "email", NotIn: true, Param: "blocked"},
+				{Field: "age", Operator: "IS NULL", IsNull: true},
+			}},
+		},
+	})
+	sql, err := factory.RenderQuery(stmt)
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+	for _, want := range []string{`"name" = ANY(:names)`, `"email" != ALL(:blocked)`} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("SQL should contain %q: %s", want, sql)
+		}
+	}
+
+	params := stmt.Params()
+	if len(params) != 2 || params[0].Type != "array" || params[1].Type != "array" {
+		t.Errorf("expected array params for names and blocked, got %+v", params)
+	}
+
+	maria, err := New[User](nil, "users", mariadb.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	s, err := maria.selectFromSpec(SelectSpec{Where: []ConditionSpec{{Field: "name", In: true, Param: "names"}}})
+	if err != nil {
+		t.Fatalf("selectFromSpec() failed: %v", err)
+	}
+	result, err := s.Render()
+	if err != nil {
+		t.Fatalf("Render() failed: %v", err)
+	}
+	if !strings.Contains(result.SQL, "`name` IN (:names)") {
+		t.Errorf("unexpected MariaDB SQL: %s", result.SQL)
+	}
+
+	noParam := NewQueryStatement("no-param", "IN without a param", QuerySpec{
+		Where: []ConditionSpec{{Field: "name", In: true}},
+	})
+	if _, err := factory.RenderQuery(noParam); err == nil {
+		t.Error("expected error for IN condition without a param")
+	}
+	if _, err := factory.removeFromSpec(DeleteSpec{Where: []ConditionSpec{{Field: "name", NotIn: true}}}).Render(); err == nil {
+		t.Error("expected error for NOT IN delete condition without a param")
+	}
+}
+
 func TestFieldToFieldComparison(t *testing.T) {
 	factory, err := New[User](nil, "users", postgres.New())
 	if err != nil {
@@ -997,6 +1088,24 @@ func TestSelectConditionVariants(t *testing.T) {
 			},
 			contains: "NOT BETWEEN",
 		},
+		{
+			name: "in",
+			spec: SelectSpec{
+				Where: []ConditionSpec{
+					{Field: "name", In: true, Param: "names"},
+				},
+			},
+			contains: `"name" = ANY(:names)`,
+		},
+		{
+			name: "not in",
+			spec: SelectSpec{
+				Where: []ConditionSpec{
+					{Field: "name", NotIn: true, Param: "names"},
+				},
+			},
+			contains: `"name" != ALL(:names)`,
+		},
 		{
 			name: "is null",
 			spec: SelectSpec{
@@ -1074,6 +1183,26 @@ func TestUpdateConditionVariants(t *testing.T) {
 			},
 			contains: "NOT BETWEEN",
 		},
+		{
+			name: "in",
+			spec: UpdateSpec{
+				Set: map[string]string{"age": "new_age"},
+				Where: []ConditionSpec{
+					{Field: "name", In: true, Param: "names"},
+				},
+			},
+			contains: `"name" = ANY(:names)`,
+		},
+		{
+			name: "not in",
+			spec: UpdateSpec{
+				Set: map[string]string{"age": "new_age"},
+				Where: []ConditionSpec{
+					{Field: "name", NotIn: true, Param: "names"},
+				},
+			},
+			contains: `"name" != ALL(:names)`,
+		},
 		{
 			name: "is null",
 			spec: UpdateSpec{
@@ -1140,6 +1269,24 @@ func TestDeleteConditionVariants(t *testing.T) {
 			},
 			contains: "NOT BETWEEN",
 		},
+		{
+			name: "in",
+			spec: DeleteSpec{
+				Where: []ConditionSpec{
+					{Field: "name", In: true, Param: "names"},
+				},
+			},
+			contains: `"name" = ANY(:names)`,
+		},
+		{
+			name: "not in",
+			spec: DeleteSpec{
+				Where: []ConditionSpec{
+					{Field: "name", NotIn: true, Param: "names"},
+				},
+			},
+			contains: `"name" != ALL(:names)`,
+		},
 		{
 			name: "is null",
 			spec: DeleteSpec{
@@ -1214,6 +1361,24 @@ func TestAggregateConditionVariants(t *testing.T) {
 			},
 			contains: "NOT BETWEEN",
 		},
+		{
+			name: "in",
+			spec: AggregateSpec{
+				Where: []ConditionSpec{
+					{Field: "name", In: true, Param: "names"},
+				},
+			},
+			contains: `"name" = ANY(:names)`,
+		},
+		{
+			name: "not in",
+			spec: AggregateSpec{
+				Where: []ConditionSpec{
+					{Field: "name", NotIn: true, Param: "names"},
+				},
+			},
+			contains: `"name" != ALL(:names)`,
+		},
 		{
 			name: "is null",
 			spec: AggregateSpec{
@@ -1386,3 +1551,54 @@ func TestConditionSpecHelpers(t *testing.T) {
 		})
 	}
 }
+
+// Purchase is a test model whose columns are SQL reserved words.
+type Purchase struct {
+	ID    int    `db:"id" type:"integer" constraints:"primarykey"`
+	Order int    `db:"order" type:"integer"`
+	User  string `db:"user" type:"text"`
+}
+
+func TestReservedWordColumnsAreQuoted(t *testing.T) {
+	tests := []struct {
+		name     string
+		renderer astql.Renderer
+		want     []string
+	}{
+		{
+			name:     "postgres",
+			renderer: postgres.New(),
+			want:     []string{`SELECT "order", "user"`, `WHERE "order" > :min_order`, `ORDER BY "user" ASC`},
+		},
+		{
+			name:     "mariadb",
+			renderer: mariadb.New(),
+			want:     []string{"SELECT `order`, `user`", "WHERE `order` > :min_order", "ORDER BY `user` ASC"},
+		},
+	}
+
+	stmt := NewQueryStatement("by-order", "Purchases by order", QuerySpec{
+		Fields:  []string{"order", "user"},
+		Where:   []ConditionSpec{{Field: "order", Operator: ">", Param: "min_order"}},
+		OrderBy: []OrderBySpec{{Field: "user", Direction: "asc"}},
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory, err := New[Purchase](nil, "purchases", tt.renderer)
+			if err != nil {
+				t.Fatalf("New() failed: %v", err)
+			}
+
+			sql, err := factory.RenderQuery(stmt)
+			if err != nil {
+				t.Fatalf("RenderQuery() failed: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(sql, want) {
+					t.Errorf("expected %q in SQL: %s", want, sql)
+				}
+			}
+		})
+	}
+}
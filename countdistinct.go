@@ -0,0 +1,94 @@
+package edamame
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// countDistinctAlias names the COUNT(DISTINCT ...) column ExecQueryCountDistinct selects.
+const countDistinctAlias = "edamame_count_distinct"
+
+// ExecQueryCountDistinct counts the distinct non-NULL values of field among the rows
+// a query statement matches, as SELECT COUNT(DISTINCT field). Only the statement's
+// WHERE applies; its fields, grouping, ordering, limits and locking are dropped.
+// Statements with OuterWhere are rejected, since it filters on the dropped fields.
+//
+// Example:
+//
+//	ages, err := exec.ExecQueryCountDistinct(ctx, ActiveUsers, "age", nil)
+func (e *Executor[T]) ExecQueryCountDistinct(ctx context.Context, stmt QueryStatement, field string, params map[string]any) (int64, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	return e.execQueryCountDistinct(withRead(ctx), e.execer(), stmt, field, params)
+}
+
+// ExecQueryCountDistinctTx counts the distinct values of field among a query statement's
+// rows within a transaction.
+func (e *Executor[T]) ExecQueryCountDistinctTx(ctx context.Context, tx *sqlx.Tx, stmt QueryStatement, field string, params map[string]any) (int64, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	return e.execQueryCountDistinct(ctx, e.execerFor(tx), stmt, field, params)
+}
+
+// execQueryCountDistinct renders the COUNT(DISTINCT ...) form of stmt and scans its result.
+func (e *Executor[T]) execQueryCountDistinct(ctx context.Context, execer sqlx.ExtContext, stmt QueryStatement, field string, params map[string]any) (int64, error) {
+	if len(stmt.spec.OuterWhere) > 0 {
+		return 0, errOuterWhereUnsupported
+	}
+	if err := e.checkKnownFields([]string{field}); err != nil {
+		return 0, fmt.Errorf("edamame: cannot count distinct values: %w", err)
+	}
+	if err := e.checkStatementFields(stmt); err != nil {
+		return 0, err
+	}
+	params, err := e.prepareParams(stmt, params)
+	if err != nil {
+		return 0, err
+	}
+	sql, binds, err := e.renderCountDistinct(stmt.spec, field)
+	if err != nil {
+		return 0, err
+	}
+	params = mergeParams(params, binds)
+	ctx = withStatement(ctx, stmt.name, "query")
+	e.emitSQL(ctx, stmt.name, "query", sql, params)
+
+	rows, err := sqlx.NamedQueryContext(ctx, execer, sql, params)
+	if err != nil {
+		return 0, fmt.Errorf("edamame: COUNT(DISTINCT) query failed: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return 0, fmt.Errorf("edamame: COUNT(DISTINCT) query failed: %w", err)
+		}
+		return 0, fmt.Errorf("edamame: COUNT(DISTINCT) query returned no rows")
+	}
+	var count int64
+	if err := rows.Scan(&count); err != nil {
+		return 0, fmt.Errorf("edamame: failed to scan COUNT(DISTINCT) result: %w", err)
+	}
+	return count, nil
+}
+
+// renderCountDistinct renders spec's WHERE as a query selecting COUNT(DISTINCT field).
+// Returns the SQL and any extra params its rewrites bind.
+func (e *Executor[T]) renderCountDistinct(spec QuerySpec, field string) (string, map[string]any, error) {
+	spec = QuerySpec{
+		SelectExprs: []SelectExprSpec{{Func: "count_distinct", Field: field, Alias: countDistinctAlias}},
+		Where:       spec.Where,
+		WithTrashed: spec.WithTrashed,
+	}
+	q, err := e.queryFromSpec(spec)
+	if err != nil {
+		return "", nil, err
+	}
+	result, err := q.Render()
+	if err != nil {
+		return "", nil, fmt.Errorf("edamame: failed to render COUNT(DISTINCT) query: %w", err)
+	}
+	return e.finalizeSQL(result.SQL, e.queryRewrites(spec))
+}
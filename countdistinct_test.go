@@ -0,0 +1,67 @@
+package edamame
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestRenderCountDistinct(t *testing.T) {
+	exec, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	limit := 10
+
+	spec := QuerySpec{
+		Fields:  []string{"id", "email"},
+		Where:   []ConditionSpec{{Field: "name", Operator: "IS DISTINCT FROM", Param: "name"}},
+		OrderBy: []OrderBySpec{{Field: "name", Direction: "asc"}},
+		Limit:   &limit,
+	}
+	sql, _, err := exec.renderCountDistinct(spec, "age")
+	if err != nil {
+		t.Fatalf("renderCountDistinct() failed: %v", err)
+	}
+	want := `SELECT COUNT(DISTINCT "age") AS "edamame_count_distinct" FROM "users" WHERE "name" IS DISTINCT FROM :name`
+	if sql != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, sql)
+	}
+}
+
+func TestExecQueryCountDistinct(t *testing.T) {
+	db := &recordingDB{}
+	exec, err := New[User](db, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ctx := context.Background()
+
+	adults := NewQueryStatement("adults", "", QuerySpec{Where: []ConditionSpec{{Field: "age", Operator: ">=", Param: "min_age"}}})
+	if _, err := exec.ExecQueryCountDistinct(ctx, adults, "name", map[string]any{"min_age": 18}); !errors.Is(err, errRecorded) {
+		t.Fatalf("expected the recorded error, got %v", err)
+	}
+	if want := `SELECT COUNT(DISTINCT "name") AS "edamame_count_distinct" FROM "users" WHERE "age" >= $1`; db.count() != 1 || db.queries[0] != want {
+		t.Errorf("expected %s, got %v", want, db.queries)
+	}
+
+	if _, err := exec.ExecQueryCountDistinct(ctx, adults, "nmae", map[string]any{"min_age": 18}); err == nil || errors.Is(err, errRecorded) {
+		t.Errorf("expected an unknown field error, got %v", err)
+	}
+	if _, err := exec.ExecQueryCountDistinct(ctx, adults, "name", nil); err == nil || errors.Is(err, errRecorded) {
+		t.Errorf("expected a missing param error, got %v", err)
+	}
+
+	outer := NewQueryStatement("outer", "", QuerySpec{
+		SelectExprs: []SelectExprSpec{{Func: "upper", Field: "name", Alias: "upper_name"}},
+		OuterWhere:  []ConditionSpec{{Field: "upper_name", Operator: "=", Param: "name"}},
+	})
+	if _, err := exec.ExecQueryCountDistinct(ctx, outer, "name", map[string]any{"name": "A"}); !errors.Is(err, errOuterWhereUnsupported) {
+		t.Errorf("expected errOuterWhereUnsupported, got %v", err)
+	}
+	if db.count() != 1 {
+		t.Errorf("expected rejected calls not to reach the database, got %v", db.queries)
+	}
+}
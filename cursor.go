@@ -0,0 +1,246 @@
+package edamame
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// cursorSeq numbers server-side cursors so concurrent cursors get distinct names.
+var cursorSeq atomic.Uint64
+
+// chanBatchSize is the cursor batch size used by ExecQueryChan.
+const chanBatchSize = 100
+
+// Cursor iterates query results fetched in batches from a PostgreSQL server-side cursor.
+// The cursor owns a transaction on the executor's database; always call Close.
+//
+// Example:
+//
+//	cur, err := exec.ExecQueryCursor(ctx, AllUsers, nil, 500)
+//	if err != nil {
+//	    return err
+//	}
+//	defer cur.Close()
+//	for cur.Next() {
+//	    process(cur.Record())
+//	}
+//	return cur.Err()
+type Cursor[T any] struct {
+	ctx       context.Context
+	tx        *sqlx.Tx
+	execer    sqlx.ExtContext // tx, annotated with the executor's SQL comments
+	name      string
+	batchSize int
+
+	batch   []*T
+	pos     int
+	current *T
+	done    bool
+	closed  bool
+	err     error
+}
+
+// ExecQueryCursor executes a query statement through a PostgreSQL server-side cursor,
+// fetching batchSize rows at a time so only one batch is held in memory.
+// It begins a transaction on the executor's database that is released by Cursor.Close.
+func (e *Executor[T]) ExecQueryCursor(ctx context.Context, stmt QueryStatement, params map[string]any, batchSize int) (*Cursor[T], error) {
+	if !e.isPostgres() {
+		return nil, fmt.Errorf("edamame: ExecQueryCursor requires the postgres renderer")
+	}
+	if batchSize <= 0 {
+		return nil, fmt.Errorf("edamame: cursor batch size must be positive, got %d", batchSize)
+	}
+	params, err := e.prepareParams(stmt, params)
+	if err != nil {
+		return nil, err
+	}
+	ctx = withStatement(ctx, stmt.name, "query")
+
+	db, ok := e.db.(txBeginner)
+	if !ok {
+		return nil, fmt.Errorf("edamame: database handle does not support transactions")
+	}
+
+	q, err := e.Query(stmt)
+	if err != nil {
+		return nil, err
+	}
+	result, err := q.Render()
+	if err != nil {
+		return nil, fmt.Errorf("edamame: failed to render query: %w", err)
+	}
+	// Index hints are not applied: the statement is wrapped in DECLARE, so a hint would not lead it.
+	rewrites := e.queryRewrites(stmt.spec)
+	rewrites.hint = ""
+	sql, binds, err := e.finalizeSQL(result.SQL, rewrites)
+	if err != nil {
+		return nil, err
+	}
+	params = mergeParams(params, binds)
+	e.emitSQL(ctx, stmt.name, "query", sql, params)
+
+	query, args, err := sqlx.Named(sql, params)
+	if err != nil {
+		return nil, fmt.Errorf("edamame: failed to bind params: %w", err)
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("edamame: failed to begin transaction: %w", err)
+	}
+
+	name := fmt.Sprintf("edamame_cursor_%d", cursorSeq.Add(1))
+	declare := fmt.Sprintf("DECLARE %s NO SCROLL CURSOR FOR %s", name, query) //nolint:gosec // query is rendered by astql with bound params; name is generated
+
+	execer := e.execerFor(tx)
+	if _, err := execer.ExecContext(ctx, tx.Rebind(declare), args...); err != nil {
+		_ = tx.Rollback()
+		return nil, fmt.Errorf("edamame: failed to declare cursor: %w", err)
+	}
+
+	return &Cursor[T]{
+		ctx:       ctx,
+		tx:        tx,
+		execer:    execer,
+		name:      name,
+		batchSize: batchSize,
+	}, nil
+}
+
+// Next advances to the next record, fetching a new batch when the current one is exhausted.
+// It returns false when the results are exhausted or an error occurs; check Err afterwards.
+func (c *Cursor[T]) Next() bool {
+	if c.closed || c.err != nil {
+		return false
+	}
+	if c.pos >= len(c.batch) {
+		if c.done || !c.fetch() {
+			c.current = nil
+			return false
+		}
+	}
+	c.current = c.batch[c.pos]
+	c.pos++
+	return true
+}
+
+// fetch loads the next batch, returning false when no rows remain or on error.
+func (c *Cursor[T]) fetch() bool {
+	rows, err := c.execer.QueryxContext(c.ctx, fmt.Sprintf("FETCH FORWARD %d FROM %s", c.batchSize, c.name)) //nolint:gosec // cursor name is generated
+	if err != nil {
+		c.err = fmt.Errorf("edamame: cursor fetch failed: %w", err)
+		return false
+	}
+	defer rows.Close()
+
+	c.batch = c.batch[:0]
+	c.pos = 0
+	for rows.Next() {
+		var record T
+		if err := rows.StructScan(&record); err != nil {
+			c.err = fmt.Errorf("edamame: failed to scan cursor row: %w", err)
+			return false
+		}
+		c.batch = append(c.batch, &record)
+	}
+	if err := rows.Err(); err != nil {
+		c.err = fmt.Errorf("edamame: cursor fetch failed: %w", err)
+		return false
+	}
+
+	if len(c.batch) < c.batchSize {
+		c.done = true
+	}
+	return len(c.batch) > 0
+}
+
+// Record returns the record at the current position.
+func (c *Cursor[T]) Record() *T {
+	return c.current
+}
+
+// Err returns the first error encountered while iterating.
+func (c *Cursor[T]) Err() error {
+	return c.err
+}
+
+// Close closes the server-side cursor and ends its transaction. It is safe to call more than once.
+func (c *Cursor[T]) Close() error {
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+
+	// Closing the cursor is best-effort: rolling back the transaction releases it regardless,
+	// and the transaction may already be aborted by an earlier error.
+	_, _ = c.execer.ExecContext(context.WithoutCancel(c.ctx), "CLOSE "+c.name)
+	if err := c.tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+		return fmt.Errorf("edamame: failed to end cursor transaction: %w", err)
+	}
+	return nil
+}
+
+// ExecQueryChan executes a query statement through a server-side cursor and sends each
+// record on the returned record channel. The channel is unbuffered, so rows are only
+// fetched as fast as the receiver consumes them.
+//
+// Both channels are closed when the results are exhausted, an error occurs, or ctx is
+// cancelled. The error channel receives at most one error; drain the record channel
+// before reading it. A receiver that stops early must cancel ctx: until then the
+// goroutine stays blocked on the send, holding the cursor's transaction and connection.
+//
+// Example:
+//
+//	ctx, cancel := context.WithCancel(ctx)
+//	defer cancel() // releases the cursor if the loop returns early
+//	records, errs := exec.ExecQueryChan(ctx, AllUsers, nil)
+//	for user := range records {
+//	    process(user)
+//	}
+//	if err := <-errs; err != nil {
+//	    return err
+//	}
+func (e *Executor[T]) ExecQueryChan(ctx context.Context, stmt QueryStatement, params map[string]any) (<-chan *T, <-chan error) {
+	records := make(chan *T)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(records)
+
+		cur, err := e.ExecQueryCursor(ctx, stmt, params, chanBatchSize)
+		if err != nil {
+			errs <- err
+			return
+		}
+
+		for cur.Next() {
+			select {
+			case records <- cur.Record():
+			case <-ctx.Done():
+				_ = cur.Close()
+				errs <- ctx.Err()
+				return
+			}
+		}
+		if err := cur.Err(); err != nil {
+			_ = cur.Close()
+			// A fetch interrupted by cancellation surfaces as a driver error; report the cause.
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			}
+			errs <- err
+			return
+		}
+		if err := cur.Close(); err != nil {
+			errs <- err
+		}
+	}()
+
+	return records, errs
+}
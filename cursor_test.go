@@ -0,0 +1,61 @@
+package edamame
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/mariadb"
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestExecQueryCursor_InvalidBatchSize(t *testing.T) {
+	factory, err := New[User](&recordingDB{}, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	if _, err := factory.ExecQueryCursor(context.Background(), queryAll, nil, 0); err == nil {
+		t.Error("ExecQueryCursor() should fail for a zero batch size")
+	}
+}
+
+func TestExecQueryCursor_NoTransactions(t *testing.T) {
+	factory, err := New[User](&recordingDB{}, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	if _, err := factory.ExecQueryCursor(context.Background(), queryAll, nil, 10); err == nil {
+		t.Error("ExecQueryCursor() should fail when the database cannot begin transactions")
+	}
+}
+
+func TestExecQueryCursor_RequiresPostgres(t *testing.T) {
+	factory, err := New[User](&recordingDB{}, "users", mariadb.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	if _, err := factory.ExecQueryCursor(context.Background(), queryAll, nil, 10); err == nil || !strings.Contains(err.Error(), "requires the postgres renderer") {
+		t.Errorf("expected a postgres renderer error, got %v", err)
+	}
+}
+
+func TestExecQueryChan_Error(t *testing.T) {
+	factory, err := New[User](&recordingDB{}, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	records, errs := factory.ExecQueryChan(context.Background(), queryAll, nil)
+	for range records {
+		t.Error("expected no records")
+	}
+	if err := <-errs; err == nil {
+		t.Error("expected an error when the database cannot begin transactions")
+	}
+	if _, ok := <-errs; ok {
+		t.Error("error channel should be closed")
+	}
+}
@@ -0,0 +1,189 @@
+package edamame
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// diffAlias names the VALUES list joined by ExecDiff, and diffRowColumn its column
+// holding each incoming record's index.
+const (
+	diffAlias     = "edamame_v"
+	diffRowColumn = "edamame_row"
+)
+
+// Diff states rendered by renderDiff.
+const (
+	diffInsert    = "insert"
+	diffUpdate    = "update"
+	diffUnchanged = "unchanged"
+)
+
+// ExecDiff classifies incoming records against the stored rows with the same values in
+// keyFields, for sync jobs deciding what to write:
+//
+//	SELECT v.edamame_row, CASE ... END FROM (VALUES (...), ...) AS v(...)
+//	LEFT JOIN t ON t.key = v.key
+//
+// A record with no stored row is returned in toInsert, one whose stored row differs in
+// any other column in toUpdate, and the rest in unchanged, each in incoming order.
+// Columns compare with IS DISTINCT FROM, so NULLs compare equal. The primary key is
+// not compared unless it is a key field, as incoming records usually do not carry it.
+// If several stored rows share a key, a record differing from any of them is an update.
+//
+// Values are bound as params and cast with each column's type tag, so every column must
+// declare one. The query runs on the primary database, never a read replica, so the
+// classification is current. PostgreSQL only.
+//
+// Example:
+//
+//	toInsert, toUpdate, _, err := exec.ExecDiff(ctx, incoming, []string{"email"})
+func (e *Executor[T]) ExecDiff(ctx context.Context, incoming []*T, keyFields []string) (toInsert, toUpdate, unchanged []*T, err error) {
+	ctx, cancel := e.withTimeout(ctx, "")
+	defer cancel()
+	return e.execDiff(ctx, e.execer(), incoming, keyFields)
+}
+
+// ExecDiffTx runs ExecDiff within a transaction.
+func (e *Executor[T]) ExecDiffTx(ctx context.Context, tx *sqlx.Tx, incoming []*T, keyFields []string) (toInsert, toUpdate, unchanged []*T, err error) {
+	ctx, cancel := e.withTimeout(ctx, "")
+	defer cancel()
+	return e.execDiff(ctx, e.execerFor(tx), incoming, keyFields)
+}
+
+// execDiff renders and runs the diff query, then splits incoming by each record's state.
+func (e *Executor[T]) execDiff(ctx context.Context, execer sqlx.ExtContext, incoming []*T, keyFields []string) (toInsert, toUpdate, unchanged []*T, err error) {
+	if len(incoming) == 0 {
+		return []*T{}, []*T{}, []*T{}, nil
+	}
+	sql, params, err := e.renderDiff(incoming, keyFields)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	ctx = withStatement(ctx, "", "query")
+	e.emitSQL(ctx, "", "query", sql, params)
+
+	rows, err := sqlx.NamedQueryContext(ctx, execer, sql, params)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("edamame: diff query failed: %w", err)
+	}
+	defer rows.Close()
+
+	states := make([]string, len(incoming))
+	for rows.Next() {
+		var row int
+		var state string
+		if err := rows.Scan(&row, &state); err != nil {
+			return nil, nil, nil, fmt.Errorf("edamame: failed to scan diff result: %w", err)
+		}
+		if row < 0 || row >= len(incoming) {
+			return nil, nil, nil, fmt.Errorf("edamame: diff returned unknown row %d", row)
+		}
+		if states[row] != diffUpdate {
+			states[row] = state
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, nil, fmt.Errorf("edamame: diff query failed: %w", err)
+	}
+
+	toInsert, toUpdate, unchanged = []*T{}, []*T{}, []*T{}
+	for i, record := range incoming {
+		switch states[i] {
+		case diffInsert:
+			toInsert = append(toInsert, record)
+		case diffUpdate:
+			toUpdate = append(toUpdate, record)
+		case diffUnchanged:
+			unchanged = append(unchanged, record)
+		default:
+			return nil, nil, nil, fmt.Errorf("edamame: diff returned no result for record %d", i)
+		}
+	}
+	return toInsert, toUpdate, unchanged, nil
+}
+
+// renderDiff renders the diff query for incoming and the params binding each value.
+// soy and astql have no VALUES lists, so it is formatted directly; identifiers and type
+// names come from validated struct tags.
+func (e *Executor[T]) renderDiff(incoming []*T, keyFields []string) (string, map[string]any, error) {
+	if !e.isPostgres() {
+		return "", nil, fmt.Errorf("edamame: ExecDiff requires the postgres renderer")
+	}
+	if len(keyFields) == 0 {
+		return "", nil, fmt.Errorf("edamame: ExecDiff requires at least one key field")
+	}
+
+	keys := make([]string, len(keyFields))
+	for i, field := range keyFields {
+		keys[i] = e.column(field)
+		if _, ok := e.columns[keys[i]]; !ok {
+			return "", nil, fmt.Errorf("edamame: unknown key field %q", field)
+		}
+	}
+	var compared []string
+	for _, col := range e.schemaColumns() {
+		if !slices.Contains(keys, col) && col != e.pk {
+			compared = append(compared, col)
+		}
+	}
+
+	allCols := append(slices.Clone(keys), compared...)
+	types := make([]string, len(allCols))
+	for j, col := range allCols {
+		typ, err := e.columnType(col)
+		if err != nil {
+			return "", nil, err
+		}
+		types[j] = typ
+	}
+
+	params := make(map[string]any, len(incoming)*len(allCols))
+	rows := make([]string, len(incoming))
+	for i, record := range incoming {
+		if record == nil {
+			return "", nil, fmt.Errorf("edamame: diff record %d is nil", i)
+		}
+		v := reflect.ValueOf(record).Elem()
+		values := make([]string, len(allCols)+1)
+		values[0] = fmt.Sprintf("%d", i)
+		for j, col := range allCols {
+			param := fmt.Sprintf("edamame_diff_%d_%d", i, j)
+			values[j+1] = fmt.Sprintf("CAST(:%s AS %s)", param, types[j])
+			params[param] = v.FieldByIndex(e.columns[col]).Interface()
+		}
+		rows[i] = "(" + strings.Join(values, ", ") + ")"
+	}
+
+	table := e.quotedTableName()
+	quoted := make([]string, len(allCols))
+	for j, col := range allCols {
+		quoted[j] = `"` + col + `"`
+	}
+	joins := make([]string, len(keys))
+	for j, col := range keys {
+		joins[j] = fmt.Sprintf(`%s."%s" = %s."%s"`, table, col, diffAlias, col)
+	}
+	state := fmt.Sprintf(`CASE WHEN %s."%s" IS NULL THEN '%s' ELSE '%s' END`, table, keys[0], diffInsert, diffUnchanged)
+	if len(compared) > 0 {
+		stored := make([]string, len(compared))
+		given := make([]string, len(compared))
+		for j, col := range compared {
+			stored[j] = fmt.Sprintf(`%s."%s"`, table, col)
+			given[j] = fmt.Sprintf(`%s."%s"`, diffAlias, col)
+		}
+		state = fmt.Sprintf(`CASE WHEN %s."%s" IS NULL THEN '%s' WHEN ROW(%s) IS DISTINCT FROM ROW(%s) THEN '%s' ELSE '%s' END`,
+			table, keys[0], diffInsert, strings.Join(stored, ", "), strings.Join(given, ", "), diffUpdate, diffUnchanged)
+	}
+
+	//nolint:gosec // type and identifiers come from validated struct tags
+	sql := fmt.Sprintf(`SELECT %s."%s", %s FROM (VALUES %s) AS %s("%s", %s) LEFT JOIN %s ON %s`,
+		diffAlias, diffRowColumn, state, strings.Join(rows, ", "),
+		diffAlias, diffRowColumn, strings.Join(quoted, ", "), table, strings.Join(joins, " AND "))
+	return sql, params, nil
+}
@@ -0,0 +1,58 @@
+package edamame
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/mariadb"
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestRenderDiff(t *testing.T) {
+	exec, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	age := 30
+	sql, params, err := exec.renderDiff([]*User{{Email: "a@example.com", Name: "Alice", Age: &age}}, []string{"email"})
+	if err != nil {
+		t.Fatalf("renderDiff() failed: %v", err)
+	}
+	want := `SELECT edamame_v."edamame_row", CASE WHEN "users"."email" IS NULL THEN 'insert' ` +
+		`WHEN ROW("users"."name", "users"."age") IS DISTINCT FROM ROW(edamame_v."name", edamame_v."age") THEN 'update' ELSE 'unchanged' END ` +
+		`FROM (VALUES (0, CAST(:edamame_diff_0_0 AS text), CAST(:edamame_diff_0_1 AS text), CAST(:edamame_diff_0_2 AS integer))) ` +
+		`AS edamame_v("edamame_row", "email", "name", "age") LEFT JOIN "users" ON "users"."email" = edamame_v."email"`
+	if sql != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, sql)
+	}
+	if params["edamame_diff_0_0"] != "a@example.com" || params["edamame_diff_0_1"] != "Alice" {
+		t.Errorf("unexpected params: %v", params)
+	}
+	if p, ok := params["edamame_diff_0_2"].(*int); !ok || *p != 30 {
+		t.Errorf("expected the age pointer to be bound, got %#v", params["edamame_diff_0_2"])
+	}
+}
+
+func TestRenderDiff_Errors(t *testing.T) {
+	exec, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if _, _, err := exec.renderDiff([]*User{{}}, nil); err == nil {
+		t.Error("expected an error without key fields")
+	}
+	if _, _, err := exec.renderDiff([]*User{{}}, []string{"nickname"}); err == nil {
+		t.Error("expected an error for an unknown key field")
+	}
+	if _, _, err := exec.renderDiff([]*User{nil}, []string{"email"}); err == nil {
+		t.Error("expected an error for a nil record")
+	}
+
+	maria, err := New[User](nil, "users", mariadb.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if _, _, _, err := maria.ExecDiff(context.Background(), []*User{{}}, []string{"email"}); err == nil {
+		t.Error("expected ExecDiff to require postgres")
+	}
+}
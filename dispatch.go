@@ -2,12 +2,21 @@ package edamame
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/astql"
 	"github.com/zoobzio/atom"
+	"github.com/zoobzio/capitan"
 	"github.com/zoobzio/soy"
 )
 
+// ErrNotFound is returned by select execution when no row matches the statement.
+var ErrNotFound = errors.New("edamame: no rows found")
+
 // Query returns a soy Query builder for the given statement.
 func (e *Executor[T]) Query(stmt QueryStatement) (*soy.Query[T], error) {
 	return e.queryFromSpec(stmt.spec)
@@ -29,11 +38,13 @@ func (e *Executor[T]) Delete(stmt DeleteStatement) *soy.Delete[T] {
 }
 
 // Aggregate returns a soy Aggregate builder for the given statement.
+// soy has no standard deviation or variance aggregate, so for those functions it
+// returns the AVG builder their SQL is derived from; run them with ExecAggregate.
 func (e *Executor[T]) Aggregate(stmt AggregateStatement) *soy.Aggregate[T] {
 	switch stmt.fn {
 	case AggSum:
 		return e.sumFromSpec(stmt.spec)
-	case AggAvg:
+	case AggAvg, AggStdDev, AggStdDevPop, AggVariance, AggVariancePop:
 		return e.avgFromSpec(stmt.spec)
 	case AggMin:
 		return e.minFromSpec(stmt.spec)
@@ -46,7 +57,8 @@ func (e *Executor[T]) Aggregate(stmt AggregateStatement) *soy.Aggregate[T] {
 
 // Insert returns a soy Create builder for inserting records.
 // This uses the underlying soy.Insert() directly since inserts
-// are driven by struct fields rather than specs.
+// are driven by struct fields rather than specs. soy's builder writes every
+// non-primary-key column, including generated ones; ExecInsert leaves those out.
 func (e *Executor[T]) Insert() *soy.Create[T] {
 	return e.soy.Insert()
 }
@@ -59,163 +71,516 @@ func (e *Executor[T]) Compound(spec CompoundQuerySpec) (*soy.Compound[T], error)
 
 // ExecQuery executes a query statement directly.
 func (e *Executor[T]) ExecQuery(ctx context.Context, stmt QueryStatement, params map[string]any) ([]*T, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
 	q, err := e.Query(stmt)
 	if err != nil {
 		return nil, err
 	}
-	return q.Exec(ctx, params)
+	records, err := e.runQuery(withRead(ctx), nil, stmt, q, params)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.assertResults(records...); err != nil {
+		return nil, err
+	}
+	return capResults(ctx, e, stmt, e.dedupResults(records)), nil
 }
 
 // ExecQueryTx executes a query statement within a transaction.
 func (e *Executor[T]) ExecQueryTx(ctx context.Context, tx *sqlx.Tx, stmt QueryStatement, params map[string]any) ([]*T, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
 	q, err := e.Query(stmt)
 	if err != nil {
 		return nil, err
 	}
-	return q.ExecTx(ctx, tx, params)
+	records, err := e.runQuery(ctx, tx, stmt, q, params)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.assertResults(records...); err != nil {
+		return nil, err
+	}
+	return capResults(ctx, e, stmt, e.dedupResults(records)), nil
 }
 
 // ExecSelect executes a select statement directly.
 func (e *Executor[T]) ExecSelect(ctx context.Context, stmt SelectStatement, params map[string]any) (*T, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
 	s, err := e.Select(stmt)
 	if err != nil {
 		return nil, err
 	}
-	return s.Exec(ctx, params)
+	record, err := e.runSelect(withRead(ctx), nil, stmt, s, params)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.assertResults(record); err != nil {
+		return nil, err
+	}
+	return record, nil
 }
 
 // ExecSelectTx executes a select statement within a transaction.
 func (e *Executor[T]) ExecSelectTx(ctx context.Context, tx *sqlx.Tx, stmt SelectStatement, params map[string]any) (*T, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
 	s, err := e.Select(stmt)
 	if err != nil {
 		return nil, err
 	}
-	return s.ExecTx(ctx, tx, params)
+	record, err := e.runSelect(ctx, tx, stmt, s, params)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.assertResults(record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// ExecSelectOrDefault executes a select statement and returns fallback when no row
+// matches. Any other error is returned as is.
+//
+// Example:
+//
+//	setting, err := settings.ExecSelectOrDefault(ctx, SettingByKey, map[string]any{"key": "theme"}, &Setting{Value: "light"})
+func (e *Executor[T]) ExecSelectOrDefault(ctx context.Context, stmt SelectStatement, params map[string]any, fallback *T) (*T, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	record, err := e.ExecSelect(ctx, stmt, params)
+	if errors.Is(err, ErrNotFound) {
+		return fallback, nil
+	}
+	return record, err
 }
 
-// ExecUpdate executes an update statement directly.
+// ExecSelectOrDefaultTx executes a select statement within a transaction and returns
+// fallback when no row matches.
+func (e *Executor[T]) ExecSelectOrDefaultTx(ctx context.Context, tx *sqlx.Tx, stmt SelectStatement, params map[string]any, fallback *T) (*T, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	record, err := e.ExecSelectTx(ctx, tx, stmt, params)
+	if errors.Is(err, ErrNotFound) {
+		return fallback, nil
+	}
+	return record, err
+}
+
+// ExecUpdate executes an update statement directly. When the spec names Returning
+// columns, only those columns of the returned record are populated.
 func (e *Executor[T]) ExecUpdate(ctx context.Context, stmt UpdateStatement, params map[string]any) (*T, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	if err := e.checkWrite(ctx, fmt.Sprintf("statement %q", stmt.name)); err != nil {
+		return nil, err
+	}
+	params, err := e.prepareParams(stmt, params)
+	if err != nil {
+		return nil, err
+	}
+	ctx = withStatement(ctx, stmt.name, "update")
+	if needsUpdateRewrite(stmt.spec) {
+		record, err := e.execRenderedUpdate(ctx, e.execer(), stmt, params)
+		return e.notifyRecord(ctx, e.execer(), record, err)
+	}
 	u := e.Update(stmt)
-	return u.Exec(ctx, params)
+	e.emitRendered(ctx, stmt.name, "update", u, params)
+	record, err := u.Exec(ctx, params)
+	return e.notifyRecord(ctx, e.execer(), record, err)
 }
 
 // ExecUpdateTx executes an update statement within a transaction.
 func (e *Executor[T]) ExecUpdateTx(ctx context.Context, tx *sqlx.Tx, stmt UpdateStatement, params map[string]any) (*T, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	if err := e.checkWrite(ctx, fmt.Sprintf("statement %q", stmt.name)); err != nil {
+		return nil, err
+	}
+	params, err := e.prepareParams(stmt, params)
+	if err != nil {
+		return nil, err
+	}
+	if needsUpdateRewrite(stmt.spec) {
+		record, err := e.execRenderedUpdate(ctx, e.execerFor(tx), stmt, params)
+		return e.notifyRecord(ctx, e.execerFor(tx), record, err)
+	}
 	u := e.Update(stmt)
-	return u.ExecTx(ctx, tx, params)
+	e.emitRendered(ctx, stmt.name, "update", u, params)
+	record, err := u.ExecTx(ctx, tx, params)
+	return e.notifyRecord(ctx, e.execerFor(tx), record, err)
 }
 
-// ExecDelete executes a delete statement directly.
+// ExecDelete executes a delete statement directly and returns the number of rows
+// deleted. Returning columns are ignored; use ExecDeleteReturning to get the rows.
 func (e *Executor[T]) ExecDelete(ctx context.Context, stmt DeleteStatement, params map[string]any) (int64, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	if err := e.checkWrite(ctx, fmt.Sprintf("statement %q", stmt.name)); err != nil {
+		return 0, err
+	}
+	params, err := e.prepareParams(stmt, params)
+	if err != nil {
+		return 0, err
+	}
+	ctx = withStatement(ctx, stmt.name, "delete")
 	d := e.Delete(stmt)
-	return d.Exec(ctx, params)
+	e.emitRendered(ctx, stmt.name, "delete", d, params)
+	count, err := d.Exec(ctx, params)
+	return e.notifyAffected(ctx, e.execer(), count, err)
 }
 
 // ExecDeleteTx executes a delete statement within a transaction.
 func (e *Executor[T]) ExecDeleteTx(ctx context.Context, tx *sqlx.Tx, stmt DeleteStatement, params map[string]any) (int64, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	if err := e.checkWrite(ctx, fmt.Sprintf("statement %q", stmt.name)); err != nil {
+		return 0, err
+	}
+	params, err := e.prepareParams(stmt, params)
+	if err != nil {
+		return 0, err
+	}
 	d := e.Delete(stmt)
-	return d.ExecTx(ctx, tx, params)
+	e.emitRendered(ctx, stmt.name, "delete", d, params)
+	count, err := d.ExecTx(ctx, tx, params)
+	return e.notifyAffected(ctx, e.execerFor(tx), count, err)
 }
 
 // ExecAggregate executes an aggregate statement directly.
 func (e *Executor[T]) ExecAggregate(ctx context.Context, stmt AggregateStatement, params map[string]any) (float64, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	if err := checkUngrouped(stmt); err != nil {
+		return 0, err
+	}
+	if isStatistical(stmt.fn) {
+		return execAggregateScalar[T, float64](withRead(ctx), e, e.execer(), stmt, params)
+	}
+	params, err := e.prepareParams(stmt, params)
+	if err != nil {
+		return 0, err
+	}
+	ctx = withStatement(ctx, stmt.name, "aggregate")
 	a := e.Aggregate(stmt)
-	return a.Exec(ctx, params)
+	e.emitRendered(ctx, stmt.name, "aggregate", a, params)
+	return a.Exec(withRead(ctx), params)
 }
 
 // ExecAggregateTx executes an aggregate statement within a transaction.
 func (e *Executor[T]) ExecAggregateTx(ctx context.Context, tx *sqlx.Tx, stmt AggregateStatement, params map[string]any) (float64, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	if err := checkUngrouped(stmt); err != nil {
+		return 0, err
+	}
+	if isStatistical(stmt.fn) {
+		return execAggregateScalar[T, float64](ctx, e, e.execerFor(tx), stmt, params)
+	}
+	params, err := e.prepareParams(stmt, params)
+	if err != nil {
+		return 0, err
+	}
 	a := e.Aggregate(stmt)
+	e.emitRendered(ctx, stmt.name, "aggregate", a, params)
 	return a.ExecTx(ctx, tx, params)
 }
 
 // ExecInsert executes an insert directly.
 func (e *Executor[T]) ExecInsert(ctx context.Context, record *T) (*T, error) {
-	return e.Insert().Exec(ctx, record)
+	ctx, cancel := e.withTimeout(ctx, "")
+	defer cancel()
+	if err := e.checkWrite(ctx, "ExecInsert"); err != nil {
+		return nil, err
+	}
+	ctx = withStatement(ctx, "", "insert")
+	if len(e.GeneratedColumns()) > 0 {
+		inserted, err := e.execInsertGenerated(ctx, e.execer(), record)
+		return e.notifyRecord(ctx, e.execer(), inserted, err)
+	}
+	ins := e.Insert()
+	e.emitRendered(ctx, "", "insert", ins, nil)
+	inserted, err := ins.Exec(ctx, record)
+	return e.notifyRecord(ctx, e.execer(), inserted, err)
 }
 
 // ExecInsertTx executes an insert within a transaction.
 func (e *Executor[T]) ExecInsertTx(ctx context.Context, tx *sqlx.Tx, record *T) (*T, error) {
-	return e.Insert().ExecTx(ctx, tx, record)
+	ctx, cancel := e.withTimeout(ctx, "")
+	defer cancel()
+	if err := e.checkWrite(ctx, "ExecInsert"); err != nil {
+		return nil, err
+	}
+	if len(e.GeneratedColumns()) > 0 {
+		inserted, err := e.execInsertGenerated(ctx, e.execerFor(tx), record)
+		return e.notifyRecord(ctx, e.execerFor(tx), inserted, err)
+	}
+	ins := e.Insert()
+	e.emitRendered(ctx, "", "insert", ins, nil)
+	inserted, err := ins.ExecTx(ctx, tx, record)
+	return e.notifyRecord(ctx, e.execerFor(tx), inserted, err)
 }
 
 // ExecInsertBatch inserts multiple records.
 // Returns the count of successfully inserted records.
 func (e *Executor[T]) ExecInsertBatch(ctx context.Context, records []*T) (int64, error) {
-	return e.Insert().ExecBatch(ctx, records)
+	ctx, cancel := e.withTimeout(ctx, "")
+	defer cancel()
+	if err := e.checkWrite(ctx, "ExecInsertBatch"); err != nil {
+		return 0, err
+	}
+	ctx = withStatement(ctx, "", "insert")
+	if len(e.GeneratedColumns()) > 0 {
+		return e.execInsertBatchGenerated(ctx, e.execer(), records)
+	}
+	ins := e.Insert()
+	e.emitRendered(ctx, "", "insert", ins, nil)
+	return ins.ExecBatch(ctx, records)
 }
 
 // ExecInsertBatchTx inserts multiple records within a transaction.
 func (e *Executor[T]) ExecInsertBatchTx(ctx context.Context, tx *sqlx.Tx, records []*T) (int64, error) {
-	return e.Insert().ExecBatchTx(ctx, tx, records)
+	ctx, cancel := e.withTimeout(ctx, "")
+	defer cancel()
+	if err := e.checkWrite(ctx, "ExecInsertBatch"); err != nil {
+		return 0, err
+	}
+	if len(e.GeneratedColumns()) > 0 {
+		return e.execInsertBatchGenerated(ctx, e.execerFor(tx), records)
+	}
+	ins := e.Insert()
+	e.emitRendered(ctx, "", "insert", ins, nil)
+	return ins.ExecBatchTx(ctx, tx, records)
 }
 
 // ExecCompound executes a compound query directly.
 func (e *Executor[T]) ExecCompound(ctx context.Context, spec CompoundQuerySpec, params map[string]any) ([]*T, error) {
+	ctx, cancel := e.withTimeout(ctx, "")
+	defer cancel()
+	ctx = withStatement(ctx, "", "compound")
 	c, err := e.Compound(spec)
 	if err != nil {
 		return nil, err
 	}
-	return c.Exec(ctx, params)
+	e.emitRendered(ctx, "", "compound", c, params)
+	return c.Exec(withRead(ctx), params)
 }
 
 // ExecCompoundTx executes a compound query within a transaction.
 func (e *Executor[T]) ExecCompoundTx(ctx context.Context, tx *sqlx.Tx, spec CompoundQuerySpec, params map[string]any) ([]*T, error) {
+	ctx, cancel := e.withTimeout(ctx, "")
+	defer cancel()
 	c, err := e.Compound(spec)
 	if err != nil {
 		return nil, err
 	}
+	e.emitRendered(ctx, "", "compound", c, params)
 	return c.ExecTx(ctx, tx, params)
 }
 
 // ExecUpdateBatch executes an update statement with multiple parameter sets.
 // Returns the total count of affected rows.
 func (e *Executor[T]) ExecUpdateBatch(ctx context.Context, stmt UpdateStatement, batchParams []map[string]any) (int64, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	if err := e.checkWrite(ctx, fmt.Sprintf("statement %q", stmt.name)); err != nil {
+		return 0, err
+	}
+	if len(stmt.spec.SetExpr) > 0 {
+		return 0, errSetExprUnsupported
+	}
+	batchParams, err := e.prepareBatchParams(stmt, batchParams)
+	if err != nil {
+		return 0, err
+	}
+	ctx = withStatement(ctx, stmt.name, "update")
 	u := e.Update(stmt)
+	e.emitRenderedBatch(ctx, stmt.name, "update", u, batchParams)
 	return u.ExecBatch(ctx, batchParams)
 }
 
 // ExecUpdateBatchTx executes an update statement with multiple parameter sets within a transaction.
 func (e *Executor[T]) ExecUpdateBatchTx(ctx context.Context, tx *sqlx.Tx, stmt UpdateStatement, batchParams []map[string]any) (int64, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	if err := e.checkWrite(ctx, fmt.Sprintf("statement %q", stmt.name)); err != nil {
+		return 0, err
+	}
+	if len(stmt.spec.SetExpr) > 0 {
+		return 0, errSetExprUnsupported
+	}
+	batchParams, err := e.prepareBatchParams(stmt, batchParams)
+	if err != nil {
+		return 0, err
+	}
 	u := e.Update(stmt)
+	e.emitRenderedBatch(ctx, stmt.name, "update", u, batchParams)
 	return u.ExecBatchTx(ctx, tx, batchParams)
 }
 
 // ExecDeleteBatch executes a delete statement with multiple parameter sets.
 // Returns the total count of deleted rows.
 func (e *Executor[T]) ExecDeleteBatch(ctx context.Context, stmt DeleteStatement, batchParams []map[string]any) (int64, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	if err := e.checkWrite(ctx, fmt.Sprintf("statement %q", stmt.name)); err != nil {
+		return 0, err
+	}
+	batchParams, err := e.prepareBatchParams(stmt, batchParams)
+	if err != nil {
+		return 0, err
+	}
+	ctx = withStatement(ctx, stmt.name, "delete")
 	d := e.Delete(stmt)
+	e.emitRenderedBatch(ctx, stmt.name, "delete", d, batchParams)
 	return d.ExecBatch(ctx, batchParams)
 }
 
 // ExecDeleteBatchTx executes a delete statement with multiple parameter sets within a transaction.
 func (e *Executor[T]) ExecDeleteBatchTx(ctx context.Context, tx *sqlx.Tx, stmt DeleteStatement, batchParams []map[string]any) (int64, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	if err := e.checkWrite(ctx, fmt.Sprintf("statement %q", stmt.name)); err != nil {
+		return 0, err
+	}
+	batchParams, err := e.prepareBatchParams(stmt, batchParams)
+	if err != nil {
+		return 0, err
+	}
 	d := e.Delete(stmt)
+	e.emitRenderedBatch(ctx, stmt.name, "delete", d, batchParams)
 	return d.ExecBatchTx(ctx, tx, batchParams)
 }
 
 // ExecQueryAtom executes a query statement and returns results as Atoms.
 // This enables type-erased execution where T is not known at consumption time.
 func (e *Executor[T]) ExecQueryAtom(ctx context.Context, stmt QueryStatement, params map[string]any) ([]*atom.Atom, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	ctx = withStatement(ctx, stmt.name, "query")
+	if err := e.queryRewrites(stmt.spec).unsupported(); err != nil {
+		return nil, err
+	}
+	params, err := e.prepareParams(stmt, params)
+	if err != nil {
+		return nil, err
+	}
 	q, err := e.Query(stmt)
 	if err != nil {
 		return nil, err
 	}
-	return q.ExecAtom(ctx, params)
+	return q.ExecAtom(withRead(ctx), params)
 }
 
 // ExecSelectAtom executes a select statement and returns the result as an Atom.
 // This enables type-erased execution where T is not known at consumption time.
 func (e *Executor[T]) ExecSelectAtom(ctx context.Context, stmt SelectStatement, params map[string]any) (*atom.Atom, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	ctx = withStatement(ctx, stmt.name, "select")
+	if err := e.selectRewrites(stmt.spec).unsupported(); err != nil {
+		return nil, err
+	}
+	params, err := e.prepareParams(stmt, params)
+	if err != nil {
+		return nil, err
+	}
 	s, err := e.Select(stmt)
 	if err != nil {
 		return nil, err
 	}
-	return s.ExecAtom(ctx, params)
+	a, err := s.ExecAtom(withRead(ctx), params)
+	return a, notFound(err)
 }
 
 // ExecInsertAtom executes an insert and returns the result as an Atom.
 // This enables type-erased execution where T is not known at consumption time.
 func (e *Executor[T]) ExecInsertAtom(ctx context.Context, params map[string]any) (*atom.Atom, error) {
+	ctx, cancel := e.withTimeout(ctx, "")
+	defer cancel()
+	if err := e.checkWrite(ctx, "ExecInsertAtom"); err != nil {
+		return nil, err
+	}
+	if cols := e.GeneratedColumns(); len(cols) > 0 {
+		return nil, fmt.Errorf("edamame: ExecInsertAtom does not support generated columns: %s", strings.Join(cols, ", "))
+	}
+	ctx = withStatement(ctx, "", "insert")
 	return e.Insert().ExecAtom(ctx, params)
 }
+
+// txBeginner is implemented by database handles that can start transactions, such as *sqlx.DB.
+type txBeginner interface {
+	BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error)
+}
+
+// ExecInTx runs fn inside a transaction on the executor's database.
+// The transaction is committed if fn returns nil and rolled back if fn returns an error or panics.
+// A panic is re-raised after the rollback completes.
+func (e *Executor[T]) ExecInTx(ctx context.Context, fn func(context.Context, *sqlx.Tx) error) error {
+	db, ok := e.db.(txBeginner)
+	if !ok {
+		return fmt.Errorf("edamame: database handle does not support transactions")
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("edamame: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(ctx, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("edamame: failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// renderable is implemented by every soy builder.
+type renderable interface {
+	Render() (*astql.QueryResult, error)
+}
+
+// emitRendered publishes the SQL a statement is about to execute on QueryRendered.
+// Render failures are not reported here; execution surfaces them.
+func (e *Executor[T]) emitRendered(ctx context.Context, name, queryType string, r renderable, params map[string]any) {
+	result, err := r.Render()
+	if err != nil {
+		return
+	}
+	e.emitSQL(ctx, name, queryType, result.SQL, params)
+}
+
+// emitRenderedBatch publishes the SQL a batch statement is about to execute on
+// QueryRendered, once for each of its parameter sets.
+func (e *Executor[T]) emitRenderedBatch(ctx context.Context, name, queryType string, r renderable, batchParams []map[string]any) {
+	result, err := r.Render()
+	if err != nil {
+		return
+	}
+	for _, params := range batchParams {
+		e.emitSQL(ctx, name, queryType, result.SQL, params)
+	}
+}
+
+// emitSQL publishes already-rendered SQL on QueryRendered, with the executor's event attributes.
+func (e *Executor[T]) emitSQL(ctx context.Context, name, queryType, sql string, params map[string]any) {
+	fields := append([]capitan.Field{
+		KeyStatement.Field(name),
+		KeyType.Field(queryType),
+		KeySQL.Field(sql),
+		KeyParams.Field(params),
+	}, e.eventAttributes()...)
+	capitan.Debug(ctx, QueryRendered, fields...)
+}
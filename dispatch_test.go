@@ -2,6 +2,7 @@ package edamame
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"testing"
@@ -417,6 +418,33 @@ func TestExecQuery(t *testing.T) {
 	}
 }
 
+func TestExecQuery_MaxResults(t *testing.T) {
+	truncateUsers(t)
+	ctx := context.Background()
+
+	for i, name := range []string{"alice", "bob", "carol"} {
+		age := 20 + i
+		insertTestUser(t, name+"@test.com", name, &age)
+	}
+
+	factory, err := New[User](testDB, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	capped := NewQueryStatement("capped", "At most two users", QuerySpec{
+		OrderBy:    []OrderBySpec{{Field: "age", Direction: "asc"}},
+		MaxResults: 2,
+	})
+
+	users, err := factory.ExecQuery(ctx, capped, nil)
+	if err != nil {
+		t.Fatalf("ExecQuery() failed: %v", err)
+	}
+	if len(users) != 2 || users[0].Name != "alice" || users[1].Name != "bob" {
+		t.Errorf("expected alice and bob, got %d users", len(users))
+	}
+}
+
 func TestExecQueryTx(t *testing.T) {
 	truncateUsers(t)
 	ctx := context.Background()
@@ -1187,3 +1215,234 @@ func TestExecCompoundTx(t *testing.T) {
 		t.Errorf("expected 2 users, got %d", len(users))
 	}
 }
+
+func TestExecInTx(t *testing.T) {
+	truncateUsers(t)
+	ctx := context.Background()
+
+	factory, err := New[User](testDB, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	err = factory.ExecInTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		_, err := factory.ExecInsertTx(ctx, tx, &User{Email: "alice@test.com", Name: "Alice"})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("ExecInTx() failed: %v", err)
+	}
+
+	users, err := factory.ExecQuery(ctx, queryAll, nil)
+	if err != nil {
+		t.Fatalf("ExecQuery() failed: %v", err)
+	}
+	if len(users) != 1 {
+		t.Errorf("expected 1 committed user, got %d", len(users))
+	}
+}
+
+func TestExecInTx_RollsBackOnError(t *testing.T) {
+	truncateUsers(t)
+	ctx := context.Background()
+
+	factory, err := New[User](testDB, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	errMidway := fmt.Errorf("midway failure")
+	err = factory.ExecInTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		if _, err := factory.ExecInsertTx(ctx, tx, &User{Email: "alice@test.com", Name: "Alice"}); err != nil {
+			return err
+		}
+		if _, err := factory.ExecInsertTx(ctx, tx, &User{Email: "bob@test.com", Name: "Bob"}); err != nil {
+			return err
+		}
+		return errMidway
+	})
+	if !errors.Is(err, errMidway) {
+		t.Fatalf("expected midway error, got %v", err)
+	}
+
+	users, err := factory.ExecQuery(ctx, queryAll, nil)
+	if err != nil {
+		t.Fatalf("ExecQuery() failed: %v", err)
+	}
+	if len(users) != 0 {
+		t.Errorf("expected no users after rollback, got %d", len(users))
+	}
+}
+
+func TestExecInTx_RollsBackOnPanic(t *testing.T) {
+	truncateUsers(t)
+	ctx := context.Background()
+
+	factory, err := New[User](testDB, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic to be re-raised")
+			}
+		}()
+		_ = factory.ExecInTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+			if _, err := factory.ExecInsertTx(ctx, tx, &User{Email: "alice@test.com", Name: "Alice"}); err != nil {
+				return err
+			}
+			panic("boom")
+		})
+	}()
+
+	users, err := factory.ExecQuery(ctx, queryAll, nil)
+	if err != nil {
+		t.Fatalf("ExecQuery() failed: %v", err)
+	}
+	if len(users) != 0 {
+		t.Errorf("expected no users after rollback, got %d", len(users))
+	}
+}
+
+func TestExecInTx_NoDatabase(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	err = factory.ExecInTx(context.Background(), func(context.Context, *sqlx.Tx) error { return nil })
+	if err == nil {
+		t.Error("ExecInTx() should fail without a transaction-capable database")
+	}
+}
+
+func TestExecQuery_ResultDedup(t *testing.T) {
+	truncateUsers(t)
+	ctx := context.Background()
+
+	age := 25
+	insertTestUser(t, "alice1@test.com", "Alice", &age)
+	insertTestUser(t, "alice2@test.com", "Alice", &age)
+	insertTestUser(t, "bob@test.com", "Bob", &age)
+
+	factory, err := New[User](testDB, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if err := factory.SetResultDedup("name"); err != nil {
+		t.Fatalf("SetResultDedup() failed: %v", err)
+	}
+
+	users, err := factory.ExecQuery(ctx, queryAll, nil)
+	if err != nil {
+		t.Fatalf("ExecQuery() failed: %v", err)
+	}
+	if len(users) != 2 {
+		t.Errorf("expected 2 users after dedup, got %d", len(users))
+	}
+}
+
+func TestExec_ResultAssertion(t *testing.T) {
+	truncateUsers(t)
+	ctx := context.Background()
+
+	valid := 25
+	invalid := -1
+	insertTestUser(t, "alice@test.com", "Alice", &valid)
+	id := insertTestUser(t, "corrupt@test.com", "Corrupt", &invalid)
+
+	factory, err := New[User](testDB, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	errNegativeAge := errors.New("age must not be negative")
+	factory.AddResultAssertion(func(u *User) error {
+		if u.Age != nil && *u.Age < 0 {
+			return errNegativeAge
+		}
+		return nil
+	})
+
+	if _, err := factory.ExecQuery(ctx, queryAll, nil); !errors.Is(err, errNegativeAge) {
+		t.Errorf("ExecQuery() error = %v, want %v", err, errNegativeAge)
+	}
+	if _, err := factory.ExecSelect(ctx, selectByID, map[string]any{"id": id}); !errors.Is(err, errNegativeAge) {
+		t.Errorf("ExecSelect() error = %v, want %v", err, errNegativeAge)
+	}
+}
+
+func TestExecAggregateInt(t *testing.T) {
+	truncateUsers(t)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		age := 20 + i
+		insertTestUser(t, fmt.Sprintf("user%d@test.com", i), fmt.Sprintf("User%d", i), &age)
+	}
+
+	factory, err := New[User](testDB, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	count, err := factory.ExecAggregateInt(ctx, countAll, nil)
+	if err != nil {
+		t.Fatalf("ExecAggregateInt() failed: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("expected count 3, got %d", count)
+	}
+
+	sum, err := factory.ExecAggregateInt(ctx, sumAge, nil)
+	if err != nil {
+		t.Fatalf("ExecAggregateInt() failed: %v", err)
+	}
+	if sum != 63 {
+		t.Errorf("expected sum 63, got %d", sum)
+	}
+}
+
+// AuditEvent is a test model with a timestamp column.
+type AuditEvent struct {
+	ID        int       `db:"id" type:"integer" constraints:"primarykey"`
+	CreatedAt time.Time `db:"created_at" type:"timestamptz"`
+}
+
+func TestExecAggregateScalar_Time(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := testDB.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS audit_events (
+			id SERIAL PRIMARY KEY,
+			created_at TIMESTAMPTZ NOT NULL
+		);
+		TRUNCATE TABLE audit_events RESTART IDENTITY
+	`)
+	if err != nil {
+		t.Fatalf("failed to create audit_events: %v", err)
+	}
+
+	latest := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	for _, ts := range []time.Time{latest.Add(-48 * time.Hour), latest, latest.Add(-time.Hour)} {
+		if _, err := testDB.ExecContext(ctx, `INSERT INTO audit_events (created_at) VALUES ($1)`, ts); err != nil {
+			t.Fatalf("failed to insert event: %v", err)
+		}
+	}
+
+	factory, err := New[AuditEvent](testDB, "audit_events", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	maxCreatedAt := NewAggregateStatement("max-created-at", "Latest event", AggMax, AggregateSpec{Field: "created_at"})
+	got, err := ExecAggregateScalar[AuditEvent, time.Time](ctx, factory, maxCreatedAt, nil)
+	if err != nil {
+		t.Fatalf("ExecAggregateScalar() failed: %v", err)
+	}
+	if !got.Equal(latest) {
+		t.Errorf("expected %v, got %v", latest, got)
+	}
+}
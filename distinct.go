@@ -0,0 +1,168 @@
+package edamame
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/zoobzio/astql/pkg/mariadb"
+	"github.com/zoobzio/astql/pkg/mssql"
+	"github.com/zoobzio/astql/pkg/sqlite"
+)
+
+// errDistinctFromUnsupported is returned by execution paths that run soy's SQL
+// unmodified and so cannot apply null-safe comparisons.
+var errDistinctFromUnsupported = errors.New("edamame: IS DISTINCT FROM is not supported by this method")
+
+// hasDistinctFrom reports whether any condition, including nested groups, is a null-safe comparison.
+func hasDistinctFrom(conditions []ConditionSpec) bool {
+	for _, c := range conditions {
+		if c.IsDistinctFrom() || (c.IsGroup() && hasDistinctFrom(c.Group)) {
+			return true
+		}
+	}
+	return false
+}
+
+// distinctFromPlaceholder returns the operator soy renders in place of a null-safe comparison.
+func distinctFromPlaceholder(operator string) string {
+	if strings.EqualFold(operator, opIsNotDistinctFrom) {
+		return "="
+	}
+	return "!="
+}
+
+// withDistinctFromPlaceholders returns conditions with each null-safe comparison swapped for
+// the plain operator soy renders, to be restored by rewriteDistinctFrom.
+func withDistinctFromPlaceholders(conditions []ConditionSpec) []ConditionSpec {
+	if !hasDistinctFrom(conditions) {
+		return conditions
+	}
+	replaced := make([]ConditionSpec, len(conditions))
+	for i, c := range conditions {
+		switch {
+		case c.IsDistinctFrom():
+			c.Operator = distinctFromPlaceholder(c.Operator)
+		case c.IsGroup():
+			c.Group = withDistinctFromPlaceholders(c.Group)
+		}
+		replaced[i] = c
+	}
+	return replaced
+}
+
+// distinctFromTarget is one placeholder comparison to restore in rendered SQL.
+type distinctFromTarget struct {
+	placeholder string // rendered text, e.g. `"name" != :name`
+	replacement string // dialect's null-safe form, e.g. `"name" IS DISTINCT FROM :name`
+	operator    string // condition operator, for errors
+	count       int    // conditions expected to render the placeholder
+}
+
+// distinctFromSQL returns the executor's dialect's null-safe comparison of the quoted
+// column with param: IS [NOT] DISTINCT FROM, SQLite's IS [NOT], or MariaDB's <=>.
+func (e *Executor[T]) distinctFromSQL(column, operator, param string) string {
+	distinct := !strings.EqualFold(operator, opIsNotDistinctFrom)
+	switch e.dialect().(type) {
+	case *sqlite.Renderer:
+		if distinct {
+			return column + " IS NOT :" + param
+		}
+		return column + " IS :" + param
+	case *mariadb.Renderer:
+		if distinct {
+			return "NOT (" + column + " <=> :" + param + ")"
+		}
+		return column + " <=> :" + param
+	}
+	return column + " " + strings.ToUpper(operator) + " :" + param
+}
+
+// collectDistinctFrom gathers the placeholders rendered for null-safe comparisons, keyed by text.
+func (e *Executor[T]) collectDistinctFrom(conditions []ConditionSpec, targets map[string]*distinctFromTarget) {
+	for _, c := range conditions {
+		if c.IsGroup() {
+			e.collectDistinctFrom(c.Group, targets)
+			continue
+		}
+		if !c.IsDistinctFrom() {
+			continue
+		}
+		column := e.quoteIdent(e.column(c.Field))
+		operator := distinctFromPlaceholder(c.Operator)
+		if _, ok := e.dialect().(*mssql.Renderer); ok && operator == "!=" {
+			operator = "<>"
+		}
+		placeholder := column + " " + operator + " :" + c.Param
+		t, ok := targets[placeholder]
+		if !ok {
+			t = &distinctFromTarget{
+				placeholder: placeholder,
+				replacement: e.distinctFromSQL(column, c.Operator, c.Param),
+				operator:    strings.ToUpper(c.Operator),
+			}
+			targets[placeholder] = t
+		}
+		t.count++
+	}
+}
+
+// rewriteDistinctFrom restores the null-safe comparisons that soy rendered as = and !=,
+// in the executor's dialect. Neither soy nor astql has a null-safe comparison, so the
+// rendered SQL is edited: each placeholder is located by its column, operator and param
+// name. A placeholder that also matches a plain comparison in the statement is ambiguous
+// and rejected.
+func (e *Executor[T]) rewriteDistinctFrom(sql string, conditions []ConditionSpec) (string, error) {
+	if !hasDistinctFrom(conditions) {
+		return sql, nil
+	}
+
+	targets := make(map[string]*distinctFromTarget)
+	e.collectDistinctFrom(conditions, targets)
+
+	for _, t := range targets {
+		found := placeholderOffsets(sql, t.placeholder)
+		if len(found) != t.count {
+			return "", fmt.Errorf("edamame: cannot place %s for %q: rendered %d matching comparisons, expected %d",
+				t.operator, t.placeholder, len(found), t.count)
+		}
+		sql = replaceOffsets(sql, found, len(t.placeholder), t.replacement)
+	}
+	return sql, nil
+}
+
+// replaceOffsets replaces the n bytes of sql at each of offsets with replacement.
+func replaceOffsets(sql string, offsets []int, n int, replacement string) string {
+	var b strings.Builder
+	last := 0
+	for _, at := range offsets {
+		b.WriteString(sql[last:at])
+		b.WriteString(replacement)
+		last = at + n
+	}
+	b.WriteString(sql[last:])
+	return b.String()
+}
+
+// placeholderOffsets returns where placeholder occurs in sql, skipping matches whose
+// param name continues past the placeholder (":name" must not match ":name_2").
+func placeholderOffsets(sql, placeholder string) []int {
+	var offsets []int
+	for from := 0; ; {
+		i := strings.Index(sql[from:], placeholder)
+		if i < 0 {
+			return offsets
+		}
+		at := from + i
+		end := at + len(placeholder)
+		if end == len(sql) || !isParamChar(sql[end]) {
+			offsets = append(offsets, at)
+		}
+		from = end
+	}
+}
+
+// isParamChar reports whether c can appear in a named parameter.
+func isParamChar(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
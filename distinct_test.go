@@ -0,0 +1,156 @@
+package edamame
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql"
+	"github.com/zoobzio/astql/pkg/mariadb"
+	"github.com/zoobzio/astql/pkg/mssql"
+	"github.com/zoobzio/astql/pkg/postgres"
+	"github.com/zoobzio/astql/pkg/sqlite"
+)
+
+func TestDistinctFrom_Render(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	changed := NewQueryStatement("age-changed", "Users whose age differs", QuerySpec{
+		Where: []ConditionSpec{{Field: "age", Operator: "IS DISTINCT FROM", Param: "age"}},
+	})
+	sql, err := factory.RenderQuery(changed)
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+	if !strings.Contains(sql, `WHERE "age" IS DISTINCT FROM :age`) {
+		t.Errorf("expected IS DISTINCT FROM, got: %s", sql)
+	}
+
+	same := NewSelectStatement("age-same", "User whose age matches", SelectSpec{
+		Where: []ConditionSpec{
+			{Field: "email", Operator: "=", Param: "email"},
+			{Logic: "OR", Group: []ConditionSpec{
+				{Field: "age", Operator: "is not distinct from", Param: "age"},
+				{Field: "name", Operator: "=", Param: "name"},
+			}},
+		},
+	})
+	sql, err = factory.RenderSelect(same)
+	if err != nil {
+		t.Fatalf("RenderSelect() failed: %v", err)
+	}
+	for _, want := range []string{`"email" = :email`, `"age" IS NOT DISTINCT FROM :age`, `"name" = :name`} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("expected %q in SQL, got: %s", want, sql)
+		}
+	}
+
+	params := changed.Params()
+	if len(params) != 1 || params[0].Name != "age" {
+		t.Errorf("expected derived param age, got %+v", params)
+	}
+}
+
+func TestDistinctFrom_Dialects(t *testing.T) {
+	tests := []struct {
+		name     string
+		renderer astql.Renderer
+		want     string
+	}{
+		{"mariadb", mariadb.New(), "(NOT (`name` <=> :name) AND `age` <=> :age)"},
+		{"mssql", mssql.New(), `([name] IS DISTINCT FROM :name AND [age] IS NOT DISTINCT FROM :age)`},
+		{"sqlite", sqlite.New(), `("name" IS NOT :name AND "age" IS :age)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory, err := New[User](nil, "users", tt.renderer)
+			if err != nil {
+				t.Fatalf("New() failed: %v", err)
+			}
+			stmt := NewQueryStatement("q", "q", QuerySpec{
+				Where: []ConditionSpec{
+					{Field: "name", Operator: "IS DISTINCT FROM", Param: "name"},
+					{Field: "age", Operator: "IS NOT DISTINCT FROM", Param: "age"},
+				},
+			})
+			sql, err := factory.RenderQuery(stmt)
+			if err != nil {
+				t.Fatalf("RenderQuery() failed: %v", err)
+			}
+			if !strings.Contains(sql, tt.want) {
+				t.Errorf("expected %q in SQL, got: %s", tt.want, sql)
+			}
+		})
+	}
+}
+
+func TestDistinctFrom_Ambiguous(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	// The plain comparison renders exactly like the placeholder.
+	stmt := NewQueryStatement("ambiguous", "Same param compared two ways", QuerySpec{
+		Where: []ConditionSpec{
+			{Field: "age", Operator: "=", Param: "age"},
+			{Field: "age", Operator: "IS NOT DISTINCT FROM", Param: "age"},
+		},
+	})
+	if _, err := factory.RenderQuery(stmt); err == nil {
+		t.Error("expected error for ambiguous placeholder")
+	}
+
+	// A param sharing a prefix is not a match.
+	stmt = NewQueryStatement("prefixed", "Params sharing a prefix", QuerySpec{
+		Where: []ConditionSpec{
+			{Field: "age", Operator: "!=", Param: "age_min"},
+			{Field: "age", Operator: "IS DISTINCT FROM", Param: "age"},
+		},
+	})
+	sql, err := factory.RenderQuery(stmt)
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+	if !strings.Contains(sql, `"age" != :age_min`) || !strings.Contains(sql, `"age" IS DISTINCT FROM :age`) {
+		t.Errorf("unexpected SQL: %s", sql)
+	}
+}
+
+func TestDistinctFrom_Unsupported(t *testing.T) {
+	factory, err := New[User](&recordingDB{}, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	where := []ConditionSpec{{Field: "age", Operator: "IS DISTINCT FROM", Param: "age"}}
+
+	query := NewQueryStatement("q", "q", QuerySpec{Where: where})
+	if _, err := factory.ExecQueryAtom(context.Background(), query, nil); !errors.Is(err, errDistinctFromUnsupported) {
+		t.Errorf("ExecQueryAtom: expected errDistinctFromUnsupported, got %v", err)
+	}
+	if _, err := factory.RenderCompound(CompoundQuerySpec{
+		Base:     QuerySpec{Where: where},
+		Operands: []SetOperandSpec{{Operation: "union", Query: QuerySpec{}}},
+	}); !errors.Is(err, errDistinctFromUnsupported) {
+		t.Errorf("RenderCompound: expected errDistinctFromUnsupported, got %v", err)
+	}
+
+	// Statement types executed by soy reject the operator before rendering.
+	const want = "IS DISTINCT FROM comparisons are only supported in query and select statements"
+	del := NewDeleteStatement("d", "d", DeleteSpec{Where: where})
+	if _, err := factory.RenderDelete(del); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("RenderDelete: expected %q, got %v", want, err)
+	}
+	upd := NewUpdateStatement("u", "u", UpdateSpec{Set: map[string]string{"name": "name"}, Where: where})
+	if _, err := factory.ExecUpdate(context.Background(), upd, map[string]any{"name": "A", "age": 30}); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("ExecUpdate: expected %q, got %v", want, err)
+	}
+	agg := NewAggregateStatement("a", "a", AggCount, AggregateSpec{Where: where})
+	if _, err := factory.ExecAggregate(context.Background(), agg, map[string]any{"age": 30}); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("ExecAggregate: expected %q, got %v", want, err)
+	}
+}
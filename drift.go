@@ -0,0 +1,183 @@
+package edamame
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MismatchKind names how a column of the live table differs from the model.
+type MismatchKind string
+
+const (
+	MismatchMissingColumn MismatchKind = "missing_column" // in the model, not in the table
+	MismatchExtraColumn   MismatchKind = "extra_column"   // in the table, not in the model
+	MismatchType          MismatchKind = "type"           // type tag differs from the column type
+	MismatchNullability   MismatchKind = "nullability"    // NOT NULL differs
+)
+
+// SchemaMismatch is one difference between T's struct tags and the live table, as
+// reported by ValidateAgainstDB. Model and Table describe each side, such as the
+// types "integer" and "text" or the nullability "NOT NULL" and "NULL"; the side a
+// missing or extra column is absent from is empty.
+type SchemaMismatch struct {
+	Column string       `json:"column"`
+	Kind   MismatchKind `json:"kind"`
+	Model  string       `json:"model,omitempty"`
+	Table  string       `json:"table,omitempty"`
+}
+
+// String describes the mismatch, e.g. `column "age": type differs: model integer, table text`.
+func (m SchemaMismatch) String() string {
+	switch m.Kind {
+	case MismatchMissingColumn:
+		return fmt.Sprintf("column %q: missing from table", m.Column)
+	case MismatchExtraColumn:
+		return fmt.Sprintf("column %q: not in model", m.Column)
+	default:
+		return fmt.Sprintf("column %q: %s differs: model %s, table %s", m.Column, m.Kind, m.Model, m.Table)
+	}
+}
+
+// columnsQuery reads the columns of a table from information_schema, defaulting the
+// schema to the connection's current schema.
+const columnsQuery = `SELECT column_name, udt_name, is_nullable FROM information_schema.columns ` +
+	`WHERE table_schema = COALESCE(NULLIF($1, ''), current_schema()) AND table_name = $2 ORDER BY ordinal_position`
+
+// ValidateAgainstDB compares T's db columns with the live table's columns in
+// information_schema and returns every difference, in model order followed by the
+// table's extra columns. An empty result means the model matches.
+//
+// Columns are compared by name. Types are compared only for fields with a type tag,
+// after resolving aliases such as int4, serial and integer, or timestamptz and
+// timestamp with time zone; lengths and precisions are ignored. A field tagged
+// notnull or primarykey is NOT NULL; any other field is expected to be nullable.
+// The query runs on the primary database. PostgreSQL only.
+//
+// Example:
+//
+//	mismatches, err := exec.ValidateAgainstDB(ctx)
+//	if err != nil {
+//	    return err
+//	}
+//	for _, m := range mismatches {
+//	    log.Printf("users: %s", m)
+//	}
+func (e *Executor[T]) ValidateAgainstDB(ctx context.Context) ([]SchemaMismatch, error) {
+	if !e.isPostgres() {
+		return nil, fmt.Errorf("edamame: ValidateAgainstDB requires the postgres renderer")
+	}
+	ctx, cancel := e.withTimeout(ctx, "")
+	defer cancel()
+
+	rows, err := e.execer().QueryxContext(ctx, columnsQuery, e.schema, e.table)
+	if err != nil {
+		return nil, fmt.Errorf("edamame: failed to read table columns: %w", err)
+	}
+	defer rows.Close()
+
+	type tableColumn struct {
+		typ     string
+		notNull bool
+	}
+	table := make(map[string]tableColumn)
+	var order []string
+	for rows.Next() {
+		var name, udt, nullable string
+		if err := rows.Scan(&name, &udt, &nullable); err != nil {
+			return nil, fmt.Errorf("edamame: failed to scan table column: %w", err)
+		}
+		table[name] = tableColumn{typ: canonicalType(udt), notNull: nullable == "NO"}
+		order = append(order, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("edamame: failed to read table columns: %w", err)
+	}
+	if len(table) == 0 {
+		return nil, fmt.Errorf("edamame: table %q not found", e.TableName())
+	}
+
+	mismatches := make([]SchemaMismatch, 0)
+	model := make(map[string]bool)
+	for _, field := range e.soy.Metadata().Fields {
+		col := field.Tags["db"]
+		if col == "" || col == "-" {
+			continue
+		}
+		model[col] = true
+		actual, ok := table[col]
+		if !ok {
+			mismatches = append(mismatches, SchemaMismatch{Column: col, Kind: MismatchMissingColumn})
+			continue
+		}
+		if typ := field.Tags["type"]; typ != "" && canonicalType(typ) != actual.typ {
+			mismatches = append(mismatches, SchemaMismatch{Column: col, Kind: MismatchType, Model: canonicalType(typ), Table: actual.typ})
+		}
+		constraints := field.Tags["constraints"]
+		if notNull := isNotNull(constraints) || isPrimaryKey(constraints); notNull != actual.notNull {
+			mismatches = append(mismatches, SchemaMismatch{Column: col, Kind: MismatchNullability, Model: nullability(notNull), Table: nullability(actual.notNull)})
+		}
+	}
+	for _, col := range order {
+		if !model[col] {
+			mismatches = append(mismatches, SchemaMismatch{Column: col, Kind: MismatchExtraColumn})
+		}
+	}
+	return mismatches, nil
+}
+
+// nullability describes a column's NOT NULL state as SQL writes it.
+func nullability(notNull bool) string {
+	if notNull {
+		return "NOT NULL"
+	}
+	return "NULL"
+}
+
+// typeModifier matches a length or precision suffix such as "(255)" or "(10, 2)".
+var typeModifier = regexp.MustCompile(`\s*\([^)]*\)`)
+
+// typeAliases maps PostgreSQL type names, including information_schema udt names,
+// to one canonical name.
+var typeAliases = map[string]string{
+	"int":         "integer",
+	"int4":        "integer",
+	"serial":      "integer",
+	"serial4":     "integer",
+	"int8":        "bigint",
+	"bigserial":   "bigint",
+	"serial8":     "bigint",
+	"int2":        "smallint",
+	"smallserial": "smallint",
+	"serial2":     "smallint",
+	"float8":      "double precision",
+	"float":       "double precision",
+	"float4":      "real",
+	"decimal":     "numeric",
+	"bool":        "boolean",
+	"varchar":     "character varying",
+	"bpchar":      "character",
+	"char":        "character",
+	"timestamptz": "timestamp with time zone",
+	"timestamp":   "timestamp without time zone",
+	"timetz":      "time with time zone",
+	"time":        "time without time zone",
+}
+
+// canonicalType normalizes a PostgreSQL type name for comparison: lower case, without
+// length or precision, aliases resolved, and arrays written as "type[]" whether given
+// as "text[]" or as the udt name "_text".
+func canonicalType(typ string) string {
+	typ = strings.ToLower(strings.TrimSpace(typeModifier.ReplaceAllString(typ, "")))
+	if base, ok := strings.CutSuffix(typ, "[]"); ok {
+		return canonicalType(base) + "[]"
+	}
+	if base, ok := strings.CutPrefix(typ, "_"); ok {
+		return canonicalType(base) + "[]"
+	}
+	if alias, ok := typeAliases[typ]; ok {
+		return alias
+	}
+	return typ
+}
@@ -0,0 +1,55 @@
+package edamame
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/mariadb"
+)
+
+func TestCanonicalType(t *testing.T) {
+	tests := map[string]string{
+		"integer":        "integer",
+		"int4":           "integer",
+		"SERIAL":         "integer",
+		"bigserial":      "bigint",
+		"varchar(255)":   "character varying",
+		"numeric(10, 2)": "numeric",
+		"timestamptz":    "timestamp with time zone",
+		"text[]":         "text[]",
+		"_text":          "text[]",
+		"_int4":          "integer[]",
+		"jsonb":          "jsonb",
+	}
+	for typ, want := range tests {
+		if got := canonicalType(typ); got != want {
+			t.Errorf("canonicalType(%q) = %q, want %q", typ, got, want)
+		}
+	}
+}
+
+func TestSchemaMismatchString(t *testing.T) {
+	tests := []struct {
+		m    SchemaMismatch
+		want string
+	}{
+		{SchemaMismatch{Column: "age", Kind: MismatchMissingColumn}, `column "age": missing from table`},
+		{SchemaMismatch{Column: "nickname", Kind: MismatchExtraColumn}, `column "nickname": not in model`},
+		{SchemaMismatch{Column: "name", Kind: MismatchType, Model: "text", Table: "integer"}, `column "name": type differs: model text, table integer`},
+	}
+	for _, tt := range tests {
+		if got := tt.m.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestValidateAgainstDB_Dialect(t *testing.T) {
+	exec, err := New[User](nil, "users", mariadb.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if _, err := exec.ValidateAgainstDB(context.Background()); err == nil {
+		t.Error("expected ValidateAgainstDB to require the postgres renderer")
+	}
+}
@@ -0,0 +1,83 @@
+package edamame
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ExecQueryWithETag executes a query statement and returns its records with an ETag
+// derived from them, so HTTP handlers can answer a matching If-None-Match with 304.
+//
+// The ETag is a quoted, strong entity tag holding the hex SHA-256 of the records'
+// column values: each record's db-tagged fields, in schema order, JSON-encoded and
+// separated so that no two different results hash alike. It is computed in process
+// over the records returned, after deduplication and MaxResults, and is stable
+// across processes and releases as long as T's db columns do not change. Row order
+// is part of the hash, so give the statement an ORDER BY that is deterministic.
+//
+// Example:
+//
+//	users, etag, err := exec.ExecQueryWithETag(ctx, Adults, params)
+//	if r.Header.Get("If-None-Match") == etag {
+//	    w.WriteHeader(http.StatusNotModified)
+//	    return
+//	}
+//	w.Header().Set("ETag", etag)
+func (e *Executor[T]) ExecQueryWithETag(ctx context.Context, stmt QueryStatement, params map[string]any) ([]*T, string, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	records, err := e.ExecQuery(ctx, stmt, params)
+	if err != nil {
+		return nil, "", err
+	}
+	etag, err := e.resultETag(records)
+	if err != nil {
+		return nil, "", err
+	}
+	return records, etag, nil
+}
+
+// ExecQueryWithETagTx executes a query statement within a transaction and returns its records with their ETag.
+func (e *Executor[T]) ExecQueryWithETagTx(ctx context.Context, tx *sqlx.Tx, stmt QueryStatement, params map[string]any) ([]*T, string, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	records, err := e.ExecQueryTx(ctx, tx, stmt, params)
+	if err != nil {
+		return nil, "", err
+	}
+	etag, err := e.resultETag(records)
+	if err != nil {
+		return nil, "", err
+	}
+	return records, etag, nil
+}
+
+// resultETag hashes the column values of records into a quoted entity tag. Each value
+// is written as its JSON encoding followed by a newline, and each record is closed by
+// an extra newline; JSON never contains a raw newline, so the framing is unambiguous.
+// A nil record is written as "-", which no JSON value encodes to.
+func (e *Executor[T]) resultETag(records []*T) (string, error) {
+	columns := e.schemaColumns()
+	h := sha256.New()
+	enc := json.NewEncoder(h)
+	for _, record := range records {
+		if record == nil {
+			fmt.Fprint(h, "-\n\n")
+			continue
+		}
+		v := reflect.ValueOf(record).Elem()
+		for _, col := range columns {
+			if err := enc.Encode(v.FieldByIndex(e.columns[col]).Interface()); err != nil {
+				return "", fmt.Errorf("edamame: failed to hash column %q: %w", col, err)
+			}
+		}
+		fmt.Fprint(h, "\n")
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil)) + `"`, nil
+}
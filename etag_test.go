@@ -0,0 +1,49 @@
+package edamame
+
+import (
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestResultETag(t *testing.T) {
+	exec, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	age := 30
+	records := func() []*User {
+		return []*User{{ID: 1, Email: "a@example.com", Name: "Alice", Age: &age}, {ID: 2, Email: "b@example.com", Name: "Bob"}}
+	}
+
+	etag, err := exec.resultETag(records())
+	if err != nil {
+		t.Fatalf("resultETag() failed: %v", err)
+	}
+	if len(etag) != 66 || etag[0] != '"' || etag[65] != '"' {
+		t.Errorf("expected a quoted SHA-256 hex digest, got %s", etag)
+	}
+	again, _ := exec.resultETag(records())
+	if again != etag {
+		t.Errorf("expected identical results to share an ETag, got %s and %s", etag, again)
+	}
+
+	changed := records()
+	changed[1].Name = "Robert"
+	if other, _ := exec.resultETag(changed); other == etag {
+		t.Error("expected a changed row to change the ETag")
+	}
+	reordered := records()
+	reordered[0], reordered[1] = reordered[1], reordered[0]
+	if other, _ := exec.resultETag(reordered); other == etag {
+		t.Error("expected a different row order to change the ETag")
+	}
+	shifted := []*User{{ID: 1, Email: "a@example.com", Name: "Alice\n", Age: &age}, {ID: 2, Email: "b@example.com", Name: "Bob"}}
+	if other, _ := exec.resultETag(shifted); other == etag {
+		t.Error("expected values to be framed unambiguously")
+	}
+	empty, _ := exec.resultETag(nil)
+	if empty == etag {
+		t.Error("expected an empty result to have its own ETag")
+	}
+}
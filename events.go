@@ -4,12 +4,20 @@ import "github.com/zoobzio/capitan"
 
 // Event keys for structured logging.
 var (
-	KeyTable    = capitan.NewStringKey("table")
-	KeyError    = capitan.NewStringKey("error")
-	KeyDuration = capitan.NewDurationKey("duration")
+	KeyTable     = capitan.NewStringKey("table")
+	KeyError     = capitan.NewStringKey("error")
+	KeyDuration  = capitan.NewDurationKey("duration")
+	KeyStatement = capitan.NewStringKey("statement")
+	KeyType      = capitan.NewStringKey("type")
+	KeySQL       = capitan.NewStringKey("sql")
+	KeyParams    = capitan.NewKey[map[string]any]("params", "edamame.Params")
+	KeyRows      = capitan.NewIntKey("rows")
+	KeyLimit     = capitan.NewIntKey("limit")
 )
 
 // Signals emitted by edamame.
 var (
 	ExecutorCreated = capitan.NewSignal("edamame.executor.created", "Executor instance created")
+	QueryRendered   = capitan.NewSignal("edamame.query.rendered", "Statement rendered to SQL for execution")
+	ResultsCapped   = capitan.NewSignal("edamame.results.capped", "Query results truncated to the statement's MaxResults")
 )
@@ -1,6 +1,15 @@
 package edamame
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/astql/pkg/postgres"
+	"github.com/zoobzio/capitan"
+)
 
 func TestEventKeys(t *testing.T) {
 	keys := []struct {
@@ -10,6 +19,12 @@ func TestEventKeys(t *testing.T) {
 		{"KeyTable", KeyTable},
 		{"KeyError", KeyError},
 		{"KeyDuration", KeyDuration},
+		{"KeyStatement", KeyStatement},
+		{"KeyType", KeyType},
+		{"KeySQL", KeySQL},
+		{"KeyParams", KeyParams},
+		{"KeyRows", KeyRows},
+		{"KeyLimit", KeyLimit},
 	}
 
 	for _, k := range keys {
@@ -27,6 +42,8 @@ func TestSignals(t *testing.T) {
 		signal interface{}
 	}{
 		{"ExecutorCreated", ExecutorCreated},
+		{"QueryRendered", QueryRendered},
+		{"ResultsCapped", ResultsCapped},
 	}
 
 	for _, s := range signals {
@@ -37,3 +54,89 @@ func TestSignals(t *testing.T) {
 		})
 	}
 }
+
+func TestSetEventAttributes(t *testing.T) {
+	factory, err := New[User](&recordingDB{}, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	keyService := capitan.NewStringKey("service")
+	factory.SetEventAttributes(keyService.Field("billing"))
+
+	stmt := NewQueryStatement("event-attrs", "Query with event attributes", QuerySpec{})
+	services := make(chan string, 1)
+	listener := capitan.Hook(QueryRendered, func(_ context.Context, e *capitan.Event) {
+		if name, _ := KeyStatement.From(e); name != stmt.Name() {
+			return
+		}
+		service, _ := keyService.From(e)
+		select {
+		case services <- service:
+		default:
+		}
+	})
+	defer listener.Close()
+
+	if _, err := factory.ExecQuery(context.Background(), stmt, nil); !errors.Is(err, errRecorded) {
+		t.Fatalf("expected recorded error, got %v", err)
+	}
+
+	select {
+	case service := <-services:
+		if service != "billing" {
+			t.Errorf("expected service attribute %q, got %q", "billing", service)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for QueryRendered event")
+	}
+}
+
+func TestQueryRendered_BatchCompoundAndInserts(t *testing.T) {
+	factory, err := New[User](&recordingDB{}, "rendered_users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	types := make(chan string, 16)
+	listener := capitan.Hook(QueryRendered, func(_ context.Context, e *capitan.Event) {
+		if sql, _ := KeySQL.From(e); !strings.Contains(sql, "rendered_users") {
+			return
+		}
+		queryType, _ := KeyType.From(e)
+		types <- queryType
+	})
+	defer listener.Close()
+
+	ctx := context.Background()
+	update := NewUpdateStatement("rename", "Rename", UpdateSpec{
+		Set:   map[string]string{"name": "name"},
+		Where: []ConditionSpec{{Field: "id", Operator: "=", Param: "id"}},
+	})
+	_, _ = factory.ExecUpdateBatch(ctx, update, []map[string]any{{"id": 1, "name": "a"}, {"id": 2, "name": "b"}})
+	remove := NewDeleteStatement("remove", "Remove", DeleteSpec{Where: []ConditionSpec{{Field: "id", Operator: "=", Param: "id"}}})
+	_, _ = factory.ExecDeleteBatch(ctx, remove, []map[string]any{{"id": 1}})
+	_, _ = factory.ExecCompound(ctx, CompoundQuerySpec{
+		Base:     QuerySpec{Fields: []string{"id"}},
+		Operands: []SetOperandSpec{{Operation: "union", Query: QuerySpec{Fields: []string{"id"}}}},
+	}, nil)
+	_, _ = factory.ExecInsertBatch(ctx, []*User{{Email: "a@example.com", Name: "A"}})
+	var returned struct {
+		ID int `db:"id"`
+	}
+	_ = ExecInsertReturningInto(ctx, factory, &User{Email: "b@example.com", Name: "B"}, []string{"id"}, &returned)
+
+	want := map[string]int{"update": 2, "delete": 1, "compound": 1, "insert": 2}
+	got := make(map[string]int)
+	for n := 0; n < 6; n++ {
+		select {
+		case queryType := <-types:
+			got[queryType]++
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for QueryRendered events, got %v", got)
+		}
+	}
+	for queryType, n := range want {
+		if got[queryType] != n {
+			t.Errorf("expected %d %s events, got %d (all: %v)", n, queryType, got[queryType], got)
+		}
+	}
+}
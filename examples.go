@@ -0,0 +1,120 @@
+package edamame
+
+import (
+	"fmt"
+	"strings"
+)
+
+// paramBinding records the column a param is compared with or assigned to.
+type paramBinding struct {
+	field string
+	list  bool // bound to IN / NOT IN, so the value is an array
+}
+
+// ExampleParams synthesizes a plausible value for each of a statement's params,
+// suitable for embedding in LLM prompts as a few-shot example. A param's Default
+// is used when set; otherwise the value is derived from the SQL type tag of the
+// column the param is compared with or assigned to, falling back to the param's Type.
+//
+// Example:
+//
+//	example, _ := exec.ExampleParams(ByID) // map[string]any{"id": 1}
+func (e *Executor[T]) ExampleParams(stmt Statement) (map[string]any, error) {
+	bindings, err := e.paramBindings(stmt)
+	if err != nil {
+		return nil, err
+	}
+	params, err := e.StatementParams(stmt)
+	if err != nil {
+		return nil, err
+	}
+	example := make(map[string]any, len(params))
+	for _, p := range params {
+		if p.Default != nil {
+			example[p.Name] = p.Default
+			continue
+		}
+		typ := p.Type
+		b, bound := bindings[p.Name]
+		if bound {
+			if colType, err := e.columnType(e.column(b.field)); err == nil {
+				typ = colType
+			}
+		}
+		value := exampleValue(typ, p.Name)
+		if bound && b.list {
+			value = []any{value}
+		}
+		example[p.Name] = value
+	}
+	return example, nil
+}
+
+// paramBindings maps each of stmt's params to the field it is compared with or assigned to.
+func (e *Executor[T]) paramBindings(stmt Statement) (map[string]paramBinding, error) {
+	bindings := make(map[string]paramBinding)
+	switch s := stmt.(type) {
+	case QueryStatement:
+		collectBindings(e.expandFragments(s.spec.Where), bindings)
+		collectBindings(s.spec.Having, bindings)
+	case SelectStatement:
+		collectBindings(e.expandFragments(s.spec.Where), bindings)
+		collectBindings(s.spec.Having, bindings)
+	case UpdateStatement:
+		for field, param := range s.spec.Set {
+			bindings[param] = paramBinding{field: field}
+		}
+		collectBindings(e.expandFragments(s.spec.Where), bindings)
+	case DeleteStatement:
+		collectBindings(e.expandFragments(s.spec.Where), bindings)
+	case AggregateStatement:
+		collectBindings(e.expandFragments(s.spec.Where), bindings)
+	default:
+		return nil, fmt.Errorf("edamame: unsupported statement type %T", stmt)
+	}
+	return bindings, nil
+}
+
+// collectBindings maps condition params to the fields they are compared with, including nested groups.
+func collectBindings(conditions []ConditionSpec, bindings map[string]paramBinding) {
+	for i := range conditions {
+		c := conditions[i]
+		switch {
+		case c.IsGroup():
+			collectBindings(c.Group, bindings)
+		case c.IsJSONPath() && c.JSONOp != jsonOpContains:
+			// A path comparison binds a value inside the column, not the column's type
+		case c.IsBetween() || c.IsNotBetween():
+			bindings[c.LowParam] = paramBinding{field: c.Field}
+			bindings[c.HighParam] = paramBinding{field: c.Field}
+		case c.Param != "":
+			bindings[c.Param] = paramBinding{field: c.Field, list: c.IsList()}
+		}
+	}
+}
+
+// exampleValue returns a representative value for a SQL type name.
+func exampleValue(typ, name string) any {
+	typ = strings.ToLower(strings.TrimSpace(typ))
+	switch {
+	case typ == "interval":
+		return "1 day"
+	case strings.HasPrefix(typ, "int") || strings.HasSuffix(typ, "int") || strings.HasSuffix(typ, "serial"):
+		return 1
+	case strings.HasPrefix(typ, "numeric") || strings.HasPrefix(typ, "decimal") ||
+		strings.HasPrefix(typ, "real") || strings.HasPrefix(typ, "double") || strings.HasPrefix(typ, "float"):
+		return 1.5
+	case strings.HasPrefix(typ, "bool"):
+		return true
+	case strings.HasPrefix(typ, "timestamp"):
+		return "2024-01-01T00:00:00Z"
+	case typ == "date":
+		return "2024-01-01"
+	case typ == "uuid":
+		return "00000000-0000-0000-0000-000000000000"
+	case strings.HasPrefix(typ, "json"):
+		return map[string]any{}
+	default:
+		return "example_" + name
+	}
+}
@@ -0,0 +1,86 @@
+package edamame
+
+import (
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestExampleParams(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	t.Run("select by id", func(t *testing.T) {
+		example, err := factory.ExampleParams(selectByID)
+		if err != nil {
+			t.Fatalf("ExampleParams() failed: %v", err)
+		}
+		if len(example) != 1 {
+			t.Fatalf("expected 1 param, got %v", example)
+		}
+		if _, ok := example["id"].(int); !ok {
+			t.Errorf("expected int id, got %T", example["id"])
+		}
+	})
+
+	t.Run("typed from columns", func(t *testing.T) {
+		stmt := NewQueryStatement("search", "Search", QuerySpec{
+			Where: []ConditionSpec{
+				{Field: "email", Operator: "=", Param: "email"},
+				{Field: "age", Between: true, LowParam: "min_age", HighParam: "max_age"},
+				{Field: "id", Operator: "IN", Param: "ids"},
+			},
+			LimitParam: "limit",
+		})
+		example, err := factory.ExampleParams(stmt)
+		if err != nil {
+			t.Fatalf("ExampleParams() failed: %v", err)
+		}
+		if example["email"] != "example_email" {
+			t.Errorf("expected string email, got %v", example["email"])
+		}
+		if example["min_age"] != 1 || example["max_age"] != 1 || example["limit"] != 1 {
+			t.Errorf("expected int bounds and limit, got %v", example)
+		}
+		if ids, ok := example["ids"].([]any); !ok || len(ids) != 1 || ids[0] != 1 {
+			t.Errorf("expected int array for IN param, got %v", example["ids"])
+		}
+	})
+
+	t.Run("update set params", func(t *testing.T) {
+		stmt := NewUpdateStatement("rename", "Rename", UpdateSpec{
+			Set:   map[string]string{"name": "new_name"},
+			Where: []ConditionSpec{{Field: "id", Operator: "=", Param: "id"}},
+		})
+		example, err := factory.ExampleParams(stmt)
+		if err != nil {
+			t.Fatalf("ExampleParams() failed: %v", err)
+		}
+		if example["new_name"] != "example_new_name" || example["id"] != 1 {
+			t.Errorf("unexpected example: %v", example)
+		}
+	})
+}
+
+func TestExampleValue(t *testing.T) {
+	tests := []struct {
+		typ  string
+		want any
+	}{
+		{"bigint", 1},
+		{"serial", 1},
+		{"interval", "1 day"},
+		{"numeric(10,2)", 1.5},
+		{"boolean", true},
+		{"timestamptz", "2024-01-01T00:00:00Z"},
+		{"uuid", "00000000-0000-0000-0000-000000000000"},
+		{"any", "example_p"},
+	}
+	for _, tt := range tests {
+		if got := exampleValue(tt.typ, "p"); got != tt.want {
+			t.Errorf("exampleValue(%q) = %v, want %v", tt.typ, got, tt.want)
+		}
+	}
+}
@@ -42,6 +42,10 @@ package edamame
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/zoobzio/astql"
@@ -52,23 +56,94 @@ import (
 // Executor provides a statement-driven query API for a specific model type.
 // It wraps soy with typed statements for compile-time safety.
 type Executor[T any] struct {
-	db  sqlx.ExtContext
-	soy *soy.Soy[T]
+	db       sqlx.ExtContext
+	router   *routedDB // wraps db for read routing, nil when db is nil
+	soy      *soy.Soy[T]
+	renderer astql.Renderer    // the renderer soy uses, wrapped when the table is schema-qualified
+	schema   string            // schema of a schema-qualified table name, empty otherwise
+	table    string            // table name without its schema
+	columns  map[string][]int  // db column name -> struct field index
+	goFields map[string]string // Go field name -> db column name
+	pk       string            // primary key column, empty if none is tagged
+
+	sqlComments       atomic.Bool
+	noParamValidation atomic.Bool // set by SetParamValidation(false)
+	paramCoercion     atomic.Bool // set by SetParamCoercion
+	readOnly          atomic.Bool // set by SetReadOnly
+	strictFields      atomic.Bool // set by SetStrictFields
+
+	mu          sync.RWMutex
+	dedupFields []string
+	tieBreaker  bool
+	softDelete  string                     // soft-delete column, empty if disabled
+	deleteScope map[string]bool            // set by SetSoftDeleteScope, nil for the default scope
+	restore     *UpdateStatement           // registered by EnableSoftDelete, nil without a primary key
+	upsert      *lastWriteWins             // set by SetLastWriteWinsUpsert
+	mapper      func(string) string        // set by SetColumnMapper, nil for the db tag default
+	eventAttrs  []capitan.Field            // appended to emitted events, set by SetEventAttributes
+	fragments   map[string][]ConditionSpec // set by DefineConditionFragment
+	subqueries  map[string]SubquerySource  // set by RegisterSubquerySource, keyed by table name
+	assertions  []func(*T) error
+	notifier    *writeNotifier[T] // set by SetNotifyOnWrite
+
+	queryable      map[string]bool // set by SetQueryableFields, nil for no allowlist
+	maxLimitParam  int             // set by SetPageParamLimits, 0 for no maximum
+	maxOffsetParam int
+
+	paramDefaults map[string]map[string]any // set by SetParamDefault, keyed by statement name then param
+
+	defaultTimeout time.Duration            // set by SetDefaultTimeout, 0 for none
+	timeouts       map[string]time.Duration // set by SetStatementTimeout, keyed by statement name
 }
 
 // New creates a new Executor for type T with the given database connection, table name, and renderer.
 //
 // The db parameter accepts sqlx.ExtContext, which is satisfied by both *sqlx.DB and *sqlx.Tx,
 // enabling transaction support by passing a transaction instead of a database connection.
+//
+// The table name may be qualified with a schema, such as "analytics.events"; the schema
+// and table are quoted independently in rendered SQL. Each part must be a plain identifier.
 func New[T any](db sqlx.ExtContext, tableName string, renderer astql.Renderer) (*Executor[T], error) {
-	c, err := soy.New[T](db, tableName, renderer)
+	schema, table, err := splitQualifiedName("table", tableName)
+	if err != nil {
+		return nil, err
+	}
+	var qualified *qualifiedRenderer
+	if schema != "" && renderer != nil {
+		qualified = &qualifiedRenderer{Renderer: renderer}
+		renderer = qualified
+	}
+
+	var router *routedDB
+	execer := db
+	if db != nil {
+		router = &routedDB{primary: db}
+		execer = router
+	}
+
+	c, err := soy.New[T](execer, tableName, renderer)
 	if err != nil {
 		return nil, fmt.Errorf("edamame: failed to create soy instance: %w", err)
 	}
+	if qualified != nil {
+		if err := qualified.init(c.Instance(), tableName, schema, table); err != nil {
+			return nil, err
+		}
+	}
 
 	e := &Executor[T]{
-		db:  db,
-		soy: c,
+		db:       db,
+		router:   router,
+		soy:      c,
+		renderer: renderer,
+		schema:   schema,
+		table:    table,
+		columns:  columnIndex(c),
+		pk:       primaryKeyColumn(c),
+	}
+	e.goFields = e.fieldColumns()
+	if router != nil {
+		router.annotate = e.annotateSQL
 	}
 
 	capitan.Emit(context.Background(), ExecutorCreated,
@@ -77,16 +152,178 @@ func New[T any](db sqlx.ExtContext, tableName string, renderer astql.Renderer) (
 	return e, nil
 }
 
+// columnIndex maps each db-tagged column of T to its struct field index.
+func columnIndex[T any](c *soy.Soy[T]) map[string][]int {
+	fields := c.Metadata().Fields
+	columns := make(map[string][]int, len(fields))
+	for _, f := range fields {
+		col := f.Tags["db"]
+		if col == "" || col == "-" {
+			continue
+		}
+		columns[col] = f.Index
+	}
+	return columns
+}
+
+// primaryKeyColumn returns the first db column of T tagged with the primarykey constraint.
+func primaryKeyColumn[T any](c *soy.Soy[T]) string {
+	for _, f := range c.Metadata().Fields {
+		if isPrimaryKey(f.Tags["constraints"]) {
+			return f.Tags["db"]
+		}
+	}
+	return ""
+}
+
+// isPrimaryKey reports whether a constraints tag declares a primary key.
+func isPrimaryKey(constraints string) bool {
+	for _, constraint := range strings.Split(constraints, ",") {
+		switch strings.ToLower(strings.TrimSpace(constraint)) {
+		case "primarykey", "primary_key":
+			return true
+		}
+	}
+	return false
+}
+
+// SetOrderByTieBreaker appends the primary key to the ORDER BY of query and select
+// statements that already order their results, unless the key is ordered on explicitly.
+// This gives paginated queries over non-unique sort keys a stable total order.
+// Statements with GROUP BY are left untouched.
+func (e *Executor[T]) SetOrderByTieBreaker(enabled bool) error {
+	if enabled && e.pk == "" {
+		return fmt.Errorf("edamame: no primary key column for tie-breaker")
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.tieBreaker = enabled
+	return nil
+}
+
+// tieBreakerColumn returns the column to append to orderBy, or empty if none is needed.
+func (e *Executor[T]) tieBreakerColumn(orderBy []OrderBySpec, groupBy []string) string {
+	e.mu.RLock()
+	enabled := e.tieBreaker
+	e.mu.RUnlock()
+
+	if !enabled || len(orderBy) == 0 || len(groupBy) > 0 {
+		return ""
+	}
+	for _, o := range orderBy {
+		if o.Field == e.pk && !o.IsExpression() {
+			return ""
+		}
+	}
+	return e.pk
+}
+
+// SetResultDedup enables Go-side de-duplication of ExecQuery results.
+// Rows sharing the same values for keyFields are collapsed, keeping the first occurrence.
+// This is a fallback for dialects without DISTINCT ON: all rows are still fetched and
+// held in memory before filtering, so prefer QuerySpec.DistinctOn where supported.
+// Calling it with no fields disables de-duplication.
+func (e *Executor[T]) SetResultDedup(keyFields ...string) error {
+	for _, f := range keyFields {
+		if _, ok := e.columns[f]; !ok {
+			return fmt.Errorf("edamame: unknown dedup field %q", f)
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.dedupFields = append([]string(nil), keyFields...)
+	return nil
+}
+
+// AddResultAssertion registers an invariant checked against every row returned by
+// ExecQuery and ExecSelect (and their Tx variants). The first failing assertion
+// aborts the call and its error is returned instead of the rows.
+func (e *Executor[T]) AddResultAssertion(fn func(*T) error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.assertions = append(e.assertions, fn)
+}
+
+// SetEventAttributes sets static fields, such as a service name or environment, that are
+// appended to every event the executor emits from now on. ExecutorCreated is emitted by
+// New before attributes can be set and does not carry them. Calling it again replaces
+// the previous attributes; calling it with none clears them.
+//
+// Example:
+//
+//	var KeyService = capitan.NewStringKey("service")
+//	exec.SetEventAttributes(KeyService.Field("billing"))
+func (e *Executor[T]) SetEventAttributes(attrs ...capitan.Field) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.eventAttrs = append([]capitan.Field(nil), attrs...)
+}
+
+// eventAttributes returns the fields set by SetEventAttributes.
+func (e *Executor[T]) eventAttributes() []capitan.Field {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.eventAttrs
+}
+
 // Soy returns the underlying soy instance for advanced usage.
 func (e *Executor[T]) Soy() *soy.Soy[T] {
 	return e.soy
 }
 
-// TableName returns the table name for this executor.
+// TableName returns the table name for this executor as passed to New, including
+// any schema. SchemaName and BaseTableName return its parts.
 func (e *Executor[T]) TableName() string {
 	return e.soy.TableName()
 }
 
+// OutputColumns returns the columns a statement's result rows carry, in SELECT order:
+// the spec's Fields (renamed by FieldAliases) followed by SelectExprs aliases, or every schema column when neither
+// is set. Update statements return every column. Delete and aggregate statements have no
+// row output and return an error.
+func (e *Executor[T]) OutputColumns(stmt Statement) ([]string, error) {
+	switch s := stmt.(type) {
+	case QueryStatement:
+		return e.selectedColumns(aliasedColumns(e.columnList(s.spec.Fields), e.columnKeys(s.spec.FieldAliases)), s.spec.SelectExprs), nil
+	case SelectStatement:
+		return e.selectedColumns(aliasedColumns(e.columnList(s.spec.Fields), e.columnKeys(s.spec.FieldAliases)), s.spec.SelectExprs), nil
+	case UpdateStatement:
+		return e.schemaColumns(), nil
+	default:
+		return nil, fmt.Errorf("edamame: statement type %T has no output columns", stmt)
+	}
+}
+
+// selectedColumns mirrors soy's SELECT list: explicit fields and expression aliases,
+// falling back to SELECT * only when both are empty.
+func (e *Executor[T]) selectedColumns(fields []string, exprs []SelectExprSpec) []string {
+	if len(fields) == 0 && len(exprs) == 0 {
+		return e.schemaColumns()
+	}
+	columns := make([]string, 0, len(fields)+len(exprs))
+	columns = append(columns, fields...)
+	for _, expr := range exprs {
+		columns = append(columns, expr.Alias)
+	}
+	return columns
+}
+
+// schemaColumns returns every db-tagged column of T in struct field order.
+func (e *Executor[T]) schemaColumns() []string {
+	fields := e.soy.Metadata().Fields
+	columns := make([]string, 0, len(fields))
+	for _, f := range fields {
+		col := f.Tags["db"]
+		if col == "" || col == "-" {
+			continue
+		}
+		columns = append(columns, col)
+	}
+	return columns
+}
+
 // RenderQuery renders a query statement to SQL for inspection or debugging.
 func (e *Executor[T]) RenderQuery(stmt QueryStatement) (string, error) {
 	q, err := e.queryFromSpec(stmt.spec)
@@ -97,7 +334,11 @@ func (e *Executor[T]) RenderQuery(stmt QueryStatement) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return result.SQL, nil
+	sql, _, err := e.finalizeSQL(result.SQL, e.queryRewrites(stmt.spec))
+	if err != nil {
+		return "", err
+	}
+	return e.annotateRendered(sql, stmt.name, "query"), nil
 }
 
 // RenderSelect renders a select statement to SQL for inspection or debugging.
@@ -110,49 +351,65 @@ func (e *Executor[T]) RenderSelect(stmt SelectStatement) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return result.SQL, nil
+	sql, _, err := e.finalizeSQL(result.SQL, e.selectRewrites(stmt.spec))
+	if err != nil {
+		return "", err
+	}
+	return e.annotateRendered(sql, stmt.name, "select"), nil
 }
 
 // RenderUpdate renders an update statement to SQL for inspection or debugging.
 func (e *Executor[T]) RenderUpdate(stmt UpdateStatement) (string, error) {
+	if err := e.checkStatementFields(stmt); err != nil {
+		return "", err
+	}
+	if err := e.checkSoyConditions(stmt); err != nil {
+		return "", err
+	}
+	if needsUpdateRewrite(stmt.spec) {
+		sql, err := e.renderUpdate(stmt.spec)
+		if err != nil {
+			return "", err
+		}
+		return e.annotateRendered(sql, stmt.name, "update"), nil
+	}
 	u := e.modifyFromSpec(stmt.spec)
 	result, err := u.Render()
 	if err != nil {
 		return "", err
 	}
-	return result.SQL, nil
+	return e.annotateRendered(result.SQL, stmt.name, "update"), nil
 }
 
 // RenderDelete renders a delete statement to SQL for inspection or debugging.
 func (e *Executor[T]) RenderDelete(stmt DeleteStatement) (string, error) {
+	if err := e.checkStatementFields(stmt); err != nil {
+		return "", err
+	}
+	if err := e.checkSoyConditions(stmt); err != nil {
+		return "", err
+	}
 	d := e.removeFromSpec(stmt.spec)
 	result, err := d.Render()
 	if err != nil {
 		return "", err
 	}
-	return result.SQL, nil
+	return e.annotateRendered(result.SQL, stmt.name, "delete"), nil
 }
 
 // RenderAggregate renders an aggregate statement to SQL for inspection or debugging.
 func (e *Executor[T]) RenderAggregate(stmt AggregateStatement) (string, error) {
-	var agg *soy.Aggregate[T]
-	switch stmt.fn {
-	case AggSum:
-		agg = e.sumFromSpec(stmt.spec)
-	case AggAvg:
-		agg = e.avgFromSpec(stmt.spec)
-	case AggMin:
-		agg = e.minFromSpec(stmt.spec)
-	case AggMax:
-		agg = e.maxFromSpec(stmt.spec)
-	default:
-		agg = e.countFromSpec(stmt.spec)
+	if err := e.checkStatementFields(stmt); err != nil {
+		return "", err
 	}
-	result, err := agg.Render()
+	if err := e.checkSoyConditions(stmt); err != nil {
+		return "", err
+	}
+	sql, err := e.renderAggregate(stmt)
 	if err != nil {
 		return "", err
 	}
-	return result.SQL, nil
+	return e.annotateRendered(sql, stmt.name, "aggregate"), nil
 }
 
 // RenderCompound renders a compound query to SQL for inspection or debugging.
@@ -165,5 +422,68 @@ func (e *Executor[T]) RenderCompound(spec CompoundQuerySpec) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return result.SQL, nil
+	return e.annotateRendered(result.SQL, "", "compound"), nil
+}
+
+// RenderInsert renders the INSERT ExecInsert runs to SQL for inspection or debugging,
+// with a named placeholder for each inserted column.
+func (e *Executor[T]) RenderInsert() (string, error) {
+	var result *astql.QueryResult
+	var err error
+	if len(e.GeneratedColumns()) > 0 {
+		result, err = e.renderInsertReturning(e.schemaColumns())
+	} else {
+		result, err = e.Insert().Render()
+	}
+	if err != nil {
+		return "", err
+	}
+	return e.annotateRendered(result.SQL, "", "insert"), nil
+}
+
+// RenderInsertSpec renders an INSERT with the conflict handling of spec to SQL for
+// inspection or debugging.
+func (e *Executor[T]) RenderInsertSpec(spec CreateSpec) (string, error) {
+	c, err := e.insertFromSpec(spec)
+	if err != nil {
+		return "", err
+	}
+	result, err := c.Render()
+	if err != nil {
+		return "", err
+	}
+	return e.annotateRendered(result.SQL, "", "insert"), nil
+}
+
+// RenderStatement renders any statement type to SQL for inspection or debugging.
+func (e *Executor[T]) RenderStatement(stmt Statement) (string, error) {
+	switch s := stmt.(type) {
+	case QueryStatement:
+		return e.RenderQuery(s)
+	case SelectStatement:
+		return e.RenderSelect(s)
+	case UpdateStatement:
+		return e.RenderUpdate(s)
+	case DeleteStatement:
+		return e.RenderDelete(s)
+	case AggregateStatement:
+		return e.RenderAggregate(s)
+	default:
+		return "", fmt.Errorf("unsupported statement type %T", stmt)
+	}
+}
+
+// Prepare renders every given statement against this executor's schema,
+// returning the first failure annotated with the statement name.
+// Call it at startup to surface invalid specs before they are first executed.
+func (e *Executor[T]) Prepare(stmts ...Statement) error {
+	for _, stmt := range stmts {
+		if _, err := e.StatementParams(stmt); err != nil {
+			return err
+		}
+		if _, err := e.RenderStatement(stmt); err != nil {
+			return fmt.Errorf("edamame: statement %q: %w", stmt.Name(), err)
+		}
+	}
+	return nil
 }
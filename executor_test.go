@@ -1,6 +1,7 @@
 package edamame
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/zoobzio/astql/pkg/postgres"
@@ -105,3 +106,149 @@ func TestSoyAccessor(t *testing.T) {
 		t.Errorf("Soy().TableName() = %q, want %q", c.TableName(), "users")
 	}
 }
+
+func TestPrepare(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	valid := []Statement{
+		NewQueryStatement("all", "All users", QuerySpec{}),
+		NewSelectStatement("by-id", "By ID", SelectSpec{
+			Where: []ConditionSpec{{Field: "id", Operator: "=", Param: "id"}},
+		}),
+		NewUpdateStatement("rename", "Rename", UpdateSpec{
+			Set:   map[string]string{"name": "name"},
+			Where: []ConditionSpec{{Field: "id", Operator: "=", Param: "id"}},
+		}),
+		NewDeleteStatement("remove", "Remove", DeleteSpec{
+			Where: []ConditionSpec{{Field: "id", Operator: "=", Param: "id"}},
+		}),
+		NewAggregateStatement("count", "Count", AggCount, AggregateSpec{}),
+	}
+	if err := factory.Prepare(valid...); err != nil {
+		t.Fatalf("Prepare() failed for valid statements: %v", err)
+	}
+
+	invalid := NewQueryStatement("broken", "Typo in field", QuerySpec{
+		Where: []ConditionSpec{{Field: "nmae", Operator: "=", Param: "name"}},
+	})
+	err = factory.Prepare(append(valid, invalid)...)
+	if err == nil {
+		t.Fatal("Prepare() should fail for an invalid statement")
+	}
+	if !strings.Contains(err.Error(), `"broken"`) {
+		t.Errorf("error should name the failing statement: %v", err)
+	}
+}
+
+func TestSetOrderByTieBreaker(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if err := factory.SetOrderByTieBreaker(true); err != nil {
+		t.Fatalf("SetOrderByTieBreaker() failed: %v", err)
+	}
+
+	byName := NewQueryStatement("by-name", "Users by name", QuerySpec{
+		OrderBy: []OrderBySpec{{Field: "name", Direction: "asc"}},
+	})
+	sql, err := factory.RenderQuery(byName)
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+	if !strings.Contains(sql, `ORDER BY "name" ASC, "id" ASC`) {
+		t.Errorf("expected primary key tie-breaker, got: %s", sql)
+	}
+
+	byID := NewQueryStatement("by-id", "Users by id", QuerySpec{
+		OrderBy: []OrderBySpec{{Field: "name", Direction: "asc"}, {Field: "id", Direction: "desc"}},
+	})
+	sql, err = factory.RenderQuery(byID)
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+	if strings.Count(sql, `"id"`) != 1 {
+		t.Errorf("primary key should not be appended twice, got: %s", sql)
+	}
+
+	unordered := NewQueryStatement("all", "All users", QuerySpec{})
+	sql, err = factory.RenderQuery(unordered)
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+	if strings.Contains(sql, "ORDER BY") {
+		t.Errorf("unordered query should stay unordered, got: %s", sql)
+	}
+
+	if err := factory.SetOrderByTieBreaker(false); err != nil {
+		t.Fatalf("SetOrderByTieBreaker(false) failed: %v", err)
+	}
+	sql, err = factory.RenderQuery(byName)
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+	if strings.Contains(sql, `"id" ASC`) {
+		t.Errorf("tie-breaker should be disabled, got: %s", sql)
+	}
+}
+
+func TestOutputColumns(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		stmt Statement
+		want []string
+	}{
+		{
+			name: "plain select",
+			stmt: NewSelectStatement("by-id", "By ID", SelectSpec{
+				Where: []ConditionSpec{{Field: "id", Operator: "=", Param: "id"}},
+			}),
+			want: []string{"id", "email", "name", "age"},
+		},
+		{
+			name: "field-limited query",
+			stmt: NewQueryStatement("emails", "Emails", QuerySpec{Fields: []string{"id", "email"}}),
+			want: []string{"id", "email"},
+		},
+		{
+			name: "computed expression alias",
+			stmt: NewQueryStatement("upper-names", "Upper names", QuerySpec{
+				Fields:      []string{"id"},
+				SelectExprs: []SelectExprSpec{{Func: "upper", Field: "name", Alias: "upper_name"}},
+			}),
+			want: []string{"id", "upper_name"},
+		},
+		{
+			name: "update returns every column",
+			stmt: NewUpdateStatement("rename", "Rename", UpdateSpec{
+				Set:   map[string]string{"name": "name"},
+				Where: []ConditionSpec{{Field: "id", Operator: "=", Param: "id"}},
+			}),
+			want: []string{"id", "email", "name", "age"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := factory.OutputColumns(tt.stmt)
+			if err != nil {
+				t.Fatalf("OutputColumns() failed: %v", err)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := factory.OutputColumns(NewAggregateStatement("count", "Count", AggCount, AggregateSpec{})); err == nil {
+		t.Error("OutputColumns() should fail for an aggregate statement")
+	}
+}
@@ -0,0 +1,347 @@
+package edamame
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// errExistsUnsupported is returned by execution paths that run soy's SQL
+// unmodified and so cannot render EXISTS subqueries or quantified subquery comparisons.
+var errExistsUnsupported = errors.New("edamame: EXISTS conditions and subquery comparisons are not supported by this method")
+
+// Names used in the SQL rendered for EXISTS subqueries.
+const (
+	existsParamPrefix = "edamame_exists_" // placeholder param soy renders in place of each subquery condition
+	subqueryAlias     = "edamame_sub"     // alias of the subquery's table, so a self-join does not shadow the outer table
+	defaultNamespace  = "sub"
+)
+
+// SubquerySource is a table EXISTS conditions and quantified subquery comparisons
+// can select from. It is implemented
+// by *Executor for any model type, which resolves and validates the subquery's
+// fields against its own schema and column mapper.
+type SubquerySource interface {
+	TableName() string
+	existsFilter(where []ConditionSpec, inner []string) (table, filter string, columns []string, err error)
+}
+
+// RegisterSubquerySource makes src's table available to EXISTS conditions and
+// quantified subquery comparisons whose From names it. Conditions without a From select from the executor's own table,
+// which needs no registration. Registering a table again replaces it.
+//
+// Example:
+//
+//	orders, _ := edamame.New[Order](db, "orders", postgres.New())
+//	users.RegisterSubquerySource(orders)
+//
+//	var WithOpenOrders = edamame.NewQueryStatement("with-open-orders", "Users with an open order", edamame.QuerySpec{
+//	    Where: []edamame.ConditionSpec{{
+//	        Exists:    true,
+//	        From:      "orders",
+//	        Subquery:  &edamame.QuerySpec{Where: []edamame.ConditionSpec{{Field: "status", Operator: "=", Param: "status"}}},
+//	        Correlate: []edamame.CorrelationSpec{{Outer: "id", Inner: "user_id"}},
+//	    }},
+//	})
+//
+//	// SELECT * FROM "users" WHERE EXISTS (SELECT 1 FROM "orders" AS edamame_sub
+//	//     WHERE "status" = :sub_status AND "user_id" = "users"."id")
+func (e *Executor[T]) RegisterSubquerySource(src SubquerySource) error {
+	if src == nil {
+		return fmt.Errorf("edamame: subquery source must not be nil")
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.subqueries == nil {
+		e.subqueries = make(map[string]SubquerySource)
+	}
+	e.subqueries[src.TableName()] = src
+	return nil
+}
+
+// subquerySource returns the source an EXISTS condition selects from.
+func (e *Executor[T]) subquerySource(from string) (SubquerySource, error) {
+	if from == "" || from == e.TableName() {
+		return e, nil
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	src, ok := e.subqueries[from]
+	if !ok {
+		return nil, fmt.Errorf("edamame: EXISTS subquery table %q is not registered", from)
+	}
+	return src, nil
+}
+
+// hasExists reports whether any condition, including nested groups, selects from a
+// subquery: an EXISTS condition or a quantified subquery comparison.
+func hasExists(conditions []ConditionSpec) bool {
+	for _, c := range conditions {
+		if c.IsSubquery() || (c.IsGroup() && hasExists(c.Group)) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkExists validates the EXISTS conditions and quantified subquery comparisons of
+// a query or select spec: they may only appear in WHERE, and each needs a subquery
+// whose conditions edamame can render inside it.
+func checkExists(where, having, outerWhere []ConditionSpec) error {
+	if hasExists(having) || hasExists(outerWhere) {
+		return fmt.Errorf("EXISTS conditions and subquery comparisons are only supported in WHERE")
+	}
+	for _, c := range where {
+		switch {
+		case c.IsGroup():
+			if err := checkExists(c.Group, nil, nil); err != nil {
+				return err
+			}
+		case c.IsSubquery():
+			if err := checkExistsCondition(c); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkExistsCondition validates a single EXISTS condition or quantified subquery comparison.
+func checkExistsCondition(c ConditionSpec) error {
+	if c.Exists && c.NotExists {
+		return fmt.Errorf("condition cannot set both exists and not_exists")
+	}
+	if c.Subquery == nil {
+		return fmt.Errorf("EXISTS condition requires a subquery")
+	}
+	if c.IsQuantifiedSubquery() {
+		if err := checkQuantifiedSubquery(c); err != nil {
+			return err
+		}
+	}
+	for _, sub := range c.Subquery.Where {
+		switch {
+		case hasExists([]ConditionSpec{sub}):
+			return fmt.Errorf("EXISTS subqueries cannot be nested")
+		case hasFragment([]ConditionSpec{sub}):
+			return fmt.Errorf("EXISTS subqueries cannot reference condition fragments")
+		case hasDistinctFrom([]ConditionSpec{sub}):
+			return fmt.Errorf("EXISTS subqueries cannot hold null-safe comparisons")
+		case hasQuantified([]ConditionSpec{sub}):
+			return fmt.Errorf("EXISTS subqueries cannot hold ANY or ALL comparisons")
+		case hasJSONPath([]ConditionSpec{sub}):
+			return fmt.Errorf("EXISTS subqueries cannot hold JSONB path conditions")
+		case hasFullText([]ConditionSpec{sub}):
+			return fmt.Errorf("EXISTS subqueries cannot hold full-text conditions")
+		}
+	}
+	for _, pair := range c.Correlate {
+		if pair.Outer == "" || pair.Inner == "" {
+			return fmt.Errorf("EXISTS correlation requires both outer and inner fields")
+		}
+	}
+	return nil
+}
+
+// existsNamespace returns the prefix of the params of c's subquery.
+func (c ConditionSpec) existsNamespace() string {
+	if c.Namespace == "" {
+		return defaultNamespace + "_"
+	}
+	return c.Namespace + "_"
+}
+
+// subqueryWhere returns the WHERE conditions of c's subquery with every param
+// prefixed by its namespace, so they cannot collide with the outer query's params.
+func (c ConditionSpec) subqueryWhere() []ConditionSpec {
+	if c.Subquery == nil {
+		return nil
+	}
+	return prefixParams(c.Subquery.Where, c.existsNamespace())
+}
+
+// prefixParams returns conditions with prefix prepended to each param name, recursing into groups.
+func prefixParams(conditions []ConditionSpec, prefix string) []ConditionSpec {
+	if conditions == nil {
+		return nil
+	}
+	prefixed := make([]ConditionSpec, len(conditions))
+	for i, c := range conditions {
+		if c.Param != "" {
+			c.Param = prefix + c.Param
+		}
+		if c.LowParam != "" {
+			c.LowParam = prefix + c.LowParam
+		}
+		if c.HighParam != "" {
+			c.HighParam = prefix + c.HighParam
+		}
+		c.Group = prefixParams(c.Group, prefix)
+		prefixed[i] = c
+	}
+	return prefixed
+}
+
+// withExistsPlaceholders returns conditions with each EXISTS condition and quantified
+// subquery comparison swapped for a comparison of standIn against a numbered
+// placeholder param, to be replaced by rewriteExists.
+func withExistsPlaceholders(conditions []ConditionSpec, standIn string) []ConditionSpec {
+	if !hasExists(conditions) {
+		return conditions
+	}
+	n := 0
+	return replaceExists(conditions, standIn, &n)
+}
+
+// replaceExists implements withExistsPlaceholders, numbering placeholders depth-first from *n.
+func replaceExists(conditions []ConditionSpec, standIn string, n *int) []ConditionSpec {
+	replaced := make([]ConditionSpec, len(conditions))
+	for i, c := range conditions {
+		switch {
+		case c.IsSubquery():
+			c = ConditionSpec{Field: standIn, Operator: "=", Param: existsParamPrefix + strconv.Itoa(*n)}
+			*n++
+		case c.IsGroup():
+			c.Group = replaceExists(c.Group, standIn, n)
+		}
+		replaced[i] = c
+	}
+	return replaced
+}
+
+// collectExists returns the EXISTS conditions and quantified subquery comparisons in
+// conditions, depth-first, in the order withExistsPlaceholders numbers them.
+func collectExists(conditions []ConditionSpec, found []ConditionSpec) []ConditionSpec {
+	for _, c := range conditions {
+		switch {
+		case c.IsSubquery():
+			found = append(found, c)
+		case c.IsGroup():
+			found = collectExists(c.Group, found)
+		}
+	}
+	return found
+}
+
+// rewriteExists replaces the placeholder comparisons soy rendered for EXISTS conditions
+// and quantified subquery comparisons with the subqueries. soy has neither, so each
+// subquery's filter is rendered by its source and the condition is assembled here.
+func (e *Executor[T]) rewriteExists(sql string, conditions []ConditionSpec) (string, error) {
+	if !hasExists(conditions) {
+		return sql, nil
+	}
+	standIn := e.schemaColumns()[0]
+	for i, c := range collectExists(conditions, nil) {
+		subquery, err := e.existsSQL(c)
+		if err != nil {
+			return "", err
+		}
+		placeholder, err := e.conditionSQL(ConditionSpec{Field: standIn, Operator: "=", Param: existsParamPrefix + strconv.Itoa(i)})
+		if err != nil {
+			return "", err
+		}
+		found := placeholderOffsets(sql, placeholder)
+		if len(found) != 1 {
+			return "", fmt.Errorf("edamame: cannot place subquery condition %d: rendered %d placeholders, expected 1", i, len(found))
+		}
+		sql = sql[:found[0]] + subquery + sql[found[0]+len(placeholder):]
+	}
+	return sql, nil
+}
+
+// existsSQL renders c as an [NOT] EXISTS condition or, for a quantified subquery
+// comparison, as Field Operator ANY (subquery) or ALL (subquery).
+func (e *Executor[T]) existsSQL(c ConditionSpec) (string, error) {
+	if !c.IsQuantifiedSubquery() {
+		subquery, err := e.subquerySQL(c, "")
+		if err != nil {
+			return "", err
+		}
+		if c.NotExists {
+			return "NOT EXISTS (" + subquery + ")", nil
+		}
+		return "EXISTS (" + subquery + ")", nil
+	}
+	col := e.column(c.Field)
+	if _, ok := e.columns[col]; !ok {
+		return "", fmt.Errorf("edamame: unknown quantified subquery field %q", c.Field)
+	}
+	subquery, err := e.subquerySQL(c, c.Subquery.Fields[0])
+	if err != nil {
+		return "", err
+	}
+	return e.quoteIdent(col) + " " + c.Operator + " " + strings.ToUpper(c.Quantifier) + " (" + subquery + ")", nil
+}
+
+// subquerySQL renders c's subquery, correlated to the outer row, as a SELECT of the
+// subquery table's selected field, or of 1 when selected is empty.
+func (e *Executor[T]) subquerySQL(c ConditionSpec, selected string) (string, error) {
+	src, err := e.subquerySource(c.From)
+	if err != nil {
+		return "", err
+	}
+	inner := make([]string, len(c.Correlate), len(c.Correlate)+1)
+	outer := make([]string, len(c.Correlate))
+	for i, pair := range c.Correlate {
+		inner[i] = pair.Inner
+		outer[i] = e.column(pair.Outer)
+		if _, ok := e.columns[outer[i]]; !ok {
+			return "", fmt.Errorf("edamame: unknown EXISTS correlation field %q", pair.Outer)
+		}
+	}
+	if selected != "" {
+		// The source resolves the selected field with the correlation fields, as the last column.
+		inner = append(inner, selected)
+	}
+	table, filter, columns, err := src.existsFilter(c.subqueryWhere(), inner)
+	if err != nil {
+		return "", err
+	}
+	target := "1"
+	if selected != "" {
+		target = e.quoteIdent(columns[len(columns)-1])
+		columns = columns[:len(columns)-1]
+	}
+
+	parts := make([]string, 0, len(columns)+1)
+	if filter != "" {
+		parts = append(parts, filter)
+	}
+	for i, col := range columns {
+		parts = append(parts, e.quoteIdent(col)+" = "+e.quotedTableName()+"."+e.quoteIdent(outer[i]))
+	}
+	sql := "SELECT " + target + " FROM " + table + " AS " + subqueryAlias
+	if len(parts) > 0 {
+		sql += " WHERE " + strings.Join(parts, " AND ")
+	}
+	return sql, nil
+}
+
+// existsFilter renders where as the WHERE of a subquery on this executor's table,
+// excluding soft-deleted rows, and resolves the inner correlation fields, and the field
+// a quantified comparison selects, to columns. It returns the quoted table, the filter SQL (empty when there is none) and the columns.
+func (e *Executor[T]) existsFilter(where []ConditionSpec, inner []string) (table, filter string, columns []string, err error) {
+	columns = make([]string, len(inner))
+	for i, field := range inner {
+		columns[i] = e.column(field)
+		if _, ok := e.columns[columns[i]]; !ok {
+			return "", "", nil, fmt.Errorf("edamame: unknown subquery field %q on %q", field, e.TableName())
+		}
+	}
+
+	q := e.soy.Query()
+	for _, c := range e.mapConditions(where) {
+		q = applyConditionToQuery(q, c)
+	}
+	if col := e.softDeleteColumn(opQuery); col != "" {
+		q = q.WhereNull(col)
+	}
+	result, err := q.Render()
+	if err != nil {
+		return "", "", nil, fmt.Errorf("edamame: invalid subquery on %q: %w", e.TableName(), err)
+	}
+	_, filter, _ = strings.Cut(result.SQL, " WHERE ")
+	return e.quotedTableName(), filter, columns, nil
+}
@@ -0,0 +1,177 @@
+package edamame
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/mariadb"
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+type Order struct {
+	ID     int    `db:"id" type:"integer" constraints:"primarykey"`
+	UserID int    `db:"user_id" type:"integer"`
+	Status string `db:"status" type:"text"`
+}
+
+func newExistsExecutors(t *testing.T) *Executor[User] {
+	t.Helper()
+	users, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	orders, err := New[Order](nil, "orders", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if err := users.RegisterSubquerySource(orders); err != nil {
+		t.Fatalf("RegisterSubquerySource() failed: %v", err)
+	}
+	return users
+}
+
+func TestRenderQuery_Exists(t *testing.T) {
+	users := newExistsExecutors(t)
+	stmt := NewQueryStatement("with-orders", "Users with an order in a status", QuerySpec{
+		Where: []ConditionSpec{
+			{Field: "age", Operator: ">=", Param: "status"},
+			{
+				Exists:    true,
+				From:      "orders",
+				Subquery:  &QuerySpec{Where: []ConditionSpec{{Field: "Status", Operator: "=", Param: "status"}}},
+				Correlate: []CorrelationSpec{{Outer: "ID", Inner: "UserID"}},
+			},
+		},
+	})
+
+	sql, err := users.RenderQuery(stmt)
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+	want := `SELECT * FROM "users" WHERE ("age" >= :status AND EXISTS (SELECT 1 FROM "orders" AS edamame_sub` +
+		` WHERE "status" = :sub_status AND "user_id" = "users"."id"))`
+	if sql != want {
+		t.Errorf("expected %s, got %s", want, sql)
+	}
+
+	params := stmt.Params()
+	if len(params) != 2 || params[0].Name != "status" || params[1].Name != "sub_status" {
+		t.Errorf("expected params status and sub_status, got %+v", params)
+	}
+}
+
+func TestRenderQuery_ExistsMariaDB(t *testing.T) {
+	users, err := New[User](nil, "users", mariadb.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	orders, err := New[Order](nil, "orders", mariadb.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if err := users.RegisterSubquerySource(orders); err != nil {
+		t.Fatalf("RegisterSubquerySource() failed: %v", err)
+	}
+	stmt := NewQueryStatement("with-orders", "Users with an order", QuerySpec{Where: []ConditionSpec{{
+		Exists:    true,
+		From:      "orders",
+		Subquery:  &QuerySpec{Where: []ConditionSpec{{Field: "status", Operator: "=", Param: "status"}}},
+		Correlate: []CorrelationSpec{{Outer: "id", Inner: "user_id"}},
+	}}})
+
+	sql, err := users.RenderQuery(stmt)
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+	want := "EXISTS (SELECT 1 FROM `orders` AS edamame_sub WHERE `status` = :sub_status AND `user_id` = `users`.`id`)"
+	if !strings.HasSuffix(sql, want) {
+		t.Errorf("expected SQL ending in %s, got %s", want, sql)
+	}
+}
+
+func TestRenderSelect_NotExistsOwnTableInGroup(t *testing.T) {
+	users := newExistsExecutors(t)
+	sql, err := users.RenderSelect(NewSelectStatement("lonely", "User with no namesake", SelectSpec{
+		Where: []ConditionSpec{{Logic: "OR", Group: []ConditionSpec{
+			{Field: "email", Operator: "=", Param: "email"},
+			{
+				NotExists: true,
+				Namespace: "twin",
+				Subquery:  &QuerySpec{Where: []ConditionSpec{{Field: "id", Operator: "!=", Param: "id"}}},
+				Correlate: []CorrelationSpec{{Outer: "name", Inner: "name"}},
+			},
+		}}},
+	}))
+	if err != nil {
+		t.Fatalf("RenderSelect() failed: %v", err)
+	}
+	want := `("email" = :email OR NOT EXISTS (SELECT 1 FROM "users" AS edamame_sub WHERE "id" != :twin_id AND "name" = "users"."name"))`
+	if !strings.HasSuffix(sql, want) {
+		t.Errorf("expected suffix %s, got %s", want, sql)
+	}
+}
+
+func TestExists_Errors(t *testing.T) {
+	users := newExistsExecutors(t)
+	sub := &QuerySpec{Where: []ConditionSpec{{Field: "status", Operator: "=", Param: "status"}}}
+	tests := []struct {
+		name string
+		cond ConditionSpec
+		want string
+	}{
+		{"no subquery", ConditionSpec{Exists: true, From: "orders"}, "requires a subquery"},
+		{"both", ConditionSpec{Exists: true, NotExists: true, From: "orders", Subquery: sub}, "both exists and not_exists"},
+		{"unregistered", ConditionSpec{Exists: true, From: "invoices", Subquery: sub}, `"invoices" is not registered`},
+		{"unknown inner", ConditionSpec{Exists: true, From: "orders", Subquery: sub, Correlate: []CorrelationSpec{{Outer: "id", Inner: "owner"}}}, `"owner"`},
+		{"unknown outer", ConditionSpec{Exists: true, From: "orders", Subquery: sub, Correlate: []CorrelationSpec{{Outer: "owner", Inner: "user_id"}}}, `"owner"`},
+		{"nested", ConditionSpec{Exists: true, From: "orders", Subquery: &QuerySpec{Where: []ConditionSpec{{Exists: true, Subquery: sub}}}}, "cannot be nested"},
+	}
+	for _, tt := range tests {
+		stmt := NewQueryStatement("q", "Query", QuerySpec{Where: []ConditionSpec{tt.cond}})
+		if _, err := users.RenderQuery(stmt); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+
+	if err := users.DefineConditionFragment("has-orders", []ConditionSpec{{Exists: true, Subquery: sub}}); err == nil {
+		t.Error("expected EXISTS in a fragment to be rejected")
+	}
+
+	const want = "EXISTS conditions are only supported in query and select statements"
+	where := []ConditionSpec{{Exists: true, From: "orders", Subquery: sub}}
+	if _, err := users.RenderDelete(NewDeleteStatement("d", "d", DeleteSpec{Where: where})); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("RenderDelete: expected %q, got %v", want, err)
+	}
+	if _, err := users.RenderUpdate(NewUpdateStatement("u", "u", UpdateSpec{Set: map[string]string{"name": "name"}, Where: where})); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("RenderUpdate: expected %q, got %v", want, err)
+	}
+	if _, err := users.RenderAggregate(NewAggregateStatement("a", "a", AggCount, AggregateSpec{Where: where})); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("RenderAggregate: expected %q, got %v", want, err)
+	}
+}
+
+func TestExecQuery_Exists(t *testing.T) {
+	db := &recordingDB{}
+	users, err := New[User](db, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	stmt := NewQueryStatement("referred", "Users referring someone", QuerySpec{Where: []ConditionSpec{{
+		Exists:    true,
+		Subquery:  &QuerySpec{},
+		Correlate: []CorrelationSpec{{Outer: "id", Inner: "age"}},
+	}}})
+
+	if _, err := users.ExecQuery(context.Background(), stmt, nil); err == nil {
+		t.Fatal("expected the recorded error")
+	}
+	if db.count() != 1 || !strings.HasSuffix(db.queries[0], `WHERE EXISTS (SELECT 1 FROM "users" AS edamame_sub WHERE "age" = "users"."id")`) {
+		t.Errorf("expected the EXISTS query, got %v", db.queries)
+	}
+
+	if _, err := users.ExecQueryAtom(context.Background(), stmt, nil); !errors.Is(err, errExistsUnsupported) {
+		t.Errorf("expected errExistsUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package edamame
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ExecExists reports whether any row matches a query or select statement. It runs
+// SELECT EXISTS (...), so the database stops at the first matching row. Only the
+// statement's WHERE, GROUP BY and HAVING apply; its fields, select expressions,
+// ordering, limits, locking and index hint are dropped. Statements with OuterWhere
+// are rejected, since it filters on the dropped fields.
+//
+// Example:
+//
+//	taken, err := exec.ExecExists(ctx, UserByEmail, map[string]any{"email": email})
+func (e *Executor[T]) ExecExists(ctx context.Context, stmt Statement, params map[string]any) (bool, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.Name())
+	defer cancel()
+	return e.execExists(withRead(ctx), e.execer(), stmt, params)
+}
+
+// ExecExistsTx reports whether any row matches a query or select statement within a transaction.
+func (e *Executor[T]) ExecExistsTx(ctx context.Context, tx *sqlx.Tx, stmt Statement, params map[string]any) (bool, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.Name())
+	defer cancel()
+	return e.execExists(ctx, e.execerFor(tx), stmt, params)
+}
+
+// execExists runs the EXISTS form of stmt and scans its boolean result.
+func (e *Executor[T]) execExists(ctx context.Context, execer sqlx.ExtContext, stmt Statement, params map[string]any) (bool, error) {
+	spec, err := existsSpec(stmt)
+	if err != nil {
+		return false, err
+	}
+	if err := e.checkStatementFields(stmt); err != nil {
+		return false, err
+	}
+	params, err = e.prepareParams(stmt, params)
+	if err != nil {
+		return false, err
+	}
+	sql, binds, err := e.renderExists(spec)
+	if err != nil {
+		return false, err
+	}
+	params = mergeParams(params, binds)
+	ctx = withStatement(ctx, stmt.Name(), "query")
+	e.emitSQL(ctx, stmt.Name(), "query", sql, params)
+
+	rows, err := sqlx.NamedQueryContext(ctx, execer, sql, params)
+	if err != nil {
+		return false, fmt.Errorf("edamame: EXISTS query failed: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return false, fmt.Errorf("edamame: EXISTS query failed: %w", err)
+		}
+		return false, fmt.Errorf("edamame: EXISTS query returned no rows")
+	}
+	var exists bool
+	if err := rows.Scan(&exists); err != nil {
+		return false, fmt.Errorf("edamame: failed to scan EXISTS result: %w", err)
+	}
+	return exists, nil
+}
+
+// existsSpec returns the parts of a query or select statement's spec that decide
+// whether a row matches.
+func existsSpec(stmt Statement) (QuerySpec, error) {
+	var spec QuerySpec
+	switch s := stmt.(type) {
+	case QueryStatement:
+		spec = QuerySpec{Where: s.spec.Where, OuterWhere: s.spec.OuterWhere, GroupBy: s.spec.GroupBy,
+			Having: s.spec.Having, HavingAgg: s.spec.HavingAgg, WithTrashed: s.spec.WithTrashed}
+	case SelectStatement:
+		spec = QuerySpec{Where: s.spec.Where, OuterWhere: s.spec.OuterWhere, GroupBy: s.spec.GroupBy,
+			Having: s.spec.Having, HavingAgg: s.spec.HavingAgg, WithTrashed: s.spec.WithTrashed}
+	default:
+		return QuerySpec{}, fmt.Errorf("edamame: ExecExists requires a query or select statement, got %T", stmt)
+	}
+	if len(spec.OuterWhere) > 0 {
+		return QuerySpec{}, errOuterWhereUnsupported
+	}
+	return spec, nil
+}
+
+// renderExists renders spec as SELECT EXISTS (SELECT 1 FROM ...), selecting the
+// GROUP BY fields instead of 1 when the spec groups. Returns the SQL and any extra
+// params its rewrites bind.
+func (e *Executor[T]) renderExists(spec QuerySpec) (string, map[string]any, error) {
+	spec.Fields = spec.GroupBy
+	q, err := e.queryFromSpec(spec)
+	if err != nil {
+		return "", nil, err
+	}
+	result, err := q.Render()
+	if err != nil {
+		return "", nil, fmt.Errorf("edamame: failed to render EXISTS query: %w", err)
+	}
+	sql, binds, err := e.finalizeSQL(result.SQL, e.queryRewrites(spec))
+	if err != nil {
+		return "", nil, err
+	}
+	if rest, ok := strings.CutPrefix(sql, "SELECT * "); ok {
+		sql = "SELECT 1 " + rest
+	}
+	return "SELECT EXISTS (" + sql + ")", binds, nil
+}
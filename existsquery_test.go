@@ -0,0 +1,100 @@
+package edamame
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestRenderExists(t *testing.T) {
+	exec, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	limit := 10
+
+	tests := []struct {
+		name string
+		stmt Statement
+		want string
+	}{
+		{
+			"query",
+			NewQueryStatement("adults", "", QuerySpec{
+				Fields:  []string{"id", "email"},
+				Where:   []ConditionSpec{{Field: "age", Operator: ">=", Param: "min_age"}},
+				OrderBy: []OrderBySpec{{Field: "name", Direction: "asc"}},
+				Limit:   &limit,
+			}),
+			`SELECT EXISTS (SELECT 1 FROM "users" WHERE "age" >= :min_age)`,
+		},
+		{
+			"select",
+			NewSelectStatement("by-email", "", SelectSpec{
+				Where:      []ConditionSpec{{Field: "email", Operator: "=", Param: "email"}},
+				ForLocking: "update",
+			}),
+			`SELECT EXISTS (SELECT 1 FROM "users" WHERE "email" = :email)`,
+		},
+		{
+			"grouped",
+			NewQueryStatement("shared-names", "", QuerySpec{
+				GroupBy:   []string{"name"},
+				HavingAgg: []HavingAggSpec{{Func: "count", Operator: ">", Param: "min_count"}},
+			}),
+			`SELECT EXISTS (SELECT "name" FROM "users" GROUP BY "name" HAVING COUNT(*) > :min_count)`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := existsSpec(tt.stmt)
+			if err != nil {
+				t.Fatalf("existsSpec() failed: %v", err)
+			}
+			sql, _, err := exec.renderExists(spec)
+			if err != nil {
+				t.Fatalf("renderExists() failed: %v", err)
+			}
+			if sql != tt.want {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.want, sql)
+			}
+		})
+	}
+}
+
+func TestExecExists(t *testing.T) {
+	db := &recordingDB{}
+	exec, err := New[User](db, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ctx := context.Background()
+
+	byEmail := NewSelectStatement("by-email", "", SelectSpec{Where: []ConditionSpec{{Field: "email", Operator: "=", Param: "email"}}})
+	if _, err := exec.ExecExists(ctx, byEmail, map[string]any{"email": "a@example.com"}); !errors.Is(err, errRecorded) {
+		t.Fatalf("expected the recorded error, got %v", err)
+	}
+	if want := `SELECT EXISTS (SELECT 1 FROM "users" WHERE "email" = $1)`; db.count() != 1 || db.queries[0] != want {
+		t.Errorf("expected %s, got %v", want, db.queries)
+	}
+
+	if _, err := exec.ExecExists(ctx, byEmail, nil); err == nil {
+		t.Error("expected a missing param to be rejected")
+	}
+	purge := NewDeleteStatement("purge", "", DeleteSpec{Where: []ConditionSpec{{Field: "id", Operator: "=", Param: "id"}}})
+	if _, err := exec.ExecExists(ctx, purge, map[string]any{"id": 1}); err == nil {
+		t.Error("expected a delete statement to be rejected")
+	}
+	outer := NewQueryStatement("outer", "", QuerySpec{
+		SelectExprs: []SelectExprSpec{{Func: "upper", Field: "name", Alias: "upper_name"}},
+		OuterWhere:  []ConditionSpec{{Field: "upper_name", Operator: "=", Param: "name"}},
+	})
+	if _, err := exec.ExecExists(ctx, outer, map[string]any{"name": "A"}); !errors.Is(err, errOuterWhereUnsupported) {
+		t.Errorf("expected OuterWhere to be rejected, got %v", err)
+	}
+	if db.count() != 1 {
+		t.Errorf("expected no further queries, got %v", db.queries)
+	}
+}
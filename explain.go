@@ -0,0 +1,116 @@
+package edamame
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ExplainCost returns the planner's estimated total cost of running stmt with params,
+// the "Total Cost" of the top plan node of EXPLAIN (FORMAT JSON). The statement is
+// planned, not executed, so it is safe for updates and deletes. Use it to gate plan
+// regressions in CI, such as asserting a statement stays below a cost threshold.
+// Params are prepared as the Exec methods prepare them. PostgreSQL only.
+//
+// Example:
+//
+//	cost, err := exec.ExplainCost(ctx, Adults, map[string]any{"min_age": 18})
+//	if cost > 1000 {
+//	    t.Errorf("adults plan cost %.0f exceeds budget", cost)
+//	}
+func (e *Executor[T]) ExplainCost(ctx context.Context, stmt Statement, params map[string]any) (float64, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.Name())
+	defer cancel()
+	return e.explainCost(ctx, e.execer(), stmt, params)
+}
+
+// ExplainCostTx returns the estimated total cost of stmt within a transaction.
+func (e *Executor[T]) ExplainCostTx(ctx context.Context, tx *sqlx.Tx, stmt Statement, params map[string]any) (float64, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.Name())
+	defer cancel()
+	return e.explainCost(ctx, e.execerFor(tx), stmt, params)
+}
+
+// explainCost runs EXPLAIN (FORMAT JSON) for stmt on execer and extracts the top-level total cost.
+func (e *Executor[T]) explainCost(ctx context.Context, execer sqlx.ExtContext, stmt Statement, params map[string]any) (float64, error) {
+	if !e.isPostgres() {
+		return 0, fmt.Errorf("edamame: ExplainCost requires the postgres renderer")
+	}
+	sql, binds, err := e.explainSQL(stmt)
+	if err != nil {
+		return 0, fmt.Errorf("edamame: statement %q: %w", stmt.Name(), err)
+	}
+	params, err = e.prepareParams(stmt, params)
+	if err != nil {
+		return 0, err
+	}
+	params = mergeParams(params, binds)
+
+	sql = "EXPLAIN (FORMAT JSON) " + sql
+	ctx = withStatement(ctx, stmt.Name(), "explain")
+	e.emitSQL(ctx, stmt.Name(), "explain", sql, params)
+	rows, err := sqlx.NamedQueryContext(ctx, execer, sql, params)
+	if err != nil {
+		return 0, fmt.Errorf("edamame: explain of %q failed: %w", stmt.Name(), err)
+	}
+	defer rows.Close()
+
+	var plan []byte
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return 0, fmt.Errorf("edamame: explain of %q failed: %w", stmt.Name(), err)
+		}
+		return 0, fmt.Errorf("edamame: explain of %q returned no plan", stmt.Name())
+	}
+	if err := rows.Scan(&plan); err != nil {
+		return 0, fmt.Errorf("edamame: failed to scan plan of %q: %w", stmt.Name(), err)
+	}
+	return planTotalCost(plan)
+}
+
+// explainSQL renders stmt as the Exec methods run it, with any params the rewrites bind.
+func (e *Executor[T]) explainSQL(stmt Statement) (string, map[string]any, error) {
+	switch s := stmt.(type) {
+	case QueryStatement:
+		q, err := e.queryFromSpec(s.spec)
+		if err != nil {
+			return "", nil, err
+		}
+		result, err := q.Render()
+		if err != nil {
+			return "", nil, err
+		}
+		return e.finalizeSQL(result.SQL, e.queryRewrites(s.spec))
+	case SelectStatement:
+		sel, err := e.selectFromSpec(s.spec)
+		if err != nil {
+			return "", nil, err
+		}
+		result, err := sel.Render()
+		if err != nil {
+			return "", nil, err
+		}
+		return e.finalizeSQL(result.SQL, e.selectRewrites(s.spec))
+	default:
+		sql, err := e.RenderStatement(stmt)
+		return sql, nil, err
+	}
+}
+
+// planTotalCost extracts the "Total Cost" of the top plan node from EXPLAIN (FORMAT JSON) output.
+func planTotalCost(plan []byte) (float64, error) {
+	var explained []struct {
+		Plan struct {
+			TotalCost *float64 `json:"Total Cost"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal(plan, &explained); err != nil {
+		return 0, fmt.Errorf("edamame: failed to parse plan: %w", err)
+	}
+	if len(explained) == 0 || explained[0].Plan.TotalCost == nil {
+		return 0, fmt.Errorf("edamame: plan has no total cost")
+	}
+	return *explained[0].Plan.TotalCost, nil
+}
@@ -0,0 +1,36 @@
+package edamame
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/mariadb"
+)
+
+func TestPlanTotalCost(t *testing.T) {
+	cost, err := planTotalCost([]byte(`[{"Plan": {"Node Type": "Seq Scan", "Startup Cost": 0.00, "Total Cost": 22.70}}]`))
+	if err != nil {
+		t.Fatalf("planTotalCost() failed: %v", err)
+	}
+	if cost != 22.70 {
+		t.Errorf("expected 22.70, got %f", cost)
+	}
+
+	for _, plan := range []string{`[]`, `[{"Plan": {"Node Type": "Result"}}]`, `not json`} {
+		if _, err := planTotalCost([]byte(plan)); err == nil {
+			t.Errorf("expected an error for plan %s", plan)
+		}
+	}
+}
+
+func TestExplainCost_RequiresPostgres(t *testing.T) {
+	exec, err := New[User](&recordingDB{}, "users", mariadb.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	stmt := NewQueryStatement("all", "All users", QuerySpec{})
+	if _, err := exec.ExplainCost(context.Background(), stmt, nil); err == nil || !strings.Contains(err.Error(), "postgres") {
+		t.Errorf("expected a postgres-only error, got %v", err)
+	}
+}
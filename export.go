@@ -0,0 +1,66 @@
+package edamame
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ExportQueryNDJSON executes a query statement and writes its records to w as
+// newline-delimited JSON, one object per line, returning the number of records
+// written. Records are streamed through ExecQueryIter, so only the current one is
+// held in memory. Records are encoded with encoding/json, honoring T's json tags.
+//
+// On error the count covers the records already written, and w may hold a partial
+// export.
+//
+// Example:
+//
+//	f, err := os.Create("users.ndjson")
+//	if err != nil {
+//	    return err
+//	}
+//	defer f.Close()
+//	n, err := exec.ExportQueryNDJSON(ctx, AllUsers, nil, f)
+func (e *Executor[T]) ExportQueryNDJSON(ctx context.Context, stmt QueryStatement, params map[string]any, w io.Writer) (int64, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	seq, err := e.ExecQueryIter(ctx, stmt, params)
+	if err != nil {
+		return 0, err
+	}
+	return writeNDJSON(seq, w)
+}
+
+// ExportQueryNDJSONTx exports a query statement's records as newline-delimited JSON
+// within a transaction, as ExportQueryNDJSON.
+func (e *Executor[T]) ExportQueryNDJSONTx(ctx context.Context, tx *sqlx.Tx, stmt QueryStatement, params map[string]any, w io.Writer) (int64, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	seq, err := e.ExecQueryIterTx(ctx, tx, stmt, params)
+	if err != nil {
+		return 0, err
+	}
+	return writeNDJSON(seq, w)
+}
+
+// writeNDJSON encodes each record of seq to w on its own line, stopping at the
+// first error.
+func writeNDJSON[T any](seq iter.Seq2[*T, error], w io.Writer) (int64, error) {
+	enc := json.NewEncoder(w)
+	var n int64
+	for record, err := range seq {
+		if err != nil {
+			return n, err
+		}
+		if err := enc.Encode(record); err != nil {
+			return n, fmt.Errorf("edamame: failed to write record: %w", err)
+		}
+		n++
+	}
+	return n, nil
+}
@@ -0,0 +1,53 @@
+package edamame
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestWriteNDJSON(t *testing.T) {
+	users := []User{{ID: 1, Email: "a@example.com", Name: "A"}, {ID: 2, Email: "b@example.com", Name: "B"}}
+	failure := errors.New("boom")
+	seq := func(yield func(*User, error) bool) {
+		for i := range users {
+			if !yield(&users[i], nil) {
+				return
+			}
+		}
+		yield(nil, failure)
+	}
+
+	var buf bytes.Buffer
+	n, err := writeNDJSON(seq, &buf)
+	if !errors.Is(err, failure) {
+		t.Errorf("expected the iteration error, got %v", err)
+	}
+	if n != 2 {
+		t.Errorf("expected 2 records written, got %d", n)
+	}
+	lines := bytes.Split(bytes.TrimSuffix(buf.Bytes(), []byte("\n")), []byte("\n"))
+	if len(lines) != 2 || !bytes.Contains(lines[1], []byte(`"b@example.com"`)) {
+		t.Errorf("unexpected NDJSON output:\n%s", buf.String())
+	}
+}
+
+func TestExportQueryNDJSON_Error(t *testing.T) {
+	db := &recordingDB{}
+	exec, err := New[User](db, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	n, err := exec.ExportQueryNDJSON(context.Background(), NewQueryStatement("all", "", QuerySpec{}), nil, &buf)
+	if !errors.Is(err, errRecorded) || n != 0 || buf.Len() != 0 {
+		t.Errorf("expected the query error and no output, got %d, %v, %q", n, err, buf.String())
+	}
+	if db.count() != 1 {
+		t.Errorf("expected one query, got %v", db.queries)
+	}
+}
@@ -0,0 +1,80 @@
+package edamame
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ExecQueryFields executes a query statement selecting only fields, a sparse fieldset
+// such as an API's ?fields= parameter, so one statement serves every subset its
+// callers request. The records' other fields are left zero. Fields are checked as
+// QueryWithFields does.
+//
+// Example:
+//
+//	users, err := exec.ExecQueryFields(ctx, ActiveUsers, []string{"id", "email"}, nil)
+func (e *Executor[T]) ExecQueryFields(ctx context.Context, stmt QueryStatement, fields []string, params map[string]any) ([]*T, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	stmt, err := e.QueryWithFields(stmt, fields)
+	if err != nil {
+		return nil, err
+	}
+	return e.ExecQuery(ctx, stmt, params)
+}
+
+// ExecQueryFieldsTx executes a query statement selecting only fields within a transaction.
+func (e *Executor[T]) ExecQueryFieldsTx(ctx context.Context, tx *sqlx.Tx, stmt QueryStatement, fields []string, params map[string]any) ([]*T, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	stmt, err := e.QueryWithFields(stmt, fields)
+	if err != nil {
+		return nil, err
+	}
+	return e.ExecQueryTx(ctx, tx, stmt, params)
+}
+
+// QueryWithFields returns a copy of stmt, under the same name, that selects only
+// fields, in the order given. Each field must be a column of T and one the statement
+// already selects: any column for a statement selecting every column, otherwise one
+// of its Fields. Statements with field aliases, select expressions, grouping,
+// DISTINCT or OuterWhere are rejected, as narrowing their SELECT list changes their
+// result rather than just its width.
+//
+// Pass the copy to ExecQueryProjection to scan the subset into a projection struct.
+func (e *Executor[T]) QueryWithFields(stmt QueryStatement, fields []string) (QueryStatement, error) {
+	spec := stmt.spec
+	if len(spec.FieldAliases) > 0 || len(spec.SelectExprs) > 0 || len(spec.GroupBy) > 0 ||
+		spec.Distinct || len(spec.DistinctOn) > 0 || len(spec.OuterWhere) > 0 {
+		return QueryStatement{}, fmt.Errorf("edamame: statement %q cannot select a subset of fields: it selects more than plain columns", stmt.name)
+	}
+	if len(fields) == 0 {
+		return QueryStatement{}, fmt.Errorf("edamame: statement %q requires at least one field to select", stmt.name)
+	}
+	if err := e.checkKnownFields(fields); err != nil {
+		return QueryStatement{}, fmt.Errorf("edamame: statement %q: %w", stmt.name, err)
+	}
+
+	allowed := make(map[string]bool)
+	for _, col := range e.selectedColumns(e.columnList(spec.Fields), nil) {
+		allowed[col] = true
+	}
+	selected := make([]string, len(fields))
+	seen := make(map[string]bool, len(fields))
+	for i, field := range fields {
+		col := e.column(field)
+		if !allowed[col] {
+			return QueryStatement{}, fmt.Errorf("edamame: field %q is not selected by statement %q", field, stmt.name)
+		}
+		if seen[col] {
+			return QueryStatement{}, fmt.Errorf("edamame: field %q is requested more than once", field)
+		}
+		seen[col] = true
+		selected[i] = col
+	}
+
+	stmt.spec.Fields = selected
+	return stmt, nil
+}
@@ -0,0 +1,87 @@
+package edamame
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestQueryWithFields(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	all := NewQueryStatement("all", "All users", QuerySpec{
+		Where:   []ConditionSpec{{Field: "age", Operator: ">", Param: "min_age"}},
+		OrderBy: []OrderBySpec{{Field: "age", Direction: "desc"}},
+	})
+	narrowed, err := factory.QueryWithFields(all, []string{"name", "id"})
+	if err != nil {
+		t.Fatalf("QueryWithFields() failed: %v", err)
+	}
+	sql, err := factory.RenderQuery(narrowed)
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+	if !strings.HasPrefix(sql, `SELECT "name", "id" FROM "users" WHERE "age" > :min_age ORDER BY "age" DESC`) {
+		t.Errorf("unexpected SQL: %s", sql)
+	}
+	if narrowed.Name() != all.Name() || len(narrowed.Params()) != 1 {
+		t.Errorf("expected the copy to keep the statement's name and params, got %q %+v", narrowed.Name(), narrowed.Params())
+	}
+	if cols, _ := factory.OutputColumns(all); len(cols) != 4 {
+		t.Errorf("expected the original statement to be unchanged, got columns %v", cols)
+	}
+
+	contacts := NewQueryStatement("contacts", "User contacts", QuerySpec{Fields: []string{"id", "email", "name"}})
+	if _, err := factory.QueryWithFields(contacts, []string{"email"}); err != nil {
+		t.Errorf("expected a subset of the statement's fields to be allowed, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		stmt   QueryStatement
+		fields []string
+	}{
+		{"no fields", all, nil},
+		{"unknown field", all, []string{"id", "emial"}},
+		{"field the statement does not select", contacts, []string{"age"}},
+		{"duplicate field", all, []string{"id", "id"}},
+		{"select expressions", NewQueryStatement("q", "q", QuerySpec{SelectExprs: []SelectExprSpec{{Func: "upper", Field: "name", Alias: "upper_name"}}}), []string{"id"}},
+		{"aliases", NewQueryStatement("q", "q", QuerySpec{Fields: []string{"id"}, FieldAliases: map[string]string{"id": "user_id"}}), []string{"id"}},
+		{"grouped", NewQueryStatement("q", "q", QuerySpec{Fields: []string{"age"}, GroupBy: []string{"age"}}), []string{"age"}},
+		{"distinct", NewQueryStatement("q", "q", QuerySpec{Fields: []string{"age", "name"}, Distinct: true}), []string{"age"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := factory.QueryWithFields(tt.stmt, tt.fields); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestExecQueryFields(t *testing.T) {
+	db := &recordingDB{}
+	factory, err := New[User](db, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ctx := context.Background()
+
+	all := NewQueryStatement("all", "All users", QuerySpec{})
+	if _, err := factory.ExecQueryFields(ctx, all, []string{"id", "email"}, nil); !errors.Is(err, errRecorded) {
+		t.Fatalf("expected query to reach the database, got %v", err)
+	}
+	if len(db.queries) != 1 || !strings.HasPrefix(db.queries[0], `SELECT "id", "email" FROM "users"`) {
+		t.Errorf("unexpected queries: %v", db.queries)
+	}
+
+	if _, err := factory.ExecQueryFields(ctx, all, []string{"password"}, nil); err == nil || errors.Is(err, errRecorded) {
+		t.Errorf("expected error for an unknown field before reaching the database, got %v", err)
+	}
+}
@@ -0,0 +1,201 @@
+package edamame
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DefineConditionFragment registers conds under name so WHERE clauses can reuse them
+// with a {Fragment: name} condition. A reference is inlined as an AND group of the
+// fragment's conditions when a statement is rendered, and StatementParams includes
+// the fragment's params. Defining a name again replaces it; defining it with no
+// conditions removes it.
+//
+// Fragments cannot reference other fragments or hold null-safe comparisons or EXISTS conditions.
+//
+// Example:
+//
+//	exec.DefineConditionFragment("active", []edamame.ConditionSpec{
+//	    {Field: "status", Operator: "=", Param: "status"},
+//	    {Field: "deleted_at", IsNull: true},
+//	})
+//
+//	var ActiveAdults = edamame.NewQueryStatement("active-adults", "Active adult users", edamame.QuerySpec{
+//	    Where: []edamame.ConditionSpec{
+//	        {Fragment: "active"},
+//	        {Field: "age", Operator: ">=", Param: "min_age"},
+//	    },
+//	})
+func (e *Executor[T]) DefineConditionFragment(name string, conds []ConditionSpec) error {
+	if name == "" {
+		return fmt.Errorf("edamame: condition fragment name must not be empty")
+	}
+	if err := checkFragmentConditions(conds); err != nil {
+		return fmt.Errorf("edamame: condition fragment %q: %w", name, err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(conds) == 0 {
+		delete(e.fragments, name)
+		return nil
+	}
+	if e.fragments == nil {
+		e.fragments = make(map[string][]ConditionSpec)
+	}
+	e.fragments[name] = slices.Clone(conds)
+	return nil
+}
+
+// checkFragmentConditions rejects conditions a fragment cannot hold: references to
+// other fragments, and null-safe, quantified, JSONB path, full-text and subquery conditions, which
+// are rewritten from the statement's own spec rather than the rendered fragment.
+func checkFragmentConditions(conds []ConditionSpec) error {
+	for _, c := range conds {
+		switch {
+		case c.IsFragment():
+			return fmt.Errorf("fragments cannot reference fragment %q", c.Fragment)
+		case c.IsDistinctFrom():
+			return fmt.Errorf("%s conditions are not supported in fragments", strings.ToUpper(c.Operator))
+		case c.IsQuantified():
+			return fmt.Errorf("%s conditions are not supported in fragments", strings.ToUpper(c.Quantifier))
+		case c.IsJSONPath():
+			return fmt.Errorf("JSONB path conditions are not supported in fragments")
+		case c.IsFullText():
+			return fmt.Errorf("full-text conditions are not supported in fragments")
+		case c.IsSubquery():
+			return fmt.Errorf("EXISTS conditions and subquery comparisons are not supported in fragments")
+		case c.IsGroup():
+			if err := checkFragmentConditions(c.Group); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// fragment returns the conditions registered under name.
+func (e *Executor[T]) fragment(name string) ([]ConditionSpec, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	conds, ok := e.fragments[name]
+	return conds, ok
+}
+
+// hasFragment reports whether any condition, including nested groups, references a fragment.
+func hasFragment(conds []ConditionSpec) bool {
+	for _, c := range conds {
+		if c.IsFragment() || (c.IsGroup() && hasFragment(c.Group)) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkFragments returns an error if conds reference an undefined fragment.
+func (e *Executor[T]) checkFragments(conds []ConditionSpec) error {
+	for _, c := range conds {
+		if c.IsFragment() {
+			if _, ok := e.fragment(c.Fragment); !ok {
+				return fmt.Errorf("undefined condition fragment %q", c.Fragment)
+			}
+			continue
+		}
+		if c.IsGroup() {
+			if err := e.checkFragments(c.Group); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkSpecFragments validates the fragment references of a query or select spec:
+// every WHERE reference must be defined, and HAVING cannot hold any.
+func (e *Executor[T]) checkSpecFragments(where, having []ConditionSpec) error {
+	if hasFragment(having) {
+		return fmt.Errorf("condition fragments are only supported in WHERE")
+	}
+	return e.checkFragments(where)
+}
+
+// expandFragments returns conds with each defined fragment reference replaced by an
+// AND group of the fragment's conditions. Undefined references are left in place.
+func (e *Executor[T]) expandFragments(conds []ConditionSpec) []ConditionSpec {
+	if !hasFragment(conds) {
+		return conds
+	}
+	expanded := make([]ConditionSpec, len(conds))
+	for i, c := range conds {
+		switch {
+		case c.IsFragment():
+			if group, ok := e.fragment(c.Fragment); ok {
+				c = ConditionSpec{Logic: "AND", Group: group}
+			}
+		case c.IsGroup():
+			c.Group = e.expandFragments(c.Group)
+		}
+		expanded[i] = c
+	}
+	return expanded
+}
+
+// statementParamSpecs returns the caller-provided param specs of stmt's spec.
+func statementParamSpecs(stmt Statement) []ParamSpec {
+	switch s := stmt.(type) {
+	case QueryStatement:
+		return s.spec.Params
+	case SelectStatement:
+		return s.spec.Params
+	case UpdateStatement:
+		return s.spec.Params
+	case DeleteStatement:
+		return s.spec.Params
+	case AggregateStatement:
+		return s.spec.Params
+	}
+	return nil
+}
+
+// statementWhere returns the WHERE conditions and param defaults of stmt's spec.
+func statementWhere(stmt Statement) ([]ConditionSpec, map[string]any) {
+	switch s := stmt.(type) {
+	case QueryStatement:
+		return s.spec.Where, s.spec.ParamDefaults
+	case SelectStatement:
+		return s.spec.Where, s.spec.ParamDefaults
+	case UpdateStatement:
+		return s.spec.Where, s.spec.ParamDefaults
+	case DeleteStatement:
+		return s.spec.Where, s.spec.ParamDefaults
+	case AggregateStatement:
+		return s.spec.Where, s.spec.ParamDefaults
+	}
+	return nil, nil
+}
+
+// StatementParams returns stmt's params followed by the params of any condition
+// fragments its WHERE clause references, as defined on this executor, with the
+// defaults set by SetParamDefault. It returns an error if a referenced fragment is
+// undefined.
+func (e *Executor[T]) StatementParams(stmt Statement) ([]ParamSpec, error) {
+	if err := e.checkStatementFields(stmt); err != nil {
+		return nil, fmt.Errorf("edamame: statement %q: %w", stmt.Name(), err)
+	}
+	where, defaults := statementWhere(stmt)
+	if !hasFragment(where) {
+		return e.withDefaultOverrides(stmt.Name(), stmt.Params()), nil
+	}
+	if err := e.checkFragments(where); err != nil {
+		return nil, fmt.Errorf("edamame: statement %q: %w", stmt.Name(), err)
+	}
+
+	params := slices.Clone(stmt.Params())
+	seen := make(map[string]bool, len(params))
+	for _, p := range params {
+		seen[p.Name] = true
+	}
+	collectParams(e.expandFragments(where), seen, &params)
+	return e.withDefaultOverrides(stmt.Name(), withParamSpecs(withParamDefaults(params, defaults), statementParamSpecs(stmt))), nil
+}
@@ -0,0 +1,124 @@
+package edamame
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestConditionFragments(t *testing.T) {
+	exec, err := New[User](&recordingDB{}, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if err := exec.DefineConditionFragment("named", []ConditionSpec{
+		{Field: "name", Operator: "=", Param: "name"},
+		{Field: "email", Operator: "!=", Param: "blocked_email"},
+	}); err != nil {
+		t.Fatalf("DefineConditionFragment() failed: %v", err)
+	}
+
+	adults := NewQueryStatement("named-adults", "Named adults", QuerySpec{
+		Where: []ConditionSpec{
+			{Fragment: "named"},
+			{Field: "age", Operator: ">=", Param: "min_age"},
+		},
+	})
+	purge := NewDeleteStatement("purge-named", "Delete named users", DeleteSpec{
+		Where: []ConditionSpec{{Fragment: "named"}},
+	})
+
+	sql, err := exec.RenderQuery(adults)
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+	for _, want := range []string{`"name" = :name`, `"email" != :blocked_email`, `"age" >= :min_age`} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("expected %s in query SQL: %s", want, sql)
+		}
+	}
+	sql, err = exec.RenderDelete(purge)
+	if err != nil {
+		t.Fatalf("RenderDelete() failed: %v", err)
+	}
+	if !strings.Contains(sql, `"name" = :name`) {
+		t.Errorf("expected fragment condition in delete SQL: %s", sql)
+	}
+
+	params, err := exec.StatementParams(adults)
+	if err != nil {
+		t.Fatalf("StatementParams() failed: %v", err)
+	}
+	var names []string
+	for _, p := range params {
+		names = append(names, p.Name)
+	}
+	if got := strings.Join(names, ","); got != "min_age,name,blocked_email" {
+		t.Errorf("expected params min_age,name,blocked_email, got %s", got)
+	}
+
+	err = exec.ValidateParams(purge, map[string]any{"name": "x"})
+	if err == nil || !strings.Contains(err.Error(), `"blocked_email"`) {
+		t.Errorf("expected missing fragment param error, got %v", err)
+	}
+	if err := exec.Prepare(adults, purge); err != nil {
+		t.Errorf("Prepare() failed: %v", err)
+	}
+}
+
+func TestConditionFragments_Invalid(t *testing.T) {
+	exec, err := New[User](&recordingDB{}, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	if err := exec.DefineConditionFragment("", []ConditionSpec{{Field: "age", Operator: ">", Param: "age"}}); err == nil {
+		t.Error("expected error for empty fragment name")
+	}
+	if err := exec.DefineConditionFragment("nested", []ConditionSpec{{Fragment: "other"}}); err == nil {
+		t.Error("expected error for a fragment referencing a fragment")
+	}
+	if err := exec.DefineConditionFragment("null-safe", []ConditionSpec{
+		{Field: "name", Operator: "IS DISTINCT FROM", Param: "name"},
+	}); err == nil {
+		t.Error("expected error for a null-safe comparison in a fragment")
+	}
+
+	undefined := NewQueryStatement("undefined", "Undefined fragment", QuerySpec{
+		Where: []ConditionSpec{{Fragment: "missing"}},
+	})
+	if _, err := exec.RenderQuery(undefined); err == nil {
+		t.Error("expected render error for undefined fragment")
+	}
+	if _, err := exec.ExecQuery(context.Background(), undefined, nil); err == nil || !strings.Contains(err.Error(), `"missing"`) {
+		t.Errorf("expected undefined fragment error, got %v", err)
+	}
+	purge := NewDeleteStatement("purge", "Undefined fragment", DeleteSpec{
+		Where: []ConditionSpec{{Fragment: "missing"}},
+	})
+	if err := exec.Prepare(purge); err == nil {
+		t.Error("expected Prepare() to reject an undefined fragment")
+	}
+
+	if err := exec.DefineConditionFragment("adult", []ConditionSpec{{Field: "age", Operator: ">=", Param: "min_age"}}); err != nil {
+		t.Fatalf("DefineConditionFragment() failed: %v", err)
+	}
+	grouped := NewQueryStatement("grouped", "Fragment in HAVING", QuerySpec{
+		Fields:  []string{"age"},
+		GroupBy: []string{"age"},
+		Having:  []ConditionSpec{{Fragment: "adult"}},
+	})
+	if _, err := exec.RenderQuery(grouped); err == nil {
+		t.Error("expected error for a fragment in HAVING")
+	}
+
+	// Defining a fragment with no conditions removes it.
+	if err := exec.DefineConditionFragment("adult", nil); err != nil {
+		t.Fatalf("DefineConditionFragment() failed: %v", err)
+	}
+	if _, ok := exec.fragment("adult"); ok {
+		t.Error("expected fragment to be removed")
+	}
+}
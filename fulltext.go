@@ -0,0 +1,161 @@
+package edamame
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// fullTextParamPrefix names the placeholder params soy renders in place of each
+// full-text condition until rewriteFullText replaces them.
+const fullTextParamPrefix = "edamame_fulltext_"
+
+// textSearchConfig matches the text search configurations a full-text condition may
+// name, optionally schema-qualified, such as english or pg_catalog.english.
+var textSearchConfig = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// errFullTextUnsupported is returned by execution paths that run soy's SQL
+// unmodified and so cannot render full-text conditions.
+var errFullTextUnsupported = errors.New("edamame: full-text conditions are not supported by this method")
+
+// hasFullText reports whether any condition, including nested groups, is a full-text condition.
+func hasFullText(conditions []ConditionSpec) bool {
+	for _, c := range conditions {
+		if c.IsFullText() || (c.IsGroup() && hasFullText(c.Group)) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkFullText validates the full-text conditions of a query or select spec: they
+// may only appear in WHERE, including groups, and need the postgres renderer.
+func (e *Executor[T]) checkFullText(where, having, outerWhere []ConditionSpec) error {
+	if hasFullText(having) || hasFullText(outerWhere) {
+		return fmt.Errorf("edamame: full-text conditions are only supported in WHERE")
+	}
+	if !hasFullText(where) {
+		return nil
+	}
+	if !e.isPostgres() {
+		return fmt.Errorf("edamame: full-text conditions require the postgres renderer")
+	}
+	return e.checkFullTextConditions(where)
+}
+
+// checkFullTextConditions validates every full-text condition in conditions.
+func (e *Executor[T]) checkFullTextConditions(conditions []ConditionSpec) error {
+	for _, c := range conditions {
+		if c.IsGroup() {
+			if err := e.checkFullTextConditions(c.Group); err != nil {
+				return err
+			}
+			continue
+		}
+		if !c.IsFullText() {
+			continue
+		}
+		if c.Field != "" || c.Operator != "" || c.Param != "" || c.IsNull || c.IsBetween() || c.IsNotBetween() ||
+			c.IsIn() || c.IsNotIn() || c.RightField != "" || c.IsMatch() || c.IsQuantified() || c.IsJSONPath() {
+			return fmt.Errorf("edamame: full-text condition cannot be combined with another condition form")
+		}
+		ft := c.FullText
+		if len(ft.Fields) == 0 {
+			return fmt.Errorf("edamame: full-text condition requires at least one field")
+		}
+		for _, field := range ft.Fields {
+			if _, ok := e.columns[e.column(field)]; !ok {
+				return fmt.Errorf("edamame: unknown full-text field %q", field)
+			}
+		}
+		if ft.Config != "" && !textSearchConfig.MatchString(ft.Config) {
+			return fmt.Errorf("edamame: invalid text search config %q", ft.Config)
+		}
+		if ft.Param == "" {
+			return fmt.Errorf("edamame: full-text condition requires a param")
+		}
+	}
+	return nil
+}
+
+// withFullTextPlaceholders returns conditions with each full-text condition swapped
+// for a placeholder comparison on standIn, numbered depth-first, to be replaced by
+// rewriteFullText.
+func withFullTextPlaceholders(conditions []ConditionSpec, standIn string) []ConditionSpec {
+	if !hasFullText(conditions) {
+		return conditions
+	}
+	n := 0
+	return replaceFullText(conditions, standIn, &n)
+}
+
+// replaceFullText implements withFullTextPlaceholders, numbering placeholders from *n.
+func replaceFullText(conditions []ConditionSpec, standIn string, n *int) []ConditionSpec {
+	replaced := make([]ConditionSpec, len(conditions))
+	for i, c := range conditions {
+		switch {
+		case c.IsFullText():
+			c = ConditionSpec{Field: standIn, Operator: "=", Param: fullTextParamPrefix + strconv.Itoa(*n)}
+			*n++
+		case c.IsGroup():
+			c.Group = replaceFullText(c.Group, standIn, n)
+		}
+		replaced[i] = c
+	}
+	return replaced
+}
+
+// collectFullText returns the full-text conditions in conditions, depth-first, in
+// the order withFullTextPlaceholders numbers them.
+func collectFullText(conditions []ConditionSpec, found []ConditionSpec) []ConditionSpec {
+	for _, c := range conditions {
+		switch {
+		case c.IsFullText():
+			found = append(found, c)
+		case c.IsGroup():
+			found = collectFullText(c.Group, found)
+		}
+	}
+	return found
+}
+
+// rewriteFullText replaces the placeholder comparisons soy rendered for full-text
+// conditions with the tsvector match. soy has no text search operators, so the
+// expression is assembled here from the mapped, quoted fields.
+func (e *Executor[T]) rewriteFullText(sql string, conditions []ConditionSpec) (string, error) {
+	if !hasFullText(conditions) {
+		return sql, nil
+	}
+	standIn := e.schemaColumns()[0]
+	for i, c := range collectFullText(conditions, nil) {
+		placeholder, err := e.conditionSQL(ConditionSpec{Field: standIn, Operator: "=", Param: fullTextParamPrefix + strconv.Itoa(i)})
+		if err != nil {
+			return "", err
+		}
+		found := placeholderOffsets(sql, placeholder)
+		if len(found) != 1 {
+			return "", fmt.Errorf("edamame: cannot place full-text condition %d: rendered %d placeholders, expected 1", i, len(found))
+		}
+		sql = sql[:found[0]] + e.fullTextSQL(*c.FullText) + sql[found[0]+len(placeholder):]
+	}
+	return sql, nil
+}
+
+// fullTextSQL renders ft as a tsvector match against plainto_tsquery.
+func (e *Executor[T]) fullTextSQL(ft FullTextSpec) string {
+	document := `"` + e.column(ft.Fields[0]) + `"`
+	if len(ft.Fields) > 1 {
+		parts := make([]string, len(ft.Fields))
+		for i, field := range ft.Fields {
+			parts[i] = `coalesce("` + e.column(field) + `", '')`
+		}
+		document = strings.Join(parts, " || ' ' || ")
+	}
+	config := ""
+	if ft.Config != "" {
+		config = "'" + ft.Config + "', "
+	}
+	return "to_tsvector(" + config + document + ") @@ plainto_tsquery(" + config + ":" + ft.Param + ")"
+}
@@ -0,0 +1,174 @@
+package edamame
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/mariadb"
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+// Article is a test model with text columns to search.
+type Article struct {
+	ID    int     `db:"id" type:"integer" constraints:"primarykey"`
+	Title string  `db:"title" type:"text"`
+	Body  *string `db:"body" type:"text"`
+}
+
+func TestFullText_Render(t *testing.T) {
+	factory, err := New[Article](nil, "articles", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		ft   FullTextSpec
+		want string
+	}{
+		{"single field", FullTextSpec{Fields: []string{"body"}, Config: "english", Param: "q"},
+			`WHERE to_tsvector('english', "body") @@ plainto_tsquery('english', :q)`},
+		{"multiple fields", FullTextSpec{Fields: []string{"title", "body"}, Config: "english", Param: "q"},
+			`WHERE to_tsvector('english', coalesce("title", '') || ' ' || coalesce("body", '')) @@ plainto_tsquery('english', :q)`},
+		{"default config", FullTextSpec{Fields: []string{"title"}, Param: "q"},
+			`WHERE to_tsvector("title") @@ plainto_tsquery(:q)`},
+		{"qualified config", FullTextSpec{Fields: []string{"title"}, Config: "pg_catalog.simple", Param: "q"},
+			`WHERE to_tsvector('pg_catalog.simple', "title") @@ plainto_tsquery('pg_catalog.simple', :q)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ft := tt.ft
+			stmt := NewQueryStatement("q", "q", QuerySpec{Where: []ConditionSpec{{FullText: &ft}}})
+			sql, err := factory.RenderQuery(stmt)
+			if err != nil {
+				t.Fatalf("RenderQuery() failed: %v", err)
+			}
+			if !strings.Contains(sql, tt.want) {
+				t.Errorf("expected %q in SQL, got: %s", tt.want, sql)
+			}
+		})
+	}
+}
+
+func TestFullText_GroupsAndParams(t *testing.T) {
+	factory, err := New[Article](nil, "articles", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	stmt := NewSelectStatement("search", "Article search", SelectSpec{
+		Where: []ConditionSpec{
+			{Field: "id", Operator: ">", Param: "after"},
+			{Logic: "OR", Group: []ConditionSpec{
+				{FullText: &FullTextSpec{Fields: []string{"title"}, Config: "english", Param: "q"}},
+				{FullText: &FullTextSpec{Fields: []string{"body"}, Config: "simple", Param: "q"}},
+			}},
+		},
+	})
+	sql, err := factory.RenderSelect(stmt)
+	if err != nil {
+		t.Fatalf("RenderSelect() failed: %v", err)
+	}
+	for _, want := range []string{
+		`"id" > :after`,
+		`to_tsvector('english', "title") @@ plainto_tsquery('english', :q)`,
+		`to_tsvector('simple', "body") @@ plainto_tsquery('simple', :q)`,
+	} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("expected %q in SQL, got: %s", want, sql)
+		}
+	}
+	if strings.Contains(sql, fullTextParamPrefix) {
+		t.Errorf("expected placeholders to be replaced, got: %s", sql)
+	}
+
+	params := stmt.Params()
+	if len(params) != 2 || params[1].Name != "q" || params[1].Type != "string" || !params[1].Required {
+		t.Errorf("expected params after and q (string), got %+v", params)
+	}
+}
+
+func TestFullText_Validation(t *testing.T) {
+	factory, err := New[Article](nil, "articles", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		spec QuerySpec
+	}{
+		{"no fields", QuerySpec{Where: []ConditionSpec{{FullText: &FullTextSpec{Param: "q"}}}}},
+		{"unknown field", QuerySpec{Where: []ConditionSpec{{FullText: &FullTextSpec{Fields: []string{"summary"}, Param: "q"}}}}},
+		{"missing param", QuerySpec{Where: []ConditionSpec{{FullText: &FullTextSpec{Fields: []string{"title"}}}}}},
+		{"invalid config", QuerySpec{Where: []ConditionSpec{{FullText: &FullTextSpec{Fields: []string{"title"}, Config: "english'); --", Param: "q"}}}}},
+		{"combined with a comparison", QuerySpec{Where: []ConditionSpec{{Field: "title", Operator: "=", Param: "t",
+			FullText: &FullTextSpec{Fields: []string{"title"}, Param: "q"}}}}},
+		{"in HAVING", QuerySpec{GroupBy: []string{"title"}, Having: []ConditionSpec{{FullText: &FullTextSpec{Fields: []string{"title"}, Param: "q"}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := factory.RenderQuery(NewQueryStatement("q", "q", tt.spec)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	maria, err := New[Article](nil, "articles", mariadb.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	stmt := NewQueryStatement("q", "q", QuerySpec{Where: []ConditionSpec{{FullText: &FullTextSpec{Fields: []string{"title"}, Param: "q"}}}})
+	if _, err := maria.RenderQuery(stmt); err == nil || !strings.Contains(err.Error(), "postgres") {
+		t.Errorf("expected postgres renderer error, got %v", err)
+	}
+}
+
+func TestFullText_Unsupported(t *testing.T) {
+	factory, err := New[Article](&recordingDB{}, "articles", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	where := []ConditionSpec{{FullText: &FullTextSpec{Fields: []string{"title"}, Config: "english", Param: "q"}}}
+
+	query := NewQueryStatement("q", "q", QuerySpec{Where: where})
+	if _, err := factory.ExecQueryAtom(context.Background(), query, nil); !errors.Is(err, errFullTextUnsupported) {
+		t.Errorf("ExecQueryAtom: expected errFullTextUnsupported, got %v", err)
+	}
+
+	// Statement types executed by soy reject the condition before rendering.
+	const want = "full-text conditions are only supported in query and select statements"
+	del := NewDeleteStatement("d", "d", DeleteSpec{Where: where})
+	if _, err := factory.ExecDelete(context.Background(), del, map[string]any{"q": "go"}); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("ExecDelete: expected %q, got %v", want, err)
+	}
+	upd := NewUpdateStatement("u", "u", UpdateSpec{Set: map[string]string{"title": "title"}, Where: where})
+	if _, err := factory.RenderUpdate(upd); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("RenderUpdate: expected %q, got %v", want, err)
+	}
+	agg := NewAggregateStatement("a", "a", AggCount, AggregateSpec{Where: where})
+	if _, err := factory.RenderAggregate(agg); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("RenderAggregate: expected %q, got %v", want, err)
+	}
+}
+
+func TestFullText_ConditionsFromJSON(t *testing.T) {
+	factory, err := New[Article](nil, "articles", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	where, err := factory.ConditionsFromJSON([]byte(`[{"fulltext": {"fields": ["title", "body"], "config": "english", "param": "q"}}]`))
+	if err != nil {
+		t.Fatalf("ConditionsFromJSON() failed: %v", err)
+	}
+	if len(where) != 1 || !where[0].IsFullText() || len(where[0].FullText.Fields) != 2 {
+		t.Errorf("expected one full-text condition over two fields, got %+v", where)
+	}
+
+	if _, err := factory.ConditionsFromJSON([]byte(`[{"fulltext": {"fields": ["secret"], "param": "q"}}]`)); err == nil {
+		t.Error("expected error for an unknown full-text field")
+	}
+}
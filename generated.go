@@ -0,0 +1,103 @@
+package edamame
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// GeneratedColumns returns the db columns of T tagged `constraints:"generated"`, such as
+// PostgreSQL GENERATED ALWAYS columns, in schema order. They are read-only: inserts and
+// upserts leave them out, while queries and RETURNING clauses still read them.
+//
+// Example:
+//
+//	type Item struct {
+//	    ID    int `db:"id" type:"integer" constraints:"primarykey"`
+//	    Price int `db:"price" type:"integer"`
+//	    Qty   int `db:"qty" type:"integer"`
+//	    Total int `db:"total" type:"integer" constraints:"generated"`
+//	}
+//	exec.GeneratedColumns() // []string{"total"}
+func (e *Executor[T]) GeneratedColumns() []string {
+	var cols []string
+	for _, field := range e.soy.Metadata().Fields {
+		col := field.Tags["db"]
+		if col == "" || col == "-" || !isGenerated(field.Tags["constraints"]) {
+			continue
+		}
+		cols = append(cols, col)
+	}
+	return cols
+}
+
+// isGenerated reports whether a constraints tag marks the column as generated by the database.
+func isGenerated(constraints string) bool {
+	for _, constraint := range strings.Split(constraints, ",") {
+		if strings.ToLower(strings.TrimSpace(constraint)) == "generated" {
+			return true
+		}
+	}
+	return false
+}
+
+// execInsertGenerated inserts record without T's generated columns, which soy's Insert
+// would write, and returns the stored row with every column.
+func (e *Executor[T]) execInsertGenerated(ctx context.Context, execer sqlx.ExtContext, record *T) (*T, error) {
+	result, err := e.renderInsertReturning(e.schemaColumns())
+	if err != nil {
+		return nil, err
+	}
+	e.emitSQL(ctx, "", "insert", result.SQL, nil)
+
+	rows, err := sqlx.NamedQueryContext(ctx, execer, result.SQL, record)
+	if err != nil {
+		return nil, fmt.Errorf("edamame: INSERT failed: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("edamame: INSERT failed: %w", err)
+		}
+		return nil, fmt.Errorf("edamame: INSERT returned no rows")
+	}
+	var inserted T
+	if err := rows.StructScan(&inserted); err != nil {
+		return nil, fmt.Errorf("edamame: failed to scan INSERT result: %w", err)
+	}
+	return &inserted, nil
+}
+
+// execInsertBatchGenerated inserts each of records without T's generated columns,
+// returning the count inserted before any failure.
+func (e *Executor[T]) execInsertBatchGenerated(ctx context.Context, execer sqlx.ExtContext, records []*T) (int64, error) {
+	if len(records) == 0 {
+		return 0, nil
+	}
+	builder, err := e.insertBuilder()
+	if err != nil {
+		return 0, err
+	}
+	result, err := builder.Render(e.renderer)
+	if err != nil {
+		return 0, fmt.Errorf("edamame: failed to render INSERT: %w", err)
+	}
+	e.emitSQL(ctx, "", "insert", result.SQL, nil)
+
+	var count int64
+	for _, record := range records {
+		res, err := sqlx.NamedExecContext(ctx, execer, result.SQL, record)
+		if err != nil {
+			return count, fmt.Errorf("edamame: batch INSERT failed after %d records: %w", count, err)
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return count, fmt.Errorf("edamame: failed to get rows affected: %w", err)
+		}
+		count += affected
+	}
+	return count, nil
+}
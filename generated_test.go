@@ -0,0 +1,59 @@
+package edamame
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+// LineItem is a test model with a database-generated column.
+type LineItem struct {
+	ID    int `db:"id" type:"integer" constraints:"primarykey"`
+	Price int `db:"price" type:"integer"`
+	Qty   int `db:"qty" type:"integer"`
+	Total int `db:"total" type:"integer" constraints:"generated"`
+}
+
+func TestGeneratedColumns(t *testing.T) {
+	db := &recordingDB{}
+	exec, err := New[LineItem](db, "line_items", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if got := exec.GeneratedColumns(); !slices.Equal(got, []string{"total"}) {
+		t.Errorf("expected generated column total, got %v", got)
+	}
+
+	result, err := exec.renderInsertReturning(exec.schemaColumns())
+	if err != nil {
+		t.Fatalf("renderInsertReturning() failed: %v", err)
+	}
+	want := `INSERT INTO "line_items" ("price", "qty") VALUES (:price, :qty) RETURNING "id", "price", "qty", "total"`
+	if result.SQL != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, result.SQL)
+	}
+
+	ctx := context.Background()
+	if _, err := exec.ExecInsert(ctx, &LineItem{Price: 5, Qty: 2}); !errors.Is(err, errRecorded) {
+		t.Fatalf("expected the insert to run, got %v", err)
+	}
+	if _, err := exec.ExecInsertBatch(ctx, []*LineItem{{Price: 5, Qty: 2}}); !errors.Is(err, errRecorded) {
+		t.Fatalf("expected the batch insert to run, got %v", err)
+	}
+	for _, q := range db.queries {
+		if strings.Contains(q, `VALUES`) && strings.Contains(q, `"total")`) {
+			t.Errorf("expected the generated column to be omitted, got %s", q)
+		}
+	}
+	if db.count() != 2 {
+		t.Errorf("expected 2 inserts, got %v", db.queries)
+	}
+
+	if _, err := exec.ExecInsertAtom(ctx, map[string]any{"price": 5, "qty": 2}); err == nil || !strings.Contains(err.Error(), "generated columns") {
+		t.Errorf("expected ExecInsertAtom to reject generated columns, got %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package edamame
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// groupValueAlias is the column alias of the aggregate value in a grouped aggregate.
+const groupValueAlias = "edamame_value"
+
+// GroupRow is one row of a grouped aggregate: the values of the GroupBy fields,
+// keyed by field name, and the aggregate computed over the group.
+type GroupRow struct {
+	Group map[string]any `json:"group"`
+	Value float64        `json:"value"`
+}
+
+// ExecGroupedAggregate executes an aggregate statement with GroupBy fields and returns
+// one row per group, ordered by the group values. A NULL aggregate value is returned as 0.
+// Aggregates without GroupBy run through ExecAggregate.
+//
+// Example:
+//
+//	var CountByStatus = edamame.NewAggregateStatement("count-by-status", "Orders per status", edamame.AggCount, edamame.AggregateSpec{
+//	    GroupBy: []string{"status"},
+//	})
+//
+//	rows, err := exec.ExecGroupedAggregate(ctx, CountByStatus, nil)
+//	// []GroupRow{{Group: {"status": "paid"}, Value: 12}, {Group: {"status": "pending"}, Value: 3}}
+func (e *Executor[T]) ExecGroupedAggregate(ctx context.Context, stmt AggregateStatement, params map[string]any) ([]GroupRow, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	return e.execGroupedAggregate(withRead(ctx), e.execer(), stmt, params)
+}
+
+// ExecGroupedAggregateTx executes a grouped aggregate statement within a transaction.
+func (e *Executor[T]) ExecGroupedAggregateTx(ctx context.Context, tx *sqlx.Tx, stmt AggregateStatement, params map[string]any) ([]GroupRow, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	return e.execGroupedAggregate(ctx, e.execerFor(tx), stmt, params)
+}
+
+// execGroupedAggregate renders the grouped aggregate and scans each group's values and aggregate.
+func (e *Executor[T]) execGroupedAggregate(ctx context.Context, execer sqlx.ExtContext, stmt AggregateStatement, params map[string]any) ([]GroupRow, error) {
+	if len(stmt.spec.GroupBy) == 0 {
+		return nil, fmt.Errorf("edamame: aggregate %q has no GroupBy fields; use ExecAggregate", stmt.name)
+	}
+	params, err := e.prepareParams(stmt, params)
+	if err != nil {
+		return nil, err
+	}
+	ctx = withStatement(ctx, stmt.name, "aggregate")
+
+	sql, err := e.renderGroupedAggregate(stmt)
+	if err != nil {
+		return nil, fmt.Errorf("edamame: failed to render %s: %w", stmt.fn, err)
+	}
+
+	e.emitSQL(ctx, stmt.name, "aggregate", sql, params)
+
+	rows, err := sqlx.NamedQueryContext(ctx, execer, sql, params)
+	if err != nil {
+		return nil, fmt.Errorf("edamame: %s query failed: %w", stmt.fn, err)
+	}
+	defer rows.Close()
+
+	fields := stmt.spec.GroupBy
+	result := make([]GroupRow, 0)
+	for rows.Next() {
+		groups := make([]any, len(fields))
+		var value *float64
+		dest := make([]any, 0, len(fields)+1)
+		for i := range groups {
+			dest = append(dest, &groups[i])
+		}
+		dest = append(dest, &value)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("edamame: failed to scan %s result: %w", stmt.fn, err)
+		}
+
+		row := GroupRow{Group: make(map[string]any, len(fields))}
+		for i, field := range fields {
+			if b, ok := groups[i].([]byte); ok {
+				groups[i] = string(b)
+			}
+			row.Group[field] = groups[i]
+		}
+		if value != nil {
+			row.Value = *value
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("edamame: %s query failed: %w", stmt.fn, err)
+	}
+	return result, nil
+}
+
+// checkUngrouped rejects a grouped aggregate on the single-value Exec methods,
+// which would otherwise return the value of an arbitrary group.
+func checkUngrouped(stmt AggregateStatement) error {
+	if len(stmt.spec.GroupBy) > 0 {
+		return fmt.Errorf("edamame: aggregate %q has GroupBy fields; use ExecGroupedAggregate", stmt.name)
+	}
+	return nil
+}
+
+// renderGroupedAggregate renders stmt as a query selecting its GroupBy fields and
+// aggregate. Statistical functions are rendered as AVG, as in renderAggregate, and
+// the AVG call is replaced with the dialect's function.
+func (e *Executor[T]) renderGroupedAggregate(stmt AggregateStatement) (string, error) {
+	var fn string
+	if isStatistical(stmt.fn) {
+		var err error
+		if fn, err = e.statisticFunc(stmt.fn); err != nil {
+			return "", err
+		}
+	}
+	spec := groupedAggregateSpec(stmt.fn, stmt.spec)
+	// The query follows the aggregate soft delete scope rather than the query scope.
+	spec.WithTrashed = true
+	if col := e.softDeleteColumn(opAggregate); col != "" && !stmt.spec.WithTrashed {
+		spec.Where = append(slices.Clip(spec.Where), ConditionSpec{Field: col, Operator: opIsNull, IsNull: true})
+	}
+	q, err := e.queryFromSpec(spec)
+	if err != nil {
+		return "", err
+	}
+	result, err := q.Render()
+	if err != nil {
+		return "", err
+	}
+	// Plain ordering by the GroupBy fields binds no params, so only the SQL is kept.
+	sql, _, err := e.finalizeSQL(result.SQL, e.queryRewrites(spec))
+	if err != nil {
+		return "", err
+	}
+	if fn == "" {
+		return sql, nil
+	}
+	if !strings.Contains(sql, " AVG(") {
+		return "", fmt.Errorf("edamame: rendered %s has no AVG call to replace", stmt.fn)
+	}
+	return strings.Replace(sql, " AVG(", " "+fn+"(", 1), nil
+}
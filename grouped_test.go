@@ -0,0 +1,117 @@
+package edamame
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/mssql"
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestRenderAggregate_Grouped(t *testing.T) {
+	exec, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	spec := AggregateSpec{Field: "age", Where: adultAges.Where, GroupBy: []string{"name", "email"}}
+
+	tests := []struct {
+		fn   AggregateFunc
+		want string
+	}{
+		{AggCount, `SELECT "name", "email", COUNT(*) AS "edamame_value" FROM "users" WHERE "age" >= :min_age GROUP BY "name", "email" ORDER BY "name" ASC, "email" ASC`},
+		{AggSum, `SELECT "name", "email", SUM("age") AS "edamame_value" FROM "users"`},
+		{AggStdDev, `SELECT "name", "email", STDDEV_SAMP("age") AS "edamame_value" FROM "users"`},
+	}
+	for _, tt := range tests {
+		sql, err := exec.RenderAggregate(NewAggregateStatement("by-name", "Ages by name", tt.fn, spec))
+		if err != nil {
+			t.Fatalf("%s: RenderAggregate() failed: %v", tt.fn, err)
+		}
+		if !strings.HasPrefix(sql, tt.want) {
+			t.Errorf("%s: expected prefix %s, got: %s", tt.fn, tt.want, sql)
+		}
+	}
+
+	ms, err := New[User](nil, "users", mssql.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	sql, err := ms.RenderAggregate(NewAggregateStatement("by-name", "Ages by name", AggVariance, spec))
+	if err != nil {
+		t.Fatalf("RenderAggregate() failed: %v", err)
+	}
+	if !strings.HasPrefix(sql, `SELECT [name], [email], VAR([age]) AS [edamame_value]`) {
+		t.Errorf("expected SQL Server VAR, got: %s", sql)
+	}
+}
+
+func TestRenderAggregate_GroupedExists(t *testing.T) {
+	exec, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	stmt := NewAggregateStatement("by-name", "Users by name with a namesake", AggCount, AggregateSpec{
+		Where: []ConditionSpec{
+			{Field: "age", Operator: "IS DISTINCT FROM", Param: "age"},
+			{Exists: true, Subquery: &QuerySpec{Where: []ConditionSpec{{Field: "email", Operator: "!=", Param: "email"}}},
+				Correlate: []CorrelationSpec{{Outer: "name", Inner: "name"}}},
+		},
+		GroupBy: []string{"name"},
+	})
+	sql, err := exec.RenderAggregate(stmt)
+	if err != nil {
+		t.Fatalf("RenderAggregate() failed: %v", err)
+	}
+	want := `SELECT "name", COUNT(*) AS "edamame_value" FROM "users" WHERE ("age" IS DISTINCT FROM :age AND ` +
+		`EXISTS (SELECT 1 FROM "users" AS edamame_sub WHERE "email" != :sub_email AND "name" = "users"."name")) GROUP BY "name"`
+	if !strings.HasPrefix(sql, want) {
+		t.Errorf("expected prefix:\n%s\ngot:\n%s", want, sql)
+	}
+	if strings.Contains(sql, existsParamPrefix) {
+		t.Errorf("expected the EXISTS placeholder to be replaced, got: %s", sql)
+	}
+}
+
+func TestExecGroupedAggregate_Routing(t *testing.T) {
+	db := &recordingDB{}
+	exec, err := New[User](db, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ctx := context.Background()
+	grouped := NewAggregateStatement("count-by-name", "Users per name", AggCount, AggregateSpec{GroupBy: []string{"name"}})
+	single := NewAggregateStatement("count", "User count", AggCount, AggregateSpec{})
+
+	if _, err := exec.ExecAggregate(ctx, grouped, nil); err == nil || !strings.Contains(err.Error(), "use ExecGroupedAggregate") {
+		t.Errorf("expected ExecAggregate to reject GroupBy, got %v", err)
+	}
+	if _, err := exec.ExecAggregateInt(ctx, grouped, nil); err == nil || !strings.Contains(err.Error(), "use ExecGroupedAggregate") {
+		t.Errorf("expected ExecAggregateInt to reject GroupBy, got %v", err)
+	}
+	if _, err := exec.ExecGroupedAggregate(ctx, single, nil); err == nil || !strings.Contains(err.Error(), "use ExecAggregate") {
+		t.Errorf("expected ExecGroupedAggregate to reject a spec without GroupBy, got %v", err)
+	}
+	if db.count() != 0 {
+		t.Fatalf("expected no statements, got %d", db.count())
+	}
+
+	if _, err := exec.ExecGroupedAggregate(ctx, grouped, nil); err == nil {
+		t.Fatal("expected the recorded error")
+	}
+	if want := `SELECT "name", COUNT(*) AS "edamame_value" FROM "users" GROUP BY "name" ORDER BY "name" ASC`; db.count() != 1 || db.queries[0] != want {
+		t.Errorf("expected %q, got %v", want, db.queries)
+	}
+}
+
+func TestGroupRow_JSON(t *testing.T) {
+	data, err := json.Marshal(GroupRow{Group: map[string]any{"status": "paid"}, Value: 12})
+	if err != nil {
+		t.Fatalf("Marshal() failed: %v", err)
+	}
+	if want := `{"group":{"status":"paid"},"value":12}`; string(data) != want {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+}
@@ -0,0 +1,107 @@
+package edamame
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// errComplexHavingUnsupported is returned by execution paths that run soy's SQL
+// unmodified and so cannot apply grouped, BETWEEN, NULL or field-comparison HAVING conditions.
+var errComplexHavingUnsupported = errors.New("edamame: grouped, BETWEEN, NULL and field-comparison HAVING conditions are not supported by this method")
+
+// isComplexHaving reports whether a HAVING condition needs more than soy's
+// field-operator-param Having.
+func isComplexHaving(c ConditionSpec) bool {
+	return c.IsGroup() || c.IsBetween() || c.IsNotBetween() || c.IsFieldComparison() || c.IsNull
+}
+
+// hasComplexHaving reports whether any HAVING condition needs rewriteHaving.
+func hasComplexHaving(having []ConditionSpec) bool {
+	for _, c := range having {
+		if isComplexHaving(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// havingParam names the placeholder param for HAVING condition i.
+func havingParam(i int) string {
+	return fmt.Sprintf("edamame_having_%d", i)
+}
+
+// havingPlaceholder returns the simple condition soy renders in place of complex
+// HAVING condition i: its field (or a group's first field) compared with a placeholder param.
+func havingPlaceholder(i int, c ConditionSpec) ConditionSpec {
+	field := c.Field
+	for c.IsGroup() && field == "" {
+		c = c.Group[0]
+		field = c.Field
+	}
+	return ConditionSpec{Field: field, Operator: "=", Param: havingParam(i)}
+}
+
+// withHavingPlaceholders returns having with each complex condition swapped for its
+// placeholder, to be restored by rewriteHaving.
+func withHavingPlaceholders(having []ConditionSpec) []ConditionSpec {
+	if !hasComplexHaving(having) {
+		return having
+	}
+	replaced := make([]ConditionSpec, len(having))
+	for i, c := range having {
+		if isComplexHaving(c) {
+			c = havingPlaceholder(i, c)
+		}
+		replaced[i] = c
+	}
+	return replaced
+}
+
+// rewriteHaving replaces the placeholders rendered for complex HAVING conditions with
+// the conditions themselves. soy's Having only takes field-operator-param, so each
+// condition is rendered as the WHERE clause of a bare query, which supports the same
+// variety as WHERE, and spliced into the HAVING clause.
+func (e *Executor[T]) rewriteHaving(sql string, having []ConditionSpec) (string, error) {
+	if !hasComplexHaving(having) {
+		return sql, nil
+	}
+	start := strings.Index(sql, " HAVING ")
+	if start < 0 {
+		return "", fmt.Errorf("edamame: rendered SQL has no HAVING clause")
+	}
+
+	for i, c := range e.mapConditions(having) {
+		if !isComplexHaving(c) {
+			continue
+		}
+		placeholder, err := e.conditionSQL(havingPlaceholder(i, c))
+		if err != nil {
+			return "", err
+		}
+		condition, err := e.conditionSQL(c)
+		if err != nil {
+			return "", err
+		}
+		offsets := placeholderOffsets(sql[start:], placeholder)
+		if len(offsets) != 1 {
+			return "", fmt.Errorf("edamame: rendered HAVING clause has %d placeholders for condition %d, expected 1", len(offsets), i)
+		}
+		at := start + offsets[0]
+		sql = sql[:at] + condition + sql[at+len(placeholder):]
+	}
+	return sql, nil
+}
+
+// conditionSQL renders a single condition as soy would in a WHERE clause.
+func (e *Executor[T]) conditionSQL(c ConditionSpec) (string, error) {
+	result, err := applyConditionToQuery(e.soy.Query(), c).Render()
+	if err != nil {
+		return "", fmt.Errorf("edamame: invalid HAVING condition: %w", err)
+	}
+	_, where, ok := strings.Cut(result.SQL, " WHERE ")
+	if !ok {
+		return "", fmt.Errorf("edamame: rendered HAVING condition has no WHERE clause")
+	}
+	return where, nil
+}
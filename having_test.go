@@ -0,0 +1,99 @@
+package edamame
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/mariadb"
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestHavingBetween_Render(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	stmt := NewQueryStatement("ages-in-range", "Ages within a range", QuerySpec{
+		Fields:  []string{"age"},
+		GroupBy: []string{"age"},
+		Having: []ConditionSpec{
+			{Field: "age", Between: true, LowParam: "min_age", HighParam: "max_age"},
+		},
+		HavingAgg: []HavingAggSpec{{Func: "count", Operator: ">=", Param: "min_count"}},
+	})
+
+	sql, err := factory.RenderQuery(stmt)
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+	if !strings.Contains(sql, `HAVING "age" BETWEEN :min_age AND :max_age`) {
+		t.Errorf("expected BETWEEN in HAVING, got: %s", sql)
+	}
+	if !strings.Contains(sql, "COUNT(*) >= :min_count") {
+		t.Errorf("expected aggregate HAVING to be kept, got: %s", sql)
+	}
+	if strings.Contains(sql, "edamame_having") {
+		t.Errorf("placeholder left in SQL: %s", sql)
+	}
+
+	var names []string
+	for _, p := range stmt.Params() {
+		names = append(names, p.Name)
+	}
+	if strings.Join(names, ",") != "min_age,max_age,min_count" {
+		t.Errorf("unexpected derived params: %v", names)
+	}
+}
+
+func TestHavingVariants_Render(t *testing.T) {
+	factory, err := New[User](nil, "users", mariadb.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	stmt := NewSelectStatement("grouped", "Grouped with mixed HAVING", SelectSpec{
+		Fields:  []string{"age", "id"},
+		GroupBy: []string{"age", "id"},
+		Having: []ConditionSpec{
+			{Field: "age", Operator: ">", Param: "min_age"},
+			{Logic: "OR", Group: []ConditionSpec{
+				{Field: "age", Operator: "IS NULL", IsNull: true},
+				{Field: "age", NotBetween: true, LowParam: "lo", HighParam: "hi"},
+			}},
+			{Field: "id", Operator: "<", RightField: "age"},
+		},
+	})
+
+	sql, err := factory.RenderSelect(stmt)
+	if err != nil {
+		t.Fatalf("RenderSelect() failed: %v", err)
+	}
+	want := "HAVING `age` > :min_age AND (`age` IS NULL OR `age` NOT BETWEEN :lo AND :hi) AND `id` < `age`"
+	if !strings.Contains(sql, want) {
+		t.Errorf("expected SQL to contain:\n%s\ngot:\n%s", want, sql)
+	}
+}
+
+func TestHavingComplex_UnsupportedPaths(t *testing.T) {
+	factory, err := New[User](&recordingDB{}, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	having := []ConditionSpec{{Field: "age", Between: true, LowParam: "lo", HighParam: "hi"}}
+	stmt := NewQueryStatement("grouped", "Grouped ages", QuerySpec{Fields: []string{"age"}, GroupBy: []string{"age"}, Having: having})
+	if _, err := factory.ExecQueryAtom(context.Background(), stmt, nil); !errors.Is(err, errComplexHavingUnsupported) {
+		t.Errorf("expected errComplexHavingUnsupported, got %v", err)
+	}
+
+	compound := CompoundQuerySpec{
+		Base:     QuerySpec{Fields: []string{"age"}, GroupBy: []string{"age"}, Having: having},
+		Operands: []SetOperandSpec{{Operation: "union", Query: QuerySpec{Fields: []string{"age"}}}},
+	}
+	if _, err := factory.RenderCompound(compound); !errors.Is(err, errComplexHavingUnsupported) {
+		t.Errorf("expected errComplexHavingUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,289 @@
+package edamame
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/astql/pkg/postgres"
+	"github.com/zoobzio/soy"
+)
+
+// supportsHints reports whether the executor's dialect reads leading optimizer hint
+// comments. Only PostgreSQL (with the pg_hint_plan extension) does.
+func (e *Executor[T]) supportsHints() bool {
+	return e.isPostgres()
+}
+
+// isPostgres reports whether the executor renders PostgreSQL.
+func (e *Executor[T]) isPostgres() bool {
+	_, ok := e.dialect().(*postgres.Renderer)
+	return ok
+}
+
+// withIndexHint prepends hint to sql as a /*+ ... */ comment.
+// Returns sql unchanged when hint is empty or the dialect has no hint support.
+func (e *Executor[T]) withIndexHint(sql, hint string) (string, error) {
+	if hint == "" {
+		return sql, nil
+	}
+	if strings.Contains(hint, "*/") {
+		return "", fmt.Errorf("edamame: index hint must not contain %q", "*/")
+	}
+	if !e.supportsHints() {
+		return sql, nil
+	}
+	return "/*+ " + hint + " */ " + sql, nil
+}
+
+// sqlRewrites holds the parts of a query or select spec that edamame applies to
+// soy-rendered SQL rather than through soy's builders.
+type sqlRewrites struct {
+	aliases    map[string]string
+	exprs      []SelectExprSpec
+	where      []ConditionSpec
+	outerWhere []ConditionSpec
+	having     []ConditionSpec
+	orderBy    []OrderBySpec
+	forLocking string
+	hint       string
+	likeEscape bool // match conditions need an ESCAPE clause in the dialect
+}
+
+// queryRewrites returns the rewrites a query spec needs.
+func (e *Executor[T]) queryRewrites(spec QuerySpec) sqlRewrites {
+	return sqlRewrites{aliases: spec.FieldAliases, exprs: spec.SelectExprs, where: spec.Where, outerWhere: spec.OuterWhere,
+		having: spec.Having, orderBy: spec.OrderBy, forLocking: spec.ForLocking, hint: spec.IndexHint,
+		likeEscape: e.likeEscapes() && len(collectMatches(e.expandFragments(spec.Where), nil)) > 0}
+}
+
+// selectRewrites returns the rewrites a select spec needs.
+func (e *Executor[T]) selectRewrites(spec SelectSpec) sqlRewrites {
+	return sqlRewrites{aliases: spec.FieldAliases, exprs: spec.SelectExprs, where: spec.Where, outerWhere: spec.OuterWhere,
+		having: spec.Having, orderBy: spec.OrderBy, forLocking: spec.ForLocking, hint: spec.IndexHint,
+		likeEscape: e.likeEscapes() && len(collectMatches(e.expandFragments(spec.Where), nil)) > 0}
+}
+
+// needed reports whether soy's SQL must be rewritten before it runs.
+func (r sqlRewrites) needed() bool {
+	return r.hint != "" || r.unsupported() != nil
+}
+
+// unsupported returns the error for execution paths that run soy's SQL unmodified,
+// or nil if the spec needs no rewrite beyond an index hint, which such paths skip.
+func (r sqlRewrites) unsupported() error {
+	switch {
+	case hasCustomOrdering(r.orderBy):
+		return errCustomOrderingUnsupported
+	case hasDistinctFrom(r.where):
+		return errDistinctFromUnsupported
+	case hasQuantified(r.where):
+		return errQuantifierUnsupported
+	case hasJSONPath(r.where):
+		return errJSONPathUnsupported
+	case hasFullText(r.where):
+		return errFullTextUnsupported
+	case hasExists(r.where):
+		return errExistsUnsupported
+	case r.likeEscape:
+		return errMatchUnsupported
+	case len(r.aliases) > 0:
+		return errFieldAliasesUnsupported
+	case hasRowExprs(r.exprs):
+		return errRowExprUnsupported
+	case hasComplexHaving(r.having):
+		return errComplexHavingUnsupported
+	case len(r.outerWhere) > 0:
+		return errOuterWhereUnsupported
+	}
+	return nil
+}
+
+// finalizeSQL applies the rewrites edamame makes to soy-rendered SQL: field aliases,
+// row_min and row_max expressions, null-safe, quantified, JSONB path and full-text comparisons, EXISTS subqueries,
+// match condition ESCAPE clauses, complex HAVING conditions, custom ordering,
+// the OuterWhere wrapping query and index hints.
+// Returns the final SQL and any extra params it binds.
+func (e *Executor[T]) finalizeSQL(sql string, r sqlRewrites) (string, map[string]any, error) {
+	sql, err := rewriteFieldAliases(sql, e.columnKeys(r.aliases))
+	if err != nil {
+		return "", nil, err
+	}
+	sql, err = e.rewriteRowExprs(sql, r.exprs)
+	if err != nil {
+		return "", nil, err
+	}
+	sql, err = e.rewriteComparisons(sql, r.where)
+	if err != nil {
+		return "", nil, err
+	}
+	sql, err = e.rewriteExists(sql, r.where)
+	if err != nil {
+		return "", nil, err
+	}
+	if r.likeEscape {
+		sql = escapeMatches(sql, e.expandFragments(r.where))
+	}
+	sql, err = e.rewriteHaving(sql, r.having)
+	if err != nil {
+		return "", nil, err
+	}
+	sql, binds, err := rewriteCustomOrdering(sql, r.orderBy)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(r.outerWhere) > 0 && r.forLocking != "" {
+		return "", nil, fmt.Errorf("edamame: OuterWhere cannot be combined with ForLocking")
+	}
+	sql, err = e.wrapOuterWhere(sql, r.outerWhere, selectAliases(r.exprs, r.aliases))
+	if err != nil {
+		return "", nil, err
+	}
+	sql, err = e.withIndexHint(sql, r.hint)
+	if err != nil {
+		return "", nil, err
+	}
+	return sql, binds, nil
+}
+
+// rewriteComparisons restores the WHERE comparisons soy cannot render, which it
+// rendered as placeholders: null-safe, quantified, JSONB path and full-text comparisons.
+func (e *Executor[T]) rewriteComparisons(sql string, where []ConditionSpec) (string, error) {
+	sql, err := e.rewriteDistinctFrom(sql, where)
+	if err != nil {
+		return "", err
+	}
+	sql, err = rewriteQuantified(sql, where)
+	if err != nil {
+		return "", err
+	}
+	sql, err = rewriteJSONPaths(sql, where)
+	if err != nil {
+		return "", err
+	}
+	return e.rewriteFullText(sql, where)
+}
+
+// runQuery executes a query builder, routing through rewritten SQL when the
+// statement needs SQL rewrites (see finalizeSQL) or carries an index hint the dialect supports.
+// A nil tx executes outside a transaction.
+func (e *Executor[T]) runQuery(ctx context.Context, tx *sqlx.Tx, stmt QueryStatement, q *soy.Query[T], params map[string]any) ([]*T, error) {
+	params, err := e.prepareParams(stmt, params)
+	if err != nil {
+		return nil, err
+	}
+	ctx = withStatement(ctx, stmt.name, "query")
+	if r := e.queryRewrites(stmt.spec); r.needed() {
+		result, err := q.Render()
+		if err != nil {
+			return nil, err
+		}
+		sql, binds, err := e.finalizeSQL(result.SQL, r)
+		if err != nil {
+			return nil, err
+		}
+		if sql != result.SQL {
+			params = mergeParams(params, binds)
+			e.emitSQL(ctx, stmt.name, "query", sql, params)
+			return execRenderedQuery[T](ctx, e.execerFor(tx), sql, params)
+		}
+	}
+
+	e.emitRendered(ctx, stmt.name, "query", q, params)
+	if tx != nil {
+		return q.ExecTx(ctx, tx, params)
+	}
+	return q.Exec(ctx, params)
+}
+
+// runSelect executes a select builder, routing through rewritten SQL when the
+// statement needs SQL rewrites (see finalizeSQL) or carries an index hint the dialect supports.
+// A nil tx executes outside a transaction.
+func (e *Executor[T]) runSelect(ctx context.Context, tx *sqlx.Tx, stmt SelectStatement, s *soy.Select[T], params map[string]any) (*T, error) {
+	params, err := e.prepareParams(stmt, params)
+	if err != nil {
+		return nil, err
+	}
+	ctx = withStatement(ctx, stmt.name, "select")
+	if r := e.selectRewrites(stmt.spec); r.needed() {
+		result, err := s.Render()
+		if err != nil {
+			return nil, err
+		}
+		sql, binds, err := e.finalizeSQL(result.SQL, r)
+		if err != nil {
+			return nil, err
+		}
+		if sql != result.SQL {
+			params = mergeParams(params, binds)
+			e.emitSQL(ctx, stmt.name, "select", sql, params)
+			return execRenderedSelect[T](ctx, e.execerFor(tx), sql, params)
+		}
+	}
+
+	e.emitRendered(ctx, stmt.name, "select", s, params)
+	var record *T
+	if tx != nil {
+		record, err = s.ExecTx(ctx, tx, params)
+	} else {
+		record, err = s.Exec(ctx, params)
+	}
+	return record, notFound(err)
+}
+
+// notFound returns ErrNotFound in place of soy's error for a select that matched no
+// row, and err otherwise. soy does not wrap a sentinel, so the error is matched by message.
+func notFound(err error) error {
+	if err != nil && err.Error() == "no rows found" {
+		return ErrNotFound
+	}
+	return err
+}
+
+// execerFor returns tx when set, otherwise the executor's database.
+// Transactions are wrapped so their statements carry SQL comments too.
+func (e *Executor[T]) execerFor(tx *sqlx.Tx) sqlx.ExtContext {
+	if tx != nil {
+		return &routedDB{primary: tx, annotate: e.annotateSQL}
+	}
+	return e.execer()
+}
+
+// execRenderedQuery runs already-rendered SQL with named params and scans every row into T.
+func execRenderedQuery[T any](ctx context.Context, execer sqlx.ExtContext, query string, params map[string]any) ([]*T, error) {
+	rows, err := sqlx.NamedQueryContext(ctx, execer, query, params)
+	if err != nil {
+		return nil, fmt.Errorf("edamame: query execution failed: %w", err)
+	}
+	defer rows.Close()
+
+	records := make([]*T, 0)
+	for rows.Next() {
+		var record T
+		if err := rows.StructScan(&record); err != nil {
+			return nil, fmt.Errorf("edamame: failed to scan row: %w", err)
+		}
+		records = append(records, &record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("edamame: query execution failed: %w", err)
+	}
+	return records, nil
+}
+
+// execRenderedSelect runs already-rendered SQL with named params and scans exactly one row into T.
+func execRenderedSelect[T any](ctx context.Context, execer sqlx.ExtContext, query string, params map[string]any) (*T, error) {
+	records, err := execRenderedQuery[T](ctx, execer, query, params)
+	if err != nil {
+		return nil, err
+	}
+	switch len(records) {
+	case 0:
+		return nil, ErrNotFound
+	case 1:
+		return records[0], nil
+	default:
+		return nil, fmt.Errorf("edamame: expected exactly one row, found multiple")
+	}
+}
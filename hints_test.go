@@ -0,0 +1,102 @@
+package edamame
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/mariadb"
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+var hintedByEmail = NewQueryStatement("hinted-by-email", "Users by email with index hint", QuerySpec{
+	Where:     []ConditionSpec{{Field: "email", Operator: "=", Param: "email"}},
+	IndexHint: "IndexScan(users users_email_idx)",
+})
+
+func TestIndexHint_Render(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	sql, err := factory.RenderQuery(hintedByEmail)
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+	if !strings.HasPrefix(sql, "/*+ IndexScan(users users_email_idx) */ SELECT") {
+		t.Errorf("expected leading hint comment, got: %s", sql)
+	}
+
+	hintedSelect := NewSelectStatement("hinted-by-id", "User by id with index hint", SelectSpec{
+		Where:     []ConditionSpec{{Field: "id", Operator: "=", Param: "id"}},
+		IndexHint: "IndexScan(users users_pkey)",
+	})
+	sql, err = factory.RenderSelect(hintedSelect)
+	if err != nil {
+		t.Fatalf("RenderSelect() failed: %v", err)
+	}
+	if !strings.HasPrefix(sql, "/*+ IndexScan(users users_pkey) */ SELECT") {
+		t.Errorf("expected leading hint comment, got: %s", sql)
+	}
+}
+
+func TestIndexHint_IgnoredWithoutHintSupport(t *testing.T) {
+	factory, err := New[User](nil, "users", mariadb.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	sql, err := factory.RenderQuery(hintedByEmail)
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+	if strings.Contains(sql, "/*+") {
+		t.Errorf("hint should be ignored on mariadb, got: %s", sql)
+	}
+}
+
+func TestIndexHint_RejectsCommentTerminator(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	stmt := NewQueryStatement("bad-hint", "Hint closing the comment", QuerySpec{
+		IndexHint: "SeqScan(users) */ DROP TABLE users; /*",
+	})
+	if _, err := factory.RenderQuery(stmt); err == nil {
+		t.Error("RenderQuery() should reject a hint containing */")
+	}
+}
+
+func TestIndexHint_Exec(t *testing.T) {
+	db := &recordingDB{}
+	factory, err := New[User](db, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	_, _ = factory.ExecQuery(context.Background(), hintedByEmail, map[string]any{"email": "a@test.com"})
+	if db.count() != 1 {
+		t.Fatalf("expected 1 statement, got %d", db.count())
+	}
+	if !strings.HasPrefix(db.queries[0], "/*+ IndexScan(users users_email_idx) */ SELECT") {
+		t.Errorf("expected hinted SQL to be executed, got: %s", db.queries[0])
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	if err := notFound(fmt.Errorf("no rows found")); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	other := fmt.Errorf("query execution failed: boom")
+	if err := notFound(other); err != other {
+		t.Errorf("expected other errors unchanged, got %v", err)
+	}
+	if err := notFound(nil); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+}
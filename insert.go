@@ -0,0 +1,241 @@
+package edamame
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/astql"
+)
+
+// ExecInsertReturningInto inserts record and scans the RETURNING columns cols into dest.
+// Use it to return a projection of the inserted row, such as an audit record, rather
+// than the full model. Fields of R are matched to cols by their db tags.
+//
+// Example:
+//
+//	var audit struct {
+//	    ID        int       `db:"id"`
+//	    CreatedAt time.Time `db:"created_at"`
+//	}
+//	err := edamame.ExecInsertReturningInto(ctx, exec, user, []string{"id", "created_at"}, &audit)
+func ExecInsertReturningInto[T, R any](ctx context.Context, e *Executor[T], record *T, cols []string, dest *R) error {
+	ctx, cancel := e.withTimeout(ctx, "")
+	defer cancel()
+	return execInsertReturningInto(ctx, e, e.execer(), record, cols, dest)
+}
+
+// ExecInsertReturningIntoTx inserts record within a transaction and scans the RETURNING columns cols into dest.
+func ExecInsertReturningIntoTx[T, R any](ctx context.Context, e *Executor[T], tx *sqlx.Tx, record *T, cols []string, dest *R) error {
+	ctx, cancel := e.withTimeout(ctx, "")
+	defer cancel()
+	return execInsertReturningInto(ctx, e, e.execerFor(tx), record, cols, dest)
+}
+
+// execInsertReturningInto renders an INSERT returning cols and scans the single returned row into dest.
+func execInsertReturningInto[T, R any](ctx context.Context, e *Executor[T], execer sqlx.ExtContext, record *T, cols []string, dest *R) error {
+	if err := e.checkWrite(ctx, "ExecInsertReturningInto"); err != nil {
+		return err
+	}
+	result, err := e.renderInsertReturning(cols)
+	if err != nil {
+		return err
+	}
+	ctx = withStatement(ctx, "", "insert")
+	e.emitSQL(ctx, "", "insert", result.SQL, nil)
+
+	rows, err := sqlx.NamedQueryContext(ctx, execer, result.SQL, record)
+	if err != nil {
+		return fmt.Errorf("edamame: INSERT failed: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("edamame: INSERT failed: %w", err)
+		}
+		return fmt.Errorf("edamame: INSERT returned no rows")
+	}
+	if err := rows.StructScan(dest); err != nil {
+		return fmt.Errorf("edamame: failed to scan INSERT result: %w", err)
+	}
+	if err := rows.Close(); err != nil {
+		return fmt.Errorf("edamame: INSERT failed: %w", err)
+	}
+	// dest is not a T, so the payload function gets nil, as for ExecDelete.
+	return e.notifyWrite(ctx, execer, nil)
+}
+
+// renderInsertReturning renders an INSERT of every non-primary-key column of T, returning only cols.
+func (e *Executor[T]) renderInsertReturning(cols []string) (*astql.QueryResult, error) {
+	if len(cols) == 0 {
+		return nil, fmt.Errorf("edamame: at least one returning column is required")
+	}
+	for _, col := range cols {
+		if _, ok := e.columns[col]; !ok {
+			return nil, fmt.Errorf("edamame: unknown returning column %q", col)
+		}
+	}
+
+	builder, err := e.insertBuilder()
+	if err != nil {
+		return nil, err
+	}
+	builder, err = e.returning(builder, cols)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := builder.Render(e.renderer)
+	if err != nil {
+		return nil, fmt.Errorf("edamame: failed to render INSERT: %w", err)
+	}
+	return result, nil
+}
+
+// insertBuilder builds an INSERT of every non-primary-key column of T, each bound
+// to the param of the same name. Mirrors the column selection of soy's Insert, except
+// that generated columns are left out.
+func (e *Executor[T]) insertBuilder() (*astql.Builder, error) {
+	instance := e.soy.Instance()
+	t, err := instance.TryT(e.soy.TableName())
+	if err != nil {
+		return nil, fmt.Errorf("edamame: invalid table %q: %w", e.soy.TableName(), err)
+	}
+
+	values := instance.ValueMap()
+	for _, col := range e.insertColumns() {
+		f, err := instance.TryF(col)
+		if err != nil {
+			return nil, fmt.Errorf("edamame: invalid field %q: %w", col, err)
+		}
+		p, err := instance.TryP(col)
+		if err != nil {
+			return nil, fmt.Errorf("edamame: invalid param %q: %w", col, err)
+		}
+		values[f] = p
+	}
+	return astql.Insert(t).Values(values), nil
+}
+
+// insertColumns returns the db columns of T an insert writes: all but the primary key
+// and generated columns.
+func (e *Executor[T]) insertColumns() []string {
+	fields := e.soy.Metadata().Fields
+	cols := make([]string, 0, len(fields))
+	for _, field := range fields {
+		col := field.Tags["db"]
+		if col == "" || col == "-" || isPrimaryKey(field.Tags["constraints"]) || isGenerated(field.Tags["constraints"]) {
+			continue
+		}
+		cols = append(cols, col)
+	}
+	return cols
+}
+
+// returning adds a RETURNING clause for cols to builder.
+func (e *Executor[T]) returning(builder *astql.Builder, cols []string) (*astql.Builder, error) {
+	instance := e.soy.Instance()
+	for _, col := range cols {
+		f, err := instance.TryF(col)
+		if err != nil {
+			return nil, fmt.Errorf("edamame: invalid field %q: %w", col, err)
+		}
+		builder = builder.Returning(f)
+	}
+	return builder, nil
+}
+
+// ExecInsertDefaults inserts a row made entirely of column defaults and returns it, using
+// INSERT ... DEFAULT VALUES. Every non-primary-key column of T must accept a default: a
+// NOT NULL column needs a `default` tag or a serial type, otherwise an error is returned
+// without touching the database. PostgreSQL only.
+//
+// Example:
+//
+//	session, err := sessions.ExecInsertDefaults(ctx)
+func (e *Executor[T]) ExecInsertDefaults(ctx context.Context) (*T, error) {
+	ctx, cancel := e.withTimeout(ctx, "")
+	defer cancel()
+	if err := e.checkWrite(ctx, "ExecInsertDefaults"); err != nil {
+		return nil, err
+	}
+	record, err := e.execInsertDefaults(ctx, e.execer())
+	return e.notifyRecord(ctx, e.execer(), record, err)
+}
+
+// ExecInsertDefaultsTx inserts a row of column defaults within a transaction.
+func (e *Executor[T]) ExecInsertDefaultsTx(ctx context.Context, tx *sqlx.Tx) (*T, error) {
+	ctx, cancel := e.withTimeout(ctx, "")
+	defer cancel()
+	if err := e.checkWrite(ctx, "ExecInsertDefaults"); err != nil {
+		return nil, err
+	}
+	record, err := e.execInsertDefaults(ctx, e.execerFor(tx))
+	return e.notifyRecord(ctx, e.execerFor(tx), record, err)
+}
+
+// execInsertDefaults renders the DEFAULT VALUES insert and scans the returned row.
+func (e *Executor[T]) execInsertDefaults(ctx context.Context, execer sqlx.ExtContext) (*T, error) {
+	sql, err := e.renderInsertDefaults()
+	if err != nil {
+		return nil, err
+	}
+	ctx = withStatement(ctx, "", "insert")
+	e.emitSQL(ctx, "", "insert", sql, nil)
+
+	var record T
+	if err := execer.QueryRowxContext(ctx, sql).StructScan(&record); err != nil {
+		return nil, fmt.Errorf("edamame: INSERT failed: %w", err)
+	}
+	return &record, nil
+}
+
+// renderInsertDefaults builds "INSERT INTO t DEFAULT VALUES RETURNING ..." over every
+// column of T. astql requires at least one value, so it is formatted directly; the table
+// and column names were validated against the schema by soy.
+func (e *Executor[T]) renderInsertDefaults() (string, error) {
+	if !e.isPostgres() {
+		return "", fmt.Errorf("edamame: ExecInsertDefaults requires the postgres renderer")
+	}
+	for _, field := range e.soy.Metadata().Fields {
+		col := field.Tags["db"]
+		if col == "" || col == "-" || isPrimaryKey(field.Tags["constraints"]) || isGenerated(field.Tags["constraints"]) {
+			continue
+		}
+		if _, ok := field.Tags["default"]; ok || isSerial(field.Tags["type"]) {
+			continue
+		}
+		if isNotNull(field.Tags["constraints"]) {
+			return "", fmt.Errorf("edamame: column %q is NOT NULL without a default", col)
+		}
+	}
+
+	columns := e.schemaColumns()
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = e.quoteIdent(col)
+	}
+	return fmt.Sprintf(`INSERT INTO %s DEFAULT VALUES RETURNING %s`, e.quotedTableName(), strings.Join(quoted, ", ")), nil
+}
+
+// isNotNull reports whether a constraints tag marks the column NOT NULL.
+func isNotNull(constraints string) bool {
+	for _, constraint := range strings.Split(constraints, ",") {
+		switch strings.ToLower(strings.TrimSpace(constraint)) {
+		case "notnull", "not_null":
+			return true
+		}
+	}
+	return false
+}
+
+// isSerial reports whether a type tag names an auto-incrementing PostgreSQL type.
+func isSerial(typ string) bool {
+	switch strings.ToLower(strings.TrimSpace(typ)) {
+	case "serial", "smallserial", "bigserial", "serial2", "serial4", "serial8":
+		return true
+	}
+	return false
+}
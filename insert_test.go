@@ -0,0 +1,125 @@
+package edamame
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/mariadb"
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestRenderInsertReturning(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	result, err := factory.renderInsertReturning([]string{"id", "email"})
+	if err != nil {
+		t.Fatalf("renderInsertReturning() failed: %v", err)
+	}
+	if !strings.Contains(result.SQL, `RETURNING "id", "email"`) {
+		t.Errorf("expected RETURNING projection, got: %s", result.SQL)
+	}
+	if !strings.Contains(result.SQL, `INSERT INTO "users"`) {
+		t.Errorf("expected INSERT into users, got: %s", result.SQL)
+	}
+
+	if _, err := factory.renderInsertReturning([]string{"id", "created_at"}); err == nil {
+		t.Error("renderInsertReturning() should fail for an unknown column")
+	}
+	if _, err := factory.renderInsertReturning(nil); err == nil {
+		t.Error("renderInsertReturning() should fail without columns")
+	}
+}
+
+func TestRenderInsert(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	sql, err := factory.RenderInsert()
+	if err != nil {
+		t.Fatalf("RenderInsert() failed: %v", err)
+	}
+	want := `INSERT INTO "users" ("age", "email", "name") VALUES (:age, :email, :name)`
+	if !strings.HasPrefix(sql, want) {
+		t.Errorf("expected prefix:\n%s\ngot:\n%s", want, sql)
+	}
+	if strings.Contains(sql, "ON CONFLICT") {
+		t.Errorf("expected no conflict handling, got: %s", sql)
+	}
+
+	sql, err = factory.RenderInsertSpec(CreateSpec{OnConflict: []string{"email"}, ConflictAction: "nothing"})
+	if err != nil {
+		t.Fatalf("RenderInsertSpec() failed: %v", err)
+	}
+	if !strings.HasPrefix(sql, want) || !strings.Contains(sql, `ON CONFLICT ("email") DO NOTHING`) {
+		t.Errorf("expected ON CONFLICT DO NOTHING, got: %s", sql)
+	}
+
+	sql, err = factory.RenderInsertSpec(CreateSpec{OnConflict: []string{"email"}, ConflictAction: "update", ConflictSet: map[string]string{"name": "name"}})
+	if err != nil {
+		t.Fatalf("RenderInsertSpec() failed: %v", err)
+	}
+	if !strings.Contains(sql, `ON CONFLICT ("email") DO UPDATE SET "name" = :name`) {
+		t.Errorf("expected ON CONFLICT DO UPDATE, got: %s", sql)
+	}
+
+	if _, err := factory.RenderInsertSpec(CreateSpec{OnConflict: []string{"email"}}); err == nil {
+		t.Error("RenderInsertSpec() should fail without a conflict action")
+	}
+
+	// Generated columns are left out, as ExecInsert leaves them out.
+	items, err := New[LineItem](nil, "line_items", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	sql, err = items.RenderInsert()
+	if err != nil {
+		t.Fatalf("RenderInsert() failed: %v", err)
+	}
+	if want := `INSERT INTO "line_items" ("price", "qty") VALUES (:price, :qty) RETURNING "id", "price", "qty", "total"`; sql != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, sql)
+	}
+}
+
+// Visit is a model whose columns all have defaults.
+type Visit struct {
+	ID       int     `db:"id" type:"serial" constraints:"primarykey"`
+	Status   string  `db:"status" type:"text" constraints:"notnull" default:"'new'"`
+	Counter  int     `db:"counter" type:"bigserial" constraints:"notnull"`
+	Referrer *string `db:"referrer" type:"text"`
+}
+
+func TestRenderInsertDefaults(t *testing.T) {
+	visits, err := New[Visit](nil, "visits", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	sql, err := visits.renderInsertDefaults()
+	if err != nil {
+		t.Fatalf("renderInsertDefaults() failed: %v", err)
+	}
+	want := `INSERT INTO "visits" DEFAULT VALUES RETURNING "id", "status", "counter", "referrer"`
+	if sql != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, sql)
+	}
+
+	users, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if _, err := users.renderInsertDefaults(); err == nil || !strings.Contains(err.Error(), `"email"`) {
+		t.Errorf("expected error naming the NOT NULL email column, got %v", err)
+	}
+
+	mariaVisits, err := New[Visit](nil, "visits", mariadb.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if _, err := mariaVisits.renderInsertDefaults(); err == nil {
+		t.Error("renderInsertDefaults() should require the postgres renderer")
+	}
+}
@@ -0,0 +1,105 @@
+package edamame
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ExecQueryIter executes a query statement and streams its records one row at a time,
+// so only the current record is held in memory. Params are checked and the SQL is
+// rendered before it returns; the query itself runs when iteration starts.
+//
+// Each step yields a record or, last, a non-nil error. The rows are closed when the
+// loop ends, including when it breaks early, and cancelling ctx stops iteration with
+// ctx's error. Result assertions and MaxResults apply as in ExecQuery; result dedup
+// does not, since it needs the whole result. The executor's timeout for the statement
+// bounds the whole iteration.
+//
+// Example:
+//
+//	users, err := exec.ExecQueryIter(ctx, AllUsers, nil)
+//	if err != nil {
+//	    return err
+//	}
+//	for user, err := range users {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    process(user)
+//	}
+func (e *Executor[T]) ExecQueryIter(ctx context.Context, stmt QueryStatement, params map[string]any) (iter.Seq2[*T, error], error) {
+	return e.queryIter(withRead(ctx), nil, stmt, params)
+}
+
+// ExecQueryIterTx streams a query statement's records within a transaction, as ExecQueryIter.
+// The iteration must finish before the transaction ends.
+func (e *Executor[T]) ExecQueryIterTx(ctx context.Context, tx *sqlx.Tx, stmt QueryStatement, params map[string]any) (iter.Seq2[*T, error], error) {
+	return e.queryIter(ctx, tx, stmt, params)
+}
+
+// queryIter renders stmt and returns the iterator that runs it. A nil tx executes
+// outside a transaction.
+func (e *Executor[T]) queryIter(ctx context.Context, tx *sqlx.Tx, stmt QueryStatement, params map[string]any) (iter.Seq2[*T, error], error) {
+	q, err := e.Query(stmt)
+	if err != nil {
+		return nil, err
+	}
+	params, err = e.prepareParams(stmt, params)
+	if err != nil {
+		return nil, err
+	}
+	result, err := q.Render()
+	if err != nil {
+		return nil, fmt.Errorf("edamame: failed to render query: %w", err)
+	}
+	sql, binds, err := e.finalizeSQL(result.SQL, e.queryRewrites(stmt.spec))
+	if err != nil {
+		return nil, err
+	}
+	params = mergeParams(params, binds)
+	execer := e.execerFor(tx)
+
+	return func(yield func(*T, error) bool) {
+		ctx, cancel := e.withTimeout(ctx, stmt.name)
+		defer cancel()
+		ctx = withStatement(ctx, stmt.name, "query")
+		e.emitSQL(ctx, stmt.name, "query", sql, params)
+
+		rows, err := sqlx.NamedQueryContext(ctx, execer, sql, params)
+		if err != nil {
+			yield(nil, fmt.Errorf("edamame: query execution failed: %w", err))
+			return
+		}
+		defer rows.Close()
+
+		for n := 0; rows.Next(); n++ {
+			if stmt.spec.MaxResults > 0 && n >= stmt.spec.MaxResults {
+				// The remaining rows are not read, so the event counts only the first one past the cap.
+				e.emitResultsCapped(ctx, stmt, n+1)
+				return
+			}
+			var record T
+			if err := rows.StructScan(&record); err != nil {
+				yield(nil, fmt.Errorf("edamame: failed to scan row: %w", err))
+				return
+			}
+			if err := e.assertResults(&record); err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(&record, nil) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			// A read interrupted by cancellation surfaces as a driver error; report the cause.
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			}
+			yield(nil, fmt.Errorf("edamame: query execution failed: %w", err))
+		}
+	}, nil
+}
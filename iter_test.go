@@ -0,0 +1,44 @@
+package edamame
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestExecQueryIter(t *testing.T) {
+	db := &recordingDB{}
+	exec, err := New[User](db, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ctx := context.Background()
+	adults := NewQueryStatement("adults", "Users at or above an age", QuerySpec{
+		Where: []ConditionSpec{{Field: "age", Operator: ">=", Param: "min_age"}},
+	})
+
+	if _, err := exec.ExecQueryIter(ctx, adults, nil); err == nil {
+		t.Error("expected a missing param to fail before iterating")
+	}
+
+	seq, err := exec.ExecQueryIter(ctx, adults, map[string]any{"min_age": 18})
+	if err != nil {
+		t.Fatalf("ExecQueryIter() failed: %v", err)
+	}
+	if db.count() != 0 {
+		t.Fatalf("expected no query before iterating, got %v", db.queries)
+	}
+
+	var steps int
+	for user, err := range seq {
+		steps++
+		if user != nil || !errors.Is(err, errRecorded) {
+			t.Errorf("expected the query error, got %v, %v", user, err)
+		}
+	}
+	if steps != 1 || db.count() != 1 {
+		t.Errorf("expected one query and one step, got %d queries and %d steps", db.count(), steps)
+	}
+}
@@ -0,0 +1,260 @@
+package edamame
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// JSONB operators of ConditionSpec.JSONOp.
+const (
+	jsonOpGet      = "->"
+	jsonOpGetText  = "->>"
+	jsonOpPathText = "#>>"
+	jsonOpContains = "@>"
+	jsonOpHasKey   = "?"
+)
+
+// jsonKeyParamPrefix names the placeholder params key existence conditions render
+// with until rewriteJSONPaths replaces them with the literal key.
+const jsonKeyParamPrefix = "edamame_json_key_"
+
+// errJSONPathUnsupported is returned by execution paths that run soy's SQL
+// unmodified and so cannot apply JSONB path conditions.
+var errJSONPathUnsupported = errors.New("edamame: JSONB path conditions are not supported by this method")
+
+// hasJSONPath reports whether any condition, including nested groups, is a JSONB path condition.
+func hasJSONPath(conditions []ConditionSpec) bool {
+	for _, c := range conditions {
+		if c.IsJSONPath() || (c.IsGroup() && hasJSONPath(c.Group)) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkJSONPaths validates the JSONB path conditions of a query or select spec: they
+// may only appear in WHERE, including groups, and need the postgres renderer.
+func (e *Executor[T]) checkJSONPaths(where, having, outerWhere []ConditionSpec) error {
+	if hasJSONPath(having) || hasJSONPath(outerWhere) {
+		return fmt.Errorf("edamame: JSONB path conditions are only supported in WHERE")
+	}
+	if !hasJSONPath(where) {
+		return nil
+	}
+	if !e.isPostgres() {
+		return fmt.Errorf("edamame: JSONB path conditions require the postgres renderer")
+	}
+	return checkJSONPathConditions(where)
+}
+
+// checkJSONPathConditions validates every JSONB path condition in conditions.
+func checkJSONPathConditions(conditions []ConditionSpec) error {
+	for _, c := range conditions {
+		if c.IsGroup() {
+			if err := checkJSONPathConditions(c.Group); err != nil {
+				return err
+			}
+			continue
+		}
+		if c.IsJSONPath() {
+			if err := checkJSONPathCondition(c); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkJSONPathCondition validates a single JSONB path condition.
+func checkJSONPathCondition(c ConditionSpec) error {
+	if c.IsNull || c.IsBetween() || c.IsNotBetween() || c.IsIn() || c.IsNotIn() ||
+		c.RightField != "" || c.IsMatch() || c.IsQuantified() {
+		return fmt.Errorf("edamame: JSONB path condition on %q cannot be combined with another condition form", c.Field)
+	}
+	for _, key := range c.JSONPath {
+		if err := checkJSONKey(key); err != nil {
+			return fmt.Errorf("edamame: JSONB path condition on %q: %w", c.Field, err)
+		}
+	}
+
+	switch c.JSONOp {
+	case jsonOpGet, jsonOpGetText, jsonOpPathText:
+		if len(c.JSONPath) == 0 {
+			return fmt.Errorf("edamame: JSONB operator %s on %q requires a path", c.JSONOp, c.Field)
+		}
+		switch c.Operator {
+		case "=", "!=", ">", ">=", "<", "<=", "LIKE", "NOT LIKE", "ILIKE", "NOT ILIKE":
+		default:
+			return fmt.Errorf("edamame: invalid operator %q for JSONB operator %s: must be a comparison or LIKE form", c.Operator, c.JSONOp)
+		}
+		if c.Param == "" {
+			return fmt.Errorf("edamame: JSONB operator %s on %q requires a param", c.JSONOp, c.Field)
+		}
+	case jsonOpContains:
+		if c.Operator != "" {
+			return fmt.Errorf("edamame: JSONB operator %s on %q takes no operator", c.JSONOp, c.Field)
+		}
+		if c.Param == "" {
+			return fmt.Errorf("edamame: JSONB operator %s on %q requires a param", c.JSONOp, c.Field)
+		}
+	case jsonOpHasKey:
+		if c.Operator != "" || c.Param != "" {
+			return fmt.Errorf("edamame: JSONB operator %s on %q takes no operator or param; the key is the last path element", c.JSONOp, c.Field)
+		}
+		if len(c.JSONPath) == 0 {
+			return fmt.Errorf("edamame: JSONB operator %s on %q requires a path", c.JSONOp, c.Field)
+		}
+	default:
+		return fmt.Errorf("edamame: invalid JSONB operator %q: must be one of ->, ->>, #>>, @>, ?", c.JSONOp)
+	}
+	return nil
+}
+
+// checkJSONKey rejects path keys that cannot be rendered as a literal: empty keys, and
+// keys holding a NUL byte or a colon, which sqlx would read as a named parameter.
+func checkJSONKey(key string) error {
+	switch {
+	case key == "":
+		return fmt.Errorf("path keys must not be empty")
+	case strings.ContainsAny(key, "\x00:"):
+		return fmt.Errorf("path key %q must not contain a NUL byte or colon", key)
+	}
+	return nil
+}
+
+// jsonLiteral quotes key as a SQL string literal, doubling any single quotes.
+func jsonLiteral(key string) string {
+	return "'" + strings.ReplaceAll(key, "'", "''") + "'"
+}
+
+// jsonPathSQL returns the path expression rendered after the field: each key but the
+// last follows ->, and the last follows last, or for #>> the whole path follows as a
+// text array.
+func jsonPathSQL(path []string, last string) string {
+	if last == jsonOpPathText {
+		keys := make([]string, len(path))
+		for i, key := range path {
+			keys[i] = jsonLiteral(key)
+		}
+		return " #>> ARRAY[" + strings.Join(keys, ", ") + "]"
+	}
+	var b strings.Builder
+	for i, key := range path {
+		if i == len(path)-1 {
+			b.WriteString(last)
+		} else {
+			b.WriteString(jsonOpGet)
+		}
+		b.WriteString(jsonLiteral(key))
+	}
+	return b.String()
+}
+
+// jsonPathTarget returns the comparison soy renders for a JSONB path condition and
+// the SQL rewriteJSONPaths puts in its place, given the key placeholder param name.
+func (c ConditionSpec) jsonPathTarget(keyParam string) (placeholder, replacement string) {
+	switch c.JSONOp {
+	case jsonOpContains:
+		placeholder = " " + jsonOpContains + " :" + c.Param
+		return placeholder, jsonPathSQL(c.JSONPath, jsonOpGet) + placeholder
+	case jsonOpHasKey:
+		parents, key := c.JSONPath[:len(c.JSONPath)-1], c.JSONPath[len(c.JSONPath)-1]
+		return " = :" + keyParam, jsonPathSQL(parents, jsonOpGet) + " " + jsonOpHasKey + " " + jsonLiteral(key)
+	default:
+		placeholder = " " + c.Operator + " :" + c.Param
+		return placeholder, jsonPathSQL(c.JSONPath, c.JSONOp) + placeholder
+	}
+}
+
+// withJSONPathPlaceholders returns conditions with each JSONB path condition swapped
+// for the plain comparison soy renders on its field, to be rewritten by rewriteJSONPaths.
+// Key existence conditions compare with a placeholder param numbered in condition order.
+func withJSONPathPlaceholders(conditions []ConditionSpec) []ConditionSpec {
+	if !hasJSONPath(conditions) {
+		return conditions
+	}
+	n := 0
+	return jsonPathPlaceholders(conditions, &n)
+}
+
+// jsonPathPlaceholders replaces the JSONB path conditions of conditions, numbering
+// key placeholders from *n.
+func jsonPathPlaceholders(conditions []ConditionSpec, n *int) []ConditionSpec {
+	replaced := make([]ConditionSpec, len(conditions))
+	for i, c := range conditions {
+		switch {
+		case c.IsJSONPath():
+			switch c.JSONOp {
+			case jsonOpContains:
+				c.Operator = jsonOpContains
+			case jsonOpHasKey:
+				c.Operator, c.Param = "=", jsonKeyParamPrefix+strconv.Itoa(*n)
+				*n++
+			}
+			c.JSONOp, c.JSONPath = "", nil
+		case c.IsGroup():
+			c.Group = jsonPathPlaceholders(c.Group, n)
+		}
+		replaced[i] = c
+	}
+	return replaced
+}
+
+// collectJSONPaths gathers the placeholder and replacement of each JSONB path condition,
+// numbering key placeholders from *n as jsonPathPlaceholders does.
+func collectJSONPaths(conditions []ConditionSpec, n *int, targets map[string]string) error {
+	for _, c := range conditions {
+		if c.IsGroup() {
+			if err := collectJSONPaths(c.Group, n, targets); err != nil {
+				return err
+			}
+			continue
+		}
+		if !c.IsJSONPath() {
+			continue
+		}
+		keyParam := ""
+		if c.JSONOp == jsonOpHasKey {
+			keyParam = jsonKeyParamPrefix + strconv.Itoa(*n)
+			*n++
+		}
+		placeholder, replacement := c.jsonPathTarget(keyParam)
+		if _, ok := targets[placeholder]; ok {
+			return fmt.Errorf("edamame: cannot place JSONB path for %q: param is compared by more than one path condition", strings.TrimSpace(placeholder))
+		}
+		targets[placeholder] = replacement
+	}
+	return nil
+}
+
+// rewriteJSONPaths restores the JSONB path conditions that soy rendered as plain
+// comparisons on their field. Neither soy nor astql has JSONB operators, so the
+// rendered SQL is edited: each placeholder is located by its operator and param name
+// and must directly follow the quoted field. Path keys are rendered as quoted string
+// literals. A placeholder that also matches another comparison is ambiguous and rejected.
+func rewriteJSONPaths(sql string, conditions []ConditionSpec) (string, error) {
+	if !hasJSONPath(conditions) {
+		return sql, nil
+	}
+
+	targets := make(map[string]string)
+	n := 0
+	if err := collectJSONPaths(conditions, &n, targets); err != nil {
+		return "", err
+	}
+
+	for placeholder, replacement := range targets {
+		found := placeholderOffsets(sql, placeholder)
+		if len(found) != 1 {
+			return "", fmt.Errorf("edamame: cannot place JSONB path for %q: rendered %d matching comparisons, expected 1",
+				strings.TrimSpace(placeholder), len(found))
+		}
+		if found[0] == 0 || sql[found[0]-1] != '"' {
+			return "", fmt.Errorf("edamame: cannot place JSONB path for %q: comparison does not follow a field", strings.TrimSpace(placeholder))
+		}
+		sql = replaceOffsets(sql, found, len(placeholder), replacement)
+	}
+	return sql, nil
+}
@@ -0,0 +1,163 @@
+package edamame
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/mariadb"
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+// Account is a test model with a JSONB column.
+type Account struct {
+	ID       int    `db:"id" type:"integer" constraints:"primarykey"`
+	Name     string `db:"name" type:"text"`
+	Metadata []byte `db:"metadata" type:"jsonb"`
+}
+
+func TestJSONPath_Render(t *testing.T) {
+	factory, err := New[Account](nil, "accounts", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		cond ConditionSpec
+		want string
+	}{
+		{"text value", ConditionSpec{Field: "metadata", JSONOp: "->>", JSONPath: []string{"tier"}, Operator: "=", Param: "tier"},
+			`WHERE "metadata"->>'tier' = :tier`},
+		{"nested text value", ConditionSpec{Field: "metadata", JSONOp: "->>", JSONPath: []string{"plan", "tier"}, Operator: "!=", Param: "tier"},
+			`WHERE "metadata"->'plan'->>'tier' != :tier`},
+		{"json value", ConditionSpec{Field: "metadata", JSONOp: "->", JSONPath: []string{"flags"}, Operator: "=", Param: "flags"},
+			`WHERE "metadata"->'flags' = :flags`},
+		{"path text value", ConditionSpec{Field: "metadata", JSONOp: "#>>", JSONPath: []string{"plan", "tier"}, Operator: "LIKE", Param: "tier"},
+			`WHERE "metadata" #>> ARRAY['plan', 'tier'] LIKE :tier`},
+		{"containment", ConditionSpec{Field: "metadata", JSONOp: "@>", Param: "fragment"},
+			`WHERE "metadata" @> :fragment`},
+		{"nested containment", ConditionSpec{Field: "metadata", JSONOp: "@>", JSONPath: []string{"plan"}, Param: "fragment"},
+			`WHERE "metadata"->'plan' @> :fragment`},
+		{"key exists", ConditionSpec{Field: "metadata", JSONOp: "?", JSONPath: []string{"plan", "trial"}},
+			`WHERE "metadata"->'plan' ? 'trial'`},
+		{"quoted key", ConditionSpec{Field: "metadata", JSONOp: "->>", JSONPath: []string{"o'brien"}, Operator: "=", Param: "v"},
+			`WHERE "metadata"->>'o''brien' = :v`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt := NewQueryStatement("q", "q", QuerySpec{Where: []ConditionSpec{tt.cond}})
+			sql, err := factory.RenderQuery(stmt)
+			if err != nil {
+				t.Fatalf("RenderQuery() failed: %v", err)
+			}
+			if !strings.Contains(sql, tt.want) {
+				t.Errorf("expected %q in SQL, got: %s", tt.want, sql)
+			}
+		})
+	}
+
+	stmt := NewSelectStatement("grouped", "Account by tier or trial", SelectSpec{
+		Where: []ConditionSpec{
+			{Field: "name", Operator: "=", Param: "name"},
+			{Logic: "OR", Group: []ConditionSpec{
+				{Field: "metadata", JSONOp: "->>", JSONPath: []string{"tier"}, Operator: "=", Param: "tier"},
+				{Field: "metadata", JSONOp: "?", JSONPath: []string{"trial"}},
+				{Field: "metadata", JSONOp: "?", JSONPath: []string{"beta"}},
+			}},
+		},
+	})
+	sql, err := factory.RenderSelect(stmt)
+	if err != nil {
+		t.Fatalf("RenderSelect() failed: %v", err)
+	}
+	for _, want := range []string{`"name" = :name`, `"metadata"->>'tier' = :tier`, `"metadata" ? 'trial'`, `"metadata" ? 'beta'`} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("expected %q in SQL, got: %s", want, sql)
+		}
+	}
+	if strings.Contains(sql, jsonKeyParamPrefix) {
+		t.Errorf("expected key placeholders to be replaced, got: %s", sql)
+	}
+
+	params := stmt.Params()
+	if len(params) != 2 || params[0].Name != "name" || params[1].Name != "tier" {
+		t.Errorf("expected params name and tier, got %+v", params)
+	}
+}
+
+func TestJSONPath_Validation(t *testing.T) {
+	factory, err := New[Account](nil, "accounts", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		spec QuerySpec
+	}{
+		{"unknown operator", QuerySpec{Where: []ConditionSpec{{Field: "metadata", JSONOp: "#>", JSONPath: []string{"a"}, Operator: "=", Param: "v"}}}},
+		{"missing path", QuerySpec{Where: []ConditionSpec{{Field: "metadata", JSONOp: "->>", Operator: "=", Param: "v"}}}},
+		{"missing param", QuerySpec{Where: []ConditionSpec{{Field: "metadata", JSONOp: "->>", JSONPath: []string{"a"}, Operator: "="}}}},
+		{"invalid comparison", QuerySpec{Where: []ConditionSpec{{Field: "metadata", JSONOp: "->>", JSONPath: []string{"a"}, Operator: "~", Param: "v"}}}},
+		{"containment with operator", QuerySpec{Where: []ConditionSpec{{Field: "metadata", JSONOp: "@>", Operator: "=", Param: "v"}}}},
+		{"key exists with param", QuerySpec{Where: []ConditionSpec{{Field: "metadata", JSONOp: "?", JSONPath: []string{"a"}, Param: "v"}}}},
+		{"colon in key", QuerySpec{Where: []ConditionSpec{{Field: "metadata", JSONOp: "?", JSONPath: []string{"a:b"}}}}},
+		{"empty key", QuerySpec{Where: []ConditionSpec{{Field: "metadata", JSONOp: "->>", JSONPath: []string{""}, Operator: "=", Param: "v"}}}},
+		{"combined with IN", QuerySpec{Where: []ConditionSpec{{Field: "metadata", JSONOp: "->>", JSONPath: []string{"a"}, Operator: "=", In: true, Param: "v"}}}},
+		{"shared param", QuerySpec{Where: []ConditionSpec{
+			{Field: "metadata", JSONOp: "->>", JSONPath: []string{"a"}, Operator: "=", Param: "v"},
+			{Field: "metadata", JSONOp: "->>", JSONPath: []string{"b"}, Operator: "=", Param: "v"},
+		}}},
+		{"ambiguous with a plain comparison", QuerySpec{Where: []ConditionSpec{
+			{Field: "name", Operator: "=", Param: "v"},
+			{Field: "metadata", JSONOp: "->>", JSONPath: []string{"a"}, Operator: "=", Param: "v"},
+		}}},
+		{"in HAVING", QuerySpec{GroupBy: []string{"name"}, Having: []ConditionSpec{{Field: "metadata", JSONOp: "?", JSONPath: []string{"a"}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := factory.RenderQuery(NewQueryStatement("q", "q", tt.spec)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	maria, err := New[Account](nil, "accounts", mariadb.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	stmt := NewQueryStatement("q", "q", QuerySpec{Where: []ConditionSpec{{Field: "metadata", JSONOp: "?", JSONPath: []string{"a"}}}})
+	if _, err := maria.RenderQuery(stmt); err == nil {
+		t.Error("expected error for the mariadb renderer")
+	}
+}
+
+func TestJSONPath_Unsupported(t *testing.T) {
+	factory, err := New[Account](&recordingDB{}, "accounts", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	where := []ConditionSpec{{Field: "metadata", JSONOp: "->>", JSONPath: []string{"tier"}, Operator: "=", Param: "tier"}}
+
+	query := NewQueryStatement("q", "q", QuerySpec{Where: where})
+	if _, err := factory.ExecQueryAtom(context.Background(), query, nil); !errors.Is(err, errJSONPathUnsupported) {
+		t.Errorf("ExecQueryAtom: expected errJSONPathUnsupported, got %v", err)
+	}
+
+	// Statement types executed by soy reject the JSONB operator before rendering.
+	const want = "JSONB path conditions are only supported in query and select statements"
+	del := NewDeleteStatement("d", "d", DeleteSpec{Where: where})
+	if _, err := factory.RenderDelete(del); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("RenderDelete: expected %q, got %v", want, err)
+	}
+	upd := NewUpdateStatement("u", "u", UpdateSpec{Set: map[string]string{"name": "name"}, Where: where})
+	if _, err := factory.ExecUpdate(context.Background(), upd, map[string]any{"name": "A", "tier": "gold"}); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("ExecUpdate: expected %q, got %v", want, err)
+	}
+	agg := NewAggregateStatement("a", "a", AggCount, AggregateSpec{Where: where})
+	if _, err := factory.RenderAggregate(agg); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("RenderAggregate: expected %q, got %v", want, err)
+	}
+}
@@ -0,0 +1,236 @@
+package edamame
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// keysUnnestThreshold is the key count above which ExecQueryByKeys joins against
+// unnest() instead of filtering with = ANY().
+const keysUnnestThreshold = 100
+
+// keysParam is the bind param carrying the key array.
+const keysParam = "edamame_keys"
+
+// sqlTypePattern matches the column type names accepted in the unnest cast.
+var sqlTypePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_ ]*$`)
+
+// ExecQueryByKeys executes a query statement restricted to rows whose keyField is in keys,
+// binding the keys once as an array. Small key sets filter with "keyField = ANY(:keys)";
+// larger ones join against "unnest(:keys)", which PostgreSQL plans better than a long IN list.
+// Duplicate keys are ignored. PostgreSQL only.
+//
+// The unnest cast uses the key column's type tag, so keyField must declare one.
+//
+// Example:
+//
+//	users, err := exec.ExecQueryByKeys(ctx, ActiveUsers, "id", ids, nil)
+func (e *Executor[T]) ExecQueryByKeys(ctx context.Context, stmt QueryStatement, keyField string, keys []any, params map[string]any) ([]*T, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	return e.queryByKeys(withRead(ctx), e.execer(), stmt, keyField, keys, params)
+}
+
+// queryByKeys implements ExecQueryByKeys on execer.
+func (e *Executor[T]) queryByKeys(ctx context.Context, execer sqlx.ExtContext, stmt QueryStatement, keyField string, keys []any, params map[string]any) ([]*T, error) {
+	if !e.isPostgres() {
+		return nil, fmt.Errorf("edamame: ExecQueryByKeys requires the postgres renderer")
+	}
+	params, err := e.prepareParams(stmt, params)
+	if err != nil {
+		return nil, err
+	}
+	keyField = e.column(keyField)
+	if _, ok := e.columns[keyField]; !ok {
+		return nil, fmt.Errorf("edamame: unknown key field %q", keyField)
+	}
+	if len(keys) == 0 {
+		return []*T{}, nil
+	}
+	keys = uniqueKeys(keys)
+	ctx = withStatement(ctx, stmt.name, "query")
+
+	sql, err := e.renderByKeys(stmt, keyField, len(keys))
+	if err != nil {
+		return nil, err
+	}
+	sql, binds, err := e.finalizeSQL(sql, e.queryRewrites(stmt.spec))
+	if err != nil {
+		return nil, err
+	}
+	params = mergeParams(params, binds)
+	params = mergeParams(params, map[string]any{keysParam: pq.Array(keys)})
+	e.emitSQL(ctx, stmt.name, "query", sql, params)
+
+	records, err := execRenderedQuery[T](ctx, execer, sql, params)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.assertResults(records...); err != nil {
+		return nil, err
+	}
+	return capResults(ctx, e, stmt, e.dedupResults(records)), nil
+}
+
+// selectMany is the statement ExecSelectMany restricts to the requested keys.
+var selectMany = NewQueryStatement("select-many", "Records by primary key", QuerySpec{})
+
+// ExecSelectMany returns the records whose primary key is in ids with one query, in
+// the order of ids. Missing ids are skipped and repeated ids return their record once.
+// An empty ids returns an empty result without querying. The keys are bound as an
+// array as in ExecQueryByKeys, and soft-deleted rows are excluded. Requires a primary
+// key column. PostgreSQL only.
+//
+// Example:
+//
+//	users, err := exec.ExecSelectMany(ctx, []any{3, 1, 2})
+func (e *Executor[T]) ExecSelectMany(ctx context.Context, ids []any) ([]*T, error) {
+	ctx, cancel := e.withTimeout(ctx, "")
+	defer cancel()
+	return e.selectMany(withRead(ctx), e.execer(), ids)
+}
+
+// ExecSelectManyTx returns the records whose primary key is in ids within a transaction.
+func (e *Executor[T]) ExecSelectManyTx(ctx context.Context, tx *sqlx.Tx, ids []any) ([]*T, error) {
+	ctx, cancel := e.withTimeout(ctx, "")
+	defer cancel()
+	return e.selectMany(ctx, e.execerFor(tx), ids)
+}
+
+// selectMany fetches the records with primary keys ids and orders them as ids.
+func (e *Executor[T]) selectMany(ctx context.Context, execer sqlx.ExtContext, ids []any) ([]*T, error) {
+	if e.pk == "" {
+		return nil, fmt.Errorf("edamame: ExecSelectMany requires a primary key column")
+	}
+	records, err := e.queryByKeys(ctx, execer, selectMany, e.pk, ids, nil)
+	if err != nil {
+		return nil, err
+	}
+	return e.orderByKeys(records, ids), nil
+}
+
+// orderByKeys orders records by the position of their primary key in ids. Keys are
+// compared by their printed form, so an int id matches an int64 column. Records
+// whose key is not in ids are kept at the end.
+func (e *Executor[T]) orderByKeys(records []*T, ids []any) []*T {
+	values := e.pkValues(records)
+	byKey := make(map[string][]*T, len(records))
+	for i, v := range values {
+		key := keyString(reflect.ValueOf(v))
+		byKey[key] = append(byKey[key], records[i])
+	}
+	ordered := make([]*T, 0, len(records))
+	for _, id := range slices.Concat(ids, values) {
+		key := keyString(reflect.ValueOf(id))
+		ordered = append(ordered, byKey[key]...)
+		delete(byKey, key)
+	}
+	return ordered
+}
+
+// pkValues returns the primary key value of each record.
+func (e *Executor[T]) pkValues(records []*T) []any {
+	values := make([]any, len(records))
+	for i, record := range records {
+		values[i] = reflect.ValueOf(record).Elem().FieldByIndex(e.columns[e.pk]).Interface()
+	}
+	return values
+}
+
+// keyString returns the printed form of a key, dereferencing pointers; nil prints as "<nil>".
+func keyString(v reflect.Value) string {
+	if v = reflect.Indirect(v); !v.IsValid() {
+		return fmt.Sprint(nil)
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// renderByKeys renders stmt with the key filter for n keys.
+func (e *Executor[T]) renderByKeys(stmt QueryStatement, keyField string, n int) (string, error) {
+	q, err := e.Query(stmt)
+	if err != nil {
+		return "", err
+	}
+
+	if n <= keysUnnestThreshold {
+		result, err := q.Where(keyField, "IN", keysParam).Render()
+		if err != nil {
+			return "", err
+		}
+		return result.SQL, nil
+	}
+
+	sqlType, err := e.columnType(keyField)
+	if err != nil {
+		return "", err
+	}
+	result, err := q.Render()
+	if err != nil {
+		return "", err
+	}
+	return joinUnnest(result.SQL, e.quotedTableName(), keyField, sqlType)
+}
+
+// joinUnnest splices "JOIN unnest(:keys)" after the FROM table of a rendered query.
+// soy has no join on a set-returning function, so the rendered SQL is edited.
+// SELECT * is qualified with the table so the key column is not returned.
+// table is quoted as it appears in the rendered SQL.
+func joinUnnest(sql, table, keyField, sqlType string) (string, error) {
+	from := " FROM " + table
+	i := strings.Index(sql, from)
+	if i < 0 {
+		return "", fmt.Errorf("edamame: rendered SQL has no FROM %s", table)
+	}
+	i += len(from)
+
+	//nolint:gosec // type and identifiers come from validated struct tags
+	join := fmt.Sprintf(` JOIN unnest(CAST(:%s AS %s[])) AS edamame_k(edamame_key) ON %s."%s" = edamame_k.edamame_key`,
+		keysParam, sqlType, table, keyField)
+	sql = sql[:i] + join + sql[i:]
+
+	for _, prefix := range []string{"SELECT * ", "SELECT DISTINCT * "} {
+		if strings.HasPrefix(sql, prefix) {
+			sql = strings.Replace(sql, "* ", table+".* ", 1)
+			break
+		}
+	}
+	return sql, nil
+}
+
+// columnType returns the SQL type tag of a column.
+func (e *Executor[T]) columnType(col string) (string, error) {
+	for _, f := range e.soy.Metadata().Fields {
+		if f.Tags["db"] != col {
+			continue
+		}
+		typ := f.Tags["type"]
+		if typ == "" || !sqlTypePattern.MatchString(typ) {
+			return "", fmt.Errorf("edamame: field %q needs a valid type tag, got %q", col, typ)
+		}
+		return typ, nil
+	}
+	return "", fmt.Errorf("edamame: unknown field %q", col)
+}
+
+// uniqueKeys drops repeated keys, keeping first occurrences. Non-comparable keys are kept as is.
+func uniqueKeys(keys []any) []any {
+	seen := make(map[any]struct{}, len(keys))
+	unique := make([]any, 0, len(keys))
+	for _, k := range keys {
+		if k != nil && reflect.TypeOf(k).Comparable() {
+			if _, dup := seen[k]; dup {
+				continue
+			}
+			seen[k] = struct{}{}
+		}
+		unique = append(unique, k)
+	}
+	return unique
+}
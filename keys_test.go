@@ -0,0 +1,139 @@
+package edamame
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/mariadb"
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestRenderByKeys(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	stmt := NewQueryStatement("adults", "Adults", QuerySpec{
+		Where:   []ConditionSpec{{Field: "age", Operator: ">=", Param: "min_age"}},
+		OrderBy: []OrderBySpec{{Field: "age", Direction: "asc"}},
+	})
+
+	t.Run("small key set filters with ANY", func(t *testing.T) {
+		sql, err := factory.renderByKeys(stmt, "id", keysUnnestThreshold)
+		if err != nil {
+			t.Fatalf("renderByKeys() failed: %v", err)
+		}
+		if !strings.Contains(sql, `"id" = ANY(:edamame_keys)`) || strings.Contains(sql, "unnest") {
+			t.Errorf("expected ANY filter, got: %s", sql)
+		}
+	})
+
+	t.Run("large key set joins unnest", func(t *testing.T) {
+		sql, err := factory.renderByKeys(stmt, "id", keysUnnestThreshold+1)
+		if err != nil {
+			t.Fatalf("renderByKeys() failed: %v", err)
+		}
+		want := `SELECT "users".* FROM "users" JOIN unnest(CAST(:edamame_keys AS integer[])) AS edamame_k(edamame_key) ON "users"."id" = edamame_k.edamame_key WHERE`
+		if !strings.HasPrefix(sql, want) {
+			t.Errorf("expected SQL to start with:\n%s\ngot:\n%s", want, sql)
+		}
+	})
+
+	t.Run("field-limited query keeps its fields", func(t *testing.T) {
+		emails := NewQueryStatement("emails", "Emails", QuerySpec{Fields: []string{"id", "email"}})
+		sql, err := factory.renderByKeys(emails, "id", keysUnnestThreshold+1)
+		if err != nil {
+			t.Fatalf("renderByKeys() failed: %v", err)
+		}
+		if !strings.HasPrefix(sql, `SELECT "id", "email" FROM "users" JOIN unnest(`) {
+			t.Errorf("unexpected SQL: %s", sql)
+		}
+	})
+}
+
+func TestExecQueryByKeys_Validation(t *testing.T) {
+	ctx := context.Background()
+
+	factory, err := New[User](&recordingDB{}, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if _, err := factory.ExecQueryByKeys(ctx, queryAll, "uid", []any{1}, nil); err == nil {
+		t.Error("ExecQueryByKeys() should reject an unknown key field")
+	}
+	records, err := factory.ExecQueryByKeys(ctx, queryAll, "id", nil, nil)
+	if err != nil || len(records) != 0 {
+		t.Errorf("expected no records and no error for empty keys, got %v, %v", records, err)
+	}
+
+	maria, err := New[User](&recordingDB{}, "users", mariadb.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if _, err := maria.ExecQueryByKeys(ctx, queryAll, "id", []any{1}, nil); err == nil {
+		t.Error("ExecQueryByKeys() should require the postgres renderer")
+	}
+}
+
+func TestUniqueKeys(t *testing.T) {
+	got := uniqueKeys([]any{1, 2, 1, "a", "a", []byte("x"), []byte("x")})
+	if len(got) != 5 {
+		t.Errorf("expected 5 keys, got %v", got)
+	}
+}
+
+func TestExecSelectMany(t *testing.T) {
+	ctx := context.Background()
+	db := &recordingDB{}
+	factory, err := New[User](db, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	records, err := factory.ExecSelectMany(ctx, []any{})
+	if err != nil || len(records) != 0 || records == nil {
+		t.Errorf("expected an empty result for no ids, got %v, %v", records, err)
+	}
+	if db.count() != 0 {
+		t.Errorf("expected no query for no ids, got %v", db.queries)
+	}
+
+	if _, err := factory.ExecSelectMany(ctx, []any{2, 1}); !errors.Is(err, errRecorded) {
+		t.Fatalf("expected the recorded error, got %v", err)
+	}
+	if want := `SELECT * FROM "users" WHERE "id" = ANY($1)`; db.count() != 1 || db.queries[0] != want {
+		t.Errorf("expected %s, got %v", want, db.queries)
+	}
+
+	keyless, err := New[userName](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if _, err := keyless.ExecSelectMany(ctx, []any{1}); err == nil {
+		t.Error("ExecSelectMany() should require a primary key")
+	}
+}
+
+func TestOrderByKeys(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	records := []*User{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}}
+	ids := []any{int64(3), 9, 1, 3, 2}
+
+	var got []int
+	for _, r := range factory.orderByKeys(records, ids) {
+		got = append(got, r.ID)
+	}
+	if want := []int{3, 1, 2, 4}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if len(ids) != 5 {
+		t.Errorf("expected ids to be left unchanged, got %v", ids)
+	}
+}
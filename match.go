@@ -0,0 +1,221 @@
+package edamame
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/zoobzio/astql/pkg/mssql"
+	"github.com/zoobzio/astql/pkg/sqlite"
+)
+
+// Pattern match modes of ConditionSpec.Match.
+const (
+	matchContains   = "contains"
+	matchStartsWith = "starts_with"
+	matchEndsWith   = "ends_with"
+	matchILike      = "ilike"
+)
+
+// likeEscaper escapes the LIKE wildcards and the escape character itself, so a
+// matched value is compared literally. Backslash is the default LIKE escape
+// character in PostgreSQL and MariaDB; SQLite and SQL Server are given it by an
+// ESCAPE clause (see escapeMatches).
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// mssqlLikeEscaper also escapes [, which opens a character class in SQL Server patterns.
+var mssqlLikeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`, `[`, `\[`)
+
+// errMatchUnsupported is returned by execution paths that run soy's SQL unmodified
+// and so cannot add the ESCAPE clause match conditions need on SQLite and SQL Server.
+var errMatchUnsupported = errors.New("edamame: match conditions are not supported by this method on SQLite and SQL Server")
+
+// matchParam returns the name a match condition's pattern is bound under. The pattern
+// is derived from the caller's Param by bindMatchParams, so Param itself stays the
+// plain value and can be shared with other conditions.
+func (c ConditionSpec) matchParam() string {
+	return "edamame_" + strings.ToLower(c.Match) + "_" + c.Param
+}
+
+// matchOperator returns the LIKE operator a match condition renders with.
+func (c ConditionSpec) matchOperator() string {
+	if c.Operator != "" {
+		return strings.ToUpper(c.Operator)
+	}
+	if strings.EqualFold(c.Match, matchILike) {
+		return "ILIKE"
+	}
+	return "LIKE"
+}
+
+// matchPattern escapes value with escaper and wraps it in wildcards for mode.
+func matchPattern(mode, value string, escaper *strings.Replacer) string {
+	value = escaper.Replace(value)
+	switch strings.ToLower(mode) {
+	case matchStartsWith:
+		return value + "%"
+	case matchEndsWith:
+		return "%" + value
+	default:
+		return "%" + value + "%"
+	}
+}
+
+// checkMatchCondition validates a single match condition.
+func checkMatchCondition(c ConditionSpec) error {
+	switch strings.ToLower(c.Match) {
+	case matchContains, matchStartsWith, matchEndsWith, matchILike:
+	default:
+		return fmt.Errorf("invalid match mode %q: must be one of contains, starts_with, ends_with, ilike", c.Match)
+	}
+	switch c.matchOperator() {
+	case "LIKE", "NOT LIKE", "ILIKE", "NOT ILIKE":
+	default:
+		return fmt.Errorf("invalid match operator %q: must be one of LIKE, NOT LIKE, ILIKE, NOT ILIKE", c.Operator)
+	}
+	if c.Param == "" {
+		return fmt.Errorf("match condition on %q requires a param", c.Field)
+	}
+	if c.IsNull || c.IsBetween() || c.IsNotBetween() || c.IsIn() || c.IsNotIn() || c.IsFieldComparison() {
+		return fmt.Errorf("match condition on %q cannot be combined with another condition form", c.Field)
+	}
+	return nil
+}
+
+// checkMatch validates the match conditions of a query or select spec: they may only
+// appear in WHERE, including groups and EXISTS subqueries, and use ILIKE only where
+// the dialect has it.
+func (e *Executor[T]) checkMatch(where, having, outerWhere []ConditionSpec) error {
+	if hasMatch(having) || hasMatch(outerWhere) {
+		return fmt.Errorf("match conditions are only supported in WHERE")
+	}
+	if err := checkMatchConditions(where); err != nil {
+		return err
+	}
+	return e.checkMatchILike(collectMatches(where, nil))
+}
+
+// checkMatchILike rejects ILIKE match conditions outside PostgreSQL. MariaDB would
+// render them as LIKE, whose case sensitivity depends on the column's collation, and
+// SQLite and SQL Server have no ILIKE.
+func (e *Executor[T]) checkMatchILike(matches []ConditionSpec) error {
+	if e.isPostgres() {
+		return nil
+	}
+	for _, c := range matches {
+		if strings.Contains(c.matchOperator(), "ILIKE") {
+			return fmt.Errorf("match condition on %q: ILIKE is only supported by PostgreSQL", c.Field)
+		}
+	}
+	return nil
+}
+
+// likeEscapes reports whether the executor's dialect has no default LIKE escape
+// character, so match conditions need an ESCAPE clause: SQLite and SQL Server.
+func (e *Executor[T]) likeEscapes() bool {
+	switch e.dialect().(type) {
+	case *sqlite.Renderer, *mssql.Renderer:
+		return true
+	}
+	return false
+}
+
+// escapeMatches appends ESCAPE '\' to each comparison the match conditions in where
+// rendered in sql. soy has no ESCAPE clause, so each comparison is located by its
+// derived param, which only match conditions bind.
+func escapeMatches(sql string, where []ConditionSpec) string {
+	seen := make(map[string]bool)
+	for _, c := range collectMatches(where, nil) {
+		param := ":" + c.matchParam()
+		if seen[param] {
+			continue
+		}
+		seen[param] = true
+		sql = replaceOffsets(sql, placeholderOffsets(sql, param), len(param), param+` ESCAPE '\'`)
+	}
+	return sql
+}
+
+// checkMatchConditions validates every match condition in conditions.
+func checkMatchConditions(conditions []ConditionSpec) error {
+	for _, c := range conditions {
+		switch {
+		case c.IsGroup():
+			if err := checkMatchConditions(c.Group); err != nil {
+				return err
+			}
+		case c.IsSubquery():
+			if err := checkMatchConditions(c.subqueryWhere()); err != nil {
+				return err
+			}
+		case c.IsMatch():
+			if err := checkMatchCondition(c); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// hasMatch reports whether any condition, including nested groups, is a match condition.
+func hasMatch(conditions []ConditionSpec) bool {
+	for _, c := range conditions {
+		if c.IsMatch() || (c.IsGroup() && hasMatch(c.Group)) {
+			return true
+		}
+	}
+	return false
+}
+
+// collectMatches returns the match conditions in conditions, recursing into groups
+// and EXISTS subqueries, whose params carry their namespace.
+func collectMatches(conditions []ConditionSpec, found []ConditionSpec) []ConditionSpec {
+	for _, c := range conditions {
+		switch {
+		case c.IsGroup():
+			found = collectMatches(c.Group, found)
+		case c.IsSubquery():
+			found = collectMatches(c.subqueryWhere(), found)
+		case c.IsMatch():
+			found = append(found, c)
+		}
+	}
+	return found
+}
+
+// bindMatchParams returns params with the pattern of each of stmt's match conditions
+// bound under its matchParam. Absent and nil values are left for param validation and
+// the driver. The caller's map is not modified; params is returned as is when stmt has
+// no match conditions.
+func (e *Executor[T]) bindMatchParams(stmt Statement, params map[string]any) (map[string]any, error) {
+	where, _ := statementWhere(stmt)
+	matches := collectMatches(e.expandFragments(where), nil)
+	if len(matches) == 0 {
+		return params, nil
+	}
+	if err := checkMatchConditions(matches); err != nil {
+		return nil, fmt.Errorf("edamame: statement %q: %w", stmt.Name(), err)
+	}
+
+	escaper := likeEscaper
+	if _, ok := e.dialect().(*mssql.Renderer); ok {
+		escaper = mssqlLikeEscaper
+	}
+	bound := maps.Clone(params)
+	if bound == nil {
+		bound = make(map[string]any, len(matches))
+	}
+	for _, c := range matches {
+		v, ok := params[c.Param]
+		if !ok || v == nil {
+			continue
+		}
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("edamame: match param %q for statement %q must be a string, got %T", c.Param, stmt.Name(), v)
+		}
+		bound[c.matchParam()] = matchPattern(c.Match, s, escaper)
+	}
+	return bound, nil
+}
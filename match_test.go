@@ -0,0 +1,175 @@
+package edamame
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql"
+	"github.com/zoobzio/astql/pkg/mariadb"
+	"github.com/zoobzio/astql/pkg/mssql"
+	"github.com/zoobzio/astql/pkg/postgres"
+	"github.com/zoobzio/astql/pkg/sqlite"
+)
+
+func TestRenderQuery_Match(t *testing.T) {
+	exec, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	tests := []struct {
+		name string
+		cond ConditionSpec
+		want string
+	}{
+		{"contains", ConditionSpec{Field: "name", Match: "contains", Param: "q"}, `"name" LIKE :edamame_contains_q`},
+		{"starts_with", ConditionSpec{Field: "email", Match: "starts_with", Param: "q"}, `"email" LIKE :edamame_starts_with_q`},
+		{"ilike", ConditionSpec{Field: "name", Match: "ilike", Param: "q"}, `"name" ILIKE :edamame_ilike_q`},
+		{"not like", ConditionSpec{Field: "name", Match: "ends_with", Operator: "not like", Param: "q"}, `"name" NOT LIKE :edamame_ends_with_q`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt := NewQueryStatement("search", "Search", QuerySpec{Where: []ConditionSpec{tt.cond}})
+			sql, err := exec.RenderQuery(stmt)
+			if err != nil {
+				t.Fatalf("RenderQuery() failed: %v", err)
+			}
+			if want := `SELECT * FROM "users" WHERE ` + tt.want; sql != want {
+				t.Errorf("expected %s, got %s", want, sql)
+			}
+			params := stmt.Params()
+			if len(params) != 1 || params[0].Name != "q" || params[0].Type != "string" {
+				t.Errorf("expected string param q, got %+v", params)
+			}
+		})
+	}
+}
+
+func TestRenderQuery_MatchILikeOutsidePostgres(t *testing.T) {
+	for _, renderer := range []astql.Renderer{mariadb.New(), sqlite.New()} {
+		exec, err := New[User](nil, "users", renderer)
+		if err != nil {
+			t.Fatalf("New() failed: %v", err)
+		}
+		stmt := NewQueryStatement("search", "Search", QuerySpec{Where: []ConditionSpec{{Field: "name", Match: "ilike", Param: "q"}}})
+		if _, err := exec.RenderQuery(stmt); err == nil || !strings.Contains(err.Error(), "ILIKE is only supported by PostgreSQL") {
+			t.Errorf("%T: expected an ILIKE error, got %v", renderer, err)
+		}
+		del := NewDeleteStatement("purge", "Purge", DeleteSpec{Where: []ConditionSpec{{Field: "name", Match: "starts_with", Operator: "ILIKE", Param: "q"}}})
+		if _, err := exec.RenderDelete(del); err == nil {
+			t.Errorf("%T: expected an error for an ILIKE match in a delete", renderer)
+		}
+	}
+}
+
+func TestRenderQuery_MatchEscape(t *testing.T) {
+	exec, err := New[User](&recordingDB{}, "users", sqlite.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	stmt := NewQueryStatement("search", "Search", QuerySpec{Where: []ConditionSpec{
+		{Field: "name", Match: "contains", Param: "q"},
+		{Field: "email", Match: "ends_with", Operator: "NOT LIKE", Param: "q"},
+	}})
+	sql, err := exec.RenderQuery(stmt)
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+	want := `SELECT * FROM "users" WHERE ("name" LIKE :edamame_contains_q ESCAPE '\' AND "email" NOT LIKE :edamame_ends_with_q ESCAPE '\')`
+	if sql != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, sql)
+	}
+
+	if _, err := exec.ExecQueryAtom(context.Background(), stmt, map[string]any{"q": "x"}); !errors.Is(err, errMatchUnsupported) {
+		t.Errorf("ExecQueryAtom: expected errMatchUnsupported, got %v", err)
+	}
+	del := NewDeleteStatement("purge", "Purge", DeleteSpec{Where: []ConditionSpec{{Field: "name", Match: "contains", Param: "q"}}})
+	if _, err := exec.RenderDelete(del); err == nil || !strings.Contains(err.Error(), "only supported in query and select statements") {
+		t.Errorf("expected a delete with a match condition to be rejected, got %v", err)
+	}
+
+	mssqlExec, err := New[User](nil, "users", mssql.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	sql, err = mssqlExec.RenderQuery(stmt)
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+	if !strings.Contains(sql, `[name] LIKE :edamame_contains_q ESCAPE '\'`) {
+		t.Errorf("expected an ESCAPE clause, got %s", sql)
+	}
+	params, err := mssqlExec.prepareParams(stmt, map[string]any{"q": "[a]_"})
+	if err != nil {
+		t.Fatalf("prepareParams() failed: %v", err)
+	}
+	if got := params["edamame_contains_q"]; got != `%\[a]\_%` {
+		t.Errorf("expected [ to be escaped, got %#v", got)
+	}
+}
+
+func TestRenderQuery_MatchInvalid(t *testing.T) {
+	exec, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	tests := []struct {
+		name string
+		spec QuerySpec
+		want string
+	}{
+		{"mode", QuerySpec{Where: []ConditionSpec{{Field: "name", Match: "regex", Param: "q"}}}, "invalid match mode"},
+		{"operator", QuerySpec{Where: []ConditionSpec{{Field: "name", Match: "contains", Operator: "=", Param: "q"}}}, "invalid match operator"},
+		{"param", QuerySpec{Where: []ConditionSpec{{Field: "name", Match: "contains"}}}, "requires a param"},
+		{"in", QuerySpec{Where: []ConditionSpec{{Field: "name", Match: "contains", In: true, Param: "q"}}}, "cannot be combined"},
+		{"having", QuerySpec{Having: []ConditionSpec{{Field: "name", Match: "contains", Param: "q"}}}, "only supported in WHERE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := exec.RenderQuery(NewQueryStatement("search", "Search", tt.spec))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestPrepareParams_Match(t *testing.T) {
+	exec, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	stmt := NewQueryStatement("search", "Search", QuerySpec{
+		Where: []ConditionSpec{
+			{Logic: "OR", Group: []ConditionSpec{
+				{Field: "name", Match: "contains", Param: "q"},
+				{Field: "email", Match: "starts_with", Param: "q"},
+			}},
+			{Field: "email", Match: "ends_with", Param: "domain"},
+		},
+	})
+	input := map[string]any{"q": `50%_off\`, "domain": "@example.com"}
+
+	params, err := exec.prepareParams(stmt, input)
+	if err != nil {
+		t.Fatalf("prepareParams() failed: %v", err)
+	}
+	want := map[string]string{
+		"edamame_contains_q":       `%50\%\_off\\%`,
+		"edamame_starts_with_q":    `50\%\_off\\%`,
+		"edamame_ends_with_domain": `%@example.com`,
+	}
+	for name, pattern := range want {
+		if params[name] != pattern {
+			t.Errorf("expected %s = %q, got %#v", name, pattern, params[name])
+		}
+	}
+	if len(input) != 2 {
+		t.Error("expected the caller's map to be left unmodified")
+	}
+
+	if _, err := exec.prepareParams(stmt, map[string]any{"q": 5, "domain": "x"}); err == nil || !strings.Contains(err.Error(), "must be a string") {
+		t.Errorf("expected a non-string error, got %v", err)
+	}
+}
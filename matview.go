@@ -0,0 +1,68 @@
+package edamame
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ExecRefreshMaterializedView refreshes a materialized view with REFRESH MATERIALIZED VIEW,
+// for executors whose statements read from a view rather than a table. viewName may be
+// schema-qualified, as in "analytics.daily_totals". With concurrently, the refresh does
+// not lock out readers of the view; PostgreSQL requires a unique index on the view for it.
+// PostgreSQL only.
+//
+// Example:
+//
+//	err := exec.ExecRefreshMaterializedView(ctx, "daily_totals", true)
+func (e *Executor[T]) ExecRefreshMaterializedView(ctx context.Context, viewName string, concurrently bool) error {
+	ctx, cancel := e.withTimeout(ctx, "")
+	defer cancel()
+	if err := e.checkWrite(ctx, "ExecRefreshMaterializedView"); err != nil {
+		return err
+	}
+	return e.execRefreshMaterializedView(ctx, e.execer(), viewName, concurrently)
+}
+
+// ExecRefreshMaterializedViewTx refreshes a materialized view within a transaction.
+func (e *Executor[T]) ExecRefreshMaterializedViewTx(ctx context.Context, tx *sqlx.Tx, viewName string, concurrently bool) error {
+	ctx, cancel := e.withTimeout(ctx, "")
+	defer cancel()
+	if err := e.checkWrite(ctx, "ExecRefreshMaterializedView"); err != nil {
+		return err
+	}
+	return e.execRefreshMaterializedView(ctx, e.execerFor(tx), viewName, concurrently)
+}
+
+// execRefreshMaterializedView renders and runs the REFRESH statement on execer.
+func (e *Executor[T]) execRefreshMaterializedView(ctx context.Context, execer sqlx.ExtContext, viewName string, concurrently bool) error {
+	sql, err := e.renderRefreshMaterializedView(viewName, concurrently)
+	if err != nil {
+		return err
+	}
+
+	ctx = withStatement(ctx, "refresh", "refresh")
+	e.emitSQL(ctx, "refresh", "refresh", sql, nil)
+	if _, err := execer.ExecContext(ctx, sql); err != nil {
+		return fmt.Errorf("edamame: refresh of materialized view %q failed: %w", viewName, err)
+	}
+	return nil
+}
+
+// renderRefreshMaterializedView builds the REFRESH statement for viewName. astql has no
+// REFRESH, so it is formatted directly from the validated view name.
+func (e *Executor[T]) renderRefreshMaterializedView(viewName string, concurrently bool) (string, error) {
+	if !e.isPostgres() {
+		return "", fmt.Errorf("edamame: ExecRefreshMaterializedView requires the postgres renderer")
+	}
+	schema, view, err := splitQualifiedName("view", viewName)
+	if err != nil {
+		return "", err
+	}
+	sql := "REFRESH MATERIALIZED VIEW "
+	if concurrently {
+		sql += "CONCURRENTLY "
+	}
+	return sql + quoteQualified(schema, view), nil
+}
@@ -0,0 +1,64 @@
+package edamame
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/mariadb"
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestRenderRefreshMaterializedView(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	tests := []struct {
+		view         string
+		concurrently bool
+		want         string
+	}{
+		{"user_totals", false, `REFRESH MATERIALIZED VIEW "user_totals"`},
+		{"user_totals", true, `REFRESH MATERIALIZED VIEW CONCURRENTLY "user_totals"`},
+		{"analytics.user_totals", false, `REFRESH MATERIALIZED VIEW "analytics"."user_totals"`},
+	}
+	for _, tt := range tests {
+		got, err := factory.renderRefreshMaterializedView(tt.view, tt.concurrently)
+		if err != nil {
+			t.Fatalf("renderRefreshMaterializedView(%q) failed: %v", tt.view, err)
+		}
+		if got != tt.want {
+			t.Errorf("renderRefreshMaterializedView(%q) = %q, want %q", tt.view, got, tt.want)
+		}
+	}
+
+	for _, view := range []string{"", "user totals", `user_totals"; DROP TABLE users; --`, "a.b.c"} {
+		if _, err := factory.renderRefreshMaterializedView(view, false); err == nil {
+			t.Errorf("expected error for view name %q", view)
+		}
+	}
+}
+
+func TestExecRefreshMaterializedView(t *testing.T) {
+	db := &recordingDB{}
+	factory, err := New[User](db, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if err := factory.ExecRefreshMaterializedView(context.Background(), "user_totals", true); !errors.Is(err, errRecorded) {
+		t.Errorf("expected the refresh to reach the database, got %v", err)
+	}
+	if want := `REFRESH MATERIALIZED VIEW CONCURRENTLY "user_totals"`; db.count() != 1 || db.queries[0] != want {
+		t.Errorf("expected %q, got %v", want, db.queries)
+	}
+
+	maria, err := New[User](&recordingDB{}, "users", mariadb.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if err := maria.ExecRefreshMaterializedView(context.Background(), "user_totals", false); err == nil {
+		t.Error("expected error for mariadb")
+	}
+}
@@ -0,0 +1,99 @@
+package edamame
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrNotifyFailed is wrapped by the error a write method returns when its write
+// succeeded but the NOTIFY configured by SetNotifyOnWrite failed.
+var ErrNotifyFailed = errors.New("edamame: NOTIFY failed")
+
+// writeNotifier is the NOTIFY configured by SetNotifyOnWrite.
+type writeNotifier[T any] struct {
+	channel string
+	payload func(*T) string
+}
+
+// SetNotifyOnWrite makes successful writes issue a PostgreSQL NOTIFY on channel with
+// payloadFn's result, so LISTEN connections learn of changes without polling. Tx
+// variants notify within the transaction, so the notification is delivered only if it
+// commits; other methods notify right after the write, which has then already committed.
+// When the NOTIFY fails, the method returns the written record or count together with
+// an error wrapping ErrNotifyFailed, so callers can tell that the write persisted and
+// must not be retried.
+//
+// ExecInsert, ExecInsertDefaults, ExecUpdate, an applied ExecUpsert and
+// ExecDeleteReturning (once per deleted row) pass the written row to payloadFn.
+// ExecDelete, ExecUpdateCount, ExecBulkUpdate and ExecInsertReturningInto, which scans
+// into another type, pass nil when they changed any rows. Methods built on ExecUpdate,
+// such as ExecUpdatePartial and soft delete, notify too; batch and Atom methods do not.
+// An empty channel disables it. PostgreSQL only.
+//
+// Example:
+//
+//	exec.SetNotifyOnWrite("users_changed", func(u *User) string {
+//	    if u == nil {
+//	        return ""
+//	    }
+//	    return strconv.Itoa(u.ID)
+//	})
+func (e *Executor[T]) SetNotifyOnWrite(channel string, payloadFn func(*T) string) error {
+	if channel != "" {
+		if !e.isPostgres() {
+			return fmt.Errorf("edamame: SetNotifyOnWrite requires the postgres renderer")
+		}
+		if payloadFn == nil {
+			return fmt.Errorf("edamame: SetNotifyOnWrite requires a payload function")
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if channel == "" {
+		e.notifier = nil
+		return nil
+	}
+	e.notifier = &writeNotifier[T]{channel: channel, payload: payloadFn}
+	return nil
+}
+
+// notifyWrite issues the configured NOTIFY through execer once per record.
+// It does nothing when no notifier is configured.
+func (e *Executor[T]) notifyWrite(ctx context.Context, execer sqlx.ExtContext, records ...*T) error {
+	e.mu.RLock()
+	n := e.notifier
+	e.mu.RUnlock()
+	if n == nil {
+		return nil
+	}
+
+	for _, record := range records {
+		if _, err := execer.ExecContext(ctx, "SELECT pg_notify($1, $2)", n.channel, n.payload(record)); err != nil {
+			return fmt.Errorf("%w: channel %s: %w", ErrNotifyFailed, n.channel, err)
+		}
+	}
+	return nil
+}
+
+// notifyRecord runs notifyWrite for a single written record, passing through a write
+// error. The record is returned with a NOTIFY error, as the write succeeded.
+func (e *Executor[T]) notifyRecord(ctx context.Context, execer sqlx.ExtContext, record *T, err error) (*T, error) {
+	if err != nil {
+		return nil, err
+	}
+	return record, e.notifyWrite(ctx, execer, record)
+}
+
+// notifyAffected runs notifyWrite with a nil record when a delete or counted update
+// changed any rows, passing through its error. The count is returned with a NOTIFY
+// error, as the write succeeded.
+func (e *Executor[T]) notifyAffected(ctx context.Context, execer sqlx.ExtContext, count int64, err error) (int64, error) {
+	if err != nil || count == 0 {
+		return count, err
+	}
+	return count, e.notifyWrite(ctx, execer, nil)
+}
@@ -0,0 +1,83 @@
+package edamame
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/mariadb"
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestSetNotifyOnWrite_Validation(t *testing.T) {
+	payload := func(*User) string { return "" }
+
+	maria, err := New[User](nil, "users", mariadb.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if err := maria.SetNotifyOnWrite("users_changed", payload); err == nil {
+		t.Error("expected error for a non-postgres renderer")
+	}
+
+	exec, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if err := exec.SetNotifyOnWrite("users_changed", nil); err == nil {
+		t.Error("expected error for a nil payload function")
+	}
+	if err := exec.SetNotifyOnWrite("users_changed", payload); err != nil {
+		t.Fatalf("SetNotifyOnWrite() failed: %v", err)
+	}
+	if err := exec.SetNotifyOnWrite("", nil); err != nil {
+		t.Fatalf("SetNotifyOnWrite() should disable with an empty channel: %v", err)
+	}
+	if exec.notifier != nil {
+		t.Error("expected the notifier to be removed")
+	}
+}
+
+func TestNotifyWrite(t *testing.T) {
+	db := &recordingDB{}
+	exec, err := New[User](db, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ctx := context.Background()
+
+	if err := exec.notifyWrite(ctx, db, &User{ID: 1}); err != nil || db.count() != 0 {
+		t.Fatalf("expected no NOTIFY without a notifier, got %v and %d statements", err, db.count())
+	}
+
+	var payloads []*User
+	if err := exec.SetNotifyOnWrite("users_changed", func(u *User) string {
+		payloads = append(payloads, u)
+		return "changed"
+	}); err != nil {
+		t.Fatalf("SetNotifyOnWrite() failed: %v", err)
+	}
+
+	if err := exec.notifyWrite(ctx, db, &User{ID: 1}); !errors.Is(err, errRecorded) || !errors.Is(err, ErrNotifyFailed) {
+		t.Fatalf("expected recorded error wrapped in ErrNotifyFailed, got %v", err)
+	}
+	if db.queries[0] != "SELECT pg_notify($1, $2)" {
+		t.Errorf("unexpected NOTIFY statement: %s", db.queries[0])
+	}
+	if len(payloads) != 1 || payloads[0].ID != 1 {
+		t.Errorf("expected payload for the written row, got %v", payloads)
+	}
+
+	if count, err := exec.notifyAffected(ctx, db, 0, nil); count != 0 || err != nil || db.count() != 1 {
+		t.Errorf("expected no NOTIFY when nothing was deleted, got %d, %v and %d statements", count, err, db.count())
+	}
+	if count, err := exec.notifyAffected(ctx, db, 2, nil); !errors.Is(err, ErrNotifyFailed) || count != 2 || payloads[1] != nil {
+		t.Errorf("expected NOTIFY with a nil row after a delete and the count kept, got %d, %v", count, err)
+	}
+
+	// A failed NOTIFY after a committed write keeps the written record.
+	written := &User{ID: 3}
+	if record, err := exec.notifyRecord(ctx, db, written, nil); record != written || !errors.Is(err, ErrNotifyFailed) {
+		t.Errorf("expected the written record with ErrNotifyFailed, got %v, %v", record, err)
+	}
+}
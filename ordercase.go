@@ -0,0 +1,153 @@
+package edamame
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// errCustomOrderingUnsupported is returned by execution paths that run soy's SQL
+// unmodified and so cannot apply custom value or weighted ordering.
+var errCustomOrderingUnsupported = errors.New("edamame: custom value and weighted ordering are not supported by this method")
+
+// hasCustomOrdering reports whether any ORDER BY entry uses a custom value order or a weighted sum.
+func hasCustomOrdering(orderBy []OrderBySpec) bool {
+	for _, o := range orderBy {
+		if o.IsCase() || o.IsWeighted() {
+			return true
+		}
+	}
+	return false
+}
+
+// caseOrderParam names the bind param for value j of ORDER BY entry i.
+func caseOrderParam(i, j int) string {
+	return fmt.Sprintf("edamame_order_%d_%d", i, j)
+}
+
+// rewriteCustomOrdering replaces the placeholder ORDER BY items rendered for IsCase and
+// IsWeighted entries with CASE and weighted-sum expressions, returning the rewritten SQL
+// and the values to bind. Neither soy nor astql renders these in ORDER BY, so the rendered
+// clause is edited: items are split on ", " (identifiers are schema-validated and quoted)
+// and matched to orderBy by position, a weighted entry consuming one placeholder item per
+// term. The primary-key tie-breaker, if any, trails the spec entries.
+func rewriteCustomOrdering(sql string, orderBy []OrderBySpec) (string, map[string]any, error) {
+	if !hasCustomOrdering(orderBy) {
+		return sql, nil, nil
+	}
+
+	start := strings.LastIndex(sql, " ORDER BY ")
+	if start < 0 {
+		return "", nil, fmt.Errorf("edamame: rendered SQL has no ORDER BY clause")
+	}
+	start += len(" ORDER BY ")
+	end := len(sql)
+	for _, kw := range []string{" LIMIT ", " OFFSET ", " FOR "} {
+		if i := strings.Index(sql[start:end], kw); i >= 0 {
+			end = start + i
+		}
+	}
+
+	items := strings.Split(sql[start:end], ", ")
+	expected := 0
+	for _, o := range orderBy {
+		expected += max(len(o.Terms), 1)
+	}
+	if len(items) < expected {
+		return "", nil, fmt.Errorf("edamame: rendered ORDER BY has %d items, expected at least %d", len(items), expected)
+	}
+
+	binds := make(map[string]any)
+	rewritten := make([]string, 0, len(items))
+	next := 0
+	for i, o := range orderBy {
+		switch {
+		case o.IsWeighted():
+			item, err := weightedOrderItem(o, items[next:next+len(o.Terms)])
+			if err != nil {
+				return "", nil, err
+			}
+			rewritten = append(rewritten, item)
+			next += len(o.Terms)
+		case o.IsCase():
+			item, err := caseOrderItem(i, o, items[next], binds)
+			if err != nil {
+				return "", nil, err
+			}
+			rewritten = append(rewritten, item)
+			next++
+		default:
+			rewritten = append(rewritten, items[next])
+			next++
+		}
+	}
+	rewritten = append(rewritten, items[next:]...)
+
+	return sql[:start] + strings.Join(rewritten, ", ") + sql[end:], binds, nil
+}
+
+// splitOrderItem splits a rendered ORDER BY item into its expression and direction.
+func splitOrderItem(item string) (string, string, error) {
+	sep := strings.LastIndex(item, " ")
+	if sep < 0 {
+		return "", "", fmt.Errorf("edamame: unexpected ORDER BY item %q", item)
+	}
+	return item[:sep], item[sep+1:], nil
+}
+
+// caseOrderItem builds the CASE expression for entry i from its placeholder item,
+// adding the values it compares against to binds.
+func caseOrderItem(i int, o OrderBySpec, item string, binds map[string]any) (string, error) {
+	field, direction, err := splitOrderItem(item)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("CASE ")
+	b.WriteString(field)
+	for j, v := range o.Values {
+		name := caseOrderParam(i, j)
+		fmt.Fprintf(&b, " WHEN :%s THEN %d", name, j)
+		binds[name] = v
+	}
+	fmt.Fprintf(&b, " ELSE %d END %s", len(o.Values), direction)
+	return b.String(), nil
+}
+
+// weightedOrderItem builds "(:w1 * field1 + :w2 * field2) DIR" from the placeholder
+// items rendered for each term's field.
+func weightedOrderItem(o OrderBySpec, items []string) (string, error) {
+	var b strings.Builder
+	b.WriteString("(")
+	direction := ""
+	for k, term := range o.Terms {
+		field, dir, err := splitOrderItem(items[k])
+		if err != nil {
+			return "", err
+		}
+		direction = dir
+		if k > 0 {
+			b.WriteString(" + ")
+		}
+		fmt.Fprintf(&b, ":%s * %s", term.Param, field)
+	}
+	b.WriteString(") ")
+	b.WriteString(direction)
+	return b.String(), nil
+}
+
+// mergeParams returns params with extra added, leaving params unmodified.
+func mergeParams(params, extra map[string]any) map[string]any {
+	if len(extra) == 0 {
+		return params
+	}
+	merged := make(map[string]any, len(params)+len(extra))
+	for k, v := range params {
+		merged[k] = v
+	}
+	for k, v := range extra {
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,138 @@
+package edamame
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/mariadb"
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestOrderByCase_Render(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	stmt := NewQueryStatement("by-name-priority", "Users in custom name order", QuerySpec{
+		OrderBy: []OrderBySpec{
+			OrderByCase("name", "pending", "active", "closed"),
+			{Field: "email", Direction: "desc"},
+		},
+		Limit: intPtr(10),
+	})
+
+	sql, err := factory.RenderQuery(stmt)
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+
+	want := `ORDER BY CASE "name" WHEN :edamame_order_0_0 THEN 0 WHEN :edamame_order_0_1 THEN 1 WHEN :edamame_order_0_2 THEN 2 ELSE 3 END ASC, "email" DESC LIMIT 10`
+	if !strings.HasSuffix(sql, want) {
+		t.Errorf("expected SQL to end with:\n%s\ngot:\n%s", want, sql)
+	}
+}
+
+func TestOrderByCase_RenderSelectMariaDB(t *testing.T) {
+	factory, err := New[User](nil, "users", mariadb.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	stmt := NewSelectStatement("first-by-priority", "First user in custom name order", SelectSpec{
+		OrderBy: []OrderBySpec{{Field: "name", Direction: "desc", Values: []string{"b", "a"}}},
+	})
+
+	sql, err := factory.RenderSelect(stmt)
+	if err != nil {
+		t.Fatalf("RenderSelect() failed: %v", err)
+	}
+	if !strings.Contains(sql, "ORDER BY CASE `name` WHEN :edamame_order_0_0 THEN 0 WHEN :edamame_order_0_1 THEN 1 ELSE 2 END DESC") {
+		t.Errorf("unexpected SQL: %s", sql)
+	}
+}
+
+func TestRewriteCaseOrdering_Binds(t *testing.T) {
+	orderBy := []OrderBySpec{
+		{Field: "age", Direction: "asc"},
+		OrderByCase("name", "x", "y"),
+	}
+	sql, binds, err := rewriteCustomOrdering(`SELECT * FROM "users" ORDER BY "age" ASC, "name" ASC, "id" ASC FOR UPDATE`, orderBy)
+	if err != nil {
+		t.Fatalf("rewriteCustomOrdering() failed: %v", err)
+	}
+
+	want := `SELECT * FROM "users" ORDER BY "age" ASC, CASE "name" WHEN :edamame_order_1_0 THEN 0 WHEN :edamame_order_1_1 THEN 1 ELSE 2 END ASC, "id" ASC FOR UPDATE`
+	if sql != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, sql)
+	}
+	if binds["edamame_order_1_0"] != "x" || binds["edamame_order_1_1"] != "y" || len(binds) != 2 {
+		t.Errorf("unexpected binds: %v", binds)
+	}
+}
+
+func TestOrderByCase_UnsupportedPaths(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	spec := CompoundQuerySpec{
+		Base:     QuerySpec{OrderBy: []OrderBySpec{OrderByCase("name", "a")}},
+		Operands: []SetOperandSpec{{Operation: "union", Query: QuerySpec{}}},
+	}
+	if _, err := factory.RenderCompound(spec); err == nil {
+		t.Error("RenderCompound() should reject custom value ordering")
+	}
+}
+
+func TestOrderByWeighted_Render(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	stmt := NewQueryStatement("ranked", "Users by weighted score", QuerySpec{
+		OrderBy: []OrderBySpec{
+			OrderByWeighted("desc", OrderTermSpec{Field: "age", Param: "w_age"}, OrderTermSpec{Field: "id", Param: "w_id"}),
+			OrderByCase("name", "a"),
+			{Field: "email", Direction: "asc"},
+		},
+		Limit: intPtr(5),
+	})
+
+	sql, err := factory.RenderQuery(stmt)
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+	want := `ORDER BY (:w_age * "age" + :w_id * "id") DESC, CASE "name" WHEN :edamame_order_1_0 THEN 0 ELSE 1 END ASC, "email" ASC LIMIT 5`
+	if !strings.HasSuffix(sql, want) {
+		t.Errorf("expected SQL to end with:\n%s\ngot:\n%s", want, sql)
+	}
+
+	params := stmt.Params()
+	if len(params) != 2 || params[0].Name != "w_age" || params[1].Name != "w_id" {
+		t.Errorf("expected derived params w_age, w_id, got %+v", params)
+	}
+}
+
+func TestOrderByWeighted_Invalid(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	badParam := NewSelectStatement("bad-param", "Weighted with invalid param", SelectSpec{
+		OrderBy: []OrderBySpec{OrderByWeighted("desc", OrderTermSpec{Field: "age", Param: "w; DROP TABLE users"})},
+	})
+	if _, err := factory.RenderSelect(badParam); err == nil {
+		t.Error("expected error for invalid term param")
+	}
+
+	badField := NewQueryStatement("bad-field", "Weighted with unknown field", QuerySpec{
+		OrderBy: []OrderBySpec{OrderByWeighted("desc", OrderTermSpec{Field: "nope", Param: "w"})},
+	})
+	if _, err := factory.RenderQuery(badField); err == nil {
+		t.Error("expected error for unknown term field")
+	}
+}
@@ -0,0 +1,99 @@
+package edamame
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// errOuterWhereUnsupported is returned by execution paths that run soy's SQL
+// unmodified and so cannot wrap the query for OuterWhere conditions.
+var errOuterWhereUnsupported = errors.New("edamame: OuterWhere is not supported by this method")
+
+// outerWhereAlias names the derived table a query is wrapped in for OuterWhere.
+const outerWhereAlias = "edamame_w"
+
+// outerWhereTail lists the clauses that move from the wrapped query to the outer
+// query, so ordering and paging apply to the filtered rows.
+var outerWhereTail = []string{" ORDER BY ", " LIMIT ", " OFFSET ", " FETCH "}
+
+// selectAliases returns the output aliases of a spec's SelectExprs and FieldAliases,
+// the names OuterWhere conditions may reference.
+func selectAliases(exprs []SelectExprSpec, fieldAliases map[string]string) map[string]bool {
+	aliases := make(map[string]bool, len(exprs)+len(fieldAliases))
+	for _, expr := range exprs {
+		aliases[expr.Alias] = true
+	}
+	for _, alias := range fieldAliases {
+		aliases[alias] = true
+	}
+	return aliases
+}
+
+// wrapOuterWhere wraps sql in "SELECT * FROM (sql) AS edamame_w WHERE ..." so its
+// conditions can filter on the aliases of SELECT expressions, which SQL does not
+// allow in the query's own WHERE. The trailing ORDER BY, LIMIT and OFFSET clauses
+// move to the outer query.
+func (e *Executor[T]) wrapOuterWhere(sql string, conditions []ConditionSpec, aliases map[string]bool) (string, error) {
+	if len(conditions) == 0 {
+		return sql, nil
+	}
+	parts := make([]string, len(conditions))
+	for i, c := range conditions {
+		part, err := e.outerConditionSQL(c, aliases)
+		if err != nil {
+			return "", err
+		}
+		parts[i] = part
+	}
+
+	cut := len(sql)
+	for _, clause := range outerWhereTail {
+		if i := topLevelIndex(sql, 0, clause); i >= 0 && i < cut {
+			cut = i
+		}
+	}
+	return "SELECT * FROM (" + sql[:cut] + ") AS " + outerWhereAlias +
+		" WHERE " + strings.Join(parts, " AND ") + sql[cut:], nil
+}
+
+// outerConditionSQL renders an OuterWhere condition. soy validates fields against
+// the schema, which holds no aliases, so each condition is rendered on a stand-in
+// column and the quoted stand-in is replaced with the quoted alias.
+func (e *Executor[T]) outerConditionSQL(c ConditionSpec, aliases map[string]bool) (string, error) {
+	if c.IsGroup() {
+		logic := strings.ToUpper(c.Logic)
+		if logic != "AND" && logic != "OR" {
+			return "", fmt.Errorf("edamame: OuterWhere group logic must be AND or OR, got %q", c.Logic)
+		}
+		parts := make([]string, len(c.Group))
+		for i, g := range c.Group {
+			part, err := e.outerConditionSQL(g, aliases)
+			if err != nil {
+				return "", err
+			}
+			parts[i] = part
+		}
+		return "(" + strings.Join(parts, " "+logic+" ") + ")", nil
+	}
+	if c.Fragment != "" || c.IsFieldComparison() {
+		return "", fmt.Errorf("edamame: OuterWhere conditions cannot use fragments or field comparisons")
+	}
+	if !aliases[c.Field] {
+		return "", fmt.Errorf("edamame: OuterWhere field %q is not a SELECT alias", c.Field)
+	}
+
+	standIn := e.schemaColumns()[0]
+	alias := c.Field
+	c.Field = standIn
+	result, err := applyConditionToQuery(e.soy.Query(), c).Render()
+	if err != nil {
+		return "", fmt.Errorf("edamame: invalid OuterWhere condition on %q: %w", alias, err)
+	}
+	_, where, ok := strings.Cut(result.SQL, " WHERE ")
+	if !ok || len(where) < len(standIn)+2 || where[1:len(standIn)+1] != standIn {
+		return "", fmt.Errorf("edamame: unexpected rendering of OuterWhere condition on %q", alias)
+	}
+	open, closing := where[:1], where[len(standIn)+1:len(standIn)+2]
+	return open + alias + closing + where[len(standIn)+2:], nil
+}
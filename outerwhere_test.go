@@ -0,0 +1,111 @@
+package edamame
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+var fullName = []SelectExprSpec{{Func: "concat", Fields: []string{"name", "email"}, Alias: "full_name"}}
+
+func TestRenderQuery_OuterWhere(t *testing.T) {
+	exec, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	limit := 10
+	stmt := NewQueryStatement("search", "Search by full name", QuerySpec{
+		Fields:      []string{"id"},
+		SelectExprs: fullName,
+		Where:       []ConditionSpec{{Field: "age", Operator: ">=", Param: "min_age"}},
+		OuterWhere:  []ConditionSpec{{Field: "full_name", Operator: "LIKE", Param: "q"}},
+		OrderBy:     []OrderBySpec{{Field: "id", Direction: "asc"}},
+		Limit:       &limit,
+	})
+
+	sql, err := exec.RenderQuery(stmt)
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+	want := `SELECT * FROM (SELECT "id", CONCAT("name", "email") AS "full_name" FROM "users" WHERE "age" >= :min_age) AS edamame_w` +
+		` WHERE "full_name" LIKE :q ORDER BY "id" ASC LIMIT 10`
+	if sql != want {
+		t.Errorf("expected %s, got %s", want, sql)
+	}
+
+	params := stmt.Params()
+	if len(params) != 2 || params[1].Name != "q" || !params[1].Required {
+		t.Errorf("expected OuterWhere param q to be derived, got %+v", params)
+	}
+}
+
+func TestRenderQuery_OuterWhereGroupsAndFieldAliases(t *testing.T) {
+	exec, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	sql, err := exec.RenderQuery(NewQueryStatement("search", "Search", QuerySpec{
+		Fields:       []string{"email"},
+		FieldAliases: map[string]string{"email": "contact"},
+		SelectExprs:  fullName,
+		OuterWhere: []ConditionSpec{{Logic: "OR", Group: []ConditionSpec{
+			{Field: "full_name", Operator: "LIKE", Param: "q"},
+			{Field: "contact", IsNull: true, Operator: "IS NULL"},
+		}}},
+	}))
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+	if !strings.HasSuffix(sql, `AS edamame_w WHERE ("full_name" LIKE :q OR "contact" IS NULL)`) {
+		t.Errorf("expected grouped outer WHERE, got: %s", sql)
+	}
+}
+
+func TestOuterWhere_Errors(t *testing.T) {
+	exec, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	tests := []struct {
+		name string
+		spec QuerySpec
+		want string
+	}{
+		{"unknown alias", QuerySpec{SelectExprs: fullName, OuterWhere: []ConditionSpec{{Field: "name", Operator: "=", Param: "q"}}}, `"name" is not a SELECT alias`},
+		{"field comparison", QuerySpec{SelectExprs: fullName, OuterWhere: []ConditionSpec{{Field: "full_name", Operator: "=", RightField: "name"}}}, "field comparisons"},
+		{"bad logic", QuerySpec{SelectExprs: fullName, OuterWhere: []ConditionSpec{{Logic: "XOR", Group: []ConditionSpec{{Field: "full_name", Operator: "=", Param: "q"}}}}}, "AND or OR"},
+		{"bad operator", QuerySpec{SelectExprs: fullName, OuterWhere: []ConditionSpec{{Field: "full_name", Operator: "~~~", Param: "q"}}}, "invalid OuterWhere condition"},
+		{"locking", QuerySpec{SelectExprs: fullName, ForLocking: "update", OuterWhere: []ConditionSpec{{Field: "full_name", Operator: "=", Param: "q"}}}, "ForLocking"},
+	}
+	for _, tt := range tests {
+		if _, err := exec.RenderQuery(NewQueryStatement("search", "Search", tt.spec)); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+}
+
+func TestExecQuery_OuterWhere(t *testing.T) {
+	db := &recordingDB{}
+	exec, err := New[User](db, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	stmt := NewQueryStatement("search", "Search", QuerySpec{SelectExprs: fullName, OuterWhere: []ConditionSpec{{Field: "full_name", Operator: "LIKE", Param: "q"}}})
+
+	if _, err := exec.ExecQuery(context.Background(), stmt, map[string]any{"q": "%ann%"}); err == nil {
+		t.Fatal("expected the recorded error")
+	}
+	if db.count() != 1 || !strings.HasPrefix(db.queries[0], "SELECT * FROM (SELECT CONCAT(") {
+		t.Errorf("expected the wrapped query, got %v", db.queries)
+	}
+
+	if _, err := exec.ExecQueryAtom(context.Background(), stmt, map[string]any{"q": "%ann%"}); !errors.Is(err, errOuterWhereUnsupported) {
+		t.Errorf("expected errOuterWhereUnsupported, got %v", err)
+	}
+	if _, err := exec.ExecPaginate(context.Background(), stmt, map[string]any{"q": "%ann%"}, 1, 10); !errors.Is(err, errOuterWhereUnsupported) {
+		t.Errorf("expected errOuterWhereUnsupported, got %v", err)
+	}
+}
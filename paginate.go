@@ -0,0 +1,111 @@
+package edamame
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PageResult is one page of a paginated query with the metadata needed to render pagers.
+type PageResult[T any] struct {
+	Items      []*T  `json:"items"`
+	Total      int64 `json:"total"`       // rows matching the statement across all pages
+	Page       int   `json:"page"`        // 1-based page number
+	PageSize   int   `json:"page_size"`   // maximum items per page
+	TotalPages int   `json:"total_pages"` // zero when Total is zero
+}
+
+// ExecPaginate executes a query statement for a 1-based page of pageSize rows and counts
+// the rows matching its Where conditions. The statement's Limit and Offset are replaced.
+// A page past the end returns no items with the totals still set. Statements with
+// GROUP BY, HAVING or DISTINCT cannot be counted this way and return an error.
+//
+// Order the statement on a unique key (or enable SetOrderByTieBreaker) so pages are stable.
+//
+// Example:
+//
+//	page, err := exec.ExecPaginate(ctx, ActiveUsers, params, 2, 20)
+func (e *Executor[T]) ExecPaginate(ctx context.Context, stmt QueryStatement, params map[string]any, page, pageSize int) (PageResult[T], error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	return e.paginate(withRead(ctx), nil, stmt, params, page, pageSize)
+}
+
+// ExecPaginateTx executes a paginated query statement within a transaction.
+func (e *Executor[T]) ExecPaginateTx(ctx context.Context, tx *sqlx.Tx, stmt QueryStatement, params map[string]any, page, pageSize int) (PageResult[T], error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	return e.paginate(ctx, tx, stmt, params, page, pageSize)
+}
+
+// paginate counts the statement's rows and fetches the requested page. A nil tx runs outside a transaction.
+func (e *Executor[T]) paginate(ctx context.Context, tx *sqlx.Tx, stmt QueryStatement, params map[string]any, page, pageSize int) (PageResult[T], error) {
+	var result PageResult[T]
+	if page < 1 {
+		return result, fmt.Errorf("edamame: page must be positive, got %d", page)
+	}
+	if pageSize < 1 {
+		return result, fmt.Errorf("edamame: page size must be positive, got %d", pageSize)
+	}
+	params, err := e.prepareParams(stmt, params)
+	if err != nil {
+		return result, err
+	}
+	spec := stmt.spec
+	if len(spec.GroupBy) > 0 || len(spec.Having) > 0 || len(spec.HavingAgg) > 0 || spec.Distinct || len(spec.DistinctOn) > 0 {
+		return result, fmt.Errorf("edamame: cannot paginate grouped or distinct statement %q", stmt.name)
+	}
+	if hasDistinctFrom(spec.Where) {
+		return result, errDistinctFromUnsupported
+	}
+	if hasQuantified(spec.Where) {
+		return result, errQuantifierUnsupported
+	}
+	if hasJSONPath(spec.Where) {
+		return result, errJSONPathUnsupported
+	}
+	if hasFullText(spec.Where) {
+		return result, errFullTextUnsupported
+	}
+	if hasExists(spec.Where) {
+		return result, errExistsUnsupported
+	}
+	if e.queryRewrites(spec).likeEscape {
+		return result, errMatchUnsupported
+	}
+	if len(spec.OuterWhere) > 0 {
+		return result, errOuterWhereUnsupported
+	}
+
+	count := NewAggregateStatement(stmt.name+"-count", "Row count for "+stmt.name, AggCount, AggregateSpec{Where: spec.Where, ParamDefaults: spec.ParamDefaults, Params: spec.Params, WithTrashed: spec.WithTrashed})
+	total, err := execAggregateScalar[T, int64](ctx, e, e.execerFor(tx), count, params)
+	if err != nil {
+		return result, err
+	}
+
+	offset := (page - 1) * pageSize
+	spec.Limit, spec.LimitParam = &pageSize, ""
+	spec.Offset, spec.OffsetParam = &offset, ""
+	paged := stmt
+	paged.spec = spec
+
+	q, err := e.queryFromSpec(spec)
+	if err != nil {
+		return result, err
+	}
+	records, err := e.runQuery(ctx, tx, paged, q, params)
+	if err != nil {
+		return result, err
+	}
+	if err := e.assertResults(records...); err != nil {
+		return result, err
+	}
+
+	result.Items = e.dedupResults(records)
+	result.Total = total
+	result.Page = page
+	result.PageSize = pageSize
+	result.TotalPages = int((total + int64(pageSize) - 1) / int64(pageSize))
+	return result, nil
+}
@@ -0,0 +1,37 @@
+package edamame
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestExecPaginate_Validation(t *testing.T) {
+	factory, err := New[User](&recordingDB{}, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ctx := context.Background()
+	all := NewQueryStatement("all", "All users", QuerySpec{})
+
+	if _, err := factory.ExecPaginate(ctx, all, nil, 0, 10); err == nil {
+		t.Error("expected error for page 0")
+	}
+	if _, err := factory.ExecPaginate(ctx, all, nil, 1, 0); err == nil {
+		t.Error("expected error for page size 0")
+	}
+
+	grouped := NewQueryStatement("by-age", "Users grouped by age", QuerySpec{
+		Fields:  []string{"age"},
+		GroupBy: []string{"age"},
+	})
+	if _, err := factory.ExecPaginate(ctx, grouped, nil, 1, 10); err == nil {
+		t.Error("expected error for grouped statement")
+	}
+
+	if _, err := factory.ExecPaginate(ctx, all, nil, 1, 10); !errors.Is(err, errRecorded) {
+		t.Errorf("expected valid page to reach the database, got %v", err)
+	}
+}
@@ -0,0 +1,273 @@
+package edamame
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"slices"
+)
+
+// ValidateParams checks that params supplies every required parameter of stmt.
+// A parameter is required when its ParamSpec is Required and has no Default;
+// optional parameters such as a parameterized limit may be absent.
+//
+// Exec methods run this check before executing unless it is disabled with
+// SetParamValidation, so a missing parameter is reported by name rather than
+// as a driver binding error.
+//
+// Example:
+//
+//	if err := exec.ValidateParams(Adults, params); err != nil {
+//	    return err // edamame: missing required param "min_age" for statement "adults"
+//	}
+func (e *Executor[T]) ValidateParams(stmt Statement, params map[string]any) error {
+	specs, err := e.StatementParams(stmt)
+	if err != nil {
+		return err
+	}
+	return validateParams(stmt.Name(), specs, params)
+}
+
+// validateParams reports the first required param in specs that params does not supply.
+func validateParams(name string, specs []ParamSpec, params map[string]any) error {
+	for _, p := range specs {
+		if !p.Required || p.Default != nil {
+			continue
+		}
+		if _, ok := params[p.Name]; !ok {
+			return fmt.Errorf("edamame: missing required param %q for statement %q", p.Name, name)
+		}
+	}
+	return nil
+}
+
+// SetParamValidation enables or disables the required-parameter check Exec methods
+// run before executing a statement. It is enabled by default; performance-sensitive
+// callers that build params programmatically can disable it.
+func (e *Executor[T]) SetParamValidation(enabled bool) {
+	e.noParamValidation.Store(!enabled)
+}
+
+// prepareParams rejects conditions the statement type cannot render (see
+// checkSoyConditions), fills in parameter defaults, coerces string values when param coercion
+// is enabled, checks limit and offset params and, unless parameter validation is
+// disabled, runs ValidateParams. The caller's map is not modified.
+func (e *Executor[T]) prepareParams(stmt Statement, params map[string]any) (map[string]any, error) {
+	if err := e.checkSoyConditions(stmt); err != nil {
+		return nil, err
+	}
+	specs, err := e.StatementParams(stmt)
+	if err != nil {
+		return nil, err
+	}
+	params = applyParamDefaults(specs, params)
+	if e.paramCoercion.Load() {
+		if params, err = e.coerceParams(stmt, specs, params); err != nil {
+			return nil, err
+		}
+	}
+	if params, err = e.bindMatchParams(stmt, params); err != nil {
+		return nil, err
+	}
+	if err := e.checkPageParams(stmt, params); err != nil {
+		return nil, err
+	}
+	if e.noParamValidation.Load() {
+		return params, nil
+	}
+	if err := validateParams(stmt.Name(), specs, params); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// SetParamDefault overrides the default of param for the statement named stmtName on
+// this executor, layered over the default from the statement's spec. The statement
+// itself is unchanged, so the same statement can run with different defaults per
+// environment. A nil value removes the override. Overrides for params the statement
+// does not reference are ignored.
+//
+// Example:
+//
+//	exec.SetParamDefault(RecentOrders.Name(), "limit", 50)
+func (e *Executor[T]) SetParamDefault(stmtName, param string, value any) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if value == nil {
+		delete(e.paramDefaults[stmtName], param)
+		if len(e.paramDefaults[stmtName]) == 0 {
+			delete(e.paramDefaults, stmtName)
+		}
+		return
+	}
+	if e.paramDefaults == nil {
+		e.paramDefaults = make(map[string]map[string]any)
+	}
+	if e.paramDefaults[stmtName] == nil {
+		e.paramDefaults[stmtName] = make(map[string]any)
+	}
+	e.paramDefaults[stmtName][param] = value
+}
+
+// withDefaultOverrides returns params with the defaults set by SetParamDefault for
+// the statement named name, copying params rather than modifying it.
+func (e *Executor[T]) withDefaultOverrides(name string, params []ParamSpec) []ParamSpec {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	overrides := e.paramDefaults[name]
+	if len(overrides) == 0 {
+		return params
+	}
+	return withParamDefaults(slices.Clone(params), overrides)
+}
+
+// SetPageParamLimits sets the largest value a statement's LimitParam and OffsetParam
+// may take. Exec methods reject a negative limit or offset, or one above its maximum,
+// before executing, so a caller-supplied value cannot request an unbounded scan. A
+// maximum of 0 leaves that param unbounded; negative values are always rejected.
+//
+// Example:
+//
+//	exec.SetPageParamLimits(500, 100_000)
+func (e *Executor[T]) SetPageParamLimits(maxLimit, maxOffset int) error {
+	if maxLimit < 0 || maxOffset < 0 {
+		return fmt.Errorf("edamame: page param limits must not be negative")
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.maxLimitParam, e.maxOffsetParam = maxLimit, maxOffset
+	return nil
+}
+
+// checkPageParams checks the values params supplies for stmt's LimitParam and
+// OffsetParam against zero and the maximums set by SetPageParamLimits.
+func (e *Executor[T]) checkPageParams(stmt Statement, params map[string]any) error {
+	limitParam, offsetParam := statementPageParams(stmt)
+	if limitParam == "" && offsetParam == "" {
+		return nil
+	}
+	e.mu.RLock()
+	maxLimit, maxOffset := e.maxLimitParam, e.maxOffsetParam
+	e.mu.RUnlock()
+
+	if err := checkPageParam("limit", limitParam, params, maxLimit); err != nil {
+		return err
+	}
+	return checkPageParam("offset", offsetParam, params, maxOffset)
+}
+
+// checkPageParam checks the value of the kind ("limit" or "offset") param name.
+// Absent and non-integer values are left to the driver.
+func checkPageParam(kind, name string, params map[string]any, maximum int) error {
+	if name == "" {
+		return nil
+	}
+	n, ok := intParam(params[name])
+	if !ok {
+		return nil
+	}
+	if n < 0 {
+		return fmt.Errorf("edamame: %s param %q must not be negative, got %d", kind, name, n)
+	}
+	if maximum > 0 && n > int64(maximum) {
+		return fmt.Errorf("edamame: %s param %q exceeds the maximum of %d, got %d", kind, name, maximum, n)
+	}
+	return nil
+}
+
+// intParam returns v as an int64 if it is an integer. Unsigned values beyond
+// math.MaxInt64 are clamped to it.
+func intParam(v any) (int64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return int64(min(rv.Uint(), math.MaxInt64)), true
+	}
+	return 0, false
+}
+
+// statementPageParams returns the LimitParam and OffsetParam of stmt's spec.
+func statementPageParams(stmt Statement) (limit, offset string) {
+	switch s := stmt.(type) {
+	case QueryStatement:
+		return s.spec.LimitParam, s.spec.OffsetParam
+	case SelectStatement:
+		return s.spec.LimitParam, s.spec.OffsetParam
+	}
+	return "", ""
+}
+
+// prepareBatchParams runs prepareParams on every parameter set of a batch.
+func (e *Executor[T]) prepareBatchParams(stmt Statement, batchParams []map[string]any) ([]map[string]any, error) {
+	prepared := make([]map[string]any, len(batchParams))
+	for i, params := range batchParams {
+		params, err := e.prepareParams(stmt, params)
+		if err != nil {
+			return nil, err
+		}
+		prepared[i] = params
+	}
+	return prepared, nil
+}
+
+// applyParamDefaults returns params with the Default of each parameter in specs
+// that params does not supply. A key that is present is authoritative, even when its
+// value is nil. params is returned as is when no default applies.
+func applyParamDefaults(specs []ParamSpec, params map[string]any) map[string]any {
+	var merged map[string]any
+	for _, p := range specs {
+		if p.Default == nil {
+			continue
+		}
+		if _, ok := params[p.Name]; ok {
+			continue
+		}
+		if merged == nil {
+			merged = make(map[string]any, len(params)+1)
+			maps.Copy(merged, params)
+		}
+		merged[p.Name] = p.Default
+	}
+	if merged == nil {
+		return params
+	}
+	return merged
+}
+
+// withParamSpecs overlays the caller-provided specs in overrides onto the derived
+// params, matching by name: a non-empty Type or Description and a non-nil Default
+// replace the derived value. Required stays as derived. Specs for parameters the
+// spec does not reference are ignored.
+func withParamSpecs(params []ParamSpec, overrides []ParamSpec) []ParamSpec {
+	for _, o := range overrides {
+		for i := range params {
+			if params[i].Name != o.Name {
+				continue
+			}
+			if o.Type != "" {
+				params[i].Type = o.Type
+			}
+			if o.Description != "" {
+				params[i].Description = o.Description
+			}
+			if o.Default != nil {
+				params[i].Default = o.Default
+			}
+		}
+	}
+	return params
+}
+
+// withParamDefaults sets the Default of each derived parameter named in defaults.
+// Defaults for parameters the spec does not reference are ignored.
+func withParamDefaults(params []ParamSpec, defaults map[string]any) []ParamSpec {
+	for i := range params {
+		if v, ok := defaults[params[i].Name]; ok {
+			params[i].Default = v
+		}
+	}
+	return params
+}
@@ -0,0 +1,268 @@
+package edamame
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestValidateParams(t *testing.T) {
+	exec, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	stmt := NewQueryStatement("adults", "Users at or above an age", QuerySpec{
+		Where:      []ConditionSpec{{Field: "age", Operator: ">=", Param: "min_age"}},
+		LimitParam: "limit",
+	})
+
+	err = exec.ValidateParams(stmt, nil)
+	if err == nil {
+		t.Fatal("expected error for missing required param")
+	}
+	if want := `edamame: missing required param "min_age" for statement "adults"`; err.Error() != want {
+		t.Errorf("expected %q, got %q", want, err.Error())
+	}
+
+	// The limit param is optional.
+	if err := exec.ValidateParams(stmt, map[string]any{"min_age": 18}); err != nil {
+		t.Errorf("expected optional param to be allowed absent, got %v", err)
+	}
+}
+
+func TestExecChecksParams(t *testing.T) {
+	db := &recordingDB{}
+	exec, err := New[User](db, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ctx := context.Background()
+	query := NewQueryStatement("adults", "Users at or above an age", QuerySpec{
+		Where: []ConditionSpec{{Field: "age", Operator: ">=", Param: "min_age"}},
+	})
+	update := NewUpdateStatement("rename", "Rename a user", UpdateSpec{
+		Set:   map[string]string{"name": "name"},
+		Where: []ConditionSpec{{Field: "id", Operator: "=", Param: "id"}},
+	})
+	del := NewDeleteStatement("remove", "Remove a user", DeleteSpec{
+		Where: []ConditionSpec{{Field: "id", Operator: "=", Param: "id"}},
+	})
+	count := NewAggregateStatement("count-adults", "Count adults", AggCount, AggregateSpec{
+		Where: []ConditionSpec{{Field: "age", Operator: ">=", Param: "min_age"}},
+	})
+
+	calls := map[string]func() error{
+		"ExecQuery": func() error { _, err := exec.ExecQuery(ctx, query, nil); return err },
+		"ExecUpdate": func() error {
+			_, err := exec.ExecUpdate(ctx, update, map[string]any{"name": "x"})
+			return err
+		},
+		"ExecDelete":       func() error { _, err := exec.ExecDelete(ctx, del, nil); return err },
+		"ExecAggregate":    func() error { _, err := exec.ExecAggregate(ctx, count, nil); return err },
+		"ExecAggregateInt": func() error { _, err := exec.ExecAggregateInt(ctx, count, nil); return err },
+		"ExecPaginate":     func() error { _, err := exec.ExecPaginate(ctx, query, nil, 1, 10); return err },
+		"ExecDeleteBatch": func() error {
+			_, err := exec.ExecDeleteBatch(ctx, del, []map[string]any{{"id": 1}, {}})
+			return err
+		},
+	}
+	for name, call := range calls {
+		err := call()
+		if err == nil || errors.Is(err, errRecorded) {
+			t.Errorf("%s: expected missing param error, got %v", name, err)
+		}
+	}
+	if n := db.count(); n != 0 {
+		t.Errorf("expected no database calls, got %d", n)
+	}
+
+	exec.SetParamValidation(false)
+	if _, err := exec.ExecQuery(ctx, query, nil); err != nil && strings.Contains(err.Error(), "missing required param") {
+		t.Errorf("expected validation to be skipped when disabled, got %v", err)
+	}
+}
+
+func TestParamDefaults(t *testing.T) {
+	stmt := NewQueryStatement("adults", "Users at or above an age", QuerySpec{
+		Where:         []ConditionSpec{{Field: "age", Operator: ">=", Param: "min_age"}},
+		LimitParam:    "limit",
+		ParamDefaults: map[string]any{"min_age": 18, "limit": 50, "unused": true},
+	})
+
+	for _, p := range stmt.Params() {
+		if p.Name == "unused" {
+			t.Error("expected no param for a default the spec does not reference")
+		}
+	}
+
+	got := applyParamDefaults(stmt.Params(), map[string]any{"limit": nil})
+	if got["min_age"] != 18 {
+		t.Errorf("expected min_age default 18, got %v", got["min_age"])
+	}
+	if v, ok := got["limit"]; !ok || v != nil {
+		t.Errorf("expected explicit nil limit to override the default, got %v", v)
+	}
+	if _, ok := got["unused"]; ok {
+		t.Error("expected unreferenced default not to be applied")
+	}
+
+	caller := map[string]any{"limit": 10}
+	applyParamDefaults(stmt.Params(), caller)
+	if len(caller) != 1 {
+		t.Errorf("expected caller's params to be left unmodified, got %v", caller)
+	}
+}
+
+func TestParamSpecOverlay(t *testing.T) {
+	stmt := NewQueryStatement("adults", "Users at or above an age", QuerySpec{
+		Where: []ConditionSpec{
+			{Field: "age", Operator: ">=", Param: "min_age"},
+			{Field: "name", Operator: "=", Param: "name"},
+		},
+		LimitParam: "limit",
+		Params: []ParamSpec{
+			{Name: "min_age", Type: "integer", Description: "Minimum age in years"},
+			{Name: "unused", Description: "Not bound"},
+		},
+	})
+
+	params := make(map[string]ParamSpec)
+	for _, p := range stmt.Params() {
+		params[p.Name] = p
+	}
+	if len(params) != 3 {
+		t.Fatalf("expected 3 params, got %v", stmt.Params())
+	}
+	if p := params["min_age"]; p.Type != "integer" || p.Description != "Minimum age in years" || !p.Required {
+		t.Errorf("expected min_age to keep its derived Required and take the overlay, got %+v", p)
+	}
+	if p := params["name"]; p.Type != "any" || p.Description != "" || !p.Required {
+		t.Errorf("expected name to be auto-derived, got %+v", p)
+	}
+	if p := params["limit"]; p.Type != "integer" || p.Required {
+		t.Errorf("expected limit to be auto-derived, got %+v", p)
+	}
+	if _, ok := params["unused"]; ok {
+		t.Error("expected no param for a spec the statement does not bind")
+	}
+}
+
+func TestExecAppliesParamDefaults(t *testing.T) {
+	exec, err := New[User](&recordingDB{}, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	stmt := NewQueryStatement("adults", "Users at or above an age", QuerySpec{
+		Where:         []ConditionSpec{{Field: "age", Operator: ">=", Param: "min_age"}},
+		ParamDefaults: map[string]any{"min_age": 18},
+	})
+	if _, err := exec.ExecQuery(context.Background(), stmt, nil); !errors.Is(err, errRecorded) {
+		t.Errorf("expected query to run with the default, got %v", err)
+	}
+}
+
+func TestSetParamDefault(t *testing.T) {
+	db := &recordingDB{}
+	exec, err := New[User](db, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if err := exec.SetPageParamLimits(100, 0); err != nil {
+		t.Fatalf("SetPageParamLimits() failed: %v", err)
+	}
+	stmt := NewQueryStatement("recent", "Most recent users", QuerySpec{
+		OrderBy:       []OrderBySpec{{Field: "id", Direction: "desc"}},
+		LimitParam:    "limit",
+		ParamDefaults: map[string]any{"limit": 50},
+	})
+	limitDefault := func(params []ParamSpec) any {
+		for _, p := range params {
+			if p.Name == "limit" {
+				return p.Default
+			}
+		}
+		return nil
+	}
+
+	exec.SetParamDefault(stmt.Name(), "limit", 500)
+	exec.SetParamDefault("other", "limit", 1)
+
+	params, err := exec.StatementParams(stmt)
+	if err != nil {
+		t.Fatalf("StatementParams() failed: %v", err)
+	}
+	if got := limitDefault(params); got != 500 {
+		t.Errorf("expected overridden default 500, got %v", got)
+	}
+	if got := limitDefault(stmt.Params()); got != 50 {
+		t.Errorf("expected the statement's own default to stay 50, got %v", got)
+	}
+
+	// The overridden default is checked against the page limit like a supplied value.
+	_, err = exec.ExecQuery(context.Background(), stmt, nil)
+	if err == nil || !strings.Contains(err.Error(), "exceeds the maximum of 100, got 500") {
+		t.Errorf("expected the override to apply, got %v", err)
+	}
+	if _, err := exec.ExecQuery(context.Background(), stmt, map[string]any{"limit": 10}); !errors.Is(err, errRecorded) {
+		t.Errorf("expected a supplied value to win over the override, got %v", err)
+	}
+
+	exec.SetParamDefault(stmt.Name(), "limit", nil)
+	if _, err := exec.ExecQuery(context.Background(), stmt, nil); !errors.Is(err, errRecorded) {
+		t.Errorf("expected the spec default after removing the override, got %v", err)
+	}
+}
+
+func TestExecChecksPageParams(t *testing.T) {
+	db := &recordingDB{}
+	exec, err := New[User](db, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if err := exec.SetPageParamLimits(100, 1000); err != nil {
+		t.Fatalf("SetPageParamLimits() failed: %v", err)
+	}
+	ctx := context.Background()
+	page := NewQueryStatement("page", "Page of users", QuerySpec{
+		OrderBy:     []OrderBySpec{{Field: "id", Direction: "asc"}},
+		LimitParam:  "limit",
+		OffsetParam: "offset",
+	})
+
+	tests := map[string]struct {
+		params map[string]any
+		want   string
+	}{
+		"negative offset": {map[string]any{"limit": 10, "offset": -1}, `offset param "offset" must not be negative, got -1`},
+		"over-cap limit":  {map[string]any{"limit": int64(1_000_000), "offset": 0}, `limit param "limit" exceeds the maximum of 100, got 1000000`},
+		"over-cap offset": {map[string]any{"limit": 10, "offset": uint(5000)}, `offset param "offset" exceeds the maximum of 1000, got 5000`},
+	}
+	for name, tt := range tests {
+		_, err := exec.ExecQuery(ctx, page, tt.params)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected %q, got %v", name, tt.want, err)
+		}
+	}
+	if n := db.count(); n != 0 {
+		t.Errorf("expected no database calls, got %d", n)
+	}
+
+	if _, err := exec.ExecQuery(ctx, page, map[string]any{"limit": 100, "offset": 1000}); !errors.Is(err, errRecorded) {
+		t.Errorf("expected values at the maximum to reach the database, got %v", err)
+	}
+	if err := exec.SetPageParamLimits(0, 0); err != nil {
+		t.Fatalf("SetPageParamLimits() failed: %v", err)
+	}
+	if _, err := exec.ExecQuery(ctx, page, map[string]any{"limit": 1_000_000, "offset": 0}); !errors.Is(err, errRecorded) {
+		t.Errorf("expected no maximum after reset, got %v", err)
+	}
+	if _, err := exec.ExecQuery(ctx, page, map[string]any{"limit": -5, "offset": 0}); err == nil || errors.Is(err, errRecorded) {
+		t.Errorf("expected negative limit to be rejected without a maximum, got %v", err)
+	}
+	if err := exec.SetPageParamLimits(-1, 0); err == nil {
+		t.Error("SetPageParamLimits() should reject a negative maximum")
+	}
+}
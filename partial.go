@@ -0,0 +1,79 @@
+package edamame
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ExecUpdatePartial updates only changedFields of record, matching the row by primary key,
+// and returns the updated row. Values are read from record, so there is no need to define
+// an UpdateStatement per combination of fields.
+//
+// Example:
+//
+//	user.Name = "Alice"
+//	updated, err := exec.ExecUpdatePartial(ctx, user, []string{"name"})
+func (e *Executor[T]) ExecUpdatePartial(ctx context.Context, record *T, changedFields []string) (*T, error) {
+	ctx, cancel := e.withTimeout(ctx, "")
+	defer cancel()
+	if err := e.checkWrite(ctx, "ExecUpdatePartial"); err != nil {
+		return nil, err
+	}
+	stmt, params, err := e.partialUpdate(record, changedFields)
+	if err != nil {
+		return nil, err
+	}
+	return e.ExecUpdate(ctx, stmt, params)
+}
+
+// ExecUpdatePartialTx updates only changedFields of record within a transaction.
+func (e *Executor[T]) ExecUpdatePartialTx(ctx context.Context, tx *sqlx.Tx, record *T, changedFields []string) (*T, error) {
+	ctx, cancel := e.withTimeout(ctx, "")
+	defer cancel()
+	if err := e.checkWrite(ctx, "ExecUpdatePartial"); err != nil {
+		return nil, err
+	}
+	stmt, params, err := e.partialUpdate(record, changedFields)
+	if err != nil {
+		return nil, err
+	}
+	return e.ExecUpdateTx(ctx, tx, stmt, params)
+}
+
+// partialUpdate builds an update statement setting fields by primary key, with params bound from record.
+func (e *Executor[T]) partialUpdate(record *T, fields []string) (UpdateStatement, map[string]any, error) {
+	if record == nil {
+		return UpdateStatement{}, nil, fmt.Errorf("edamame: record is nil")
+	}
+	if e.pk == "" {
+		return UpdateStatement{}, nil, fmt.Errorf("edamame: no primary key column for partial update")
+	}
+	if len(fields) == 0 {
+		return UpdateStatement{}, nil, fmt.Errorf("edamame: at least one changed field is required")
+	}
+
+	v := reflect.ValueOf(record).Elem()
+	set := make(map[string]string, len(fields))
+	params := make(map[string]any, len(fields)+1)
+	for _, f := range fields {
+		index, ok := e.columns[f]
+		if !ok {
+			return UpdateStatement{}, nil, fmt.Errorf("edamame: unknown field %q", f)
+		}
+		if f == e.pk {
+			return UpdateStatement{}, nil, fmt.Errorf("edamame: cannot update primary key field %q", f)
+		}
+		set[f] = f
+		params[f] = v.FieldByIndex(index).Interface()
+	}
+	params[e.pk] = v.FieldByIndex(e.columns[e.pk]).Interface()
+
+	stmt := NewUpdateStatement("update-partial", "Update changed fields by primary key", UpdateSpec{
+		Set:   set,
+		Where: []ConditionSpec{{Field: e.pk, Operator: "=", Param: e.pk}},
+	})
+	return stmt, params, nil
+}
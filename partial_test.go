@@ -0,0 +1,59 @@
+package edamame
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestPartialUpdate(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	age := 30
+	user := &User{ID: 7, Email: "a@test.com", Name: "Alice", Age: &age}
+
+	stmt, params, err := factory.partialUpdate(user, []string{"name", "age"})
+	if err != nil {
+		t.Fatalf("partialUpdate() failed: %v", err)
+	}
+	sql, err := factory.RenderUpdate(stmt)
+	if err != nil {
+		t.Fatalf("RenderUpdate() failed: %v", err)
+	}
+	if strings.Contains(sql, `"email" =`) || !strings.Contains(sql, `"name" = :name`) || !strings.Contains(sql, `WHERE "id" = :id`) {
+		t.Errorf("unexpected SQL: %s", sql)
+	}
+	if params["id"] != 7 || params["name"] != "Alice" || params["age"] != &age || len(params) != 3 {
+		t.Errorf("unexpected params: %v", params)
+	}
+}
+
+func TestPartialUpdate_Validation(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	user := &User{ID: 1}
+
+	tests := []struct {
+		name   string
+		record *User
+		fields []string
+	}{
+		{"nil record", nil, []string{"name"}},
+		{"no fields", user, nil},
+		{"unknown field", user, []string{"nickname"}},
+		{"primary key", user, []string{"id"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := factory.partialUpdate(tt.record, tt.fields); err == nil {
+				t.Error("partialUpdate() should fail")
+			}
+		})
+	}
+}
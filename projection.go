@@ -0,0 +1,136 @@
+package edamame
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ExecQueryProjection executes a query statement and scans each row into a projection
+// struct R instead of T. R must have exactly one field per selected column, matched by
+// `db` tag (or lowercased field name), so a projection cannot silently leave fields
+// zero-valued. Use it with statements that select a subset of T's columns.
+//
+// Example:
+//
+//	type UserName struct {
+//	    ID   int    `db:"id"`
+//	    Name string `db:"name"`
+//	}
+//
+//	names, err := edamame.ExecQueryProjection[User, UserName](ctx, exec, UserNames, nil)
+func ExecQueryProjection[T, R any](ctx context.Context, e *Executor[T], stmt QueryStatement, params map[string]any) ([]R, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	return execQueryProjection[T, R](withRead(ctx), e, e.execer(), stmt, params)
+}
+
+// ExecQueryProjectionTx executes a query statement within a transaction and scans each row into R.
+func ExecQueryProjectionTx[T, R any](ctx context.Context, e *Executor[T], tx *sqlx.Tx, stmt QueryStatement, params map[string]any) ([]R, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	return execQueryProjection[T, R](ctx, e, e.execerFor(tx), stmt, params)
+}
+
+// execQueryProjection checks R against the statement's output columns, then renders
+// the statement and scans every row into R.
+func execQueryProjection[T, R any](ctx context.Context, e *Executor[T], execer sqlx.ExtContext, stmt QueryStatement, params map[string]any) ([]R, error) {
+	params, err := e.prepareParams(stmt, params)
+	if err != nil {
+		return nil, err
+	}
+	selected, err := e.OutputColumns(stmt)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := projectionColumns(reflect.TypeFor[R]())
+	if err != nil {
+		return nil, err
+	}
+	if err := checkProjection(selected, fields); err != nil {
+		return nil, err
+	}
+
+	ctx = withStatement(ctx, stmt.name, "query")
+	q, err := e.queryFromSpec(stmt.spec)
+	if err != nil {
+		return nil, err
+	}
+	result, err := q.Render()
+	if err != nil {
+		return nil, err
+	}
+	sql, binds, err := e.finalizeSQL(result.SQL, e.queryRewrites(stmt.spec))
+	if err != nil {
+		return nil, err
+	}
+	params = mergeParams(params, binds)
+	e.emitSQL(ctx, stmt.name, "query", sql, params)
+
+	records, err := execRenderedQuery[R](ctx, execer, sql, params)
+	if err != nil {
+		return nil, err
+	}
+	projected := make([]R, len(records))
+	for i, r := range records {
+		projected[i] = *r
+	}
+	return capResults(ctx, e, stmt, projected), nil
+}
+
+// checkProjection reports an error unless fields and selected name the same columns.
+func checkProjection(selected, fields []string) error {
+	want := make(map[string]bool, len(selected))
+	for _, col := range selected {
+		want[col] = true
+	}
+	have := make(map[string]bool, len(fields))
+	for _, col := range fields {
+		if !want[col] {
+			return fmt.Errorf("edamame: projection field %q is not selected by the statement", col)
+		}
+		have[col] = true
+	}
+	for _, col := range selected {
+		if !have[col] {
+			return fmt.Errorf("edamame: projection has no field for selected column %q", col)
+		}
+	}
+	return nil
+}
+
+// projectionColumns returns the column each exported field of struct type t scans from,
+// following sqlx: the `db` tag, or the lowercased field name when untagged. Untagged
+// embedded structs contribute their own fields.
+func projectionColumns(t reflect.Type) ([]string, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("edamame: projection type %s is not a struct", t)
+	}
+	var columns []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("db")
+		if tag == "-" {
+			continue
+		}
+		if f.Anonymous && tag == "" && f.Type.Kind() == reflect.Struct {
+			embedded, err := projectionColumns(f.Type)
+			if err != nil {
+				return nil, err
+			}
+			columns = append(columns, embedded...)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if tag == "" {
+			tag = strings.ToLower(f.Name)
+		}
+		columns = append(columns, tag)
+	}
+	return columns, nil
+}
@@ -0,0 +1,69 @@
+package edamame
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+// userName projects the id and name columns of User.
+type userName struct {
+	ID   int    `db:"id"`
+	Name string `db:"name"`
+}
+
+func TestExecQueryProjection(t *testing.T) {
+	db := &recordingDB{}
+	factory, err := New[User](db, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ctx := context.Background()
+
+	names := NewQueryStatement("names", "User names", QuerySpec{Fields: []string{"id", "name"}})
+	if _, err := ExecQueryProjection[User, userName](ctx, factory, names, nil); !errors.Is(err, errRecorded) {
+		t.Fatalf("expected projection to reach the database, got %v", err)
+	}
+	if len(db.queries) != 1 || !strings.HasPrefix(db.queries[0], `SELECT "id", "name" FROM "users"`) {
+		t.Errorf("unexpected queries: %v", db.queries)
+	}
+
+	wide := NewQueryStatement("ids", "User IDs", QuerySpec{Fields: []string{"id"}})
+	if _, err := ExecQueryProjection[User, userName](ctx, factory, wide, nil); err == nil || errors.Is(err, errRecorded) {
+		t.Errorf("expected error for projection field not selected, got %v", err)
+	}
+
+	all := NewQueryStatement("all", "All users", QuerySpec{})
+	if _, err := ExecQueryProjection[User, userName](ctx, factory, all, nil); err == nil || errors.Is(err, errRecorded) {
+		t.Errorf("expected error for selected column missing from projection, got %v", err)
+	}
+
+	if _, err := ExecQueryProjection[User, int](ctx, factory, names, nil); err == nil {
+		t.Error("expected error for non-struct projection")
+	}
+}
+
+func TestProjectionColumns(t *testing.T) {
+	type base struct {
+		ID int `db:"id"`
+	}
+	type projection struct {
+		base
+		Name    string
+		Email   string `db:"email"`
+		Ignored string `db:"-"`
+		hidden  string //nolint:unused // unexported fields are not scanned
+	}
+
+	columns, err := projectionColumns(reflect.TypeFor[projection]())
+	if err != nil {
+		t.Fatalf("projectionColumns() failed: %v", err)
+	}
+	if strings.Join(columns, ",") != "id,name,email" {
+		t.Errorf("unexpected columns: %v", columns)
+	}
+}
@@ -0,0 +1,216 @@
+package edamame
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/zoobzio/astql/pkg/sqlite"
+)
+
+// Array comparison quantifiers of ConditionSpec.Quantifier.
+const (
+	quantifierAny = "any"
+	quantifierAll = "all"
+)
+
+// errQuantifierUnsupported is returned by execution paths that run soy's SQL
+// unmodified and so cannot apply ANY and ALL comparisons.
+var errQuantifierUnsupported = errors.New("edamame: ANY and ALL comparisons are not supported by this method")
+
+// operator returns the operator a simple condition renders with. A quantified
+// condition's operator carries its quantifier, such as "> ANY", a JSONB path
+// condition's its JSONB operator and a full-text condition's is @@, all of which soy
+// rejects; query and select specs swap them for placeholders that are rewritten after
+// rendering, and checkSoyConditions rejects them in other statements.
+func (c ConditionSpec) operator() string {
+	if c.IsFullText() {
+		return "@@"
+	}
+	if c.IsJSONPath() {
+		return "JSON " + c.JSONOp
+	}
+	if c.IsQuantified() {
+		return c.Operator + " " + strings.ToUpper(c.Quantifier)
+	}
+	return c.Operator
+}
+
+// hasQuantified reports whether any condition, including nested groups, is quantified.
+func hasQuantified(conditions []ConditionSpec) bool {
+	for _, c := range conditions {
+		if c.IsQuantified() || (c.IsGroup() && hasQuantified(c.Group)) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkQuantifiers validates the quantified conditions of a query or select spec: they
+// may only appear in WHERE, including groups. Array comparisons need the postgres
+// renderer, and subquery comparisons any renderer but SQLite's, which has neither.
+func (e *Executor[T]) checkQuantifiers(where, having, outerWhere []ConditionSpec) error {
+	if hasQuantified(having) || hasQuantified(outerWhere) {
+		return fmt.Errorf("edamame: ANY and ALL comparisons are only supported in WHERE")
+	}
+	if _, ok := e.dialect().(*sqlite.Renderer); ok && hasQuantifiedSubquery(where) {
+		return fmt.Errorf("edamame: ANY and ALL subquery comparisons are not supported by the sqlite renderer")
+	}
+	if !hasQuantified(where) {
+		return nil
+	}
+	if !e.isPostgres() {
+		return fmt.Errorf("edamame: ANY and ALL comparisons require the postgres renderer")
+	}
+	return checkQuantifiedConditions(where)
+}
+
+// checkQuantifiedConditions validates every quantified condition in conditions.
+func checkQuantifiedConditions(conditions []ConditionSpec) error {
+	for _, c := range conditions {
+		if c.IsGroup() {
+			if err := checkQuantifiedConditions(c.Group); err != nil {
+				return err
+			}
+			continue
+		}
+		if !c.IsQuantified() {
+			continue
+		}
+		if err := checkQuantifier(c); err != nil {
+			return err
+		}
+		if c.Param == "" {
+			return fmt.Errorf("edamame: quantified condition on %q requires a param", c.Field)
+		}
+		if c.IsNull || c.IsBetween() || c.IsNotBetween() || c.IsIn() || c.IsNotIn() || c.IsFieldComparison() || c.IsMatch() {
+			return fmt.Errorf("edamame: quantified condition on %q cannot be combined with another condition form", c.Field)
+		}
+	}
+	return nil
+}
+
+// checkQuantifier validates the quantifier and comparison operator of a quantified condition.
+func checkQuantifier(c ConditionSpec) error {
+	switch strings.ToLower(c.Quantifier) {
+	case quantifierAny, quantifierAll:
+	default:
+		return fmt.Errorf("edamame: invalid quantifier %q: must be one of any, all", c.Quantifier)
+	}
+	switch c.Operator {
+	case "=", "!=", ">", ">=", "<", "<=":
+	default:
+		return fmt.Errorf("edamame: invalid quantified operator %q: must be one of =, !=, >, >=, <, <=", c.Operator)
+	}
+	return nil
+}
+
+// hasQuantifiedSubquery reports whether any condition, including nested groups, is a
+// quantified subquery comparison.
+func hasQuantifiedSubquery(conditions []ConditionSpec) bool {
+	for _, c := range conditions {
+		if c.IsQuantifiedSubquery() || (c.IsGroup() && hasQuantifiedSubquery(c.Group)) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkQuantifiedSubquery validates a quantified subquery comparison: Field compared by
+// Operator with the single field its subquery selects. Param and the EXISTS flags stay empty.
+func checkQuantifiedSubquery(c ConditionSpec) error {
+	if err := checkQuantifier(c); err != nil {
+		return err
+	}
+	if c.Field == "" {
+		return fmt.Errorf("edamame: quantified subquery comparison requires a field")
+	}
+	if len(c.Subquery.Fields) != 1 {
+		return fmt.Errorf("edamame: quantified subquery comparison on %q must select exactly one field, got %d", c.Field, len(c.Subquery.Fields))
+	}
+	if c.IsExists() || c.Param != "" || c.IsNull || c.IsBetween() || c.IsNotBetween() || c.IsIn() || c.IsNotIn() ||
+		c.IsFieldComparison() || c.IsMatch() || c.IsJSONPath() || c.IsFullText() {
+		return fmt.Errorf("edamame: quantified subquery comparison on %q cannot be combined with another condition form", c.Field)
+	}
+	return nil
+}
+
+// withQuantifierPlaceholders returns conditions with each quantifier cleared, so soy
+// renders a plain comparison for rewriteQuantified to restore.
+func withQuantifierPlaceholders(conditions []ConditionSpec) []ConditionSpec {
+	if !hasQuantified(conditions) {
+		return conditions
+	}
+	replaced := make([]ConditionSpec, len(conditions))
+	for i, c := range conditions {
+		switch {
+		case c.IsQuantified():
+			c.Quantifier = ""
+		case c.IsGroup():
+			c.Group = withQuantifierPlaceholders(c.Group)
+		}
+		replaced[i] = c
+	}
+	return replaced
+}
+
+// quantifiedTarget is one placeholder comparison to quantify in rendered SQL.
+type quantifiedTarget struct {
+	placeholder string // rendered text, e.g. " > :ids"
+	quantifier  string // ANY or ALL
+	count       int    // conditions expected to render the placeholder
+}
+
+// collectQuantified gathers the placeholders rendered for quantified conditions, keyed by text.
+func collectQuantified(conditions []ConditionSpec, targets map[string]*quantifiedTarget) error {
+	for _, c := range conditions {
+		if c.IsGroup() {
+			if err := collectQuantified(c.Group, targets); err != nil {
+				return err
+			}
+			continue
+		}
+		if !c.IsQuantified() {
+			continue
+		}
+		placeholder := " " + c.Operator + " :" + c.Param
+		quantifier := strings.ToUpper(c.Quantifier)
+		t, ok := targets[placeholder]
+		if !ok {
+			t = &quantifiedTarget{placeholder: placeholder, quantifier: quantifier}
+			targets[placeholder] = t
+		}
+		if t.quantifier != quantifier {
+			return fmt.Errorf("edamame: cannot place ANY and ALL on the same comparison %q", strings.TrimSpace(placeholder))
+		}
+		t.count++
+	}
+	return nil
+}
+
+// rewriteQuantified wraps the param of each quantified comparison, rendered by soy as a
+// plain comparison, in ANY(...) or ALL(...). soy has no quantified comparison, so the
+// rendered SQL is edited: each placeholder is located by its operator and param name.
+// A placeholder that also matches an unquantified comparison is ambiguous and rejected.
+func rewriteQuantified(sql string, conditions []ConditionSpec) (string, error) {
+	if !hasQuantified(conditions) {
+		return sql, nil
+	}
+
+	targets := make(map[string]*quantifiedTarget)
+	if err := collectQuantified(conditions, targets); err != nil {
+		return "", err
+	}
+
+	for _, t := range targets {
+		found := placeholderOffsets(sql, t.placeholder)
+		if len(found) != t.count {
+			return "", fmt.Errorf("edamame: cannot place %s for %q: rendered %d matching comparisons, expected %d",
+				t.quantifier, strings.TrimSpace(t.placeholder), len(found), t.count)
+		}
+		at := strings.Index(t.placeholder, " :")
+		replacement := t.placeholder[:at] + " " + t.quantifier + "(" + t.placeholder[at+1:] + ")"
+		sql = replaceOffsets(sql, found, len(t.placeholder), replacement)
+	}
+	return sql, nil
+}
@@ -0,0 +1,226 @@
+package edamame
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/mariadb"
+	"github.com/zoobzio/astql/pkg/postgres"
+	"github.com/zoobzio/astql/pkg/sqlite"
+)
+
+func TestQuantifier_Render(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	older := NewQueryStatement("older-than-any", "Users older than any given age", QuerySpec{
+		Where: []ConditionSpec{{Field: "age", Operator: ">", Quantifier: "any", Param: "ages"}},
+	})
+	sql, err := factory.RenderQuery(older)
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+	if !strings.Contains(sql, `WHERE "age" > ANY(:ages)`) {
+		t.Errorf("expected > ANY, got: %s", sql)
+	}
+
+	stmt := NewSelectStatement("mixed", "User matching grouped quantifiers", SelectSpec{
+		Where: []ConditionSpec{
+			{Field: "email", Operator: "=", Param: "email"},
+			{Logic: "OR", Group: []ConditionSpec{
+				{Field: "id", Operator: "=", Quantifier: "ANY", Param: "ids"},
+				{Field: "age", Operator: "<=", Quantifier: "all", Param: "limits"},
+			}},
+		},
+	})
+	sql, err = factory.RenderSelect(stmt)
+	if err != nil {
+		t.Fatalf("RenderSelect() failed: %v", err)
+	}
+	for _, want := range []string{`"email" = :email`, `"id" = ANY(:ids)`, `"age" <= ALL(:limits)`} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("expected %q in SQL, got: %s", want, sql)
+		}
+	}
+
+	params := older.Params()
+	if len(params) != 1 || params[0].Name != "ages" || params[0].Type != "array" {
+		t.Errorf("expected derived array param ages, got %+v", params)
+	}
+}
+
+func TestQuantifier_Ambiguous(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	// The plain comparison renders exactly like the placeholder.
+	stmt := NewQueryStatement("ambiguous", "Same param compared two ways", QuerySpec{
+		Where: []ConditionSpec{
+			{Field: "age", Operator: ">", Param: "age"},
+			{Field: "age", Operator: ">", Quantifier: "all", Param: "age"},
+		},
+	})
+	if _, err := factory.RenderQuery(stmt); err == nil {
+		t.Error("expected error for ambiguous placeholder")
+	}
+
+	stmt = NewQueryStatement("mixed", "Same comparison under both quantifiers", QuerySpec{
+		Where: []ConditionSpec{
+			{Field: "age", Operator: ">", Quantifier: "any", Param: "ages"},
+			{Field: "age", Operator: ">", Quantifier: "all", Param: "ages"},
+		},
+	})
+	if _, err := factory.RenderQuery(stmt); err == nil {
+		t.Error("expected error for ANY and ALL on the same comparison")
+	}
+}
+
+func TestQuantifier_Validation(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		spec QuerySpec
+	}{
+		{"unknown quantifier", QuerySpec{Where: []ConditionSpec{{Field: "age", Operator: ">", Quantifier: "some", Param: "ages"}}}},
+		{"pattern operator", QuerySpec{Where: []ConditionSpec{{Field: "name", Operator: "LIKE", Quantifier: "any", Param: "names"}}}},
+		{"missing param", QuerySpec{Where: []ConditionSpec{{Field: "age", Operator: ">", Quantifier: "any"}}}},
+		{"combined with IN", QuerySpec{Where: []ConditionSpec{{Field: "age", Operator: "=", In: true, Quantifier: "any", Param: "ages"}}}},
+		{"in HAVING", QuerySpec{GroupBy: []string{"age"}, Having: []ConditionSpec{{Field: "age", Operator: ">", Quantifier: "any", Param: "ages"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := factory.RenderQuery(NewQueryStatement("q", "q", tt.spec)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	maria, err := New[User](nil, "users", mariadb.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	stmt := NewQueryStatement("q", "q", QuerySpec{Where: []ConditionSpec{{Field: "age", Operator: ">", Quantifier: "any", Param: "ages"}}})
+	if _, err := maria.RenderQuery(stmt); err == nil {
+		t.Error("expected error for the mariadb renderer")
+	}
+}
+
+func TestQuantifier_Unsupported(t *testing.T) {
+	factory, err := New[User](&recordingDB{}, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	where := []ConditionSpec{{Field: "age", Operator: ">", Quantifier: "any", Param: "ages"}}
+
+	query := NewQueryStatement("q", "q", QuerySpec{Where: where})
+	if _, err := factory.ExecQueryAtom(context.Background(), query, nil); !errors.Is(err, errQuantifierUnsupported) {
+		t.Errorf("ExecQueryAtom: expected errQuantifierUnsupported, got %v", err)
+	}
+
+	// Statement types executed by soy reject the quantified operator before rendering.
+	const want = "ANY and ALL comparisons are only supported in query and select statements"
+	del := NewDeleteStatement("d", "d", DeleteSpec{Where: where})
+	if _, err := factory.RenderDelete(del); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("RenderDelete: expected %q, got %v", want, err)
+	}
+	upd := NewUpdateStatement("u", "u", UpdateSpec{Set: map[string]string{"name": "name"}, Where: where})
+	if _, err := factory.RenderUpdate(upd); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("RenderUpdate: expected %q, got %v", want, err)
+	}
+	agg := NewAggregateStatement("a", "a", AggCount, AggregateSpec{Where: where})
+	if _, err := factory.ExecAggregate(context.Background(), agg, map[string]any{"ages": []int{30}}); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("ExecAggregate: expected %q, got %v", want, err)
+	}
+}
+
+func TestQuantifier_RenderSubquery(t *testing.T) {
+	users := newExistsExecutors(t)
+	stmt := NewQueryStatement("quantified-subqueries", "Users compared with subquery rows", QuerySpec{
+		Where: []ConditionSpec{
+			{
+				Field: "id", Operator: "=", Quantifier: "any",
+				From:     "orders",
+				Subquery: &QuerySpec{Fields: []string{"UserID"}, Where: []ConditionSpec{{Field: "status", Operator: "=", Param: "status"}}},
+			},
+			{
+				Field: "Age", Operator: ">", Quantifier: "ALL",
+				Subquery:  &QuerySpec{Fields: []string{"age"}, Where: []ConditionSpec{{Field: "name", Operator: "=", Param: "name"}}},
+				Namespace: "peer",
+			},
+		},
+	})
+	sql, err := users.RenderQuery(stmt)
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+	want := `SELECT * FROM "users" WHERE ("id" = ANY (SELECT "user_id" FROM "orders" AS edamame_sub WHERE "status" = :sub_status)` +
+		` AND "age" > ALL (SELECT "age" FROM "users" AS edamame_sub WHERE "name" = :peer_name))`
+	if sql != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, sql)
+	}
+
+	params := stmt.Params()
+	if len(params) != 2 || params[0].Name != "sub_status" || params[1].Name != "peer_name" {
+		t.Errorf("expected the subquery params sub_status and peer_name, got %+v", params)
+	}
+
+	correlated := NewQueryStatement("correlated", "Correlated", QuerySpec{Where: []ConditionSpec{{
+		Field: "age", Operator: "<=", Quantifier: "any",
+		Subquery:  &QuerySpec{Fields: []string{"age"}},
+		Correlate: []CorrelationSpec{{Outer: "email", Inner: "email"}},
+	}}})
+	sql, err = users.RenderQuery(correlated)
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+	if want := `"age" <= ANY (SELECT "age" FROM "users" AS edamame_sub WHERE "email" = "users"."email")`; !strings.Contains(sql, want) {
+		t.Errorf("expected %s in SQL, got: %s", want, sql)
+	}
+}
+
+func TestQuantifier_SubqueryValidation(t *testing.T) {
+	users := newExistsExecutors(t)
+	sub := &QuerySpec{Fields: []string{"user_id"}}
+	tests := []struct {
+		name string
+		cond ConditionSpec
+		want string
+	}{
+		{"no field selected", ConditionSpec{Field: "id", Operator: "=", Quantifier: "any", From: "orders", Subquery: &QuerySpec{}}, "exactly one field"},
+		{"operator", ConditionSpec{Field: "id", Operator: "LIKE", Quantifier: "any", From: "orders", Subquery: sub}, "invalid quantified operator"},
+		{"param", ConditionSpec{Field: "id", Operator: "=", Quantifier: "all", Param: "ids", From: "orders", Subquery: sub}, "cannot be combined"},
+		{"exists", ConditionSpec{Exists: true, Field: "id", Operator: "=", Quantifier: "all", From: "orders", Subquery: sub}, "cannot be combined"},
+		{"unknown selected field", ConditionSpec{Field: "id", Operator: "=", Quantifier: "any", From: "orders", Subquery: &QuerySpec{Fields: []string{"total"}}}, `"total"`},
+		{"nested", ConditionSpec{Field: "id", Operator: "=", Quantifier: "any", From: "orders", Subquery: &QuerySpec{Fields: []string{"user_id"}, Where: []ConditionSpec{{Field: "id", Operator: "=", Quantifier: "any", Subquery: sub}}}}, "cannot be nested"},
+	}
+	for _, tt := range tests {
+		stmt := NewQueryStatement("q", "Query", QuerySpec{Where: []ConditionSpec{tt.cond}})
+		if _, err := users.RenderQuery(stmt); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+
+	where := []ConditionSpec{{Field: "id", Operator: "=", Quantifier: "any", From: "orders", Subquery: sub}}
+	if _, err := users.RenderDelete(NewDeleteStatement("d", "d", DeleteSpec{Where: where})); err == nil || !strings.Contains(err.Error(), "ANY and ALL subquery comparisons are only supported in query and select statements") {
+		t.Errorf("RenderDelete: expected a subquery comparison error, got %v", err)
+	}
+
+	lite, err := New[User](nil, "users", sqlite.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	stmt := NewQueryStatement("q", "Query", QuerySpec{Where: []ConditionSpec{{Field: "age", Operator: ">", Quantifier: "all", Subquery: &QuerySpec{Fields: []string{"age"}}}}})
+	if _, err := lite.RenderQuery(stmt); err == nil || !strings.Contains(err.Error(), "sqlite") {
+		t.Errorf("expected SQLite to reject a subquery comparison, got %v", err)
+	}
+}
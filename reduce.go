@@ -0,0 +1,54 @@
+package edamame
+
+import (
+	"context"
+	"iter"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ExecQueryReduce executes a query statement and folds its records into an accumulator
+// in Go, starting from init, for aggregations that are awkward to express in SQL.
+// Records are streamed through ExecQueryIter, so only the current one is held in memory.
+// On error the zero A is returned.
+//
+// Example:
+//
+//	total, err := edamame.ExecQueryReduce(ctx, exec, AllUsers, nil, 0, func(sum int, u *User) int {
+//	    return sum + u.Age
+//	})
+func ExecQueryReduce[T, A any](ctx context.Context, e *Executor[T], stmt QueryStatement, params map[string]any, init A, fn func(A, *T) A) (A, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	seq, err := e.ExecQueryIter(ctx, stmt, params)
+	if err != nil {
+		var zero A
+		return zero, err
+	}
+	return reduce(seq, init, fn)
+}
+
+// ExecQueryReduceTx folds a query statement's records into an accumulator within a
+// transaction, as ExecQueryReduce.
+func ExecQueryReduceTx[T, A any](ctx context.Context, e *Executor[T], tx *sqlx.Tx, stmt QueryStatement, params map[string]any, init A, fn func(A, *T) A) (A, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	seq, err := e.ExecQueryIterTx(ctx, tx, stmt, params)
+	if err != nil {
+		var zero A
+		return zero, err
+	}
+	return reduce(seq, init, fn)
+}
+
+// reduce folds seq into acc with fn, stopping at the first error.
+func reduce[T, A any](seq iter.Seq2[*T, error], acc A, fn func(A, *T) A) (A, error) {
+	for record, err := range seq {
+		if err != nil {
+			var zero A
+			return zero, err
+		}
+		acc = fn(acc, record)
+	}
+	return acc, nil
+}
@@ -0,0 +1,52 @@
+package edamame
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestReduce(t *testing.T) {
+	ages := []int{30, 12, 45}
+	seq := func(yield func(*User, error) bool) {
+		for i := range ages {
+			if !yield(&User{ID: i + 1, Age: &ages[i]}, nil) {
+				return
+			}
+		}
+	}
+	sum, err := reduce(seq, 0, func(acc int, u *User) int { return acc + *u.Age })
+	if err != nil || sum != 87 {
+		t.Errorf("expected 87, got %d, %v", sum, err)
+	}
+
+	failure := errors.New("boom")
+	failing := func(yield func(*User, error) bool) {
+		if yield(&User{ID: 1, Age: &ages[0]}, nil) {
+			yield(nil, failure)
+		}
+	}
+	sum, err = reduce(failing, 0, func(acc int, u *User) int { return acc + *u.Age })
+	if !errors.Is(err, failure) || sum != 0 {
+		t.Errorf("expected the iteration error and a zero result, got %d, %v", sum, err)
+	}
+}
+
+func TestExecQueryReduce_Error(t *testing.T) {
+	db := &recordingDB{}
+	exec, err := New[User](db, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	count := func(n int, _ *User) int { return n + 1 }
+
+	adults := NewQueryStatement("adults", "", QuerySpec{Where: []ConditionSpec{{Field: "age", Operator: ">=", Param: "min_age"}}})
+	if _, err := ExecQueryReduce(context.Background(), exec, adults, nil, 0, count); err == nil || db.count() != 0 {
+		t.Errorf("expected a missing param to fail before querying, got %v", err)
+	}
+	if _, err := ExecQueryReduce(context.Background(), exec, adults, map[string]any{"min_age": 18}, 0, count); !errors.Is(err, errRecorded) {
+		t.Errorf("expected the query error, got %v", err)
+	}
+}
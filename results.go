@@ -0,0 +1,91 @@
+package edamame
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/zoobzio/capitan"
+)
+
+// dedupResults removes rows whose dedup key fields match an earlier row.
+// Returns records unchanged when no dedup fields are configured.
+func (e *Executor[T]) dedupResults(records []*T) []*T {
+	e.mu.RLock()
+	fields := e.dedupFields
+	e.mu.RUnlock()
+
+	if len(fields) == 0 || len(records) < 2 {
+		return records
+	}
+
+	seen := make(map[string]struct{}, len(records))
+	result := make([]*T, 0, len(records))
+	for _, record := range records {
+		key := e.rowKey(record, fields)
+		if _, dup := seen[key]; dup {
+			continue
+		}
+		seen[key] = struct{}{}
+		result = append(result, record)
+	}
+	return result
+}
+
+// capResults truncates records to the statement's MaxResults, a safety net independent
+// of any SQL LIMIT, and emits ResultsCapped when rows are dropped.
+func capResults[T, R any](ctx context.Context, e *Executor[T], stmt QueryStatement, records []R) []R {
+	limit := stmt.spec.MaxResults
+	if limit <= 0 || len(records) <= limit {
+		return records
+	}
+	e.emitResultsCapped(ctx, stmt, len(records))
+	return records[:limit]
+}
+
+// emitResultsCapped publishes ResultsCapped for stmt, which fetched rows rows.
+func (e *Executor[T]) emitResultsCapped(ctx context.Context, stmt QueryStatement, rows int) {
+	fields := append([]capitan.Field{
+		KeyTable.Field(e.TableName()),
+		KeyStatement.Field(stmt.name),
+		KeyRows.Field(rows),
+		KeyLimit.Field(stmt.spec.MaxResults),
+	}, e.eventAttributes()...)
+	capitan.Warn(ctx, ResultsCapped, fields...)
+}
+
+// assertResults runs the registered result assertions over records.
+// Returns the first assertion error encountered.
+func (e *Executor[T]) assertResults(records ...*T) error {
+	e.mu.RLock()
+	assertions := e.assertions
+	e.mu.RUnlock()
+
+	for _, record := range records {
+		for _, assert := range assertions {
+			if err := assert(record); err != nil {
+				return fmt.Errorf("edamame: result assertion failed: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// rowKey builds a comparable key from the given columns of a record.
+func (e *Executor[T]) rowKey(record *T, fields []string) string {
+	v := reflect.ValueOf(record).Elem()
+	parts := make([]string, len(fields))
+	for i, f := range fields {
+		fv := v.FieldByIndex(e.columns[f])
+		if fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				parts[i] = "\x01nil"
+				continue
+			}
+			fv = fv.Elem()
+		}
+		parts[i] = fmt.Sprintf("%v", fv.Interface())
+	}
+	return strings.Join(parts, "\x00")
+}
@@ -0,0 +1,156 @@
+package edamame
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/astql/pkg/postgres"
+	"github.com/zoobzio/capitan"
+)
+
+func TestSetResultDedup_UnknownField(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	if err := factory.SetResultDedup("nmae"); err == nil {
+		t.Error("SetResultDedup() should fail for unknown field")
+	}
+}
+
+func TestDedupResults(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	age := 30
+	records := []*User{
+		{ID: 1, Email: "a@test.com", Name: "Alice", Age: &age},
+		{ID: 2, Email: "b@test.com", Name: "Alice", Age: &age},
+		{ID: 3, Email: "c@test.com", Name: "Alice", Age: nil},
+		{ID: 4, Email: "d@test.com", Name: "Bob", Age: &age},
+	}
+
+	// Disabled by default
+	if got := factory.dedupResults(records); len(got) != 4 {
+		t.Errorf("expected 4 records without dedup, got %d", len(got))
+	}
+
+	if err := factory.SetResultDedup("name"); err != nil {
+		t.Fatalf("SetResultDedup() failed: %v", err)
+	}
+	got := factory.dedupResults(records)
+	if len(got) != 2 || got[0].ID != 1 || got[1].ID != 4 {
+		t.Errorf("dedup by name: got %d records", len(got))
+	}
+
+	if err := factory.SetResultDedup("name", "age"); err != nil {
+		t.Fatalf("SetResultDedup() failed: %v", err)
+	}
+	if got := factory.dedupResults(records); len(got) != 3 {
+		t.Errorf("dedup by name+age: expected 3 records, got %d", len(got))
+	}
+
+	if err := factory.SetResultDedup(); err != nil {
+		t.Fatalf("SetResultDedup() failed: %v", err)
+	}
+	if got := factory.dedupResults(records); len(got) != 4 {
+		t.Errorf("expected dedup to be disabled, got %d records", len(got))
+	}
+}
+
+func TestAssertResults(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	valid := 30
+	invalid := -5
+	records := []*User{
+		{ID: 1, Name: "Alice", Age: &valid},
+		{ID: 2, Name: "Bob", Age: &invalid},
+	}
+
+	if err := factory.assertResults(records...); err != nil {
+		t.Fatalf("assertResults() with no assertions = %v", err)
+	}
+
+	errNegativeAge := errors.New("age must not be negative")
+	factory.AddResultAssertion(func(u *User) error {
+		if u.Age != nil && *u.Age < 0 {
+			return errNegativeAge
+		}
+		return nil
+	})
+
+	if err := factory.assertResults(records[0]); err != nil {
+		t.Errorf("assertResults() on valid row = %v", err)
+	}
+	if err := factory.assertResults(records...); !errors.Is(err, errNegativeAge) {
+		t.Errorf("assertResults() = %v, want %v", err, errNegativeAge)
+	}
+}
+
+func TestCapResults(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	stmt := NewQueryStatement("capped", "Capped query", QuerySpec{MaxResults: 2})
+
+	type capped struct{ rows, limit int }
+	events := make(chan capped, 1)
+	listener := capitan.Hook(ResultsCapped, func(_ context.Context, e *capitan.Event) {
+		if name, _ := KeyStatement.From(e); name != stmt.Name() {
+			return
+		}
+		rows, _ := KeyRows.From(e)
+		limit, _ := KeyLimit.From(e)
+		select {
+		case events <- capped{rows, limit}:
+		default:
+		}
+	})
+	defer listener.Close()
+
+	records := []*User{{ID: 1}, {ID: 2}}
+	if got := capResults(context.Background(), factory, stmt, records); len(got) != 2 {
+		t.Errorf("expected results at the cap to be kept, got %d", len(got))
+	}
+
+	records = append(records, &User{ID: 3})
+	got := capResults(context.Background(), factory, stmt, records)
+	if len(got) != 2 || got[0].ID != 1 || got[1].ID != 2 {
+		t.Errorf("expected the first 2 results, got %d", len(got))
+	}
+
+	select {
+	case e := <-events:
+		if e.rows != 3 || e.limit != 2 {
+			t.Errorf("expected rows=3 limit=2, got rows=%d limit=%d", e.rows, e.limit)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for ResultsCapped event")
+	}
+
+	unlimited := NewQueryStatement("unlimited", "Uncapped query", QuerySpec{})
+	if got := capResults(context.Background(), factory, unlimited, records); len(got) != 3 {
+		t.Errorf("expected MaxResults 0 to keep all results, got %d", len(got))
+	}
+}
+
+func TestMaxResults_Negative(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	stmt := NewQueryStatement("negative", "Negative cap", QuerySpec{MaxResults: -1})
+	if _, err := factory.RenderQuery(stmt); err == nil {
+		t.Error("expected error for negative MaxResults")
+	}
+}
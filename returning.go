@@ -0,0 +1,170 @@
+package edamame
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/astql"
+)
+
+// ExecDeleteReturning executes a delete statement and returns the deleted rows. Only the
+// spec's Returning columns are populated; with no Returning columns every column of T
+// is returned. Requires a dialect with RETURNING on DELETE.
+//
+// Example:
+//
+//	var PurgeExpired = edamame.NewDeleteStatement("purge-expired", "Delete expired sessions", edamame.DeleteSpec{
+//	    Where:     []edamame.ConditionSpec{{Field: "expires_at", Operator: "<", Param: "now"}},
+//	    Returning: []string{"id", "user_id"},
+//	})
+//
+//	purged, err := exec.ExecDeleteReturning(ctx, PurgeExpired, map[string]any{"now": time.Now()})
+func (e *Executor[T]) ExecDeleteReturning(ctx context.Context, stmt DeleteStatement, params map[string]any) ([]*T, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	if err := e.checkWrite(ctx, fmt.Sprintf("statement %q", stmt.name)); err != nil {
+		return nil, err
+	}
+	return e.execDeleteReturning(ctx, e.execer(), stmt, params)
+}
+
+// ExecDeleteReturningTx executes a delete statement within a transaction and returns the deleted rows.
+func (e *Executor[T]) ExecDeleteReturningTx(ctx context.Context, tx *sqlx.Tx, stmt DeleteStatement, params map[string]any) ([]*T, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	if err := e.checkWrite(ctx, fmt.Sprintf("statement %q", stmt.name)); err != nil {
+		return nil, err
+	}
+	return e.execDeleteReturning(ctx, e.execerFor(tx), stmt, params)
+}
+
+// execDeleteReturning renders the delete with its RETURNING clause and scans every deleted row.
+func (e *Executor[T]) execDeleteReturning(ctx context.Context, execer sqlx.ExtContext, stmt DeleteStatement, params map[string]any) ([]*T, error) {
+	params, err := e.prepareParams(stmt, params)
+	if err != nil {
+		return nil, err
+	}
+	sql, err := e.renderDeleteReturning(stmt.spec)
+	if err != nil {
+		return nil, err
+	}
+	ctx = withStatement(ctx, stmt.name, "delete")
+	e.emitSQL(ctx, stmt.name, "delete", sql, params)
+	records, err := execRenderedQuery[T](ctx, execer, sql, params)
+	if err != nil {
+		return nil, err
+	}
+	// The rows are returned with a NOTIFY error, as the delete succeeded.
+	return records, e.notifyWrite(ctx, execer, records...)
+}
+
+// renderDeleteReturning renders spec as soy would, followed by a RETURNING clause for
+// the spec's Returning columns, or every column of T when there are none.
+func (e *Executor[T]) renderDeleteReturning(spec DeleteSpec) (string, error) {
+	if !e.renderer.Capabilities().ReturningOnDelete {
+		return "", fmt.Errorf("edamame: RETURNING on DELETE is not supported by this dialect")
+	}
+	cols := spec.Returning
+	if len(cols) == 0 {
+		cols = e.schemaColumns()
+	}
+	clause, err := e.returningClause(cols)
+	if err != nil {
+		return "", err
+	}
+	result, err := e.removeFromSpec(spec).Render()
+	if err != nil {
+		return "", err
+	}
+	return result.SQL + clause, nil
+}
+
+// needsUpdateRewrite reports whether spec needs SQL edits soy's Update cannot make,
+// so ExecUpdate must run the statement through renderUpdate.
+func needsUpdateRewrite(spec UpdateSpec) bool {
+	return len(spec.Returning) > 0 || len(spec.SetExpr) > 0
+}
+
+// execRenderedUpdate runs an update rendered by renderUpdate and scans the single
+// updated row, matching the one-row contract of soy's Update.
+func (e *Executor[T]) execRenderedUpdate(ctx context.Context, execer sqlx.ExtContext, stmt UpdateStatement, params map[string]any) (*T, error) {
+	sql, err := e.renderUpdate(stmt.spec)
+	if err != nil {
+		return nil, err
+	}
+	e.emitSQL(ctx, stmt.name, "update", sql, params)
+	records, err := execRenderedQuery[T](ctx, execer, sql, params)
+	if err != nil {
+		return nil, err
+	}
+	switch len(records) {
+	case 0:
+		return nil, fmt.Errorf("edamame: no rows updated")
+	case 1:
+		return records[0], nil
+	default:
+		return nil, fmt.Errorf("edamame: expected exactly one row updated, found multiple")
+	}
+}
+
+// renderUpdate renders spec as soy would, then applies its SET expressions and replaces
+// soy's RETURNING of every column with the spec's Returning columns, if any. The update
+// runs with RETURNING, so the dialect must support it.
+func (e *Executor[T]) renderUpdate(spec UpdateSpec) (string, error) {
+	if !e.renderer.Capabilities().ReturningOnUpdate {
+		return "", fmt.Errorf("edamame: Returning and SetExpr require RETURNING on UPDATE, which this dialect does not support")
+	}
+	result, err := e.modifyFromSpec(spec).Render()
+	if err != nil {
+		return "", err
+	}
+	mapped := e.mapUpdateSpec(spec)
+	sql, err := e.rewriteSetExprs(result.SQL, mapped.Set, mapped.SetExpr)
+	if err != nil {
+		return "", err
+	}
+	if len(spec.Returning) == 0 {
+		return sql, nil
+	}
+
+	clause, err := e.returningClause(spec.Returning)
+	if err != nil {
+		return "", err
+	}
+	at := strings.LastIndex(sql, " RETURNING ")
+	if at < 0 {
+		return "", fmt.Errorf("edamame: rendered UPDATE has no RETURNING clause")
+	}
+	return sql[:at] + clause, nil
+}
+
+// returningClause renders " RETURNING ..." for cols, which may name fields or columns.
+// The clause is rendered by astql on a bare DELETE so columns are quoted for the dialect.
+func (e *Executor[T]) returningClause(cols []string) (string, error) {
+	cols = e.columnList(cols)
+	for _, col := range cols {
+		if _, ok := e.columns[col]; !ok {
+			return "", fmt.Errorf("edamame: unknown returning column %q", col)
+		}
+	}
+
+	t, err := e.soy.Instance().TryT(e.soy.TableName())
+	if err != nil {
+		return "", fmt.Errorf("edamame: invalid table %q: %w", e.soy.TableName(), err)
+	}
+	builder, err := e.returning(astql.Delete(t), cols)
+	if err != nil {
+		return "", err
+	}
+	result, err := builder.Render(e.renderer)
+	if err != nil {
+		return "", fmt.Errorf("edamame: failed to render RETURNING clause: %w", err)
+	}
+	at := strings.Index(result.SQL, " RETURNING ")
+	if at < 0 {
+		return "", fmt.Errorf("edamame: rendered SQL has no RETURNING clause")
+	}
+	return result.SQL[at:], nil
+}
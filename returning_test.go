@@ -0,0 +1,125 @@
+package edamame
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/mariadb"
+	"github.com/zoobzio/astql/pkg/mssql"
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+var byUserID = []ConditionSpec{{Field: "id", Operator: "=", Param: "user_id"}}
+
+func TestRenderUpdate_Returning(t *testing.T) {
+	exec, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	sql, err := exec.RenderUpdate(NewUpdateStatement("rename", "Rename user", UpdateSpec{
+		Set:       map[string]string{"name": "new_name"},
+		Where:     byUserID,
+		Returning: []string{"id", "name"},
+	}))
+	if err != nil {
+		t.Fatalf("RenderUpdate() failed: %v", err)
+	}
+	if !strings.HasSuffix(sql, `WHERE "id" = :user_id RETURNING "id", "name"`) {
+		t.Errorf("expected RETURNING of the listed columns, got: %s", sql)
+	}
+}
+
+func TestRenderUpdate_NoReturningUnchanged(t *testing.T) {
+	exec, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	spec := UpdateSpec{Set: map[string]string{"name": "new_name"}, Where: byUserID}
+
+	sql, err := exec.RenderUpdate(NewUpdateStatement("rename", "Rename user", spec))
+	if err != nil {
+		t.Fatalf("RenderUpdate() failed: %v", err)
+	}
+	result, err := exec.Update(NewUpdateStatement("rename", "Rename user", spec)).Render()
+	if err != nil {
+		t.Fatalf("Render() failed: %v", err)
+	}
+	if sql != result.SQL {
+		t.Errorf("expected soy's SQL %q, got %q", result.SQL, sql)
+	}
+}
+
+func TestReturning_Errors(t *testing.T) {
+	pg, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	maria, err := New[User](nil, "users", mariadb.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ms, err := New[User](nil, "users", mssql.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	update := UpdateSpec{Set: map[string]string{"name": "new_name"}, Where: byUserID, Returning: []string{"id"}}
+	if _, err := maria.renderUpdate(update); err == nil {
+		t.Error("expected error for RETURNING on UPDATE with mariadb")
+	}
+	if _, err := ms.renderDeleteReturning(DeleteSpec{Where: byUserID}); err == nil {
+		t.Error("expected error for RETURNING on DELETE with mssql")
+	}
+	update.Returning = []string{"nickname"}
+	if _, err := pg.renderUpdate(update); err == nil || !strings.Contains(err.Error(), `unknown returning column "nickname"`) {
+		t.Errorf("expected unknown column error, got %v", err)
+	}
+}
+
+func TestRenderDeleteReturning(t *testing.T) {
+	exec, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	sql, err := exec.renderDeleteReturning(DeleteSpec{Where: byUserID, Returning: []string{"id", "email"}})
+	if err != nil {
+		t.Fatalf("renderDeleteReturning() failed: %v", err)
+	}
+	if want := `DELETE FROM "users" WHERE "id" = :user_id RETURNING "id", "email"`; sql != want {
+		t.Errorf("expected %q, got %q", want, sql)
+	}
+
+	sql, err = exec.renderDeleteReturning(DeleteSpec{Where: byUserID})
+	if err != nil {
+		t.Fatalf("renderDeleteReturning() failed: %v", err)
+	}
+	if !strings.HasSuffix(sql, `RETURNING "id", "email", "name", "age"`) {
+		t.Errorf("expected RETURNING of every column, got: %s", sql)
+	}
+}
+
+func TestExecDeleteReturning_Query(t *testing.T) {
+	db := &recordingDB{}
+	exec, err := New[User](db, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	stmt := NewDeleteStatement("purge", "Purge user", DeleteSpec{Where: byUserID, Returning: []string{"id"}})
+
+	if _, err := exec.ExecDeleteReturning(context.Background(), stmt, map[string]any{"user_id": 1}); err == nil {
+		t.Fatal("expected the recorded error")
+	}
+	if db.count() != 1 {
+		t.Fatalf("expected 1 statement, got %d", db.count())
+	}
+	if want := `DELETE FROM "users" WHERE "id" = $1 RETURNING "id"`; db.queries[0] != want {
+		t.Errorf("expected %q, got %q", want, db.queries[0])
+	}
+
+	if _, err := exec.ExecDeleteReturning(context.Background(), stmt, nil); err == nil || !strings.Contains(err.Error(), "missing required param") {
+		t.Errorf("expected missing param error, got %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package edamame
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// readIntentKey marks a context as carrying a read-only statement.
+type readIntentKey struct{}
+
+// primaryOverrideKey marks a context as pinned to the primary database.
+type primaryOverrideKey struct{}
+
+// WithPrimary returns a context that routes reads to the primary database even when
+// a read database is configured. Use it for read-after-write consistency.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryOverrideKey{}, true)
+}
+
+// withRead marks ctx as carrying a read-only statement.
+func withRead(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readIntentKey{}, true)
+}
+
+// routedDB sends statements marked as reads to an optional read database and
+// everything else to the primary. Binding follows the primary's driver.
+// Every statement passes through annotate on its way to the database.
+type routedDB struct {
+	primary  sqlx.ExtContext
+	read     atomic.Pointer[sqlx.ExtContext]
+	annotate func(context.Context, string) string // adds SQL comments, nil for none
+}
+
+// route picks the handle for a statement executed with ctx.
+func (r *routedDB) route(ctx context.Context) sqlx.ExtContext {
+	if read, _ := ctx.Value(readIntentKey{}).(bool); !read {
+		return r.primary
+	}
+	if pinned, _ := ctx.Value(primaryOverrideKey{}).(bool); pinned {
+		return r.primary
+	}
+	if db := r.read.Load(); db != nil {
+		return *db
+	}
+	return r.primary
+}
+
+// sql applies annotate to query.
+func (r *routedDB) sql(ctx context.Context, query string) string {
+	if r.annotate == nil {
+		return query
+	}
+	return r.annotate(ctx, query)
+}
+
+func (r *routedDB) DriverName() string {
+	return r.primary.DriverName()
+}
+
+func (r *routedDB) Rebind(query string) string {
+	return r.primary.Rebind(query)
+}
+
+func (r *routedDB) BindNamed(query string, arg any) (string, []any, error) {
+	return r.primary.BindNamed(query, arg)
+}
+
+func (r *routedDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return r.route(ctx).QueryContext(ctx, r.sql(ctx, query), args...)
+}
+
+func (r *routedDB) QueryxContext(ctx context.Context, query string, args ...any) (*sqlx.Rows, error) {
+	return r.route(ctx).QueryxContext(ctx, r.sql(ctx, query), args...)
+}
+
+func (r *routedDB) QueryRowxContext(ctx context.Context, query string, args ...any) *sqlx.Row {
+	return r.route(ctx).QueryRowxContext(ctx, r.sql(ctx, query), args...)
+}
+
+func (r *routedDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return r.route(ctx).ExecContext(ctx, r.sql(ctx, query), args...)
+}
+
+// SetReadDB routes ExecQuery, ExecSelect, ExecAggregate and ExecCompound (and their
+// Atom and typed variants) to db, typically a read replica. Inserts, updates, deletes
+// and all Tx methods keep using the primary. Wrap the context with WithPrimary to force
+// a read onto the primary. Passing nil removes the read database.
+func (e *Executor[T]) SetReadDB(db sqlx.ExtContext) error {
+	if e.router == nil {
+		return fmt.Errorf("edamame: executor has no primary database")
+	}
+	if db == nil {
+		e.router.read.Store(nil)
+		return nil
+	}
+	e.router.read.Store(&db)
+	return nil
+}
+
+// execer returns the handle statements execute against outside a transaction.
+func (e *Executor[T]) execer() sqlx.ExtContext {
+	if e.router != nil {
+		return e.router
+	}
+	return e.db
+}
@@ -0,0 +1,124 @@
+package edamame
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+// errRecorded is returned by recordingDB for every statement.
+var errRecorded = errors.New("recorded")
+
+// recordingDB is a sqlx.ExtContext that records statements without executing them.
+type recordingDB struct {
+	mu      sync.Mutex
+	queries []string
+}
+
+func (r *recordingDB) record(query string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queries = append(r.queries, query)
+}
+
+func (r *recordingDB) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.queries)
+}
+
+func (r *recordingDB) DriverName() string { return "postgres" }
+
+func (r *recordingDB) Rebind(query string) string { return sqlx.Rebind(sqlx.DOLLAR, query) }
+
+func (r *recordingDB) BindNamed(query string, arg any) (string, []any, error) {
+	return sqlx.BindNamed(sqlx.DOLLAR, query, arg)
+}
+
+func (r *recordingDB) QueryContext(_ context.Context, query string, _ ...any) (*sql.Rows, error) {
+	r.record(query)
+	return nil, errRecorded
+}
+
+func (r *recordingDB) QueryxContext(_ context.Context, query string, _ ...any) (*sqlx.Rows, error) {
+	r.record(query)
+	return nil, errRecorded
+}
+
+func (r *recordingDB) QueryRowxContext(_ context.Context, query string, _ ...any) *sqlx.Row {
+	r.record(query)
+	return &sqlx.Row{}
+}
+
+func (r *recordingDB) ExecContext(_ context.Context, query string, _ ...any) (sql.Result, error) {
+	r.record(query)
+	return nil, errRecorded
+}
+
+func TestSetReadDB_Routing(t *testing.T) {
+	primary := &recordingDB{}
+	replica := &recordingDB{}
+
+	factory, err := New[User](primary, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if err := factory.SetReadDB(replica); err != nil {
+		t.Fatalf("SetReadDB() failed: %v", err)
+	}
+
+	ctx := context.Background()
+	byID := map[string]any{"id": 1}
+
+	// Reads go to the replica.
+	_, _ = factory.ExecQuery(ctx, queryAll, nil)
+	_, _ = factory.ExecSelect(ctx, selectByID, byID)
+	_, _ = factory.ExecAggregate(ctx, countAll, nil)
+	if replica.count() != 3 {
+		t.Errorf("expected 3 reads on replica, got %d", replica.count())
+	}
+	if primary.count() != 0 {
+		t.Errorf("expected no reads on primary, got %d", primary.count())
+	}
+
+	// Writes go to the primary.
+	_, _ = factory.ExecUpdate(ctx, updateName, map[string]any{"id": 1, "new_name": "x"})
+	_, _ = factory.ExecDelete(ctx, deleteByID, byID)
+	_, _ = factory.ExecInsert(ctx, &User{Email: "a@test.com", Name: "A"})
+	if primary.count() != 3 {
+		t.Errorf("expected 3 writes on primary, got %d", primary.count())
+	}
+	if replica.count() != 3 {
+		t.Errorf("expected no writes on replica, got %d", replica.count()-3)
+	}
+
+	// WithPrimary pins reads to the primary.
+	_, _ = factory.ExecSelect(WithPrimary(ctx), selectByID, byID)
+	if primary.count() != 4 {
+		t.Errorf("expected pinned read on primary, got %d primary statements", primary.count())
+	}
+
+	// Clearing the read database sends reads back to the primary.
+	if err := factory.SetReadDB(nil); err != nil {
+		t.Fatalf("SetReadDB(nil) failed: %v", err)
+	}
+	_, _ = factory.ExecQuery(ctx, queryAll, nil)
+	if primary.count() != 5 || replica.count() != 3 {
+		t.Errorf("expected read on primary after clearing, got primary=%d replica=%d", primary.count(), replica.count())
+	}
+}
+
+func TestSetReadDB_NoPrimary(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if err := factory.SetReadDB(&recordingDB{}); err == nil {
+		t.Error("SetReadDB() should fail without a primary database")
+	}
+}
@@ -0,0 +1,88 @@
+package edamame
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/zoobzio/astql/pkg/sqlite"
+)
+
+// Row-wise select expression functions, distinct from the aggregate "min" and "max".
+const (
+	selectExprRowMin = "row_min"
+	selectExprRowMax = "row_max"
+)
+
+// errRowExprUnsupported is returned by execution paths that run soy's SQL unmodified
+// and so cannot apply row_min and row_max select expressions.
+var errRowExprUnsupported = errors.New("edamame: row_min and row_max select expressions are not supported by this method")
+
+// isRowExpr reports whether expr is a row_min or row_max expression.
+func isRowExpr(expr SelectExprSpec) bool {
+	fn := strings.ToLower(expr.Func)
+	return fn == selectExprRowMin || fn == selectExprRowMax
+}
+
+// hasRowExprs reports whether any select expression is a row_min or row_max expression.
+func hasRowExprs(exprs []SelectExprSpec) bool {
+	for _, expr := range exprs {
+		if isRowExpr(expr) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRowExprs validates the row_min and row_max expressions of a query or select
+// spec: each compares at least two fields.
+func checkRowExprs(exprs []SelectExprSpec) error {
+	for _, expr := range exprs {
+		if isRowExpr(expr) && len(expr.Fields) < 2 {
+			return fmt.Errorf("edamame: %s expression %q requires at least two fields", strings.ToLower(expr.Func), expr.Alias)
+		}
+	}
+	return nil
+}
+
+// rowExprFunc returns the function the executor's dialect computes a row-wise
+// expression with: GREATEST and LEAST, or SQLite's multi-argument MAX and MIN.
+func (e *Executor[T]) rowExprFunc(expr SelectExprSpec) string {
+	greatest := strings.EqualFold(expr.Func, selectExprRowMax)
+	if _, ok := e.dialect().(*sqlite.Renderer); ok {
+		if greatest {
+			return "MAX"
+		}
+		return "MIN"
+	}
+	if greatest {
+		return "GREATEST"
+	}
+	return "LEAST"
+}
+
+// rewriteRowExprs replaces the concatenation soy rendered for each row_min and row_max
+// expression with the dialect's row-wise function over the same fields. soy has no
+// GREATEST or LEAST, so the expressions are applied to the builders as concat.
+func (e *Executor[T]) rewriteRowExprs(sql string, exprs []SelectExprSpec) (string, error) {
+	_, isSQLite := e.dialect().(*sqlite.Renderer)
+	for _, expr := range exprs {
+		if !isRowExpr(expr) {
+			continue
+		}
+		quoted := make([]string, len(expr.Fields))
+		for i, field := range expr.Fields {
+			quoted[i] = e.quoteIdent(e.column(field))
+		}
+		alias := " AS " + e.quoteIdent(expr.Alias)
+		placeholder := "CONCAT(" + strings.Join(quoted, ", ") + ")" + alias
+		if isSQLite {
+			placeholder = "(" + strings.Join(quoted, " || ") + ")" + alias
+		}
+		if strings.Count(sql, placeholder) != 1 {
+			return "", fmt.Errorf("edamame: rendered SQL has no single %s expression %q to rewrite", strings.ToLower(expr.Func), expr.Alias)
+		}
+		sql = strings.Replace(sql, placeholder, e.rowExprFunc(expr)+"("+strings.Join(quoted, ", ")+")"+alias, 1)
+	}
+	return sql, nil
+}
@@ -0,0 +1,96 @@
+package edamame
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql"
+	"github.com/zoobzio/astql/pkg/mariadb"
+	"github.com/zoobzio/astql/pkg/mssql"
+	"github.com/zoobzio/astql/pkg/postgres"
+	"github.com/zoobzio/astql/pkg/sqlite"
+)
+
+func TestRowExprs_RenderDistinctFromAggregates(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	stmt := NewQueryStatement("age-bounds", "Row-wise and aggregate minimums", QuerySpec{
+		Fields: []string{"id"},
+		SelectExprs: []SelectExprSpec{
+			{Func: "row_min", Fields: []string{"age", "id"}, Alias: "row_low"},
+			{Func: "row_max", Fields: []string{"age", "id"}, Alias: "row_high"},
+			{Func: "min", Field: "age", Alias: "min_age"},
+		},
+		GroupBy: []string{"id", "age"},
+	})
+	sql, err := factory.RenderQuery(stmt)
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+	want := `SELECT "id", LEAST("age", "id") AS "row_low", GREATEST("age", "id") AS "row_high", MIN("age") AS "min_age" FROM "users" GROUP BY "id", "age"`
+	if sql != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, sql)
+	}
+
+	sel := NewSelectStatement("user-high", "Row-wise maximum", SelectSpec{
+		Where:       []ConditionSpec{{Field: "id", Operator: "=", Param: "id"}},
+		SelectExprs: []SelectExprSpec{{Func: "row_max", Fields: []string{"ID", "Age"}, Alias: "high"}},
+	})
+	sql, err = factory.RenderSelect(sel)
+	if err != nil {
+		t.Fatalf("RenderSelect() failed: %v", err)
+	}
+	if !strings.Contains(sql, `GREATEST("id", "age") AS "high"`) {
+		t.Errorf("expected GREATEST over the mapped columns, got: %s", sql)
+	}
+}
+
+func TestRowExprs_Dialects(t *testing.T) {
+	tests := []struct {
+		name     string
+		renderer astql.Renderer
+		want     string
+	}{
+		{"mariadb", mariadb.New(), "LEAST(`age`, `id`) AS `low`"},
+		{"mssql", mssql.New(), `LEAST([age], [id]) AS [low]`},
+		{"sqlite", sqlite.New(), `MIN("age", "id") AS "low"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory, err := New[User](nil, "users", tt.renderer)
+			if err != nil {
+				t.Fatalf("New() failed: %v", err)
+			}
+			stmt := NewQueryStatement("q", "q", QuerySpec{SelectExprs: []SelectExprSpec{{Func: "row_min", Fields: []string{"age", "id"}, Alias: "low"}}})
+			sql, err := factory.RenderQuery(stmt)
+			if err != nil {
+				t.Fatalf("RenderQuery() failed: %v", err)
+			}
+			if !strings.Contains(sql, tt.want) {
+				t.Errorf("expected %q in SQL, got: %s", tt.want, sql)
+			}
+		})
+	}
+}
+
+func TestRowExprs_Validation(t *testing.T) {
+	factory, err := New[User](&recordingDB{}, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	single := NewQueryStatement("q", "q", QuerySpec{SelectExprs: []SelectExprSpec{{Func: "row_max", Fields: []string{"age"}, Alias: "high"}}})
+	if _, err := factory.RenderQuery(single); err == nil || !strings.Contains(err.Error(), "at least two fields") {
+		t.Errorf("expected an error for a single field, got %v", err)
+	}
+
+	stmt := NewQueryStatement("q", "q", QuerySpec{SelectExprs: []SelectExprSpec{{Func: "row_max", Fields: []string{"age", "id"}, Alias: "high"}}})
+	if _, err := factory.ExecQueryAtom(context.Background(), stmt, nil); !errors.Is(err, errRowExprUnsupported) {
+		t.Errorf("ExecQueryAtom: expected errRowExprUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package edamame
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// errSetExprUnsupported is returned by execution paths that run soy's SQL unmodified
+// and so cannot apply expression SET clauses.
+var errSetExprUnsupported = errors.New("edamame: SetExpr is not supported by this method")
+
+// setExprOperators lists the arithmetic operators a SET expression may use.
+var setExprOperators = map[string]bool{"+": true, "-": true, "*": true, "/": true}
+
+// setExprOperand matches a SET expression operand: a field name or a :param.
+var setExprOperand = regexp.MustCompile(`^:?[A-Za-z_][A-Za-z0-9_]*$`)
+
+// setExpr is a parsed SET expression: a single operand, or two joined by an operator.
+// Each operand is a field name or a param name prefixed with ':'.
+type setExpr struct {
+	left, op, right string
+}
+
+// parseSetExpr parses expr, which is "operand" or "operand op operand" with tokens
+// separated by spaces, such as "views + :delta" or "subtotal * :tax_rate".
+func parseSetExpr(expr string) (setExpr, error) {
+	tokens := strings.Fields(expr)
+	var x setExpr
+	switch len(tokens) {
+	case 1:
+		x.left = tokens[0]
+	case 3:
+		x.left, x.op, x.right = tokens[0], tokens[1], tokens[2]
+		if !setExprOperators[x.op] {
+			return setExpr{}, fmt.Errorf("invalid SET expression %q: unsupported operator %q", expr, x.op)
+		}
+	default:
+		return setExpr{}, fmt.Errorf("invalid SET expression %q: expected \"operand\" or \"operand op operand\"", expr)
+	}
+	for _, operand := range x.operands() {
+		if !setExprOperand.MatchString(operand) {
+			return setExpr{}, fmt.Errorf("invalid SET expression %q: invalid operand %q", expr, operand)
+		}
+	}
+	return x, nil
+}
+
+// operands returns the expression's operands.
+func (x setExpr) operands() []string {
+	if x.op == "" {
+		return []string{x.left}
+	}
+	return []string{x.left, x.right}
+}
+
+// params returns the names of the params the expression references.
+func (x setExpr) params() []string {
+	var params []string
+	for _, operand := range x.operands() {
+		if name, ok := strings.CutPrefix(operand, ":"); ok {
+			params = append(params, name)
+		}
+	}
+	return params
+}
+
+// setExprParam names the placeholder param soy renders for the SET expression of column.
+func setExprParam(column string) string {
+	return "edamame_set_" + column
+}
+
+// setExprParams returns the params referenced by exprs, ordered by target field.
+// Invalid expressions contribute nothing; they are reported when the statement is rendered.
+func setExprParams(exprs map[string]string) []string {
+	var params []string
+	for _, field := range slices.Sorted(maps.Keys(exprs)) {
+		x, err := parseSetExpr(exprs[field])
+		if err != nil {
+			continue
+		}
+		params = append(params, x.params()...)
+	}
+	return params
+}
+
+// rewriteSetExprs replaces the placeholder param rendered for each SET expression with
+// the expression itself. soy's Set only assigns a param, so each target is rendered as
+// `col = :placeholder` and the placeholder is edited. Field operands are validated against
+// the schema and quoted like the target column. Fields in both Set and SetExpr are rejected.
+func (e *Executor[T]) rewriteSetExprs(sql string, set, exprs map[string]string) (string, error) {
+	for field := range exprs {
+		if _, ok := set[field]; ok {
+			return "", fmt.Errorf("edamame: field %q is in both Set and SetExpr", field)
+		}
+	}
+
+	end := strings.Index(sql, " WHERE ")
+	if end < 0 {
+		end = len(sql)
+	}
+	for field, expr := range exprs {
+		col := e.column(field)
+		x, err := parseSetExpr(expr)
+		if err != nil {
+			return "", fmt.Errorf("edamame: SET %q: %w", field, err)
+		}
+
+		placeholder := " = :" + setExprParam(col)
+		offsets := placeholderOffsets(sql[:end], placeholder)
+		if len(offsets) != 1 || offsets[0] < len(col)+2 {
+			return "", fmt.Errorf("edamame: rendered SET clause has %d placeholders for %q, expected 1", len(offsets), col)
+		}
+		at := offsets[0]
+		open, closing := sql[at-len(col)-2:at-len(col)-1], sql[at-1:at]
+
+		operands := x.operands()
+		for i, operand := range operands {
+			if strings.HasPrefix(operand, ":") {
+				continue
+			}
+			operandCol := e.column(operand)
+			if _, ok := e.columns[operandCol]; !ok {
+				return "", fmt.Errorf("edamame: SET %q: unknown field %q", field, operand)
+			}
+			operands[i] = open + operandCol + closing
+		}
+
+		replacement := " = " + strings.Join(operands, " "+x.op+" ")
+		sql = sql[:at] + replacement + sql[at+len(placeholder):]
+		end += len(replacement) - len(placeholder)
+	}
+	return sql, nil
+}
@@ -0,0 +1,140 @@
+package edamame
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+// Counter is a model with numeric columns for SET expression tests.
+type Counter struct {
+	ID       int `db:"id" type:"integer" constraints:"primarykey"`
+	Views    int `db:"views" type:"integer"`
+	ViewsMax int `db:"views_max" type:"integer"`
+}
+
+func newCounterExecutor(t *testing.T) *Executor[Counter] {
+	t.Helper()
+	exec, err := New[Counter](nil, "counters", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	return exec
+}
+
+var counterByID = []ConditionSpec{{Field: "id", Operator: "=", Param: "id"}}
+
+func TestParseSetExpr(t *testing.T) {
+	valid := []string{"views + :delta", "views", ":views", "views_max - views", "views * :factor", " views  /  :n "}
+	for _, expr := range valid {
+		if _, err := parseSetExpr(expr); err != nil {
+			t.Errorf("parseSetExpr(%q) failed: %v", expr, err)
+		}
+	}
+
+	invalid := []string{"", "views +", "views % :n", "views + :delta + 1", "views + 1", "views; DROP TABLE counters", "views + :"}
+	for _, expr := range invalid {
+		if _, err := parseSetExpr(expr); err == nil {
+			t.Errorf("parseSetExpr(%q) should fail", expr)
+		}
+	}
+}
+
+func TestRenderUpdate_SetExpr(t *testing.T) {
+	exec := newCounterExecutor(t)
+
+	sql, err := exec.RenderUpdate(NewUpdateStatement("bump", "Bump views", UpdateSpec{
+		SetExpr: map[string]string{"views": "views + :delta", "views_max": "views_max"},
+		Where:   counterByID,
+	}))
+	if err != nil {
+		t.Fatalf("RenderUpdate() failed: %v", err)
+	}
+	for _, want := range []string{`"views" = "views" + :delta`, `"views_max" = "views_max"`} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("expected %s, got: %s", want, sql)
+		}
+	}
+	if strings.Contains(sql, "edamame_set_") {
+		t.Errorf("placeholder left in SQL: %s", sql)
+	}
+}
+
+func TestRenderUpdate_SetExprWithSet(t *testing.T) {
+	exec := newCounterExecutor(t)
+
+	sql, err := exec.RenderUpdate(NewUpdateStatement("bump", "Bump views", UpdateSpec{
+		Set:       map[string]string{"views_max": "max"},
+		SetExpr:   map[string]string{"views": ":delta * views"},
+		Where:     counterByID,
+		Returning: []string{"views"},
+	}))
+	if err != nil {
+		t.Fatalf("RenderUpdate() failed: %v", err)
+	}
+	for _, want := range []string{`"views_max" = :max`, `"views" = :delta * "views"`, `RETURNING "views"`} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("expected %s, got: %s", want, sql)
+		}
+	}
+}
+
+func TestRenderUpdate_SetExprErrors(t *testing.T) {
+	exec := newCounterExecutor(t)
+
+	tests := map[string]UpdateSpec{
+		"unknown field": {SetExpr: map[string]string{"views": "clicks + :delta"}, Where: counterByID},
+		"invalid":       {SetExpr: map[string]string{"views": "views + 1"}, Where: counterByID},
+		"set and expr":  {Set: map[string]string{"views": "views"}, SetExpr: map[string]string{"views": "views + :delta"}, Where: counterByID},
+	}
+	for name, spec := range tests {
+		if _, err := exec.RenderUpdate(NewUpdateStatement("bump", "Bump views", spec)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestSetExpr_Params(t *testing.T) {
+	stmt := NewUpdateStatement("bump", "Bump views", UpdateSpec{
+		SetExpr: map[string]string{"views": "views + :delta", "views_max": ":delta"},
+		Where:   counterByID,
+	})
+
+	var names []string
+	for _, p := range stmt.Params() {
+		names = append(names, p.Name)
+		if !p.Required {
+			t.Errorf("param %q should be required", p.Name)
+		}
+	}
+	if got := strings.Join(names, ","); got != "delta,id" {
+		t.Errorf("expected params delta,id, got %s", got)
+	}
+}
+
+func TestSetExpr_Exec(t *testing.T) {
+	db := &recordingDB{}
+	exec, err := New[Counter](db, "counters", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	stmt := NewUpdateStatement("bump", "Bump views", UpdateSpec{
+		SetExpr: map[string]string{"views": "views + :delta"},
+		Where:   counterByID,
+	})
+	ctx := context.Background()
+
+	_, _ = exec.ExecUpdate(ctx, stmt, map[string]any{"delta": 1, "id": 7})
+	if db.count() != 1 {
+		t.Fatalf("expected 1 statement, got %d", db.count())
+	}
+	if !strings.HasPrefix(db.queries[0], `UPDATE "counters" SET "views" = "views" + $1 WHERE "id" = $2 RETURNING`) {
+		t.Errorf("unexpected SQL: %s", db.queries[0])
+	}
+
+	if _, err := exec.ExecUpdateBatch(ctx, stmt, []map[string]any{{"delta": 1, "id": 7}}); err != errSetExprUnsupported {
+		t.Errorf("expected errSetExprUnsupported from ExecUpdateBatch, got %v", err)
+	}
+}
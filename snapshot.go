@@ -0,0 +1,130 @@
+package edamame
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+// ExecutorSnapshot is a point-in-time copy of an executor's runtime configuration,
+// taken by Snapshot and applied by RestoreSnapshot.
+type ExecutorSnapshot[T any] struct {
+	dedupFields       []string
+	tieBreaker        bool
+	softDelete        string
+	deleteScope       map[string]bool
+	restore           *UpdateStatement
+	upsert            *lastWriteWins
+	mapper            func(string) string
+	eventAttrs        []capitan.Field
+	fragments         map[string][]ConditionSpec
+	subqueries        map[string]SubquerySource
+	assertions        []func(*T) error
+	notifier          *writeNotifier[T]
+	queryable         map[string]bool
+	maxLimitParam     int
+	maxOffsetParam    int
+	paramDefaults     map[string]map[string]any
+	defaultTimeout    time.Duration
+	timeouts          map[string]time.Duration
+	sqlComments       bool
+	noParamValidation bool
+	paramCoercion     bool
+	readOnly          bool
+	strictFields      bool
+}
+
+// Snapshot captures the executor's runtime configuration: result dedup, the ORDER BY
+// tie-breaker, soft delete and its scope, last-write-wins upsert, the column mapper, event attributes,
+// condition fragments, subquery sources, result assertions, the write notifier, the
+// queryable field allowlist, page param limits, param default overrides, timeouts, SQL comments, parameter
+// validation, parameter coercion, read-only mode and strict fields. The database
+// handles are not included.
+//
+// Take a snapshot before reapplying configuration, such as on a config reload, so a
+// reload that fails validation can be rolled back with RestoreSnapshot.
+//
+// Example:
+//
+//	snap := exec.Snapshot()
+//	if err := applyConfig(exec, cfg); err != nil {
+//	    exec.RestoreSnapshot(snap)
+//	}
+func (e *Executor[T]) Snapshot() ExecutorSnapshot[T] {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return ExecutorSnapshot[T]{
+		dedupFields:       slices.Clone(e.dedupFields),
+		tieBreaker:        e.tieBreaker,
+		softDelete:        e.softDelete,
+		deleteScope:       maps.Clone(e.deleteScope),
+		restore:           e.restore,
+		upsert:            e.upsert,
+		mapper:            e.mapper,
+		eventAttrs:        slices.Clone(e.eventAttrs),
+		fragments:         maps.Clone(e.fragments),
+		subqueries:        maps.Clone(e.subqueries),
+		assertions:        slices.Clone(e.assertions),
+		notifier:          e.notifier,
+		queryable:         maps.Clone(e.queryable),
+		maxLimitParam:     e.maxLimitParam,
+		maxOffsetParam:    e.maxOffsetParam,
+		paramDefaults:     cloneParamDefaults(e.paramDefaults),
+		defaultTimeout:    e.defaultTimeout,
+		timeouts:          maps.Clone(e.timeouts),
+		sqlComments:       e.sqlComments.Load(),
+		noParamValidation: e.noParamValidation.Load(),
+		paramCoercion:     e.paramCoercion.Load(),
+		readOnly:          e.readOnly.Load(),
+		strictFields:      e.strictFields.Load(),
+	}
+}
+
+// RestoreSnapshot replaces the executor's runtime configuration with s. A zero
+// ExecutorSnapshot resets every setting to its default.
+//
+// Settings guarded by the executor's lock are swapped together, so a concurrent
+// statement sees either the old or the new set. The SQL comment, param validation,
+// param coercion, read-only and strict fields flags are atomics read without the lock;
+// a statement running alongside the restore may see a mix of old and new flags.
+func (e *Executor[T]) RestoreSnapshot(s ExecutorSnapshot[T]) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.dedupFields = slices.Clone(s.dedupFields)
+	e.tieBreaker = s.tieBreaker
+	e.softDelete = s.softDelete
+	e.deleteScope = maps.Clone(s.deleteScope)
+	e.restore = s.restore
+	e.upsert = s.upsert
+	e.mapper = s.mapper
+	e.eventAttrs = slices.Clone(s.eventAttrs)
+	e.fragments = maps.Clone(s.fragments)
+	e.subqueries = maps.Clone(s.subqueries)
+	e.assertions = slices.Clone(s.assertions)
+	e.notifier = s.notifier
+	e.queryable = maps.Clone(s.queryable)
+	e.maxLimitParam = s.maxLimitParam
+	e.maxOffsetParam = s.maxOffsetParam
+	e.paramDefaults = cloneParamDefaults(s.paramDefaults)
+	e.defaultTimeout = s.defaultTimeout
+	e.timeouts = maps.Clone(s.timeouts)
+	e.sqlComments.Store(s.sqlComments)
+	e.noParamValidation.Store(s.noParamValidation)
+	e.paramCoercion.Store(s.paramCoercion)
+	e.readOnly.Store(s.readOnly)
+	e.strictFields.Store(s.strictFields)
+}
+
+// cloneParamDefaults copies the param default overrides, including each statement's map.
+func cloneParamDefaults(defaults map[string]map[string]any) map[string]map[string]any {
+	if defaults == nil {
+		return nil
+	}
+	cloned := make(map[string]map[string]any, len(defaults))
+	for name, params := range defaults {
+		cloned[name] = maps.Clone(params)
+	}
+	return cloned
+}
@@ -0,0 +1,81 @@
+package edamame
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	exec, err := New[Document](nil, "documents", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if err := exec.EnableSoftDelete("deleted_at"); err != nil {
+		t.Fatalf("EnableSoftDelete() failed: %v", err)
+	}
+	if err := exec.SetResultDedup("title"); err != nil {
+		t.Fatalf("SetResultDedup() failed: %v", err)
+	}
+	exec.AddResultAssertion(func(*Document) error { return nil })
+	exec.SetParamDefault("by-title", "limit", 10)
+
+	stmt := NewQueryStatement("by-title", "Documents by title", QuerySpec{
+		OrderBy: []OrderBySpec{{Field: "title", Direction: "asc"}},
+	})
+	original, err := exec.RenderQuery(stmt)
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+
+	snap := exec.Snapshot()
+
+	// A reload that changes everything, then fails.
+	if err := exec.EnableSoftDelete(""); err != nil {
+		t.Fatalf("EnableSoftDelete() failed: %v", err)
+	}
+	if err := exec.SetOrderByTieBreaker(true); err != nil {
+		t.Fatalf("SetOrderByTieBreaker() failed: %v", err)
+	}
+	if err := exec.SetResultDedup(); err != nil {
+		t.Fatalf("SetResultDedup() failed: %v", err)
+	}
+	exec.AddResultAssertion(func(*Document) error { return errors.New("rejected") })
+	exec.SetSQLComments(true)
+	exec.SetParamDefault("by-title", "limit", 1000)
+
+	mutated, err := exec.RenderQuery(stmt)
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+	if mutated == original {
+		t.Fatal("expected mutated configuration to change the rendered SQL")
+	}
+
+	exec.RestoreSnapshot(snap)
+
+	restored, err := exec.RenderQuery(stmt)
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+	if restored != original {
+		t.Errorf("expected restored SQL:\n%s\ngot:\n%s", original, restored)
+	}
+	if !strings.Contains(restored, `"deleted_at" IS NULL`) {
+		t.Errorf("expected soft-delete predicate after restore, got: %s", restored)
+	}
+	if got := exec.dedupFields; len(got) != 1 || got[0] != "title" {
+		t.Errorf("expected dedup on title after restore, got %v", got)
+	}
+	if err := exec.assertResults(&Document{}); err != nil {
+		t.Errorf("expected the failing assertion to be rolled back, got %v", err)
+	}
+	if got := exec.paramDefaults["by-title"]["limit"]; got != 10 {
+		t.Errorf("expected limit default 10 after restore, got %v", got)
+	}
+	if _, _, err := exec.restoreStatement(1); err != nil {
+		t.Errorf("expected restore statement after restore, got %v", err)
+	}
+}
@@ -0,0 +1,240 @@
+package edamame
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/zoobzio/soy"
+)
+
+// Operation types the soft-delete predicate can be injected into.
+const (
+	opQuery     = "query"
+	opSelect    = "select"
+	opAggregate = "aggregate"
+	opUpdate    = "update"
+	opDelete    = "delete"
+)
+
+// defaultSoftDeleteScope lists the operations that exclude soft-deleted rows unless
+// SetSoftDeleteScope changes them. Reads hide them; updates and deletes still reach
+// them so rows can be restored or purged by primary key.
+var defaultSoftDeleteScope = map[string]bool{
+	opQuery:     true,
+	opSelect:    true,
+	opAggregate: true,
+	opUpdate:    false,
+	opDelete:    false,
+}
+
+// EnableSoftDelete treats rows with a non-NULL column as deleted. Query, select and
+// aggregate statements (including compound, cursor and atom variants) gain a
+// "column IS NULL" predicate unless their spec sets WithTrashed; update and delete
+// statements are left untouched so they can still target soft-deleted rows.
+// SetSoftDeleteScope changes which statements gain the predicate.
+// ExecSoftDelete runs a delete statement as an update that sets the column. When the
+// model has a primary key it also registers the restore statement used by
+// ExecRestore. Passing an empty column disables it.
+func (e *Executor[T]) EnableSoftDelete(column string) error {
+	if column != "" {
+		if _, ok := e.columns[column]; !ok {
+			return fmt.Errorf("edamame: unknown soft delete column %q", column)
+		}
+	}
+
+	var restore *UpdateStatement
+	if column != "" && e.pk != "" {
+		stmt := NewUpdateStatement("restore", "Restore a soft-deleted record by primary key", UpdateSpec{
+			Set:   map[string]string{column: column},
+			Where: []ConditionSpec{{Field: e.pk, Operator: "=", Param: e.pk}},
+		})
+		restore = &stmt
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.softDelete = column
+	e.restore = restore
+	return nil
+}
+
+// SetSoftDeleteScope sets the operations whose statements exclude soft-deleted rows
+// once EnableSoftDelete is on: any of "query", "select", "aggregate", "update" and
+// "delete". The default scope is query, select and aggregate. Passing no operations
+// excludes soft-deleted rows from none. Update and delete statements gain the
+// predicate only alongside a WHERE condition. With "update" in scope, ExecRestore
+// cannot reach soft-deleted rows; with "delete", ExecDelete cannot purge them.
+//
+// Example:
+//
+//	err := exec.SetSoftDeleteScope("query", "select", "aggregate", "update")
+func (e *Executor[T]) SetSoftDeleteScope(ops ...string) error {
+	scope := make(map[string]bool, len(ops))
+	for _, op := range ops {
+		if _, ok := defaultSoftDeleteScope[op]; !ok {
+			return fmt.Errorf("edamame: unknown soft delete operation %q: must be one of query, select, aggregate, update, delete", op)
+		}
+		scope[op] = true
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.deleteScope = scope
+	return nil
+}
+
+// ExecSoftDelete runs a delete statement as an UPDATE that sets the soft-delete column
+// to CURRENT_TIMESTAMP on the live rows its WHERE matches, and returns the number of
+// rows marked. Rows already soft-deleted keep their timestamp. ExecDelete still
+// removes rows physically, so the same statement can purge them.
+// Requires EnableSoftDelete.
+//
+// Example:
+//
+//	var DeleteUser = edamame.NewDeleteStatement("delete-user", "Delete a user", edamame.DeleteSpec{
+//	    Where: []edamame.ConditionSpec{{Field: "id", Operator: "=", Param: "id"}},
+//	})
+//	n, err := exec.ExecSoftDelete(ctx, DeleteUser, map[string]any{"id": 42})
+func (e *Executor[T]) ExecSoftDelete(ctx context.Context, stmt DeleteStatement, params map[string]any) (int64, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	if err := e.checkWrite(ctx, fmt.Sprintf("statement %q", stmt.name)); err != nil {
+		return 0, err
+	}
+	ctx = withStatement(ctx, stmt.name, "update")
+	count, err := e.execSoftDelete(ctx, e.execer(), stmt, params)
+	return e.notifyAffected(ctx, e.execer(), count, err)
+}
+
+// ExecSoftDeleteTx soft-deletes the rows a delete statement matches within a transaction.
+func (e *Executor[T]) ExecSoftDeleteTx(ctx context.Context, tx *sqlx.Tx, stmt DeleteStatement, params map[string]any) (int64, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	if err := e.checkWrite(ctx, fmt.Sprintf("statement %q", stmt.name)); err != nil {
+		return 0, err
+	}
+	count, err := e.execSoftDelete(ctx, e.execerFor(tx), stmt, params)
+	return e.notifyAffected(ctx, e.execerFor(tx), count, err)
+}
+
+// execSoftDelete runs the soft-delete form of stmt and returns the rows affected.
+func (e *Executor[T]) execSoftDelete(ctx context.Context, execer sqlx.ExtContext, stmt DeleteStatement, params map[string]any) (int64, error) {
+	params, err := e.prepareParams(stmt, params)
+	if err != nil {
+		return 0, err
+	}
+	sql, err := e.renderSoftDelete(stmt.spec)
+	if err != nil {
+		return 0, err
+	}
+	e.emitSQL(ctx, stmt.name, "update", sql, params)
+
+	result, err := sqlx.NamedExecContext(ctx, execer, sql, params)
+	if err != nil {
+		return 0, fmt.Errorf("edamame: soft delete failed: %w", err)
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("edamame: failed to get rows affected: %w", err)
+	}
+	return count, nil
+}
+
+// renderSoftDelete renders spec as soy would render the delete, restricted to live
+// rows, and replaces its DELETE FROM clause with an UPDATE of the soft-delete column.
+func (e *Executor[T]) renderSoftDelete(spec DeleteSpec) (string, error) {
+	e.mu.RLock()
+	column := e.softDelete
+	e.mu.RUnlock()
+	if column == "" {
+		return "", fmt.Errorf("edamame: soft delete is not enabled")
+	}
+	// Mirror soy's guard against unconditional deletes.
+	if len(spec.Where) == 0 {
+		return "", fmt.Errorf("edamame: soft delete requires at least one WHERE condition")
+	}
+
+	d := e.removeFromSpec(spec)
+	if e.softDeleteColumn(opDelete) == "" {
+		d = d.WhereNull(column)
+	}
+	result, err := d.Render()
+	if err != nil {
+		return "", err
+	}
+	at := strings.Index(result.SQL, " WHERE ")
+	if at < 0 {
+		return "", fmt.Errorf("edamame: unexpected DELETE rendering: %s", result.SQL)
+	}
+	return fmt.Sprintf(`UPDATE %s SET %s = CURRENT_TIMESTAMP%s`, e.quotedTableName(), e.quoteIdent(column), result.SQL[at:]), nil
+}
+
+// ExecRestore clears the soft-delete column of the record with primary key id,
+// making it visible to reads again. Returns the restored record.
+// Requires EnableSoftDelete on a model with a primary key.
+func (e *Executor[T]) ExecRestore(ctx context.Context, id any) (*T, error) {
+	ctx, cancel := e.withTimeout(ctx, "")
+	defer cancel()
+	if err := e.checkWrite(ctx, "ExecRestore"); err != nil {
+		return nil, err
+	}
+	stmt, params, err := e.restoreStatement(id)
+	if err != nil {
+		return nil, err
+	}
+	return e.ExecUpdate(ctx, stmt, params)
+}
+
+// ExecRestoreTx clears the soft-delete column of the record with primary key id within a transaction.
+func (e *Executor[T]) ExecRestoreTx(ctx context.Context, tx *sqlx.Tx, id any) (*T, error) {
+	ctx, cancel := e.withTimeout(ctx, "")
+	defer cancel()
+	if err := e.checkWrite(ctx, "ExecRestore"); err != nil {
+		return nil, err
+	}
+	stmt, params, err := e.restoreStatement(id)
+	if err != nil {
+		return nil, err
+	}
+	return e.ExecUpdateTx(ctx, tx, stmt, params)
+}
+
+// restoreStatement returns the registered restore statement and its params for id.
+func (e *Executor[T]) restoreStatement(id any) (UpdateStatement, map[string]any, error) {
+	e.mu.RLock()
+	column, restore := e.softDelete, e.restore
+	e.mu.RUnlock()
+
+	if column == "" {
+		return UpdateStatement{}, nil, fmt.Errorf("edamame: soft delete is not enabled")
+	}
+	if restore == nil {
+		return UpdateStatement{}, nil, fmt.Errorf("edamame: no primary key column for restore")
+	}
+	return *restore, map[string]any{column: nil, e.pk: id}, nil
+}
+
+// softDeleteColumn returns the column to filter on for op, or empty if op is not filtered.
+func (e *Executor[T]) softDeleteColumn(op string) string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	scope := e.deleteScope
+	if scope == nil {
+		scope = defaultSoftDeleteScope
+	}
+	if !scope[op] {
+		return ""
+	}
+	return e.softDelete
+}
+
+// excludeSoftDeletedFromAggregate adds the soft-delete predicate to an aggregate
+// builder, unless spec sets WithTrashed.
+func (e *Executor[T]) excludeSoftDeletedFromAggregate(agg *soy.Aggregate[T], spec AggregateSpec) *soy.Aggregate[T] {
+	if col := e.softDeleteColumn(opAggregate); col != "" && !spec.WithTrashed {
+		return agg.WhereNull(col)
+	}
+	return agg
+}
@@ -0,0 +1,274 @@
+package edamame
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/astql"
+	"github.com/zoobzio/astql/pkg/mariadb"
+	"github.com/zoobzio/astql/pkg/mssql"
+	"github.com/zoobzio/astql/pkg/postgres"
+	"github.com/zoobzio/astql/pkg/sqlite"
+)
+
+// Document is a soft-deletable model for testing.
+type Document struct {
+	ID        int        `db:"id" type:"integer" constraints:"primarykey"`
+	Title     string     `db:"title" type:"text"`
+	DeletedAt *time.Time `db:"deleted_at" type:"timestamptz"`
+}
+
+func newSoftDeleteExecutor(t *testing.T) *Executor[Document] {
+	t.Helper()
+	exec, err := New[Document](nil, "documents", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if err := exec.EnableSoftDelete("deleted_at"); err != nil {
+		t.Fatalf("EnableSoftDelete() failed: %v", err)
+	}
+	return exec
+}
+
+func TestEnableSoftDelete_UnknownColumn(t *testing.T) {
+	exec, err := New[Document](nil, "documents", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if err := exec.EnableSoftDelete("removed_at"); err == nil {
+		t.Error("EnableSoftDelete() should reject an unknown column")
+	}
+}
+
+func TestEnableSoftDelete_Scope(t *testing.T) {
+	exec := newSoftDeleteExecutor(t)
+	byID := []ConditionSpec{{Field: "id", Operator: "=", Param: "id"}}
+	const predicate = `"deleted_at" IS NULL`
+
+	reads := []Statement{
+		NewQueryStatement("docs", "All documents", QuerySpec{}),
+		NewSelectStatement("doc", "Document by id", SelectSpec{Where: byID}),
+		NewAggregateStatement("doc-count", "Count documents", AggCount, AggregateSpec{}),
+		NewAggregateStatement("doc-max", "Max document id", AggMax, AggregateSpec{Field: "id"}),
+	}
+	for _, stmt := range reads {
+		sql, err := exec.RenderStatement(stmt)
+		if err != nil {
+			t.Fatalf("%s: render failed: %v", stmt.Name(), err)
+		}
+		if !strings.Contains(sql, predicate) {
+			t.Errorf("%s: expected soft-delete predicate, got: %s", stmt.Name(), sql)
+		}
+	}
+
+	writes := []Statement{
+		NewUpdateStatement("rename", "Rename document", UpdateSpec{Set: map[string]string{"title": "title"}, Where: byID}),
+		NewDeleteStatement("purge", "Purge document", DeleteSpec{Where: byID}),
+	}
+	for _, stmt := range writes {
+		sql, err := exec.RenderStatement(stmt)
+		if err != nil {
+			t.Fatalf("%s: render failed: %v", stmt.Name(), err)
+		}
+		if strings.Contains(sql, predicate) {
+			t.Errorf("%s: soft-delete predicate should not apply, got: %s", stmt.Name(), sql)
+		}
+	}
+}
+
+func TestSetSoftDeleteScope(t *testing.T) {
+	exec := newSoftDeleteExecutor(t)
+	other := newSoftDeleteExecutor(t)
+	byID := []ConditionSpec{{Field: "id", Operator: "=", Param: "id"}}
+	const predicate = `"deleted_at" IS NULL`
+
+	if err := exec.SetSoftDeleteScope("select", "update", "delete"); err != nil {
+		t.Fatalf("SetSoftDeleteScope() failed: %v", err)
+	}
+	tests := []struct {
+		stmt     Statement
+		filtered bool
+	}{
+		{NewQueryStatement("docs", "All documents", QuerySpec{}), false},
+		{NewSelectStatement("doc", "Document by id", SelectSpec{Where: byID}), true},
+		{NewAggregateStatement("doc-count", "Count documents", AggCount, AggregateSpec{}), false},
+		{NewAggregateStatement("doc-count-by-title", "Count documents per title", AggCount, AggregateSpec{GroupBy: []string{"title"}}), false},
+		{NewUpdateStatement("rename", "Rename document", UpdateSpec{Set: map[string]string{"title": "title"}, Where: byID}), true},
+		{NewDeleteStatement("purge", "Purge document", DeleteSpec{Where: byID}), true},
+	}
+	for _, tt := range tests {
+		sql, err := exec.RenderStatement(tt.stmt)
+		if err != nil {
+			t.Fatalf("%s: render failed: %v", tt.stmt.Name(), err)
+		}
+		if strings.Contains(sql, predicate) != tt.filtered {
+			t.Errorf("%s: expected soft-delete predicate %v, got: %s", tt.stmt.Name(), tt.filtered, sql)
+		}
+	}
+
+	// The scope is per executor.
+	sql, err := other.RenderQuery(NewQueryStatement("docs", "All documents", QuerySpec{}))
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+	if !strings.Contains(sql, predicate) {
+		t.Errorf("expected the default scope on another executor, got: %s", sql)
+	}
+
+	// A soft delete in scope filters live rows once.
+	sql, err = exec.renderSoftDelete(DeleteSpec{Where: byID})
+	if err != nil {
+		t.Fatalf("renderSoftDelete() failed: %v", err)
+	}
+	if strings.Count(sql, predicate) != 1 {
+		t.Errorf("expected one soft-delete predicate, got: %s", sql)
+	}
+
+	if err := exec.SetSoftDeleteScope("aggregate"); err != nil {
+		t.Fatalf("SetSoftDeleteScope() failed: %v", err)
+	}
+	sql, err = exec.RenderStatement(NewAggregateStatement("doc-count-by-title", "Count documents per title", AggCount, AggregateSpec{GroupBy: []string{"title"}}))
+	if err != nil {
+		t.Fatalf("RenderStatement() failed: %v", err)
+	}
+	if strings.Count(sql, predicate) != 1 {
+		t.Errorf("expected grouped aggregates to follow the aggregate scope, got: %s", sql)
+	}
+
+	if err := exec.SetSoftDeleteScope(); err != nil {
+		t.Fatalf("SetSoftDeleteScope() failed: %v", err)
+	}
+	sql, err = exec.RenderSelect(NewSelectStatement("doc", "Document by id", SelectSpec{Where: byID}))
+	if err != nil {
+		t.Fatalf("RenderSelect() failed: %v", err)
+	}
+	if strings.Contains(sql, predicate) {
+		t.Errorf("expected an empty scope to filter nothing, got: %s", sql)
+	}
+
+	if err := exec.SetSoftDeleteScope("query", "insert"); err == nil {
+		t.Error("SetSoftDeleteScope() should reject an unknown operation")
+	}
+}
+
+func TestEnableSoftDelete_Disable(t *testing.T) {
+	exec := newSoftDeleteExecutor(t)
+	if err := exec.EnableSoftDelete(""); err != nil {
+		t.Fatalf("EnableSoftDelete(\"\") failed: %v", err)
+	}
+
+	sql, err := exec.RenderQuery(NewQueryStatement("docs", "All documents", QuerySpec{}))
+	if err != nil {
+		t.Fatalf("RenderQuery() failed: %v", err)
+	}
+	if strings.Contains(sql, "deleted_at") {
+		t.Errorf("expected no soft-delete predicate after disabling, got: %s", sql)
+	}
+}
+
+func TestExecRestore_RequiresSoftDelete(t *testing.T) {
+	exec, err := New[Document](nil, "documents", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if _, err := exec.ExecRestore(context.Background(), 1); err == nil {
+		t.Error("ExecRestore() should fail when soft delete is not enabled")
+	}
+}
+
+func TestExecRestore_Render(t *testing.T) {
+	exec := newSoftDeleteExecutor(t)
+
+	stmt, params, err := exec.restoreStatement(7)
+	if err != nil {
+		t.Fatalf("restoreStatement() failed: %v", err)
+	}
+	sql, err := exec.RenderUpdate(stmt)
+	if err != nil {
+		t.Fatalf("RenderUpdate() failed: %v", err)
+	}
+	if !strings.Contains(sql, `SET "deleted_at" = :deleted_at`) || !strings.Contains(sql, `"id" = :id`) {
+		t.Errorf("unexpected restore SQL: %s", sql)
+	}
+	if params["deleted_at"] != nil || params["id"] != 7 {
+		t.Errorf("unexpected restore params: %v", params)
+	}
+}
+
+func TestExecSoftDelete_Render(t *testing.T) {
+	exec := newSoftDeleteExecutor(t)
+
+	sql, err := exec.renderSoftDelete(DeleteSpec{Where: []ConditionSpec{{Field: "id", Operator: "=", Param: "id"}}})
+	if err != nil {
+		t.Fatalf("renderSoftDelete() failed: %v", err)
+	}
+	want := `UPDATE "documents" SET "deleted_at" = CURRENT_TIMESTAMP WHERE ("id" = :id AND "deleted_at" IS NULL)`
+	if sql != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, sql)
+	}
+
+	if _, err := exec.renderSoftDelete(DeleteSpec{}); err == nil {
+		t.Error("renderSoftDelete() should require a WHERE condition")
+	}
+
+	plain, err := New[Document](nil, "documents", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	purge := NewDeleteStatement("purge", "Purge document", DeleteSpec{Where: []ConditionSpec{{Field: "id", Operator: "=", Param: "id"}}})
+	if _, err := plain.ExecSoftDelete(context.Background(), purge, map[string]any{"id": 1}); err == nil {
+		t.Error("ExecSoftDelete() should fail when soft delete is not enabled")
+	}
+}
+
+func TestExecSoftDelete_RenderDialects(t *testing.T) {
+	tests := []struct {
+		name     string
+		renderer astql.Renderer
+		want     string
+	}{
+		{"mariadb", mariadb.New(), "UPDATE `documents` SET `deleted_at` = CURRENT_TIMESTAMP WHERE (`id` = :id AND `deleted_at` IS NULL)"},
+		{"mssql", mssql.New(), `UPDATE [documents] SET [deleted_at] = CURRENT_TIMESTAMP WHERE ([id] = :id AND [deleted_at] IS NULL)`},
+		{"sqlite", sqlite.New(), `UPDATE "documents" SET "deleted_at" = CURRENT_TIMESTAMP WHERE ("id" = :id AND "deleted_at" IS NULL)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec, err := New[Document](nil, "documents", tt.renderer)
+			if err != nil {
+				t.Fatalf("New() failed: %v", err)
+			}
+			if err := exec.EnableSoftDelete("deleted_at"); err != nil {
+				t.Fatalf("EnableSoftDelete() failed: %v", err)
+			}
+			sql, err := exec.renderSoftDelete(DeleteSpec{Where: []ConditionSpec{{Field: "id", Operator: "=", Param: "id"}}})
+			if err != nil {
+				t.Fatalf("renderSoftDelete() failed: %v", err)
+			}
+			if sql != tt.want {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.want, sql)
+			}
+		})
+	}
+}
+
+func TestWithTrashed(t *testing.T) {
+	exec := newSoftDeleteExecutor(t)
+
+	reads := []Statement{
+		NewQueryStatement("docs", "All documents", QuerySpec{WithTrashed: true}),
+		NewSelectStatement("doc", "Document by id", SelectSpec{Where: []ConditionSpec{{Field: "id", Operator: "=", Param: "id"}}, WithTrashed: true}),
+		NewAggregateStatement("doc-count", "Count documents", AggCount, AggregateSpec{WithTrashed: true}),
+		NewAggregateStatement("doc-count-by-title", "Count documents per title", AggCount, AggregateSpec{GroupBy: []string{"title"}, WithTrashed: true}),
+	}
+	for _, stmt := range reads {
+		sql, err := exec.RenderStatement(stmt)
+		if err != nil {
+			t.Fatalf("%s: render failed: %v", stmt.Name(), err)
+		}
+		if strings.Contains(sql, "deleted_at") {
+			t.Errorf("%s: expected no soft-delete predicate, got: %s", stmt.Name(), sql)
+		}
+	}
+}
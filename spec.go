@@ -1,5 +1,7 @@
 package edamame
 
+import "strings"
+
 // -----------------------------------------------------------------------------
 // Query Building Specs
 // -----------------------------------------------------------------------------
@@ -25,10 +27,31 @@ package edamame
 //
 //	{"field": "age", "not_between": true, "low_param": "min_age", "high_param": "max_age"}
 //
+// Null-safe comparison (query and select statements; NULL equals NULL):
+//
+//	{"field": "manager_id", "operator": "IS DISTINCT FROM", "param": "manager_id"}
+//
 // Field-to-field comparison:
 //
 //	{"field": "created_at", "operator": "<", "right_field": "updated_at"}
 //
+// Pattern match (the param is bound as a plain string and wrapped in wildcards):
+//
+//	{"field": "name", "match": "contains", "param": "q"}
+//
+// EXISTS subquery (query and select statements; the param is bound as "sub_status"):
+//
+//	{
+//	  "exists": true,
+//	  "from": "orders",
+//	  "subquery": {"where": [{"field": "status", "operator": "=", "param": "status"}]},
+//	  "correlate": [{"outer": "id", "inner": "user_id"}]
+//	}
+//
+// Full-text search (query and select statements, PostgreSQL; the param is bound as text):
+//
+//	{"fulltext": {"fields": ["title", "body"], "config": "english", "param": "q"}}
+//
 // Condition group (AND/OR):
 //
 //	{
@@ -51,12 +74,79 @@ type ConditionSpec struct {
 	LowParam   string `json:"low_param,omitempty"`
 	HighParam  string `json:"high_param,omitempty"`
 
+	// IN / NOT IN condition fields; Param is bound to a slice
+	In    bool `json:"in,omitempty"`
+	NotIn bool `json:"not_in,omitempty"`
+
+	// Comparison quantifier (query and select WHERE only): "any" or "all" compares
+	// Field with each element of Param, bound to an array, as Field Operator ANY(:param)
+	// (PostgreSQL), or with each row of Subquery, which selects one field, as
+	// Field Operator ANY (SELECT ...) (not SQLite)
+	Quantifier string `json:"quantifier,omitempty"`
+
+	// JSONB path fields (query and select WHERE only, PostgreSQL). JSONOp is "->",
+	// "->>" or "#>>" to compare the value at JSONPath with Param using Operator, "@>"
+	// to test that Field (or the value at JSONPath) contains the JSONB document bound
+	// to Param, or "?" to test that the object at the path's parent has the path's last
+	// key. Path keys are rendered as quoted string literals, never as params.
+	JSONPath []string `json:"json_path,omitempty"`
+	JSONOp   string   `json:"json_op,omitempty"`
+
+	// Pattern match mode (WHERE only): "contains", "starts_with", "ends_with" or "ilike"
+	// (case-insensitive contains). Param is bound to a plain string that edamame escapes
+	// and wraps in % wildcards; Operator may be LIKE (the default), NOT LIKE, ILIKE or NOT ILIKE.
+	Match string `json:"match,omitempty"`
+
 	// Field-to-field comparison
 	RightField string `json:"right_field,omitempty"`
 
 	// Condition group fields (for AND/OR grouping)
 	Logic string          `json:"logic,omitempty"` // "AND" or "OR"
 	Group []ConditionSpec `json:"group,omitempty"` // Nested conditions
+
+	// Reference to a fragment registered with DefineConditionFragment (WHERE only)
+	Fragment string `json:"fragment,omitempty"`
+
+	// EXISTS / NOT EXISTS subquery fields (query and select WHERE only), also used by
+	// quantified subquery comparisons. Only the subquery's Where, and for a quantified
+	// comparison its single field, are used; its params are prefixed with Namespace and "_".
+	Exists    bool              `json:"exists,omitempty"`
+	NotExists bool              `json:"not_exists,omitempty"`
+	Subquery  *QuerySpec        `json:"subquery,omitempty"`
+	From      string            `json:"from,omitempty"`      // table registered with RegisterSubquerySource, empty for the executor's own table
+	Correlate []CorrelationSpec `json:"correlate,omitempty"` // outer = inner field pairs joining the subquery to the outer row
+	Namespace string            `json:"namespace,omitempty"` // param prefix, "sub" when empty
+
+	// Full-text search (query and select WHERE only, PostgreSQL); the condition's
+	// other fields stay empty
+	FullText *FullTextSpec `json:"fulltext,omitempty"`
+}
+
+// CorrelationSpec pairs a field of the outer query with a field of an EXISTS
+// subquery, rendered as an equality in the subquery's WHERE.
+type CorrelationSpec struct {
+	Outer string `json:"outer"`
+	Inner string `json:"inner"`
+}
+
+// FullTextSpec matches Fields against a plain-text search query bound to Param, as
+// to_tsvector(config, fields) @@ plainto_tsquery(config, :param). Multiple fields are
+// concatenated with spaces, NULLs treated as empty. Config names the text search
+// configuration, such as "english"; when empty the server's default_text_search_config applies.
+type FullTextSpec struct {
+	Fields []string `json:"fields"`
+	Config string   `json:"config,omitempty"`
+	Param  string   `json:"param"`
+}
+
+// IsFragment returns true if this ConditionSpec references a condition fragment.
+func (c ConditionSpec) IsFragment() bool {
+	return c.Fragment != ""
+}
+
+// IsMatch returns true if this ConditionSpec is a pattern match with a Match mode.
+func (c ConditionSpec) IsMatch() bool {
+	return c.Match != ""
 }
 
 // IsGroup returns true if this ConditionSpec represents a condition group.
@@ -74,11 +164,77 @@ func (c ConditionSpec) IsNotBetween() bool {
 	return c.NotBetween && c.LowParam != "" && c.HighParam != ""
 }
 
+// IsIn returns true if this ConditionSpec represents an IN condition.
+func (c ConditionSpec) IsIn() bool {
+	return c.In
+}
+
+// IsNotIn returns true if this ConditionSpec represents a NOT IN condition.
+func (c ConditionSpec) IsNotIn() bool {
+	return c.NotIn && !c.In
+}
+
+// IsJSONPath returns true if this ConditionSpec applies a JSONB operator to Field.
+func (c ConditionSpec) IsJSONPath() bool {
+	return c.JSONOp != ""
+}
+
+// IsFullText returns true if this ConditionSpec is a full-text search condition.
+func (c ConditionSpec) IsFullText() bool {
+	return c.FullText != nil
+}
+
+// IsQuantified returns true if this ConditionSpec compares against ANY or ALL of an array param.
+func (c ConditionSpec) IsQuantified() bool {
+	return c.Quantifier != "" && c.Subquery == nil
+}
+
+// IsQuantifiedSubquery returns true if this ConditionSpec compares against ANY or ALL of a subquery's rows.
+func (c ConditionSpec) IsQuantifiedSubquery() bool {
+	return c.Quantifier != "" && c.Subquery != nil
+}
+
+// IsList returns true if this ConditionSpec binds its Param to a list of values,
+// either through In/NotIn, the IN and NOT IN operators or a quantifier.
+func (c ConditionSpec) IsList() bool {
+	if c.IsIn() || c.IsNotIn() || c.IsQuantified() {
+		return true
+	}
+	op := strings.ToUpper(strings.TrimSpace(c.Operator))
+	return op == opIn || op == opNotIn
+}
+
+// listOperator returns the operator rendered for an In or NotIn condition.
+func (c ConditionSpec) listOperator() string {
+	if c.IsIn() {
+		return opIn
+	}
+	return opNotIn
+}
+
 // IsFieldComparison returns true if this ConditionSpec compares two fields.
 func (c ConditionSpec) IsFieldComparison() bool {
 	return c.RightField != "" && c.Operator != ""
 }
 
+// IsDistinctFrom returns true if this ConditionSpec is a null-safe comparison
+// (IS DISTINCT FROM or IS NOT DISTINCT FROM) against a param.
+func (c ConditionSpec) IsDistinctFrom() bool {
+	return c.Param != "" && c.RightField == "" &&
+		(strings.EqualFold(c.Operator, opIsDistinctFrom) || strings.EqualFold(c.Operator, opIsNotDistinctFrom))
+}
+
+// IsExists returns true if this ConditionSpec is an EXISTS or NOT EXISTS subquery condition.
+func (c ConditionSpec) IsExists() bool {
+	return c.Exists || c.NotExists
+}
+
+// IsSubquery returns true if this ConditionSpec selects from a subquery: an EXISTS
+// condition or a quantified subquery comparison.
+func (c ConditionSpec) IsSubquery() bool {
+	return c.IsExists() || c.IsQuantifiedSubquery()
+}
+
 // OrderBySpec represents an ORDER BY clause in a serializable format.
 //
 // Simple ordering:
@@ -92,12 +248,52 @@ func (c ConditionSpec) IsFieldComparison() bool {
 // Expression-based ordering (for vector distance with pgvector):
 //
 //	{"field": "embedding", "operator": "<->", "param": "query_vec", "direction": "asc"}
+//
+// Custom value ordering (rendered as CASE "status" WHEN ... THEN 0 ... END):
+//
+//	{"field": "status", "direction": "asc", "values": ["pending", "active", "closed"]}
+//
+// Weighted ordering (rendered as (:w_pop * "popularity" + :w_rec * "recency") DESC):
+//
+//	{"direction": "desc", "terms": [{"field": "popularity", "param": "w_pop"}, {"field": "recency", "param": "w_rec"}]}
 type OrderBySpec struct {
-	Field     string `json:"field"`
-	Direction string `json:"direction"`          // "asc" or "desc"
-	Nulls     string `json:"nulls,omitempty"`    // "first" or "last" for NULLS FIRST/LAST
-	Operator  string `json:"operator,omitempty"` // For vector ops: "<->", "<#>", "<=>", "<+>"
-	Param     string `json:"param,omitempty"`    // Parameter for expression-based ordering
+	Field     string          `json:"field"`
+	Direction string          `json:"direction"`          // "asc" or "desc"
+	Nulls     string          `json:"nulls,omitempty"`    // "first" or "last" for NULLS FIRST/LAST
+	Operator  string          `json:"operator,omitempty"` // For vector ops: "<->", "<#>", "<=>", "<+>"
+	Param     string          `json:"param,omitempty"`    // Parameter for expression-based ordering
+	Values    []string        `json:"values,omitempty"`   // Custom value order; unlisted values sort last
+	Terms     []OrderTermSpec `json:"terms,omitempty"`    // Weighted sum of fields; Field is unused
+}
+
+// OrderTermSpec is one term of a weighted ORDER BY: Param * Field.
+type OrderTermSpec struct {
+	Field string `json:"field"`
+	Param string `json:"param"` // Parameter bound to the term's coefficient
+}
+
+// OrderByWeighted returns an OrderBySpec that sorts by the sum of each term's field
+// multiplied by its param, e.g. a ranking score:
+//
+//	OrderByWeighted("desc", OrderTermSpec{"popularity", "w_pop"}, OrderTermSpec{"recency", "w_rec"})
+func OrderByWeighted(direction string, terms ...OrderTermSpec) OrderBySpec {
+	return OrderBySpec{Direction: direction, Terms: terms}
+}
+
+// OrderByCase returns an OrderBySpec that sorts field by the position of its value
+// in values, e.g. OrderByCase("status", "pending", "active", "closed").
+func OrderByCase(field string, values ...string) OrderBySpec {
+	return OrderBySpec{Field: field, Direction: "asc", Values: values}
+}
+
+// IsCase returns true if this OrderBySpec orders by a custom list of values.
+func (o OrderBySpec) IsCase() bool {
+	return len(o.Values) > 0
+}
+
+// IsWeighted returns true if this OrderBySpec orders by a weighted sum of fields.
+func (o OrderBySpec) IsWeighted() bool {
+	return len(o.Terms) > 0
 }
 
 // HasNulls returns true if this OrderBySpec specifies NULLS ordering.
@@ -162,7 +358,8 @@ type HavingAggSpec struct {
 //
 //	{"func": "cast", "field": "id", "cast_type": "text", "alias": "id_str"}
 //
-// Aggregate functions (inline in SELECT):
+// Aggregate functions (inline in SELECT). Note that "min" and "max" are the
+// aggregate MIN/MAX across rows; see row_min and row_max for the per-row form:
 //
 //	{"func": "count_star", "alias": "total"}
 //	{"func": "count", "field": "id", "alias": "id_count"}
@@ -172,6 +369,11 @@ type HavingAggSpec struct {
 //	{"func": "min", "field": "created_at", "alias": "first_created"}
 //	{"func": "max", "field": "updated_at", "alias": "last_updated"}
 //
+// Row-wise functions (LEAST and GREATEST of two or more fields within each row):
+//
+//	{"func": "row_min", "fields": ["start_a", "start_b"], "alias": "first_start"}
+//	{"func": "row_max", "fields": ["end_a", "end_b"], "alias": "last_end"}
+//
 // Aggregate with filter:
 //
 //	{"func": "sum", "field": "amount", "filter": {"field": "status", "operator": "=", "param": "paid"}, "alias": "paid_total"}
@@ -183,7 +385,7 @@ type HavingAggSpec struct {
 type SelectExprSpec struct {
 	Func     string         `json:"func"`                // Function name (see examples above)
 	Field    string         `json:"field,omitempty"`     // Primary field for single-field functions
-	Fields   []string       `json:"fields,omitempty"`    // Multiple fields (for concat)
+	Fields   []string       `json:"fields,omitempty"`    // Multiple fields (for concat, row_min and row_max)
 	Params   []string       `json:"params,omitempty"`    // Additional parameters
 	CastType string         `json:"cast_type,omitempty"` // Target type for cast (text, int, float, etc.)
 	Filter   *ConditionSpec `json:"filter,omitempty"`    // Filter condition for filtered aggregates
@@ -218,20 +420,27 @@ type SelectExprSpec struct {
 //	  "offset_param": "page_offset"
 //	}
 type QuerySpec struct {
-	Fields      []string         `json:"fields,omitempty"`
-	SelectExprs []SelectExprSpec `json:"select_exprs,omitempty"` // Computed expressions (UPPER, COUNT, etc.)
-	Where       []ConditionSpec  `json:"where,omitempty"`
-	OrderBy     []OrderBySpec    `json:"order_by,omitempty"`
-	GroupBy     []string         `json:"group_by,omitempty"`
-	Having      []ConditionSpec  `json:"having,omitempty"`
-	HavingAgg   []HavingAggSpec  `json:"having_agg,omitempty"`
-	Limit       *int             `json:"limit,omitempty"`
-	LimitParam  string           `json:"limit_param,omitempty"` // Parameterized limit (mutually exclusive with Limit)
-	Offset      *int             `json:"offset,omitempty"`
-	OffsetParam string           `json:"offset_param,omitempty"` // Parameterized offset (mutually exclusive with Offset)
-	Distinct    bool             `json:"distinct,omitempty"`
-	DistinctOn  []string         `json:"distinct_on,omitempty"` // PostgreSQL DISTINCT ON fields
-	ForLocking  string           `json:"for_locking,omitempty"` // "update", "no_key_update", "share", "key_share"
+	Fields        []string          `json:"fields,omitempty"`
+	FieldAliases  map[string]string `json:"field_aliases,omitempty"` // Field -> alias, rendered as "field" AS "alias"; the field must be in Fields
+	SelectExprs   []SelectExprSpec  `json:"select_exprs,omitempty"`  // Computed expressions (UPPER, COUNT, etc.)
+	Where         []ConditionSpec   `json:"where,omitempty"`
+	OuterWhere    []ConditionSpec   `json:"outer_where,omitempty"` // Conditions on SelectExprs and FieldAliases aliases, applied by wrapping the query
+	OrderBy       []OrderBySpec     `json:"order_by,omitempty"`
+	GroupBy       []string          `json:"group_by,omitempty"`
+	Having        []ConditionSpec   `json:"having,omitempty"`
+	HavingAgg     []HavingAggSpec   `json:"having_agg,omitempty"`
+	Limit         *int              `json:"limit,omitempty"`
+	LimitParam    string            `json:"limit_param,omitempty"` // Parameterized limit (mutually exclusive with Limit)
+	Offset        *int              `json:"offset,omitempty"`
+	OffsetParam   string            `json:"offset_param,omitempty"` // Parameterized offset (mutually exclusive with Offset)
+	Distinct      bool              `json:"distinct,omitempty"`
+	DistinctOn    []string          `json:"distinct_on,omitempty"`    // PostgreSQL DISTINCT ON fields
+	ForLocking    string            `json:"for_locking,omitempty"`    // "update", "no_key_update", "share", "key_share"
+	IndexHint     string            `json:"index_hint,omitempty"`     // pg_hint_plan hint, e.g. "IndexScan(users users_email_idx)"; ignored on other dialects
+	ParamDefaults map[string]any    `json:"param_defaults,omitempty"` // Param -> value used when the caller omits the param
+	Params        []ParamSpec       `json:"params,omitempty"`         // Type, Description and Default overlaid on the derived param of the same name
+	MaxResults    int               `json:"max_results,omitempty"`    // Hard cap on returned rows, applied after the fetch; 0 is unlimited
+	WithTrashed   bool              `json:"with_trashed,omitempty"`   // Include soft-deleted rows; see EnableSoftDelete
 }
 
 // SelectSpec represents a SELECT query that returns a single record in a serializable format.
@@ -253,20 +462,26 @@ type QuerySpec struct {
 //	  "for_locking": "update"
 //	}
 type SelectSpec struct {
-	Fields      []string         `json:"fields,omitempty"`
-	SelectExprs []SelectExprSpec `json:"select_exprs,omitempty"` // Computed expressions (UPPER, COUNT, etc.)
-	Where       []ConditionSpec  `json:"where,omitempty"`
-	OrderBy     []OrderBySpec    `json:"order_by,omitempty"`
-	GroupBy     []string         `json:"group_by,omitempty"`
-	Having      []ConditionSpec  `json:"having,omitempty"`
-	HavingAgg   []HavingAggSpec  `json:"having_agg,omitempty"`
-	Limit       *int             `json:"limit,omitempty"`
-	LimitParam  string           `json:"limit_param,omitempty"` // Parameterized limit (mutually exclusive with Limit)
-	Offset      *int             `json:"offset,omitempty"`
-	OffsetParam string           `json:"offset_param,omitempty"` // Parameterized offset (mutually exclusive with Offset)
-	Distinct    bool             `json:"distinct,omitempty"`
-	DistinctOn  []string         `json:"distinct_on,omitempty"` // PostgreSQL DISTINCT ON fields
-	ForLocking  string           `json:"for_locking,omitempty"` // "update", "no_key_update", "share", "key_share"
+	Fields        []string          `json:"fields,omitempty"`
+	FieldAliases  map[string]string `json:"field_aliases,omitempty"` // Field -> alias, rendered as "field" AS "alias"; the field must be in Fields
+	SelectExprs   []SelectExprSpec  `json:"select_exprs,omitempty"`  // Computed expressions (UPPER, COUNT, etc.)
+	Where         []ConditionSpec   `json:"where,omitempty"`
+	OuterWhere    []ConditionSpec   `json:"outer_where,omitempty"` // Conditions on SelectExprs and FieldAliases aliases, applied by wrapping the query
+	OrderBy       []OrderBySpec     `json:"order_by,omitempty"`
+	GroupBy       []string          `json:"group_by,omitempty"`
+	Having        []ConditionSpec   `json:"having,omitempty"`
+	HavingAgg     []HavingAggSpec   `json:"having_agg,omitempty"`
+	Limit         *int              `json:"limit,omitempty"`
+	LimitParam    string            `json:"limit_param,omitempty"` // Parameterized limit (mutually exclusive with Limit)
+	Offset        *int              `json:"offset,omitempty"`
+	OffsetParam   string            `json:"offset_param,omitempty"` // Parameterized offset (mutually exclusive with Offset)
+	Distinct      bool              `json:"distinct,omitempty"`
+	DistinctOn    []string          `json:"distinct_on,omitempty"`    // PostgreSQL DISTINCT ON fields
+	ForLocking    string            `json:"for_locking,omitempty"`    // "update", "no_key_update", "share", "key_share"
+	IndexHint     string            `json:"index_hint,omitempty"`     // pg_hint_plan hint, e.g. "IndexScan(users users_email_idx)"; ignored on other dialects
+	ParamDefaults map[string]any    `json:"param_defaults,omitempty"` // Param -> value used when the caller omits the param
+	Params        []ParamSpec       `json:"params,omitempty"`         // Type, Description and Default overlaid on the derived param of the same name
+	WithTrashed   bool              `json:"with_trashed,omitempty"`   // Include soft-deleted rows; see EnableSoftDelete
 }
 
 // UpdateSpec represents an UPDATE query in a serializable format.
@@ -284,8 +499,12 @@ type SelectSpec struct {
 //	  ]
 //	}
 type UpdateSpec struct {
-	Set   map[string]string `json:"set"`
-	Where []ConditionSpec   `json:"where"`
+	Set           map[string]string `json:"set"`
+	SetExpr       map[string]string `json:"set_expr,omitempty"` // Field -> expression, e.g. "views + :delta"
+	Where         []ConditionSpec   `json:"where"`
+	Returning     []string          `json:"returning,omitempty"`      // Columns ExecUpdate populates; empty returns every column
+	ParamDefaults map[string]any    `json:"param_defaults,omitempty"` // Param -> value used when the caller omits the param
+	Params        []ParamSpec       `json:"params,omitempty"`         // Type, Description and Default overlaid on the derived param of the same name
 }
 
 // CreateSpec represents an INSERT query with optional ON CONFLICT handling.
@@ -329,7 +548,10 @@ type CreateSpec struct {
 //	  ]
 //	}
 type DeleteSpec struct {
-	Where []ConditionSpec `json:"where"`
+	Where         []ConditionSpec `json:"where"`
+	Returning     []string        `json:"returning,omitempty"`      // Columns ExecDeleteReturning populates; empty returns every column
+	ParamDefaults map[string]any  `json:"param_defaults,omitempty"` // Param -> value used when the caller omits the param
+	Params        []ParamSpec     `json:"params,omitempty"`         // Type, Description and Default overlaid on the derived param of the same name
 }
 
 // AggregateSpec represents an aggregate query (COUNT/SUM/AVG/MIN/MAX) in a serializable format.
@@ -351,9 +573,20 @@ type DeleteSpec struct {
 //	    {"field": "status", "operator": "=", "param": "paid"}
 //	  ]
 //	}
+//
+// Example JSON for a grouped aggregate, one value per status:
+//
+//	{
+//	  "field": "amount",
+//	  "group_by": ["status"]
+//	}
 type AggregateSpec struct {
-	Field string          `json:"field,omitempty"` // Required for SUM/AVG/MIN/MAX, not used for COUNT
-	Where []ConditionSpec `json:"where,omitempty"`
+	Field         string          `json:"field,omitempty"` // Required for SUM/AVG/MIN/MAX, not used for COUNT
+	Where         []ConditionSpec `json:"where,omitempty"`
+	GroupBy       []string        `json:"group_by,omitempty"`       // Fields to group by; run with ExecGroupedAggregate
+	ParamDefaults map[string]any  `json:"param_defaults,omitempty"` // Param -> value used when the caller omits the param
+	Params        []ParamSpec     `json:"params,omitempty"`         // Type, Description and Default overlaid on the derived param of the same name
+	WithTrashed   bool            `json:"with_trashed,omitempty"`   // Include soft-deleted rows; see EnableSoftDelete
 }
 
 // SetOperandSpec represents one operand in a compound query (UNION, INTERSECT, EXCEPT).
@@ -166,6 +166,80 @@ func TestConditionSpecIsFieldComparison(t *testing.T) {
 	}
 }
 
+func TestConditionSpecIsDistinctFrom(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     ConditionSpec
+		expected bool
+	}{
+		{
+			name:     "simple condition",
+			spec:     ConditionSpec{Field: "age", Operator: "=", Param: "age"},
+			expected: false,
+		},
+		{
+			name:     "is distinct from",
+			spec:     ConditionSpec{Field: "age", Operator: "IS DISTINCT FROM", Param: "age"},
+			expected: true,
+		},
+		{
+			name:     "is not distinct from, lowercase",
+			spec:     ConditionSpec{Field: "age", Operator: "is not distinct from", Param: "age"},
+			expected: true,
+		},
+		{
+			name:     "field comparison",
+			spec:     ConditionSpec{Field: "age", Operator: "IS DISTINCT FROM", RightField: "min_age"},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.spec.IsDistinctFrom(); got != tt.expected {
+				t.Errorf("IsDistinctFrom() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConditionSpecIsList(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     ConditionSpec
+		expected bool
+	}{
+		{
+			name:     "simple condition",
+			spec:     ConditionSpec{Field: "status", Operator: "=", Param: "status"},
+			expected: false,
+		},
+		{
+			name:     "in",
+			spec:     ConditionSpec{Field: "status", In: true, Param: "statuses"},
+			expected: true,
+		},
+		{
+			name:     "not in",
+			spec:     ConditionSpec{Field: "status", NotIn: true, Param: "statuses"},
+			expected: true,
+		},
+		{
+			name:     "in operator, lowercase",
+			spec:     ConditionSpec{Field: "status", Operator: "not in", Param: "statuses"},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.spec.IsList(); got != tt.expected {
+				t.Errorf("IsList() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestOrderBySpecHasNulls(t *testing.T) {
 	tests := []struct {
 		name     string
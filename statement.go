@@ -1,6 +1,10 @@
 package edamame
 
-import "github.com/google/uuid"
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
 
 // ParamSpec describes a parameter required for statement execution.
 type ParamSpec struct {
@@ -11,6 +15,16 @@ type ParamSpec struct {
 	Description string `json:"description,omitempty"`
 }
 
+// Statement is implemented by every statement type.
+// It exposes the metadata shared by queries, selects, updates, deletes, and aggregates.
+type Statement interface {
+	ID() uuid.UUID
+	Name() string
+	Description() string
+	Params() []ParamSpec
+	Tags() []string
+}
+
 // QueryStatement defines a SELECT query that returns multiple records.
 // Statements are defined as package-level variables and passed directly to execution methods.
 type QueryStatement struct {
@@ -30,7 +44,7 @@ func NewQueryStatement(name, description string, spec QuerySpec, tags ...string)
 		name:        name,
 		description: description,
 		spec:        spec,
-		params:      deriveQueryParams(spec),
+		params:      withParamSpecs(withParamDefaults(deriveQueryParams(spec), spec.ParamDefaults), spec.Params),
 		tags:        tags,
 	}
 }
@@ -69,7 +83,7 @@ func NewSelectStatement(name, description string, spec SelectSpec, tags ...strin
 		name:        name,
 		description: description,
 		spec:        spec,
-		params:      deriveSelectParams(spec),
+		params:      withParamSpecs(withParamDefaults(deriveSelectParams(spec), spec.ParamDefaults), spec.Params),
 		tags:        tags,
 	}
 }
@@ -108,7 +122,7 @@ func NewUpdateStatement(name, description string, spec UpdateSpec, tags ...strin
 		name:        name,
 		description: description,
 		spec:        spec,
-		params:      deriveUpdateParams(spec),
+		params:      withParamSpecs(withParamDefaults(deriveUpdateParams(spec), spec.ParamDefaults), spec.Params),
 		tags:        tags,
 	}
 }
@@ -147,7 +161,7 @@ func NewDeleteStatement(name, description string, spec DeleteSpec, tags ...strin
 		name:        name,
 		description: description,
 		spec:        spec,
-		params:      deriveDeleteParams(spec),
+		params:      withParamSpecs(withParamDefaults(deriveDeleteParams(spec), spec.ParamDefaults), spec.Params),
 		tags:        tags,
 	}
 }
@@ -188,6 +202,13 @@ const (
 	AggAvg   AggregateFunc = "AVG"
 	AggMin   AggregateFunc = "MIN"
 	AggMax   AggregateFunc = "MAX"
+
+	// Statistical aggregates. AggStdDev and AggVariance are the sample statistics;
+	// the Pop variants are the population statistics. SQLite does not support them.
+	AggStdDev      AggregateFunc = "STDDEV_SAMP"
+	AggStdDevPop   AggregateFunc = "STDDEV_POP"
+	AggVariance    AggregateFunc = "VAR_SAMP"
+	AggVariancePop AggregateFunc = "VAR_POP"
 )
 
 // NewAggregateStatement creates a new AggregateStatement with an auto-generated UUID.
@@ -199,7 +220,7 @@ func NewAggregateStatement(name, description string, fn AggregateFunc, spec Aggr
 		description: description,
 		spec:        spec,
 		fn:          fn,
-		params:      deriveAggregateParams(spec),
+		params:      withParamSpecs(withParamDefaults(deriveAggregateParams(spec), spec.ParamDefaults), spec.Params),
 		tags:        tags,
 	}
 }
@@ -222,6 +243,59 @@ func (s AggregateStatement) Params() []ParamSpec { return s.params }
 // Tags returns the statement's tags.
 func (s AggregateStatement) Tags() []string { return s.tags }
 
+// DeriveParams returns the params a spec binds, as the matching statement
+// constructor would derive them. It accepts QuerySpec, SelectSpec, UpdateSpec,
+// DeleteSpec, AggregateSpec and CompoundQuerySpec, so tooling can inspect an
+// ad-hoc spec without building a statement.
+//
+// Compound queries bind each query's params under a prefix: "q0_" for the base
+// query and "q1_", "q2_", ... for the operands in order.
+//
+// Example:
+//
+//	params, err := edamame.DeriveParams(edamame.QuerySpec{
+//	    Where: []edamame.ConditionSpec{{Field: "age", Operator: ">=", Param: "min_age"}},
+//	})
+//	// []ParamSpec{{Name: "min_age", Type: "any", Required: true}}
+func DeriveParams(spec any) ([]ParamSpec, error) {
+	switch s := spec.(type) {
+	case QuerySpec:
+		return withParamSpecs(withParamDefaults(deriveQueryParams(s), s.ParamDefaults), s.Params), nil
+	case SelectSpec:
+		return withParamSpecs(withParamDefaults(deriveSelectParams(s), s.ParamDefaults), s.Params), nil
+	case UpdateSpec:
+		return withParamSpecs(withParamDefaults(deriveUpdateParams(s), s.ParamDefaults), s.Params), nil
+	case DeleteSpec:
+		return withParamSpecs(withParamDefaults(deriveDeleteParams(s), s.ParamDefaults), s.Params), nil
+	case AggregateSpec:
+		return withParamSpecs(withParamDefaults(deriveAggregateParams(s), s.ParamDefaults), s.Params), nil
+	case CompoundQuerySpec:
+		return deriveCompoundParams(s), nil
+	default:
+		return nil, fmt.Errorf("edamame: cannot derive params from %T", spec)
+	}
+}
+
+// deriveCompoundParams extracts each query's params under the prefix the
+// renderer binds it with.
+func deriveCompoundParams(spec CompoundQuerySpec) []ParamSpec {
+	queries := make([]QuerySpec, 0, len(spec.Operands)+1)
+	queries = append(queries, spec.Base)
+	for _, operand := range spec.Operands {
+		queries = append(queries, operand.Query)
+	}
+
+	params := make([]ParamSpec, 0)
+	for i, q := range queries {
+		prefix := fmt.Sprintf("q%d_", i)
+		for _, p := range withParamSpecs(withParamDefaults(deriveQueryParams(q), q.ParamDefaults), q.Params) {
+			p.Name = prefix + p.Name
+			params = append(params, p)
+		}
+	}
+	return params
+}
+
 // deriveQueryParams extracts params from all parts of a QuerySpec.
 func deriveQueryParams(spec QuerySpec) []ParamSpec {
 	seen := make(map[string]bool)
@@ -230,6 +304,9 @@ func deriveQueryParams(spec QuerySpec) []ParamSpec {
 	// WHERE conditions
 	collectParams(spec.Where, seen, &params)
 
+	// Outer WHERE conditions on SELECT aliases
+	collectParams(spec.OuterWhere, seen, &params)
+
 	// HAVING conditions
 	collectParams(spec.Having, seen, &params)
 
@@ -245,7 +322,7 @@ func deriveQueryParams(spec QuerySpec) []ParamSpec {
 		}
 	}
 
-	// ORDER BY expressions (for vector distance params)
+	// ORDER BY expressions (for vector distance params) and weighted term coefficients
 	for _, o := range spec.OrderBy {
 		if o.IsExpression() && !seen[o.Param] {
 			seen[o.Param] = true
@@ -255,6 +332,16 @@ func deriveQueryParams(spec QuerySpec) []ParamSpec {
 				Required: true,
 			})
 		}
+		for _, term := range o.Terms {
+			if !seen[term.Param] {
+				seen[term.Param] = true
+				params = append(params, ParamSpec{
+					Name:     term.Param,
+					Type:     "numeric",
+					Required: true,
+				})
+			}
+		}
 	}
 
 	// Parameterized limit/offset
@@ -286,6 +373,9 @@ func deriveSelectParams(spec SelectSpec) []ParamSpec {
 	// WHERE conditions
 	collectParams(spec.Where, seen, &params)
 
+	// Outer WHERE conditions on SELECT aliases
+	collectParams(spec.OuterWhere, seen, &params)
+
 	// HAVING conditions
 	collectParams(spec.Having, seen, &params)
 
@@ -301,7 +391,7 @@ func deriveSelectParams(spec SelectSpec) []ParamSpec {
 		}
 	}
 
-	// ORDER BY expressions (for vector distance params)
+	// ORDER BY expressions (for vector distance params) and weighted term coefficients
 	for _, o := range spec.OrderBy {
 		if o.IsExpression() && !seen[o.Param] {
 			seen[o.Param] = true
@@ -311,6 +401,16 @@ func deriveSelectParams(spec SelectSpec) []ParamSpec {
 				Required: true,
 			})
 		}
+		for _, term := range o.Terms {
+			if !seen[term.Param] {
+				seen[term.Param] = true
+				params = append(params, ParamSpec{
+					Name:     term.Param,
+					Type:     "numeric",
+					Required: true,
+				})
+			}
+		}
 	}
 
 	// Parameterized limit/offset
@@ -352,6 +452,19 @@ func deriveUpdateParams(spec UpdateSpec) []ParamSpec {
 		})
 	}
 
+	// SET expression params
+	for _, param := range setExprParams(spec.SetExpr) {
+		if seen[param] {
+			continue
+		}
+		seen[param] = true
+		params = append(params, ParamSpec{
+			Name:     param,
+			Type:     "any",
+			Required: true,
+		})
+	}
+
 	// WHERE params
 	collectParams(spec.Where, seen, &params)
 
@@ -383,6 +496,25 @@ func collectParams(conditions []ConditionSpec, seen map[string]bool, params *[]P
 			continue
 		}
 
+		// EXISTS and quantified subqueries, whose params carry the subquery's namespace
+		if conditions[i].IsSubquery() {
+			collectParams(conditions[i].subqueryWhere(), seen, params)
+			continue
+		}
+
+		// Full-text conditions bind the search query as text
+		if conditions[i].IsFullText() {
+			if ft := conditions[i].FullText; ft.Param != "" && !seen[ft.Param] {
+				seen[ft.Param] = true
+				*params = append(*params, ParamSpec{
+					Name:     ft.Param,
+					Type:     "string",
+					Required: true,
+				})
+			}
+			continue
+		}
+
 		// BETWEEN conditions
 		if conditions[i].IsBetween() || conditions[i].IsNotBetween() {
 			if conditions[i].LowParam != "" && !seen[conditions[i].LowParam] {
@@ -410,9 +542,15 @@ func collectParams(conditions []ConditionSpec, seen map[string]bool, params *[]P
 		}
 		seen[conditions[i].Param] = true
 
+		typ := "any"
+		if conditions[i].IsList() {
+			typ = "array"
+		} else if conditions[i].IsMatch() {
+			typ = "string"
+		}
 		*params = append(*params, ParamSpec{
 			Name:     conditions[i].Param,
-			Type:     "any",
+			Type:     typ,
 			Required: true,
 		})
 	}
@@ -1,9 +1,11 @@
 package edamame
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/zoobzio/astql/pkg/postgres"
 )
 
 func TestQueryStatement_Accessors(t *testing.T) {
@@ -310,3 +312,71 @@ func TestSelectStatement_ParamDerivation(t *testing.T) {
 		}
 	}
 }
+
+func TestDeriveParams(t *testing.T) {
+	where := []ConditionSpec{{Field: "status", Operator: "=", Param: "status"}}
+	tests := []struct {
+		name string
+		spec any
+		want []string
+	}{
+		{"query", QuerySpec{Where: where, LimitParam: "limit"}, []string{"status", "limit"}},
+		{"select", SelectSpec{Where: where}, []string{"status"}},
+		{"update", UpdateSpec{Set: map[string]string{"name": "new_name"}, Where: where}, []string{"new_name", "status"}},
+		{"delete", DeleteSpec{Where: where}, []string{"status"}},
+		{"aggregate", AggregateSpec{Field: "age", Where: where}, []string{"status"}},
+		{"compound", CompoundQuerySpec{
+			Base: QuerySpec{Where: where},
+			Operands: []SetOperandSpec{
+				{Operation: "union", Query: QuerySpec{Where: []ConditionSpec{{Field: "age", Operator: ">=", Param: "min_age"}}}},
+				{Operation: "except", Query: QuerySpec{Where: where}},
+			},
+		}, []string{"q0_status", "q1_min_age", "q2_status"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, err := DeriveParams(tt.spec)
+			if err != nil {
+				t.Fatalf("DeriveParams() failed: %v", err)
+			}
+			if len(params) != len(tt.want) {
+				t.Fatalf("expected %d params, got %+v", len(tt.want), params)
+			}
+			for i, name := range tt.want {
+				if params[i].Name != name {
+					t.Errorf("param %d: expected %q, got %q", i, name, params[i].Name)
+				}
+			}
+		})
+	}
+
+	if _, err := DeriveParams(CreateSpec{}); err == nil {
+		t.Error("expected error for unsupported spec type")
+	}
+}
+
+func TestDeriveParams_CompoundMatchesRenderedSQL(t *testing.T) {
+	exec, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	spec := CompoundQuerySpec{
+		Base: QuerySpec{Where: []ConditionSpec{{Field: "name", Operator: "=", Param: "name"}}},
+		Operands: []SetOperandSpec{
+			{Operation: "union", Query: QuerySpec{Where: []ConditionSpec{{Field: "age", Operator: ">=", Param: "min_age"}}}},
+		},
+	}
+	sql, err := exec.RenderCompound(spec)
+	if err != nil {
+		t.Fatalf("RenderCompound() failed: %v", err)
+	}
+	params, err := DeriveParams(spec)
+	if err != nil {
+		t.Fatalf("DeriveParams() failed: %v", err)
+	}
+	for _, p := range params {
+		if !strings.Contains(sql, ":"+p.Name) {
+			t.Errorf("expected rendered SQL to bind :%s, got: %s", p.Name, sql)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package edamame
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zoobzio/astql/pkg/mariadb"
+	"github.com/zoobzio/astql/pkg/mssql"
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+// mssqlStatFuncs maps the statistical aggregates to SQL Server's function names.
+var mssqlStatFuncs = map[AggregateFunc]string{
+	AggStdDev:      "STDEV",
+	AggStdDevPop:   "STDEVP",
+	AggVariance:    "VAR",
+	AggVariancePop: "VARP",
+}
+
+// isStatistical reports whether fn is a standard deviation or variance aggregate.
+func isStatistical(fn AggregateFunc) bool {
+	switch fn {
+	case AggStdDev, AggStdDevPop, AggVariance, AggVariancePop:
+		return true
+	}
+	return false
+}
+
+// statisticFunc returns the SQL function the executor's dialect computes fn with.
+func (e *Executor[T]) statisticFunc(fn AggregateFunc) (string, error) {
+	switch e.dialect().(type) {
+	case *postgres.Renderer, *mariadb.Renderer:
+		return string(fn), nil
+	case *mssql.Renderer:
+		return mssqlStatFuncs[fn], nil
+	}
+	return "", fmt.Errorf("edamame: %s is not supported by this dialect", fn)
+}
+
+// renderAggregate renders stmt through soy. soy has no standard deviation or variance
+// aggregate, so statistical functions are rendered as AVG over the same field and
+// filter, and the AVG call is replaced with the dialect's function. Statements with
+// GroupBy fields render through renderGroupedAggregate.
+func (e *Executor[T]) renderAggregate(stmt AggregateStatement) (string, error) {
+	if len(stmt.spec.GroupBy) > 0 {
+		return e.renderGroupedAggregate(stmt)
+	}
+	var fn string
+	if isStatistical(stmt.fn) {
+		var err error
+		if fn, err = e.statisticFunc(stmt.fn); err != nil {
+			return "", err
+		}
+	}
+	result, err := e.Aggregate(stmt).Render()
+	if err != nil {
+		return "", err
+	}
+	if fn == "" {
+		return result.SQL, nil
+	}
+	rest, ok := strings.CutPrefix(result.SQL, "SELECT AVG(")
+	if !ok {
+		return "", fmt.Errorf("edamame: rendered %s has no AVG call to replace", stmt.fn)
+	}
+	return "SELECT " + fn + "(" + rest, nil
+}
@@ -0,0 +1,82 @@
+package edamame
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql"
+	"github.com/zoobzio/astql/pkg/mariadb"
+	"github.com/zoobzio/astql/pkg/mssql"
+	"github.com/zoobzio/astql/pkg/postgres"
+	"github.com/zoobzio/astql/pkg/sqlite"
+)
+
+var adultAges = AggregateSpec{
+	Field: "age",
+	Where: []ConditionSpec{{Field: "age", Operator: ">=", Param: "min_age"}},
+}
+
+func TestRenderAggregate_Statistical(t *testing.T) {
+	tests := []struct {
+		renderer astql.Renderer
+		fn       AggregateFunc
+		want     string
+	}{
+		{postgres.New(), AggStdDev, `SELECT STDDEV_SAMP("age")`},
+		{postgres.New(), AggStdDevPop, `SELECT STDDEV_POP("age")`},
+		{postgres.New(), AggVariance, `SELECT VAR_SAMP("age")`},
+		{postgres.New(), AggVariancePop, `SELECT VAR_POP("age")`},
+		{mariadb.New(), AggStdDev, "SELECT STDDEV_SAMP(`age`)"},
+		{mssql.New(), AggStdDevPop, `SELECT STDEVP([age])`},
+		{mssql.New(), AggVariance, `SELECT VAR([age])`},
+	}
+	for _, tt := range tests {
+		exec, err := New[User](nil, "users", tt.renderer)
+		if err != nil {
+			t.Fatalf("New() failed: %v", err)
+		}
+		sql, err := exec.RenderAggregate(NewAggregateStatement("stat", "Age statistic", tt.fn, adultAges))
+		if err != nil {
+			t.Fatalf("%s: RenderAggregate() failed: %v", tt.fn, err)
+		}
+		if !strings.HasPrefix(sql, tt.want) {
+			t.Errorf("%s: expected prefix %s, got: %s", tt.fn, tt.want, sql)
+		}
+		if !strings.Contains(sql, ">= :min_age") {
+			t.Errorf("%s: expected WHERE clause, got: %s", tt.fn, sql)
+		}
+	}
+}
+
+func TestRenderAggregate_StatisticalUnsupported(t *testing.T) {
+	exec, err := New[User](nil, "users", sqlite.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if _, err := exec.RenderAggregate(NewAggregateStatement("stat", "Age statistic", AggStdDev, adultAges)); err == nil {
+		t.Error("expected error for STDDEV_SAMP on sqlite")
+	}
+}
+
+func TestExecAggregate_Statistical(t *testing.T) {
+	db := &recordingDB{}
+	exec, err := New[User](db, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	stmt := NewAggregateStatement("age-variance", "Age variance", AggVariance, adultAges)
+
+	_, err = exec.ExecAggregate(context.Background(), stmt, map[string]any{"min_age": 18})
+	if !errors.Is(err, errRecorded) {
+		t.Fatalf("expected recorded error, got %v", err)
+	}
+	if want := `SELECT VAR_SAMP("age")`; !strings.HasPrefix(db.queries[0], want) {
+		t.Errorf("expected prefix %s, got: %s", want, db.queries[0])
+	}
+
+	if _, err := exec.ExecAggregate(context.Background(), stmt, nil); err == nil || errors.Is(err, errRecorded) {
+		t.Errorf("expected missing param error, got %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package edamame
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// SetStrictFields enables or disables strict field checking. With it enabled, Prepare
+// and every Exec method reject a statement referencing a field that is not a db
+// column of T, naming the closest column when one is near:
+//
+//	edamame: statement "by-name": unknown field "nmae" (did you mean "name"?)
+//
+// The check covers condition Field and RightField, including groups, fragments and
+// EXISTS correlations; HAVING; Fields, FieldAliases, SelectExprs, GroupBy,
+// DistinctOn and OrderBy; an aggregate's Field; and update Set and SetExpr targets.
+// Fields are resolved like spec fields, so Go field names and mapped names pass.
+// It is disabled by default, leaving unknown fields to fail when SQL is built.
+func (e *Executor[T]) SetStrictFields(enabled bool) {
+	e.strictFields.Store(enabled)
+}
+
+// checkKnownFields returns an error for the first of fields that is not a column.
+func (e *Executor[T]) checkKnownFields(fields []string) error {
+	for _, field := range fields {
+		if field == "" || field == "*" {
+			continue
+		}
+		col := e.column(field)
+		if _, ok := e.columns[col]; ok {
+			continue
+		}
+		if match := closestColumn(col, e.schemaColumns()); match != "" {
+			return fmt.Errorf("unknown field %q (did you mean %q?)", field, match)
+		}
+		return fmt.Errorf("unknown field %q", field)
+	}
+	return nil
+}
+
+// statementSetFields returns the columns an update statement assigns.
+func statementSetFields(stmt Statement) []string {
+	s, ok := stmt.(UpdateStatement)
+	if !ok {
+		return nil
+	}
+	fields := slices.Sorted(maps.Keys(s.spec.Set))
+	return append(fields, slices.Sorted(maps.Keys(s.spec.SetExpr))...)
+}
+
+// closestColumn returns the column nearest name by edit distance, or "" when none is
+// within two edits or a third of name's length, so unrelated names get no suggestion.
+func closestColumn(name string, columns []string) string {
+	best, bestDist := "", max(2, len(name)/3)+1
+	for _, col := range columns {
+		if d := editDistance(name, col); d < bestDist {
+			best, bestDist = col, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,69 @@
+package edamame
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestSetStrictFields(t *testing.T) {
+	db := &recordingDB{}
+	exec, err := New[User](db, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	byName := NewQueryStatement("by-name", "", QuerySpec{
+		Where: []ConditionSpec{{Logic: "OR", Group: []ConditionSpec{
+			{Field: "Email", Operator: "=", Param: "email"},
+			{Field: "nmae", Operator: "=", Param: "name"},
+		}}},
+	})
+
+	if err := exec.Prepare(byName); err == nil || err.Error() == `edamame: statement "by-name": unknown field "nmae" (did you mean "name"?)` {
+		t.Errorf("expected soy's error without strict mode, got %v", err)
+	}
+
+	exec.SetStrictFields(true)
+	if err := exec.Prepare(byName); err == nil || err.Error() != `edamame: statement "by-name": unknown field "nmae" (did you mean "name"?)` {
+		t.Errorf("expected a suggestion for the misspelled field, got %v", err)
+	}
+	if _, err := exec.ExecQuery(context.Background(), byName, map[string]any{"email": "a", "name": "b"}); err == nil {
+		t.Error("expected ExecQuery to reject the misspelled field")
+	}
+	if db.count() != 0 {
+		t.Errorf("expected no SQL to reach the database, got %v", db.queries)
+	}
+
+	tests := []struct {
+		name string
+		stmt Statement
+		want string
+	}{
+		{"set target", NewUpdateStatement("set", "", UpdateSpec{
+			Set:   map[string]string{"emial": "email"},
+			Where: []ConditionSpec{{Field: "id", Operator: "=", Param: "id"}},
+		}), `edamame: statement "set": unknown field "emial" (did you mean "email"?)`},
+		{"having", NewQueryStatement("having", "", QuerySpec{
+			GroupBy: []string{"age"},
+			Having:  []ConditionSpec{{Field: "ages", Operator: ">", Param: "min"}},
+		}), `edamame: statement "having": unknown field "ages" (did you mean "age"?)`},
+		{"group by", NewAggregateStatement("group-by", "", AggCount, AggregateSpec{GroupBy: []string{"status"}}),
+			`edamame: statement "group-by": unknown field "status"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := exec.Prepare(tt.stmt); err == nil || err.Error() != tt.want {
+				t.Errorf("expected %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	valid := NewUpdateStatement("valid", "", UpdateSpec{
+		Set:   map[string]string{"Name": "name"},
+		Where: []ConditionSpec{{Field: "id", Operator: "=", Param: "id"}},
+	})
+	if err := exec.Prepare(valid); err != nil {
+		t.Errorf("expected known fields to pass, got %v", err)
+	}
+}
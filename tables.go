@@ -0,0 +1,173 @@
+package edamame
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/zoobzio/astql"
+	"github.com/zoobzio/astql/pkg/mariadb"
+	"github.com/zoobzio/astql/pkg/mssql"
+)
+
+// tableIdentifier matches one dot-separated part of a table or view name.
+var tableIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// splitQualifiedName splits the kind ("table" or "view") name into its schema, empty
+// when unqualified, and name. name must be "name" or "schema.name", each part a plain identifier.
+func splitQualifiedName(kind, name string) (schema, base string, err error) {
+	if name == "" {
+		return "", "", fmt.Errorf("edamame: %s name cannot be empty", kind)
+	}
+	parts := strings.Split(name, ".")
+	if len(parts) > 2 {
+		return "", "", fmt.Errorf("edamame: invalid %s name %q: expected \"%s\" or \"schema.%s\"", kind, name, kind, kind)
+	}
+	for _, part := range parts {
+		if !tableIdentifier.MatchString(part) {
+			return "", "", fmt.Errorf("edamame: invalid %s name %q: %q is not a valid identifier", kind, name, part)
+		}
+	}
+	if len(parts) == 1 {
+		return "", parts[0], nil
+	}
+	return parts[0], parts[1], nil
+}
+
+// qualifiedRenderer wraps the renderer of an executor with a schema-qualified table.
+// astql quotes the table name as a single identifier, so "analytics.events" would
+// name a table containing a dot; every rendered occurrence of that identifier is
+// replaced with the schema and table quoted independently. soy renders with this
+// renderer, so statements soy runs on a transaction directly are covered too.
+type qualifiedRenderer struct {
+	astql.Renderer
+	quoted    string // the table as astql quotes it, e.g. "analytics.events"
+	qualified string // the table with each part quoted, e.g. "analytics"."events"
+}
+
+// Render renders ast with the wrapped renderer and qualifies the table.
+func (r *qualifiedRenderer) Render(ast *astql.AST) (*astql.QueryResult, error) {
+	return r.qualify(r.Renderer.Render(ast))
+}
+
+// RenderCompound renders query with the wrapped renderer and qualifies the table.
+func (r *qualifiedRenderer) RenderCompound(query *astql.CompoundQuery) (*astql.QueryResult, error) {
+	return r.qualify(r.Renderer.RenderCompound(query))
+}
+
+// qualify replaces the single-identifier table in result's SQL.
+func (r *qualifiedRenderer) qualify(result *astql.QueryResult, err error) (*astql.QueryResult, error) {
+	if err != nil || r.quoted == "" {
+		return result, err
+	}
+	qualified := *result
+	qualified.SQL = strings.ReplaceAll(result.SQL, r.quoted, r.qualified)
+	return &qualified, nil
+}
+
+// init derives the quoted and qualified forms of the table from the wrapped renderer,
+// which keeps the quoting dialect-specific. instance must have the table registered.
+func (r *qualifiedRenderer) init(instance *astql.ASTQL, name, schema, table string) error {
+	t, err := instance.TryT(name)
+	if err != nil {
+		return fmt.Errorf("edamame: invalid table %q: %w", name, err)
+	}
+	result, err := astql.Delete(t).Render(r.Renderer)
+	if err != nil {
+		return fmt.Errorf("edamame: failed to render table %q: %w", name, err)
+	}
+	quoted, ok := strings.CutPrefix(result.SQL, "DELETE FROM ")
+	if !ok || len(quoted) != len(name)+2 {
+		return fmt.Errorf("edamame: unexpected rendering of table %q: %s", name, result.SQL)
+	}
+	open, closing := quoted[:1], quoted[len(quoted)-1:]
+	r.quoted = quoted
+	r.qualified = open + schema + closing + "." + open + table + closing
+	return nil
+}
+
+// dialect returns the renderer passed to New, for checks on the SQL dialect.
+func (e *Executor[T]) dialect() astql.Renderer {
+	if r, ok := e.renderer.(*qualifiedRenderer); ok {
+		return r.Renderer
+	}
+	return e.renderer
+}
+
+// SchemaName returns the schema of a schema-qualified table name, such as "analytics"
+// for "analytics.events", or "" when the table name is unqualified.
+func (e *Executor[T]) SchemaName() string {
+	return e.schema
+}
+
+// BaseTableName returns the table name without its schema, such as "events" for
+// "analytics.events". TableName returns the name as passed to New.
+func (e *Executor[T]) BaseTableName() string {
+	return e.table
+}
+
+// WithSchema returns a copy of the executor for the same table in schema, such as one
+// executor per tenant schema. The copy renders the table as "schema"."table", so its
+// statements do not depend on the connection's search_path; an empty schema renders
+// the unqualified table. It shares the database handles, including the read database,
+// and starts with the executor's current configuration, as taken by Snapshot. Later
+// configuration changes to either executor do not affect the other.
+//
+// Example:
+//
+//	tenant, err := exec.WithSchema("tenant_42")
+//	if err != nil {
+//	    return err
+//	}
+//	users, err := tenant.ExecQuery(ctx, AllUsers, nil)
+func (e *Executor[T]) WithSchema(schema string) (*Executor[T], error) {
+	name := e.table
+	if schema != "" {
+		name = schema + "." + e.table
+	}
+	renderer := e.renderer
+	if qualified, ok := renderer.(*qualifiedRenderer); ok {
+		renderer = qualified.Renderer
+	}
+	clone, err := New[T](e.db, name, renderer)
+	if err != nil {
+		return nil, err
+	}
+	if e.router != nil {
+		if read := e.router.read.Load(); read != nil {
+			clone.router.read.Store(read)
+		}
+	}
+	clone.RestoreSnapshot(e.Snapshot())
+	return clone, nil
+}
+
+// quotedTableName returns the table quoted as the executor's dialect quotes identifiers,
+// each part of a schema-qualified name quoted independently, for SQL formatted directly
+// rather than rendered by astql.
+func (e *Executor[T]) quotedTableName() string {
+	if e.schema == "" {
+		return e.quoteIdent(e.table)
+	}
+	return e.quoteIdent(e.schema) + "." + e.quoteIdent(e.table)
+}
+
+// quoteIdent quotes name as astql's renderer for the executor's dialect does: in
+// backticks for MariaDB, brackets for SQL Server and double quotes otherwise.
+func (e *Executor[T]) quoteIdent(name string) string {
+	switch e.dialect().(type) {
+	case *mariadb.Renderer:
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	case *mssql.Renderer:
+		return "[" + strings.ReplaceAll(name, "]", "]]") + "]"
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteQualified double-quotes name, prefixed with the double-quoted schema when set.
+func quoteQualified(schema, name string) string {
+	if schema == "" {
+		return `"` + name + `"`
+	}
+	return `"` + schema + `"."` + name + `"`
+}
@@ -0,0 +1,188 @@
+package edamame
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql"
+	"github.com/zoobzio/astql/pkg/mariadb"
+	"github.com/zoobzio/astql/pkg/mssql"
+	"github.com/zoobzio/astql/pkg/postgres"
+	"github.com/zoobzio/astql/pkg/sqlite"
+)
+
+func TestSplitTableName(t *testing.T) {
+	tests := []struct {
+		name, schema, table string
+	}{
+		{"users", "", "users"},
+		{"analytics.events", "analytics", "events"},
+		{"_private.T1", "_private", "T1"},
+	}
+	for _, tt := range tests {
+		schema, table, err := splitQualifiedName("table", tt.name)
+		if err != nil {
+			t.Fatalf("splitQualifiedName(%q) failed: %v", tt.name, err)
+		}
+		if schema != tt.schema || table != tt.table {
+			t.Errorf("splitQualifiedName(%q) = %q, %q, want %q, %q", tt.name, schema, table, tt.schema, tt.table)
+		}
+	}
+
+	for _, name := range []string{"", "a.b.c", "a..b", ".users", "users.", "1users", "users;drop", `"users"`, "my table"} {
+		if _, _, err := splitQualifiedName("table", name); err == nil {
+			t.Errorf("splitQualifiedName(%q): expected error", name)
+		}
+	}
+}
+
+func TestNew_SchemaQualified(t *testing.T) {
+	exec, err := New[User](nil, "analytics.users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if exec.TableName() != "analytics.users" || exec.SchemaName() != "analytics" || exec.BaseTableName() != "users" {
+		t.Errorf("unexpected table parts: %q, %q, %q", exec.TableName(), exec.SchemaName(), exec.BaseTableName())
+	}
+	if !exec.isPostgres() {
+		t.Error("expected the wrapped renderer to report postgres")
+	}
+
+	plain, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if plain.SchemaName() != "" || plain.BaseTableName() != "users" {
+		t.Errorf("unexpected table parts: %q, %q", plain.SchemaName(), plain.BaseTableName())
+	}
+
+	if _, err := New[User](nil, "analytics.users.v2", postgres.New()); err == nil || !strings.Contains(err.Error(), "invalid table name") {
+		t.Errorf("expected invalid table name error, got %v", err)
+	}
+}
+
+func TestSchemaQualified_Render(t *testing.T) {
+	tests := []struct {
+		renderer astql.Renderer
+		want     string
+	}{
+		{postgres.New(), `SELECT * FROM "analytics"."users" WHERE "id" = :user_id`},
+		{sqlite.New(), `SELECT * FROM "analytics"."users" WHERE "id" = :user_id`},
+		{mariadb.New(), "SELECT * FROM `analytics`.`users` WHERE `id` = :user_id"},
+		{mssql.New(), `SELECT * FROM [analytics].[users] WHERE [id] = :user_id`},
+	}
+	for _, tt := range tests {
+		exec, err := New[User](nil, "analytics.users", tt.renderer)
+		if err != nil {
+			t.Fatalf("New() failed: %v", err)
+		}
+		sql, err := exec.RenderQuery(NewQueryStatement("by-id", "User by ID", QuerySpec{Where: byUserID}))
+		if err != nil {
+			t.Fatalf("RenderQuery() failed: %v", err)
+		}
+		if sql != tt.want {
+			t.Errorf("expected %s, got %s", tt.want, sql)
+		}
+	}
+}
+
+func TestSchemaQualified_Statements(t *testing.T) {
+	exec, err := New[User](nil, "analytics.users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	sql, err := exec.RenderUpdate(NewUpdateStatement("rename", "Rename user", UpdateSpec{Set: map[string]string{"name": "new_name"}, Where: byUserID}))
+	if err != nil {
+		t.Fatalf("RenderUpdate() failed: %v", err)
+	}
+	if !strings.HasPrefix(sql, `UPDATE "analytics"."users" SET`) {
+		t.Errorf("expected qualified UPDATE, got: %s", sql)
+	}
+
+	sql, err = exec.RenderAggregate(NewAggregateStatement("count", "User count", AggCount, AggregateSpec{}))
+	if err != nil {
+		t.Fatalf("RenderAggregate() failed: %v", err)
+	}
+	if sql != `SELECT COUNT(*) FROM "analytics"."users"` {
+		t.Errorf("expected qualified COUNT, got: %s", sql)
+	}
+
+	result, err := exec.Insert().Render()
+	if err != nil {
+		t.Fatalf("Render() failed: %v", err)
+	}
+	if !strings.HasPrefix(result.SQL, `INSERT INTO "analytics"."users" (`) {
+		t.Errorf("expected qualified INSERT, got: %s", result.SQL)
+	}
+
+	sql, err = exec.renderTruncate(TruncateOpts{Confirm: "analytics.users"})
+	if err != nil {
+		t.Fatalf("renderTruncate() failed: %v", err)
+	}
+	if sql != `TRUNCATE TABLE "analytics"."users"` {
+		t.Errorf("expected qualified TRUNCATE, got: %s", sql)
+	}
+
+	sql, _, err = exec.renderBulkUpdate([]RowUpdate{{Key: 1, Set: map[string]any{"name": "a"}}})
+	if err != nil {
+		t.Fatalf("renderBulkUpdate() failed: %v", err)
+	}
+	if !strings.HasPrefix(sql, `UPDATE "analytics"."users" SET`) || !strings.Contains(sql, `WHERE "analytics"."users"."id" = edamame_v."id"`) {
+		t.Errorf("expected qualified bulk UPDATE, got: %s", sql)
+	}
+}
+
+func TestWithSchema(t *testing.T) {
+	exec, err := New[User](nil, "analytics.users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if err := exec.SetQueryableFields("id"); err != nil {
+		t.Fatalf("SetQueryableFields() failed: %v", err)
+	}
+	byID := NewQueryStatement("by-id", "User by ID", QuerySpec{Where: byUserID})
+
+	tests := []struct {
+		schema string
+		want   string
+	}{
+		{"tenant_a", `SELECT * FROM "tenant_a"."users" WHERE "id" = :user_id`},
+		{"", `SELECT * FROM "users" WHERE "id" = :user_id`},
+	}
+	for _, tt := range tests {
+		scoped, err := exec.WithSchema(tt.schema)
+		if err != nil {
+			t.Fatalf("WithSchema(%q) failed: %v", tt.schema, err)
+		}
+		if scoped.SchemaName() != tt.schema || scoped.BaseTableName() != "users" {
+			t.Errorf("unexpected table %q", scoped.TableName())
+		}
+		sql, err := scoped.RenderQuery(byID)
+		if err != nil {
+			t.Fatalf("RenderQuery() failed: %v", err)
+		}
+		if sql != tt.want {
+			t.Errorf("expected %s, got %s", tt.want, sql)
+		}
+		byEmail := NewQueryStatement("by-email", "", QuerySpec{Where: []ConditionSpec{{Field: "email", Operator: "=", Param: "email"}}})
+		if _, err := scoped.RenderQuery(byEmail); err == nil {
+			t.Error("expected the copy to keep the queryable field allowlist")
+		}
+	}
+
+	if _, err := exec.WithSchema("bad schema"); err == nil {
+		t.Error("expected an invalid schema to be rejected")
+	}
+
+	scoped, err := exec.WithSchema("tenant_b")
+	if err != nil {
+		t.Fatalf("WithSchema() failed: %v", err)
+	}
+	if err := scoped.SetQueryableFields(); err != nil {
+		t.Fatalf("SetQueryableFields() failed: %v", err)
+	}
+	if !exec.hasQueryable() {
+		t.Error("expected configuring the copy to leave the original unchanged")
+	}
+}
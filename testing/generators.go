@@ -0,0 +1,218 @@
+package testing
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/zoobzio/edamame"
+)
+
+// SpecGenConfig controls the shape of randomly generated specs.
+type SpecGenConfig struct {
+	// Fields are the column names conditions, orderings and SET clauses draw from.
+	// Use the db tags of the model under test so generated specs render.
+	Fields []string
+
+	// MaxDepth is the maximum nesting of condition groups. Zero disables groups.
+	// The converter renders one level of grouping; conditions in deeper groups are
+	// dropped, so use 1 when checking derived params against rendered SQL.
+	MaxDepth int
+
+	// MaxConditions is the maximum number of conditions at each level. Defaults to 4.
+	MaxConditions int
+}
+
+// specGen carries generation state for a single spec.
+type specGen struct {
+	rng    *rand.Rand
+	cfg    SpecGenConfig
+	params int
+}
+
+// comparisonOperators are the operators used for simple conditions.
+var comparisonOperators = []string{"=", "!=", ">", ">=", "<", "<="}
+
+func newSpecGen(rng *rand.Rand, cfg SpecGenConfig) *specGen {
+	if len(cfg.Fields) == 0 {
+		panic("edamame/testing: SpecGenConfig.Fields must not be empty")
+	}
+	if cfg.MaxConditions <= 0 {
+		cfg.MaxConditions = 4
+	}
+	return &specGen{rng: rng, cfg: cfg}
+}
+
+// RandomQuerySpec generates a QuerySpec with random conditions, groups, orderings and pagination.
+// Specs reference only cfg.Fields and render for any model with those columns.
+// Use with a seeded rng in fuzz tests to check that rendering succeeds.
+func RandomQuerySpec(rng *rand.Rand, cfg SpecGenConfig) edamame.QuerySpec {
+	g := newSpecGen(rng, cfg)
+	spec := edamame.QuerySpec{
+		Where:    g.conditions(cfg.MaxDepth),
+		OrderBy:  g.orderBy(),
+		Distinct: g.chance(4),
+	}
+	if g.chance(3) {
+		spec.Fields = g.fields()
+	}
+	spec.Limit, spec.LimitParam = g.pagination("limit")
+	spec.Offset, spec.OffsetParam = g.pagination("offset")
+	return spec
+}
+
+// RandomSelectSpec generates a SelectSpec with random conditions, groups, orderings and pagination.
+func RandomSelectSpec(rng *rand.Rand, cfg SpecGenConfig) edamame.SelectSpec {
+	g := newSpecGen(rng, cfg)
+	spec := edamame.SelectSpec{
+		Where:    g.conditions(cfg.MaxDepth),
+		OrderBy:  g.orderBy(),
+		Distinct: g.chance(4),
+	}
+	if g.chance(3) {
+		spec.Fields = g.fields()
+	}
+	spec.Limit, spec.LimitParam = g.pagination("limit")
+	spec.Offset, spec.OffsetParam = g.pagination("offset")
+	return spec
+}
+
+// RandomUpdateSpec generates an UpdateSpec with random SET fields and conditions.
+func RandomUpdateSpec(rng *rand.Rand, cfg SpecGenConfig) edamame.UpdateSpec {
+	g := newSpecGen(rng, cfg)
+	set := make(map[string]string)
+	for _, f := range g.fields() {
+		set[f] = "set_" + f
+	}
+	return edamame.UpdateSpec{
+		Set:   set,
+		Where: g.conditions(cfg.MaxDepth),
+	}
+}
+
+// RandomDeleteSpec generates a DeleteSpec with random conditions.
+func RandomDeleteSpec(rng *rand.Rand, cfg SpecGenConfig) edamame.DeleteSpec {
+	g := newSpecGen(rng, cfg)
+	return edamame.DeleteSpec{
+		Where: g.conditions(cfg.MaxDepth),
+	}
+}
+
+// RandomAggregateSpec generates an AggregateSpec with a random field and conditions.
+func RandomAggregateSpec(rng *rand.Rand, cfg SpecGenConfig) edamame.AggregateSpec {
+	g := newSpecGen(rng, cfg)
+	return edamame.AggregateSpec{
+		Field: g.field(),
+		Where: g.conditions(cfg.MaxDepth),
+	}
+}
+
+// chance returns true with probability 1/n.
+func (g *specGen) chance(n int) bool {
+	return g.rng.Intn(n) == 0
+}
+
+func (g *specGen) field() string {
+	return g.cfg.Fields[g.rng.Intn(len(g.cfg.Fields))]
+}
+
+// fields returns a non-empty random subset of the configured fields in their original order.
+func (g *specGen) fields() []string {
+	result := make([]string, 0, len(g.cfg.Fields))
+	for _, f := range g.cfg.Fields {
+		if g.chance(2) {
+			result = append(result, f)
+		}
+	}
+	if len(result) == 0 {
+		result = append(result, g.field())
+	}
+	return result
+}
+
+// param returns a fresh parameter name, or occasionally reuses an earlier one.
+func (g *specGen) param() string {
+	if g.params > 0 && g.chance(5) {
+		return fmt.Sprintf("p%d", g.rng.Intn(g.params))
+	}
+	g.params++
+	return fmt.Sprintf("p%d", g.params-1)
+}
+
+// conditions generates up to MaxConditions conditions, nesting groups while depth remains.
+func (g *specGen) conditions(depth int) []edamame.ConditionSpec {
+	n := g.rng.Intn(g.cfg.MaxConditions + 1)
+	conds := make([]edamame.ConditionSpec, 0, n)
+	for i := 0; i < n; i++ {
+		conds = append(conds, g.condition(depth))
+	}
+	return conds
+}
+
+func (g *specGen) condition(depth int) edamame.ConditionSpec {
+	if depth > 0 && g.chance(4) {
+		logic := "AND"
+		if g.chance(2) {
+			logic = "OR"
+		}
+		group := g.conditions(depth - 1)
+		if len(group) == 0 {
+			group = append(group, g.condition(0))
+		}
+		return edamame.ConditionSpec{Logic: logic, Group: group}
+	}
+
+	switch g.rng.Intn(6) {
+	case 0:
+		op := "IS NULL"
+		if g.chance(2) {
+			op = "IS NOT NULL"
+		}
+		return edamame.ConditionSpec{Field: g.field(), Operator: op, IsNull: true}
+	case 1:
+		cond := edamame.ConditionSpec{Field: g.field(), LowParam: g.param(), HighParam: g.param()}
+		if g.chance(2) {
+			cond.NotBetween = true
+		} else {
+			cond.Between = true
+		}
+		return cond
+	default:
+		return edamame.ConditionSpec{
+			Field:    g.field(),
+			Operator: comparisonOperators[g.rng.Intn(len(comparisonOperators))],
+			Param:    g.param(),
+		}
+	}
+}
+
+func (g *specGen) orderBy() []edamame.OrderBySpec {
+	n := g.rng.Intn(3)
+	orderBy := make([]edamame.OrderBySpec, 0, n)
+	for i := 0; i < n; i++ {
+		o := edamame.OrderBySpec{Field: g.field(), Direction: "asc"}
+		if g.chance(2) {
+			o.Direction = "desc"
+		}
+		if g.chance(3) {
+			o.Nulls = "last"
+			if g.chance(2) {
+				o.Nulls = "first"
+			}
+		}
+		orderBy = append(orderBy, o)
+	}
+	return orderBy
+}
+
+// pagination returns either a literal value, a parameter name, or neither.
+func (g *specGen) pagination(name string) (*int, string) {
+	switch g.rng.Intn(3) {
+	case 0:
+		n := g.rng.Intn(1000)
+		return &n, ""
+	case 1:
+		return nil, name
+	default:
+		return nil, ""
+	}
+}
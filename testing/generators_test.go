@@ -0,0 +1,150 @@
+package testing
+
+import (
+	"math/rand"
+	"regexp"
+	"sort"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/postgres"
+	"github.com/zoobzio/edamame"
+)
+
+// fuzzUser is the model random specs are rendered against.
+type fuzzUser struct {
+	ID    int    `db:"id" type:"integer" constraints:"primarykey"`
+	Email string `db:"email" type:"text"`
+	Name  string `db:"name" type:"text"`
+	Age   *int   `db:"age" type:"integer"`
+}
+
+var fuzzConfig = SpecGenConfig{
+	Fields:   []string{"id", "email", "name", "age"},
+	MaxDepth: 1,
+}
+
+func newFuzzExecutor(t *testing.T) *edamame.Executor[fuzzUser] {
+	t.Helper()
+	exec, err := edamame.New[fuzzUser](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	return exec
+}
+
+// placeholderPattern matches the named placeholders in rendered SQL, skipping casts.
+var placeholderPattern = regexp.MustCompile(`(?:^|[^:]):([A-Za-z_][A-Za-z0-9_]*)`)
+
+// assertParamsMatch checks that derived params and the placeholders in the rendered SQL name the same set.
+func assertParamsMatch(t *testing.T, derived []edamame.ParamSpec, sql string) {
+	t.Helper()
+	want := make(map[string]bool, len(derived))
+	for _, p := range derived {
+		want[p.Name] = true
+	}
+	got := make(map[string]bool)
+	for _, m := range placeholderPattern.FindAllStringSubmatch(sql, -1) {
+		got[m[1]] = true
+	}
+	if len(want) != len(got) {
+		t.Fatalf("derived params %v do not match rendered params %v\nSQL: %s", keys(want), keys(got), sql)
+	}
+	for p := range want {
+		if !got[p] {
+			t.Fatalf("derived param %q not rendered\nSQL: %s", p, sql)
+		}
+	}
+}
+
+func keys(m map[string]bool) []string {
+	result := make([]string, 0, len(m))
+	for k := range m {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func addSeeds(f *testing.F) {
+	for seed := int64(0); seed < 64; seed++ {
+		f.Add(seed)
+	}
+}
+
+func FuzzRandomQuerySpec(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		exec := newFuzzExecutor(t)
+		stmt := edamame.NewQueryStatement("fuzz", "Random query", RandomQuerySpec(rand.New(rand.NewSource(seed)), fuzzConfig))
+
+		sql, err := exec.RenderQuery(stmt)
+		if err != nil {
+			t.Fatalf("RenderQuery() failed for seed %d: %v", seed, err)
+		}
+		assertParamsMatch(t, stmt.Params(), sql)
+	})
+}
+
+func FuzzRandomSelectSpec(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		exec := newFuzzExecutor(t)
+		stmt := edamame.NewSelectStatement("fuzz", "Random select", RandomSelectSpec(rand.New(rand.NewSource(seed)), fuzzConfig))
+
+		sql, err := exec.RenderSelect(stmt)
+		if err != nil {
+			t.Fatalf("RenderSelect() failed for seed %d: %v", seed, err)
+		}
+		assertParamsMatch(t, stmt.Params(), sql)
+	})
+}
+
+func FuzzRandomUpdateSpec(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		exec := newFuzzExecutor(t)
+		stmt := edamame.NewUpdateStatement("fuzz", "Random update", RandomUpdateSpec(rand.New(rand.NewSource(seed)), fuzzConfig))
+
+		sql, err := exec.RenderUpdate(stmt)
+		if err != nil {
+			t.Fatalf("RenderUpdate() failed for seed %d: %v", seed, err)
+		}
+		assertParamsMatch(t, stmt.Params(), sql)
+	})
+}
+
+func FuzzRandomDeleteSpec(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		exec := newFuzzExecutor(t)
+		stmt := edamame.NewDeleteStatement("fuzz", "Random delete", RandomDeleteSpec(rand.New(rand.NewSource(seed)), fuzzConfig))
+
+		sql, err := exec.RenderDelete(stmt)
+		if err != nil {
+			t.Fatalf("RenderDelete() failed for seed %d: %v", seed, err)
+		}
+		assertParamsMatch(t, stmt.Params(), sql)
+	})
+}
+
+func FuzzRandomAggregateSpec(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		exec := newFuzzExecutor(t)
+		stmt := edamame.NewAggregateStatement("fuzz", "Random sum", edamame.AggSum, RandomAggregateSpec(rand.New(rand.NewSource(seed)), fuzzConfig))
+
+		sql, err := exec.RenderAggregate(stmt)
+		if err != nil {
+			t.Fatalf("RenderAggregate() failed for seed %d: %v", seed, err)
+		}
+		assertParamsMatch(t, stmt.Params(), sql)
+	})
+}
+
+func TestRandomQuerySpecDeterministic(t *testing.T) {
+	a := RandomQuerySpec(rand.New(rand.NewSource(42)), fuzzConfig)
+	b := RandomQuerySpec(rand.New(rand.NewSource(42)), fuzzConfig)
+	if len(a.Where) != len(b.Where) || len(a.OrderBy) != len(b.OrderBy) {
+		t.Error("same seed should produce the same spec")
+	}
+}
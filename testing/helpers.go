@@ -107,6 +107,23 @@ func (qc *QueryCapture) ByStatement(statement string) []RenderedQuery {
 	return result
 }
 
+// Handler returns an EventCallback that captures edamame.QueryRendered events.
+// Hook it to record the SQL of every statement an executor dispatches.
+func (qc *QueryCapture) Handler() capitan.EventCallback {
+	return func(_ context.Context, e *capitan.Event) {
+		if e.Signal() != edamame.QueryRendered {
+			return
+		}
+
+		statement, _ := edamame.KeyStatement.From(e)
+		queryType, _ := edamame.KeyType.From(e)
+		sql, _ := edamame.KeySQL.From(e)
+		params, _ := edamame.KeyParams.From(e)
+
+		qc.CaptureQuery(statement, queryType, sql, params)
+	}
+}
+
 // ExecutorEventCapture captures executor creation events.
 // Thread-safe for concurrent capture.
 type ExecutorEventCapture struct {
@@ -121,6 +121,53 @@ func TestQueryCaptureConcurrent(t *testing.T) {
 	}
 }
 
+func TestQueryCaptureHandler(t *testing.T) {
+	c := capitan.New(capitan.WithSyncMode())
+	defer c.Shutdown()
+
+	capture := NewQueryCapture()
+	c.Hook(edamame.QueryRendered, capture.Handler())
+
+	c.Emit(context.Background(), edamame.QueryRendered,
+		edamame.KeyStatement.Field("get-user"),
+		edamame.KeyType.Field("select"),
+		edamame.KeySQL.Field(`SELECT "id" FROM "users" WHERE "id" = :id`),
+		edamame.KeyParams.Field(map[string]any{"id": 1}),
+	)
+
+	if capture.Count() != 1 {
+		t.Fatalf("expected 1 query, got %d", capture.Count())
+	}
+
+	last := capture.Last()
+	if last.Statement != "get-user" {
+		t.Errorf("expected statement 'get-user', got %q", last.Statement)
+	}
+	if last.Type != "select" {
+		t.Errorf("expected type 'select', got %q", last.Type)
+	}
+	if last.SQL != `SELECT "id" FROM "users" WHERE "id" = :id` {
+		t.Errorf("unexpected SQL: %s", last.SQL)
+	}
+	if last.Params["id"] != 1 {
+		t.Errorf("expected id=1, got %v", last.Params["id"])
+	}
+}
+
+func TestQueryCaptureHandlerIgnoresOtherSignals(t *testing.T) {
+	c := capitan.New(capitan.WithSyncMode())
+	defer c.Shutdown()
+
+	capture := NewQueryCapture()
+	c.Hook(edamame.ExecutorCreated, capture.Handler())
+
+	c.Emit(context.Background(), edamame.ExecutorCreated, edamame.KeyTable.Field("users"))
+
+	if capture.Count() != 0 {
+		t.Errorf("expected 0 queries, got %d", capture.Count())
+	}
+}
+
 func TestExecutorEventCapture(t *testing.T) {
 	c := capitan.New(capitan.WithSyncMode())
 	defer c.Shutdown()
@@ -8,17 +8,26 @@
 package integration
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
 	"github.com/zoobzio/astql/pkg/postgres"
+	"github.com/zoobzio/capitan"
 	"github.com/zoobzio/edamame"
+	edamametesting "github.com/zoobzio/edamame/testing"
 )
 
 // User is a test model for integration tests.
@@ -109,6 +118,11 @@ func NewPostgresContainer(ctx context.Context) (*PostgresContainer, error) {
 	}, nil
 }
 
+// DSN returns the connection string for the container's database.
+func (pc *PostgresContainer) DSN() string {
+	return fmt.Sprintf("host=%s port=%s user=test password=test dbname=testdb sslmode=disable", pc.host, pc.port)
+}
+
 // DB returns the database connection.
 func (pc *PostgresContainer) DB() *sqlx.DB {
 	return pc.db
@@ -253,6 +267,51 @@ func TestPostgresIntegration_SelectByPrimaryKey(t *testing.T) {
 	}
 }
 
+func TestPostgresIntegration_SelectOrDefault(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+
+	id, err := pg.InsertTestUser(ctx, "alice@test.com", "Alice", nil)
+	if err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+
+	factory, err := edamame.New[User](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+	fallback := &User{Name: "Nobody"}
+
+	user, err := factory.ExecSelectOrDefault(ctx, selectByID, map[string]any{"id": id}, fallback)
+	if err != nil {
+		t.Fatalf("failed to execute select: %v", err)
+	}
+	if user.Name != "Alice" {
+		t.Errorf("expected name 'Alice', got %q", user.Name)
+	}
+
+	user, err = factory.ExecSelectOrDefault(ctx, selectByID, map[string]any{"id": id + 1000}, fallback)
+	if err != nil {
+		t.Fatalf("expected fallback without error, got %v", err)
+	}
+	if user != fallback {
+		t.Errorf("expected the fallback, got %+v", user)
+	}
+
+	if _, err := factory.ExecSelect(ctx, selectByID, map[string]any{"id": id + 1000}); !errors.Is(err, edamame.ErrNotFound) {
+		t.Errorf("expected ErrNotFound from ExecSelect, got %v", err)
+	}
+}
+
 func TestPostgresIntegration_Insert(t *testing.T) {
 	ctx := context.Background()
 
@@ -531,3 +590,2604 @@ func TestPostgresIntegration_BatchInsert(t *testing.T) {
 		t.Errorf("expected total count 10, got %f", totalCount)
 	}
 }
+
+func TestPostgresIntegration_QueryCapture(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+
+	capture := edamametesting.NewQueryCapture()
+	listener := capitan.Hook(edamame.QueryRendered, capture.Handler())
+	defer listener.Close()
+
+	factory, err := edamame.New[User](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+
+	if _, err := factory.ExecSelect(ctx, selectByID, map[string]any{"id": 1}); err == nil {
+		t.Fatal("expected error selecting from empty table")
+	}
+
+	if err := listener.Drain(ctx); err != nil {
+		t.Fatalf("failed to drain QueryRendered events: %v", err)
+	}
+
+	captured := capture.ByStatement(selectByID.Name())
+	if len(captured) != 1 {
+		t.Fatalf("expected 1 captured query, got %d", len(captured))
+	}
+	if captured[0].Type != "select" {
+		t.Errorf("expected type 'select', got %q", captured[0].Type)
+	}
+	if captured[0].SQL == "" {
+		t.Error("expected captured SQL")
+	}
+	if captured[0].Params["id"] != 1 {
+		t.Errorf("expected id=1, got %v", captured[0].Params["id"])
+	}
+}
+
+// AuditedUser is a model with a timestamp column for RETURNING projections.
+type AuditedUser struct {
+	ID        int       `db:"id" type:"integer" constraints:"primarykey"`
+	Email     string    `db:"email" type:"text"`
+	CreatedAt time.Time `db:"created_at" type:"timestamptz"`
+}
+
+func TestPostgresIntegration_InsertReturningInto(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	_, err = pg.DB().ExecContext(ctx, `
+		CREATE TABLE audited_users (
+			id SERIAL PRIMARY KEY,
+			email TEXT NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL
+		)
+	`)
+	if err != nil {
+		t.Fatalf("failed to create audited_users table: %v", err)
+	}
+
+	factory, err := edamame.New[AuditedUser](pg.DB(), "audited_users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+
+	created := time.Date(2025, 3, 14, 9, 26, 53, 0, time.UTC)
+	var audit struct {
+		ID        int       `db:"id"`
+		CreatedAt time.Time `db:"created_at"`
+	}
+	err = edamame.ExecInsertReturningInto(ctx, factory,
+		&AuditedUser{Email: "audit@test.com", CreatedAt: created},
+		[]string{"id", "created_at"}, &audit)
+	if err != nil {
+		t.Fatalf("failed to insert returning into: %v", err)
+	}
+
+	if audit.ID == 0 {
+		t.Error("expected generated ID")
+	}
+	if !audit.CreatedAt.Equal(created) {
+		t.Errorf("expected created_at %v, got %v", created, audit.CreatedAt)
+	}
+}
+
+// TicketStatus is a custom enum stored as text via driver.Valuer and sql.Scanner.
+type TicketStatus int
+
+const (
+	TicketOpen TicketStatus = iota + 1
+	TicketClosed
+)
+
+var ticketStatusNames = map[TicketStatus]string{TicketOpen: "open", TicketClosed: "closed"}
+
+// Value implements driver.Valuer.
+func (s TicketStatus) Value() (driver.Value, error) {
+	name, ok := ticketStatusNames[s]
+	if !ok {
+		return nil, fmt.Errorf("invalid ticket status %d", s)
+	}
+	return name, nil
+}
+
+// Scan implements sql.Scanner.
+func (s *TicketStatus) Scan(src any) error {
+	var name string
+	switch v := src.(type) {
+	case string:
+		name = v
+	case []byte:
+		name = string(v)
+	default:
+		return fmt.Errorf("unsupported ticket status source %T", src)
+	}
+	for status, n := range ticketStatusNames {
+		if n == name {
+			*s = status
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown ticket status %q", name)
+}
+
+// Ticket is a test model with a custom enum column.
+type Ticket struct {
+	ID     int          `db:"id" type:"integer" constraints:"primarykey"`
+	Title  string       `db:"title" type:"text"`
+	Status TicketStatus `db:"status" type:"text"`
+}
+
+func TestPostgresIntegration_CustomValuerTypes(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	_, err = pg.DB().ExecContext(ctx, `
+		CREATE TABLE tickets (
+			id SERIAL PRIMARY KEY,
+			title TEXT NOT NULL,
+			status TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		t.Fatalf("failed to create tickets table: %v", err)
+	}
+
+	factory, err := edamame.New[Ticket](pg.DB(), "tickets", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+
+	for _, ticket := range []*Ticket{
+		{Title: "first", Status: TicketOpen},
+		{Title: "second", Status: TicketClosed},
+		{Title: "third", Status: TicketOpen},
+	} {
+		if _, err := factory.ExecInsert(ctx, ticket); err != nil {
+			t.Fatalf("failed to insert ticket: %v", err)
+		}
+	}
+
+	var raw string
+	if err := pg.DB().GetContext(ctx, &raw, `SELECT status FROM tickets WHERE title = 'second'`); err != nil {
+		t.Fatalf("failed to read raw status: %v", err)
+	}
+	if raw != "closed" {
+		t.Errorf("expected status stored as 'closed', got %q", raw)
+	}
+
+	byStatus := edamame.NewQueryStatement("tickets-by-status", "Tickets by status", edamame.QuerySpec{
+		Where:   []edamame.ConditionSpec{{Field: "status", Operator: "=", Param: "status"}},
+		OrderBy: []edamame.OrderBySpec{{Field: "id", Direction: "asc"}},
+	})
+
+	open, err := factory.ExecQuery(ctx, byStatus, map[string]any{"status": TicketOpen})
+	if err != nil {
+		t.Fatalf("failed to query by status: %v", err)
+	}
+	if len(open) != 2 {
+		t.Fatalf("expected 2 open tickets, got %d", len(open))
+	}
+	for _, ticket := range open {
+		if ticket.Status != TicketOpen {
+			t.Errorf("expected status %d, got %d", TicketOpen, ticket.Status)
+		}
+	}
+}
+
+func TestPostgresIntegration_QueryCursor(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+
+	const total = 1000
+	_, err = pg.DB().ExecContext(ctx, `
+		INSERT INTO users (email, name, age)
+		SELECT 'user' || n || '@test.com', 'User' || n, n % 80
+		FROM generate_series(1, $1) AS n
+	`, total)
+	if err != nil {
+		t.Fatalf("failed to insert users: %v", err)
+	}
+
+	factory, err := edamame.New[User](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+
+	cur, err := factory.ExecQueryCursor(ctx, queryAdults, map[string]any{"min_age": 0}, 7)
+	if err != nil {
+		t.Fatalf("failed to open cursor: %v", err)
+	}
+	defer cur.Close()
+
+	count := 0
+	lastAge := -1
+	for cur.Next() {
+		user := cur.Record()
+		if user.Age == nil || *user.Age < lastAge {
+			t.Fatalf("rows out of order at %d", count)
+		}
+		lastAge = *user.Age
+		count++
+	}
+	if err := cur.Err(); err != nil {
+		t.Fatalf("cursor failed: %v", err)
+	}
+	if count != total {
+		t.Errorf("expected %d rows, got %d", total, count)
+	}
+
+	if err := cur.Close(); err != nil {
+		t.Errorf("failed to close cursor: %v", err)
+	}
+}
+
+func TestPostgresIntegration_QueryChan(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+
+	const total = 250
+	_, err = pg.DB().ExecContext(ctx, `
+		INSERT INTO users (email, name, age)
+		SELECT 'user' || n || '@test.com', 'User' || n, n % 80
+		FROM generate_series(1, $1) AS n
+	`, total)
+	if err != nil {
+		t.Fatalf("failed to insert users: %v", err)
+	}
+
+	factory, err := edamame.New[User](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+
+	t.Run("all rows arrive", func(t *testing.T) {
+		records, errs := factory.ExecQueryChan(ctx, queryAdults, map[string]any{"min_age": 0})
+
+		seen := make(map[int]bool, total)
+		for user := range records {
+			seen[user.ID] = true
+		}
+		if err := <-errs; err != nil {
+			t.Fatalf("stream failed: %v", err)
+		}
+		if len(seen) != total {
+			t.Errorf("expected %d distinct rows, got %d", total, len(seen))
+		}
+	})
+
+	t.Run("cancel closes channels", func(t *testing.T) {
+		cctx, cancel := context.WithCancel(ctx)
+		records, errs := factory.ExecQueryChan(cctx, queryAdults, map[string]any{"min_age": 0})
+
+		if _, ok := <-records; !ok {
+			t.Fatal("expected at least one record")
+		}
+		cancel()
+
+		for range records { //nolint:revive // drain the remaining records
+		}
+		if err := <-errs; !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	})
+}
+
+// Document is a soft-deletable model.
+type Document struct {
+	ID        int        `db:"id" type:"integer" constraints:"primarykey"`
+	Title     string     `db:"title" type:"text"`
+	DeletedAt *time.Time `db:"deleted_at" type:"timestamptz"`
+}
+
+var (
+	documentByID = edamame.NewSelectStatement("document-by-id", "Document by id", edamame.SelectSpec{
+		Where: []edamame.ConditionSpec{{Field: "id", Operator: "=", Param: "id"}},
+	})
+	softDeleteDocument = edamame.NewUpdateStatement("soft-delete-document", "Mark a document deleted", edamame.UpdateSpec{
+		Set:   map[string]string{"deleted_at": "deleted_at"},
+		Where: []edamame.ConditionSpec{{Field: "id", Operator: "=", Param: "id"}},
+	})
+	renameDocument = edamame.NewUpdateStatement("rename-document", "Rename a document", edamame.UpdateSpec{
+		Set:   map[string]string{"title": "title"},
+		Where: []edamame.ConditionSpec{{Field: "id", Operator: "=", Param: "id"}},
+	})
+	countDocuments = edamame.NewAggregateStatement("count-documents", "Count documents", edamame.AggCount, edamame.AggregateSpec{})
+	deleteDocument = edamame.NewDeleteStatement("delete-document", "Delete a document", edamame.DeleteSpec{
+		Where: []edamame.ConditionSpec{{Field: "id", Operator: "=", Param: "id"}},
+	})
+	countAllDocuments = edamame.NewAggregateStatement("count-all-documents", "Count documents, including deleted", edamame.AggCount, edamame.AggregateSpec{
+		WithTrashed: true,
+	})
+)
+
+func TestPostgresIntegration_SoftDelete(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	_, err = pg.DB().ExecContext(ctx, `
+		CREATE TABLE documents (
+			id SERIAL PRIMARY KEY,
+			title TEXT NOT NULL,
+			deleted_at TIMESTAMPTZ
+		)
+	`)
+	if err != nil {
+		t.Fatalf("failed to create documents table: %v", err)
+	}
+
+	factory, err := edamame.New[Document](pg.DB(), "documents", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+	if err := factory.EnableSoftDelete("deleted_at"); err != nil {
+		t.Fatalf("failed to enable soft delete: %v", err)
+	}
+
+	doc, err := factory.ExecInsert(ctx, &Document{Title: "draft"})
+	if err != nil {
+		t.Fatalf("failed to insert document: %v", err)
+	}
+	byID := map[string]any{"id": doc.ID}
+
+	_, err = factory.ExecUpdate(ctx, softDeleteDocument, map[string]any{"id": doc.ID, "deleted_at": time.Now()})
+	if err != nil {
+		t.Fatalf("failed to soft delete document: %v", err)
+	}
+
+	if _, err := factory.ExecSelect(ctx, documentByID, byID); err == nil {
+		t.Error("select should not see a soft-deleted document")
+	}
+	count, err := factory.ExecAggregate(ctx, countDocuments, nil)
+	if err != nil {
+		t.Fatalf("failed to count documents: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("expected count 0, got %v", count)
+	}
+
+	updated, err := factory.ExecUpdate(ctx, renameDocument, map[string]any{"id": doc.ID, "title": "archived"})
+	if err != nil {
+		t.Fatalf("update should reach a soft-deleted document: %v", err)
+	}
+	if updated.Title != "archived" || updated.DeletedAt == nil {
+		t.Errorf("unexpected updated document: %+v", updated)
+	}
+
+	restored, err := factory.ExecRestore(ctx, doc.ID)
+	if err != nil {
+		t.Fatalf("failed to restore document: %v", err)
+	}
+	if restored.DeletedAt != nil {
+		t.Errorf("expected deleted_at to be cleared, got %v", restored.DeletedAt)
+	}
+
+	found, err := factory.ExecSelect(ctx, documentByID, byID)
+	if err != nil {
+		t.Fatalf("restored document should be visible: %v", err)
+	}
+	if found.Title != "archived" {
+		t.Errorf("expected title archived, got %q", found.Title)
+	}
+
+	n, err := factory.ExecSoftDelete(ctx, deleteDocument, byID)
+	if err != nil {
+		t.Fatalf("failed to soft delete document: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("expected 1 row soft-deleted, got %d", n)
+	}
+	if n, err := factory.ExecSoftDelete(ctx, deleteDocument, byID); err != nil || n != 0 {
+		t.Errorf("expected an already deleted document to be skipped, got %d, %v", n, err)
+	}
+	count, err = factory.ExecAggregate(ctx, countAllDocuments, nil)
+	if err != nil {
+		t.Fatalf("failed to count documents with trashed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected the trashed count to include the deleted document, got %v", count)
+	}
+}
+
+func TestPostgresIntegration_OrderByCase(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+
+	factory, err := edamame.New[User](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+
+	for _, name := range []string{"closed", "unknown", "active", "pending"} {
+		if _, err := factory.ExecInsert(ctx, &User{Email: name + "@test.com", Name: name}); err != nil {
+			t.Fatalf("failed to insert user: %v", err)
+		}
+	}
+
+	byStatus := edamame.NewQueryStatement("by-status", "Users in status order", edamame.QuerySpec{
+		OrderBy: []edamame.OrderBySpec{edamame.OrderByCase("name", "pending", "active", "closed")},
+	})
+	users, err := factory.ExecQuery(ctx, byStatus, nil)
+	if err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+
+	want := []string{"pending", "active", "closed", "unknown"}
+	if len(users) != len(want) {
+		t.Fatalf("expected %d users, got %d", len(want), len(users))
+	}
+	for i, name := range want {
+		if users[i].Name != name {
+			t.Errorf("position %d: expected %q, got %q", i, name, users[i].Name)
+		}
+	}
+}
+
+func TestPostgresIntegration_QueryByKeys(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+
+	_, err = pg.DB().ExecContext(ctx, `
+		INSERT INTO users (email, name, age)
+		SELECT 'user' || n || '@test.com', 'User' || n, n % 80
+		FROM generate_series(1, 10000) AS n
+	`)
+	if err != nil {
+		t.Fatalf("failed to insert users: %v", err)
+	}
+
+	factory, err := edamame.New[User](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+
+	// Every third id, plus ids that do not exist and a duplicate.
+	keys := make([]any, 0, 4000)
+	for id := 3; id <= 12000; id += 3 {
+		keys = append(keys, id)
+	}
+	keys = append(keys, 3)
+
+	t.Run("large key array", func(t *testing.T) {
+		users, err := factory.ExecQueryByKeys(ctx, queryAdults, "id", keys, map[string]any{"min_age": 0})
+		if err != nil {
+			t.Fatalf("failed to query by keys: %v", err)
+		}
+		if len(users) != 3333 {
+			t.Errorf("expected 3333 users, got %d", len(users))
+		}
+		for _, u := range users {
+			if u.ID%3 != 0 {
+				t.Fatalf("unexpected user %d", u.ID)
+			}
+		}
+	})
+
+	t.Run("small key array", func(t *testing.T) {
+		users, err := factory.ExecQueryByKeys(ctx, queryAdults, "id", []any{1, 2, 20000}, map[string]any{"min_age": 0})
+		if err != nil {
+			t.Fatalf("failed to query by keys: %v", err)
+		}
+		if len(users) != 2 {
+			t.Errorf("expected 2 users, got %d", len(users))
+		}
+	})
+}
+
+func TestPostgresIntegration_SQLComments(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+
+	factory, err := edamame.New[User](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+	factory.SetSQLComments(true)
+
+	// Every statement type must still be valid SQL with the trailing comment.
+	user, err := factory.ExecInsert(ctx, &User{Email: "tagged@test.com", Name: "Tagged"})
+	if err != nil {
+		t.Fatalf("insert failed: %v", err)
+	}
+	if _, err := factory.ExecSelect(ctx, selectByID, map[string]any{"id": user.ID}); err != nil {
+		t.Fatalf("select failed: %v", err)
+	}
+	if users, err := factory.ExecQuery(ctx, queryAdults, map[string]any{"min_age": 0}); err != nil {
+		t.Fatalf("query failed: %v", err)
+	} else if len(users) != 0 {
+		t.Errorf("expected no users with an age, got %d", len(users))
+	}
+	if count, err := factory.ExecAggregate(ctx, countAll, nil); err != nil || count != 1 {
+		t.Fatalf("aggregate failed: %v, %v", count, err)
+	}
+	tx, err := pg.DB().BeginTxx(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if count, err := factory.ExecAggregateIntTx(ctx, tx, countAll, nil); err != nil || count != 1 {
+		t.Fatalf("aggregate in transaction failed: %v, %v", count, err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+
+	records, errs := factory.ExecQueryChan(ctx, queryAll, nil)
+	for range records { //nolint:revive // drain the stream
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("cursor query failed: %v", err)
+	}
+
+	if deleted, err := factory.ExecDelete(ctx, deleteByID, map[string]any{"id": user.ID}); err != nil || deleted != 1 {
+		t.Fatalf("delete failed: %v, %v", deleted, err)
+	}
+}
+
+func TestPostgresIntegration_UpdatePartial(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+
+	factory, err := edamame.New[User](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+
+	age := 41
+	user, err := factory.ExecInsert(ctx, &User{Email: "partial@test.com", Name: "Before", Age: &age})
+	if err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+
+	// Stale values in other fields must not be written.
+	stale := &User{ID: user.ID, Email: "stale@test.com", Name: "After"}
+	updated, err := factory.ExecUpdatePartial(ctx, stale, []string{"name"})
+	if err != nil {
+		t.Fatalf("failed to update partially: %v", err)
+	}
+
+	if updated.Name != "After" {
+		t.Errorf("expected name After, got %q", updated.Name)
+	}
+	if updated.Email != "partial@test.com" {
+		t.Errorf("email should be untouched, got %q", updated.Email)
+	}
+	if updated.Age == nil || *updated.Age != 41 {
+		t.Errorf("age should be untouched, got %v", updated.Age)
+	}
+}
+
+// SyncedUser is a model replicated with last-write-wins semantics.
+type SyncedUser struct {
+	ID        int       `db:"id" type:"integer" constraints:"primarykey"`
+	Email     string    `db:"email" type:"text" constraints:"notnull,unique"`
+	Name      string    `db:"name" type:"text"`
+	UpdatedAt time.Time `db:"updated_at" type:"timestamptz"`
+}
+
+func TestPostgresIntegration_LastWriteWinsUpsert(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	_, err = pg.DB().ExecContext(ctx, `
+		CREATE TABLE synced_users (
+			id SERIAL PRIMARY KEY,
+			email TEXT NOT NULL UNIQUE,
+			name TEXT NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL
+		)
+	`)
+	if err != nil {
+		t.Fatalf("failed to create synced_users table: %v", err)
+	}
+
+	factory, err := edamame.New[SyncedUser](pg.DB(), "synced_users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+	if err := factory.SetLastWriteWinsUpsert("updated_at", "email"); err != nil {
+		t.Fatalf("failed to configure upsert: %v", err)
+	}
+
+	t0 := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	stored, inserted, err := factory.ExecUpsert(ctx, &SyncedUser{Email: "sync@test.com", Name: "v2", UpdatedAt: t0})
+	if err != nil || stored == nil {
+		t.Fatalf("initial upsert should insert: stored=%v err=%v", stored, err)
+	}
+	if !inserted {
+		t.Error("a new row should be reported as inserted")
+	}
+
+	// An older write loses.
+	kept, inserted, err := factory.ExecUpsert(ctx, &SyncedUser{Email: "sync@test.com", Name: "v1", UpdatedAt: t0.Add(-time.Hour)})
+	if err != nil {
+		t.Fatalf("stale upsert failed: %v", err)
+	}
+	if kept != nil || inserted {
+		t.Errorf("an older incoming row should not be applied: stored=%+v inserted=%v", kept, inserted)
+	}
+
+	var name string
+	if err := pg.DB().GetContext(ctx, &name, "SELECT name FROM synced_users WHERE id = $1", stored.ID); err != nil {
+		t.Fatalf("failed to read stored row: %v", err)
+	}
+	if name != "v2" {
+		t.Errorf("newer stored row was overwritten: name=%q", name)
+	}
+
+	// A newer write wins, updating the conflicting row.
+	updated, inserted, err := factory.ExecUpsert(ctx, &SyncedUser{Email: "sync@test.com", Name: "v3", UpdatedAt: t0.Add(time.Hour)})
+	if err != nil || updated == nil {
+		t.Fatalf("newer upsert should apply: stored=%v err=%v", updated, err)
+	}
+	if inserted {
+		t.Error("a conflicting row should be reported as updated")
+	}
+	if updated.ID != stored.ID || updated.Name != "v3" {
+		t.Errorf("unexpected updated row: %+v", updated)
+	}
+}
+
+func TestPostgresIntegration_UpsertRecord(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	_, err = pg.DB().ExecContext(ctx, `
+		CREATE TABLE synced_users (
+			id SERIAL PRIMARY KEY,
+			email TEXT NOT NULL UNIQUE,
+			name TEXT NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL
+		)
+	`)
+	if err != nil {
+		t.Fatalf("failed to create synced_users table: %v", err)
+	}
+
+	factory, err := edamame.New[SyncedUser](pg.DB(), "synced_users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+
+	t0 := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	conflict, update := []string{"email"}, []string{"name"}
+
+	inserted, err := factory.ExecUpsertRecord(ctx, &SyncedUser{Email: "upsert@test.com", Name: "v1", UpdatedAt: t0}, conflict, update)
+	if err != nil {
+		t.Fatalf("initial upsert failed: %v", err)
+	}
+	if inserted.ID == 0 || inserted.Name != "v1" {
+		t.Errorf("unexpected inserted row: %+v", inserted)
+	}
+
+	// A conflicting record updates only the named columns.
+	updated, err := factory.ExecUpsertRecord(ctx, &SyncedUser{Email: "upsert@test.com", Name: "v2", UpdatedAt: t0.Add(time.Hour)}, conflict, update)
+	if err != nil {
+		t.Fatalf("conflicting upsert failed: %v", err)
+	}
+	if updated.ID != inserted.ID || updated.Name != "v2" {
+		t.Errorf("unexpected updated row: %+v", updated)
+	}
+	if !updated.UpdatedAt.Equal(t0) {
+		t.Errorf("updated_at is not an update column and should be kept: %v", updated.UpdatedAt)
+	}
+
+	var count int
+	if err := pg.DB().GetContext(ctx, &count, "SELECT COUNT(*) FROM synced_users"); err != nil {
+		t.Fatalf("failed to count rows: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 row, got %d", count)
+	}
+}
+
+func TestPostgresIntegration_ClaimNext(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+	const workers = 3
+	for i := 0; i < workers; i++ {
+		age := 20 + i
+		if _, err := pg.InsertTestUser(ctx, fmt.Sprintf("claim%d@test.com", i), "Claim", &age); err != nil {
+			t.Fatalf("failed to insert user: %v", err)
+		}
+	}
+
+	factory, err := edamame.New[User](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+
+	// Each worker holds its claim until all have claimed, so no row is released early.
+	claimed := make(chan int, workers)
+	release := make(chan struct{})
+	errs := make(chan error, workers)
+	var wg sync.WaitGroup
+	defer func() {
+		close(release)
+		wg.Wait()
+	}()
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tx, err := pg.DB().BeginTxx(ctx, nil)
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer tx.Rollback()
+
+			user, err := factory.ExecClaimNext(ctx, tx, queryAdults, map[string]any{"min_age": 18})
+			if err != nil {
+				errs <- err
+				return
+			}
+			if user == nil {
+				errs <- errors.New("no row claimed")
+				return
+			}
+			claimed <- user.ID
+			<-release
+		}()
+	}
+
+	seen := make(map[int]bool)
+	for i := 0; i < workers; i++ {
+		select {
+		case id := <-claimed:
+			if seen[id] {
+				t.Errorf("row %d claimed twice", id)
+			}
+			seen[id] = true
+		case err := <-errs:
+			t.Fatalf("worker failed: %v", err)
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for claims")
+		}
+	}
+
+	// With every row locked by a worker, another transaction finds nothing to claim.
+	tx, err := pg.DB().BeginTxx(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	user, err := factory.ExecClaimNext(ctx, tx, queryAdults, map[string]any{"min_age": 18})
+	tx.Rollback()
+	if err != nil {
+		t.Fatalf("claim failed: %v", err)
+	}
+	if user != nil {
+		t.Errorf("expected no row left to claim, got %d", user.ID)
+	}
+}
+
+func TestPostgresIntegration_CountByGroup(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+	young, old := 20, 40
+	seed := []struct {
+		email, name string
+		age         *int
+	}{
+		{"g1@test.com", "admin", &old},
+		{"g2@test.com", "member", &young},
+		{"g3@test.com", "member", &old},
+		{"g4@test.com", "member", &young},
+	}
+	for _, u := range seed {
+		if _, err := pg.InsertTestUser(ctx, u.email, u.name, u.age); err != nil {
+			t.Fatalf("failed to insert user: %v", err)
+		}
+	}
+
+	factory, err := edamame.New[User](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+
+	counts, err := factory.ExecCountByGroup(ctx, "name", nil, nil)
+	if err != nil {
+		t.Fatalf("count by group failed: %v", err)
+	}
+	if len(counts) != 2 || counts["admin"] != 1 || counts["member"] != 3 {
+		t.Errorf("unexpected counts: %v", counts)
+	}
+
+	where := []edamame.ConditionSpec{{Field: "age", Operator: ">=", Param: "min_age"}}
+	counts, err = factory.ExecCountByGroup(ctx, "name", where, map[string]any{"min_age": 30})
+	if err != nil {
+		t.Fatalf("filtered count by group failed: %v", err)
+	}
+	if len(counts) != 2 || counts["admin"] != 1 || counts["member"] != 1 {
+		t.Errorf("unexpected filtered counts: %v", counts)
+	}
+
+	counts, err = factory.ExecCountByGroup(ctx, "age", nil, nil)
+	if err != nil {
+		t.Fatalf("count by age failed: %v", err)
+	}
+	if counts["20"] != 2 || counts["40"] != 2 {
+		t.Errorf("unexpected counts by age: %v", counts)
+	}
+}
+
+func TestPostgresIntegration_GroupedAggregate(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+	young, old := 20, 40
+	seed := []struct {
+		email, name string
+		age         *int
+	}{
+		{"a1@test.com", "admin", &old},
+		{"m1@test.com", "member", &young},
+		{"m2@test.com", "member", &old},
+		{"m3@test.com", "member", nil},
+	}
+	for _, u := range seed {
+		if _, err := pg.InsertTestUser(ctx, u.email, u.name, u.age); err != nil {
+			t.Fatalf("failed to insert user: %v", err)
+		}
+	}
+
+	factory, err := edamame.New[User](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+
+	byName := edamame.AggregateSpec{Field: "age", GroupBy: []string{"name"}}
+	counts, err := factory.ExecGroupedAggregate(ctx, edamame.NewAggregateStatement("count-by-name", "Users per name", edamame.AggCount, byName), nil)
+	if err != nil {
+		t.Fatalf("grouped count failed: %v", err)
+	}
+	if len(counts) != 2 || counts[0].Group["name"] != "admin" || counts[0].Value != 1 || counts[1].Group["name"] != "member" || counts[1].Value != 3 {
+		t.Errorf("unexpected grouped counts: %+v", counts)
+	}
+
+	avgs, err := factory.ExecGroupedAggregate(ctx, edamame.NewAggregateStatement("avg-age-by-name", "Average age per name", edamame.AggAvg, byName), nil)
+	if err != nil {
+		t.Fatalf("grouped avg failed: %v", err)
+	}
+	if len(avgs) != 2 || avgs[0].Value != 40 || avgs[1].Value != 30 {
+		t.Errorf("unexpected grouped averages: %+v", avgs)
+	}
+
+	total, err := factory.ExecAggregate(ctx, edamame.NewAggregateStatement("count", "User count", edamame.AggCount, edamame.AggregateSpec{}), nil)
+	if err != nil {
+		t.Fatalf("ungrouped count failed: %v", err)
+	}
+	if total != 4 {
+		t.Errorf("expected 4 users, got %v", total)
+	}
+}
+
+func TestPostgresIntegration_SchemaQualifiedTable(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if _, err := pg.DB().ExecContext(ctx, `
+		CREATE SCHEMA analytics;
+		CREATE TABLE analytics.users (
+			id SERIAL PRIMARY KEY,
+			email TEXT NOT NULL UNIQUE,
+			name TEXT,
+			age INTEGER
+		)
+	`); err != nil {
+		t.Fatalf("failed to setup analytics.users table: %v", err)
+	}
+
+	factory, err := edamame.New[User](pg.DB(), "analytics.users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+
+	inserted, err := factory.ExecInsert(ctx, &User{Email: "alice@test.com", Name: "Alice"})
+	if err != nil {
+		t.Fatalf("insert failed: %v", err)
+	}
+	user, err := factory.ExecSelect(ctx, selectByID, map[string]any{"id": inserted.ID})
+	if err != nil {
+		t.Fatalf("select failed: %v", err)
+	}
+	if user.Email != "alice@test.com" {
+		t.Errorf("expected alice@test.com, got %q", user.Email)
+	}
+
+	// Tx variants hand the transaction to soy directly.
+	tx, err := pg.DB().BeginTxx(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if _, err := factory.ExecInsertTx(ctx, tx, &User{Email: "bob@test.com", Name: "Bob"}); err != nil {
+		t.Fatalf("insert in transaction failed: %v", err)
+	}
+	users, err := factory.ExecQueryTx(ctx, tx, queryAll, nil)
+	if err != nil {
+		t.Fatalf("query in transaction failed: %v", err)
+	}
+	if len(users) != 2 {
+		t.Errorf("expected 2 users, got %d", len(users))
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit failed: %v", err)
+	}
+
+	var count int
+	if err := pg.DB().GetContext(ctx, &count, `SELECT COUNT(*) FROM analytics.users`); err != nil {
+		t.Fatalf("count failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("expected 2 rows in analytics.users, got %d", count)
+	}
+}
+
+func TestPostgresIntegration_RefreshMaterializedView(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+	if _, err := pg.InsertTestUser(ctx, "a@test.com", "Alice", nil); err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+	if _, err := pg.DB().ExecContext(ctx, `
+		CREATE MATERIALIZED VIEW user_totals AS SELECT COUNT(*) AS total FROM users;
+		CREATE UNIQUE INDEX user_totals_total ON user_totals (total)
+	`); err != nil {
+		t.Fatalf("failed to create materialized view: %v", err)
+	}
+
+	total := func() int {
+		var n int
+		if err := pg.DB().GetContext(ctx, &n, `SELECT total FROM user_totals`); err != nil {
+			t.Fatalf("failed to read materialized view: %v", err)
+		}
+		return n
+	}
+
+	factory, err := edamame.New[User](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+	for _, email := range []string{"b@test.com", "c@test.com"} {
+		if _, err := pg.InsertTestUser(ctx, email, "User", nil); err != nil {
+			t.Fatalf("failed to insert user: %v", err)
+		}
+	}
+	if got := total(); got != 1 {
+		t.Fatalf("expected the stale total 1 before refresh, got %d", got)
+	}
+
+	if err := factory.ExecRefreshMaterializedView(ctx, "user_totals", false); err != nil {
+		t.Fatalf("refresh failed: %v", err)
+	}
+	if got := total(); got != 3 {
+		t.Errorf("expected total 3 after refresh, got %d", got)
+	}
+
+	if _, err := pg.InsertTestUser(ctx, "d@test.com", "User", nil); err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+	if err := factory.ExecRefreshMaterializedView(ctx, "public.user_totals", true); err != nil {
+		t.Fatalf("concurrent refresh failed: %v", err)
+	}
+	if got := total(); got != 4 {
+		t.Errorf("expected total 4 after concurrent refresh, got %d", got)
+	}
+}
+
+func TestPostgresIntegration_Paginate(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+	for i := 0; i < 7; i++ {
+		age := 20 + i
+		if _, err := pg.InsertTestUser(ctx, fmt.Sprintf("page%d@test.com", i), "Page", &age); err != nil {
+			t.Fatalf("failed to insert user: %v", err)
+		}
+	}
+
+	factory, err := edamame.New[User](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+
+	params := map[string]any{"min_age": 18}
+	seen := 0
+	for page := 1; page <= 3; page++ {
+		result, err := factory.ExecPaginate(ctx, queryAdults, params, page, 3)
+		if err != nil {
+			t.Fatalf("page %d failed: %v", page, err)
+		}
+		if result.Total != 7 || result.TotalPages != 3 || result.Page != page || result.PageSize != 3 {
+			t.Errorf("page %d: unexpected metadata %+v", page, result)
+		}
+		for _, u := range result.Items {
+			if want := 20 + seen; *u.Age != want {
+				t.Errorf("page %d: expected age %d, got %d", page, want, *u.Age)
+			}
+			seen++
+		}
+	}
+	if seen != 7 {
+		t.Errorf("expected 7 users across pages, got %d", seen)
+	}
+
+	past, err := factory.ExecPaginate(ctx, queryAdults, params, 4, 3)
+	if err != nil {
+		t.Fatalf("page past the end failed: %v", err)
+	}
+	if len(past.Items) != 0 || past.TotalPages != 3 {
+		t.Errorf("expected empty page past the end, got %+v", past)
+	}
+
+	filtered, err := factory.ExecPaginate(ctx, queryAdults, map[string]any{"min_age": 25}, 1, 3)
+	if err != nil {
+		t.Fatalf("filtered page failed: %v", err)
+	}
+	if filtered.Total != 2 || filtered.TotalPages != 1 || len(filtered.Items) != 2 {
+		t.Errorf("unexpected filtered page: %+v", filtered)
+	}
+}
+
+func TestPostgresIntegration_Truncate(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+
+	factory, err := edamame.New[User](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := pg.InsertTestUser(ctx, fmt.Sprintf("trunc%d@test.com", i), "Trunc", nil); err != nil {
+			t.Fatalf("failed to insert user: %v", err)
+		}
+	}
+
+	// Without RESTART IDENTITY the sequence carries on.
+	if err := factory.ExecTruncate(ctx, edamame.TruncateOpts{Confirm: "users"}); err != nil {
+		t.Fatalf("truncate failed: %v", err)
+	}
+	id, err := pg.InsertTestUser(ctx, "after@test.com", "After", nil)
+	if err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+	if id != 4 {
+		t.Errorf("expected id 4 after plain truncate, got %d", id)
+	}
+
+	if err := factory.ExecTruncate(ctx, edamame.TruncateOpts{Confirm: "users", RestartIdentity: true}); err != nil {
+		t.Fatalf("truncate with restart identity failed: %v", err)
+	}
+	count, err := factory.ExecAggregateInt(ctx, countAll, nil)
+	if err != nil {
+		t.Fatalf("count failed: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("expected empty table, got %d rows", count)
+	}
+	id, err = pg.InsertTestUser(ctx, "reset@test.com", "Reset", nil)
+	if err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+	if id != 1 {
+		t.Errorf("expected sequence reset to id 1, got %d", id)
+	}
+}
+
+// ContactUser reads users with email selected under the contact alias.
+type ContactUser struct {
+	ID      int    `db:"id" type:"integer" constraints:"primarykey"`
+	Email   string `db:"email" type:"text"`
+	Contact string `db:"contact" type:"text"`
+}
+
+func TestPostgresIntegration_FieldAliases(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+	id, err := pg.InsertTestUser(ctx, "alias@test.com", "Alias", nil)
+	if err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+
+	factory, err := edamame.New[ContactUser](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+
+	stmt := edamame.NewSelectStatement("contact-by-id", "User contact by ID", edamame.SelectSpec{
+		Fields:       []string{"id", "email"},
+		FieldAliases: map[string]string{"email": "contact"},
+		Where:        []edamame.ConditionSpec{{Field: "id", Operator: "=", Param: "id"}},
+	})
+	user, err := factory.ExecSelect(ctx, stmt, map[string]any{"id": id})
+	if err != nil {
+		t.Fatalf("select failed: %v", err)
+	}
+	if user.Contact != "alias@test.com" {
+		t.Errorf("expected contact alias@test.com, got %q", user.Contact)
+	}
+	if user.Email != "" {
+		t.Errorf("expected email to be unset, got %q", user.Email)
+	}
+}
+
+func TestPostgresIntegration_QueryProjection(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+	for _, name := range []string{"Alice", "Bob"} {
+		if _, err := pg.InsertTestUser(ctx, name+"@test.com", name, nil); err != nil {
+			t.Fatalf("failed to insert user: %v", err)
+		}
+	}
+
+	factory, err := edamame.New[User](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+
+	type userName struct {
+		ID   int    `db:"id"`
+		Name string `db:"name"`
+	}
+	stmt := edamame.NewQueryStatement("user-names", "User IDs and names", edamame.QuerySpec{
+		Fields:  []string{"id", "name"},
+		OrderBy: []edamame.OrderBySpec{{Field: "id", Direction: "asc"}},
+	})
+	names, err := edamame.ExecQueryProjection[User, userName](ctx, factory, stmt, nil)
+	if err != nil {
+		t.Fatalf("projection failed: %v", err)
+	}
+	if len(names) != 2 || names[0].Name != "Alice" || names[1].Name != "Bob" || names[0].ID == 0 {
+		t.Errorf("unexpected projection: %+v", names)
+	}
+}
+
+func TestPostgresIntegration_QueryFields(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+	age := 30
+	for _, name := range []string{"Alice", "Bob"} {
+		if _, err := pg.InsertTestUser(ctx, name+"@test.com", name, &age); err != nil {
+			t.Fatalf("failed to insert user: %v", err)
+		}
+	}
+
+	factory, err := edamame.New[User](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+	stmt := edamame.NewQueryStatement("users", "All users", edamame.QuerySpec{
+		OrderBy: []edamame.OrderBySpec{{Field: "id", Direction: "asc"}},
+	})
+
+	users, err := factory.ExecQueryFields(ctx, stmt, []string{"email", "name"}, nil)
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if len(users) != 2 || users[0].Email != "Alice@test.com" || users[1].Name != "Bob" {
+		t.Fatalf("unexpected users: %+v", users)
+	}
+	if users[0].ID != 0 || users[0].Age != nil {
+		t.Errorf("expected unrequested fields to be unset, got %+v", users[0])
+	}
+
+	type userContact struct {
+		Email string `db:"email"`
+		Name  string `db:"name"`
+	}
+	narrowed, err := factory.QueryWithFields(stmt, []string{"email", "name"})
+	if err != nil {
+		t.Fatalf("failed to narrow statement: %v", err)
+	}
+	contacts, err := edamame.ExecQueryProjection[User, userContact](ctx, factory, narrowed, nil)
+	if err != nil {
+		t.Fatalf("projection failed: %v", err)
+	}
+	if len(contacts) != 2 || contacts[0] != (userContact{Email: "Alice@test.com", Name: "Alice"}) {
+		t.Errorf("unexpected projection: %+v", contacts)
+	}
+
+	if _, err := factory.ExecQueryFields(ctx, stmt, []string{"email", "password"}, nil); err == nil {
+		t.Error("expected error for a field outside the schema")
+	}
+}
+
+// Visit is a model whose columns are all filled by database defaults.
+type Visit struct {
+	ID        int       `db:"id" type:"serial" constraints:"primarykey"`
+	Status    string    `db:"status" type:"text" constraints:"notnull" default:"'new'"`
+	CreatedAt time.Time `db:"created_at" type:"timestamptz" constraints:"notnull" default:"now()"`
+}
+
+func TestPostgresIntegration_InsertDefaults(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	_, err = pg.DB().ExecContext(ctx, `
+		CREATE TABLE visits (
+			id SERIAL PRIMARY KEY,
+			status TEXT NOT NULL DEFAULT 'new',
+			created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)
+	`)
+	if err != nil {
+		t.Fatalf("failed to create visits table: %v", err)
+	}
+
+	factory, err := edamame.New[Visit](pg.DB(), "visits", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+
+	first, err := factory.ExecInsertDefaults(ctx)
+	if err != nil {
+		t.Fatalf("insert defaults failed: %v", err)
+	}
+	if first.ID == 0 || first.Status != "new" || first.CreatedAt.IsZero() {
+		t.Errorf("expected defaulted row, got %+v", first)
+	}
+
+	tx, err := pg.DB().BeginTxx(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	second, err := factory.ExecInsertDefaultsTx(ctx, tx)
+	if err != nil {
+		t.Fatalf("insert defaults in transaction failed: %v", err)
+	}
+	if second.ID != first.ID+1 {
+		t.Errorf("expected id %d, got %d", first.ID+1, second.ID)
+	}
+}
+
+func TestPostgresIntegration_InCondition(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+	for _, name := range []string{"Alice", "Bob", "Carol"} {
+		if _, err := pg.InsertTestUser(ctx, name+"@test.com", name, nil); err != nil {
+			t.Fatalf("failed to insert user: %v", err)
+		}
+	}
+
+	factory, err := edamame.New[User](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+
+	byNames := edamame.NewQueryStatement("by-names", "Users with any of the names", edamame.QuerySpec{
+		Where:   []edamame.ConditionSpec{{Field: "name", In: true, Param: "names"}},
+		OrderBy: []edamame.OrderBySpec{{Field: "name", Direction: "asc"}},
+	})
+	users, err := factory.ExecQuery(ctx, byNames, map[string]any{"names": pq.Array([]string{"Alice", "Carol"})})
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if len(users) != 2 || users[0].Name != "Alice" || users[1].Name != "Carol" {
+		t.Errorf("expected Alice and Carol, got %+v", users)
+	}
+
+	excluded := edamame.NewDeleteStatement("delete-except", "Delete users not in the names", edamame.DeleteSpec{
+		Where: []edamame.ConditionSpec{{Field: "name", NotIn: true, Param: "names"}},
+	})
+	deleted, err := factory.ExecDelete(ctx, excluded, map[string]any{"names": pq.Array([]string{"Alice"})})
+	if err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("expected 2 deleted users, got %d", deleted)
+	}
+}
+
+func TestPostgresIntegration_QuantifiedCondition(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+	for i, name := range []string{"Alice", "Bob", "Carol"} {
+		age := 20 + i*10
+		if _, err := pg.InsertTestUser(ctx, name+"@test.com", name, &age); err != nil {
+			t.Fatalf("failed to insert user: %v", err)
+		}
+	}
+
+	factory, err := edamame.New[User](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+
+	ages := map[string]any{"ages": pq.Array([]int{25, 35})}
+	olderThan := func(quantifier string) edamame.QueryStatement {
+		return edamame.NewQueryStatement("older-than-"+quantifier, "Users older than the ages", edamame.QuerySpec{
+			Where:   []edamame.ConditionSpec{{Field: "age", Operator: ">", Quantifier: quantifier, Param: "ages"}},
+			OrderBy: []edamame.OrderBySpec{{Field: "name", Direction: "asc"}},
+		})
+	}
+
+	users, err := factory.ExecQuery(ctx, olderThan("any"), ages)
+	if err != nil {
+		t.Fatalf("ANY query failed: %v", err)
+	}
+	if len(users) != 2 || users[0].Name != "Bob" || users[1].Name != "Carol" {
+		t.Errorf("expected Bob and Carol older than any age, got %+v", users)
+	}
+
+	users, err = factory.ExecQuery(ctx, olderThan("all"), ages)
+	if err != nil {
+		t.Fatalf("ALL query failed: %v", err)
+	}
+	if len(users) != 1 || users[0].Name != "Carol" {
+		t.Errorf("expected Carol older than all ages, got %+v", users)
+	}
+}
+
+// Account is a model with a JSONB metadata column.
+type Account struct {
+	ID       int    `db:"id" type:"integer" constraints:"primarykey"`
+	Name     string `db:"name" type:"text"`
+	Metadata []byte `db:"metadata" type:"jsonb"`
+}
+
+func TestPostgresIntegration_JSONPathCondition(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	_, err = pg.DB().ExecContext(ctx, `
+		CREATE TABLE accounts (
+			id SERIAL PRIMARY KEY,
+			name TEXT NOT NULL,
+			metadata JSONB NOT NULL
+		);
+		INSERT INTO accounts (name, metadata) VALUES
+			('acme', '{"tier": "pro", "plan": {"seats": 10, "trial": false}}'),
+			('globex', '{"tier": "free", "plan": {"seats": 1}}'),
+			('initech', '{"tier": "pro", "plan": {"seats": 3}}')
+	`)
+	if err != nil {
+		t.Fatalf("failed to create accounts table: %v", err)
+	}
+
+	factory, err := edamame.New[Account](pg.DB(), "accounts", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+
+	names := func(where []edamame.ConditionSpec, params map[string]any) []string {
+		t.Helper()
+		stmt := edamame.NewQueryStatement("accounts", "Filtered accounts", edamame.QuerySpec{
+			Where:   where,
+			OrderBy: []edamame.OrderBySpec{{Field: "name", Direction: "asc"}},
+		})
+		accounts, err := factory.ExecQuery(ctx, stmt, params)
+		if err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+		out := make([]string, len(accounts))
+		for i, a := range accounts {
+			out[i] = a.Name
+		}
+		return out
+	}
+
+	got := names([]edamame.ConditionSpec{{Field: "metadata", JSONOp: "->>", JSONPath: []string{"tier"}, Operator: "=", Param: "tier"}},
+		map[string]any{"tier": "pro"})
+	if strings.Join(got, ",") != "acme,initech" {
+		t.Errorf("->>: expected acme and initech, got %v", got)
+	}
+
+	got = names([]edamame.ConditionSpec{{Field: "metadata", JSONOp: "#>>", JSONPath: []string{"plan", "seats"}, Operator: "=", Param: "seats"}},
+		map[string]any{"seats": "3"})
+	if strings.Join(got, ",") != "initech" {
+		t.Errorf("#>>: expected initech, got %v", got)
+	}
+
+	got = names([]edamame.ConditionSpec{{Field: "metadata", JSONOp: "@>", Param: "fragment"}},
+		map[string]any{"fragment": `{"tier": "free"}`})
+	if strings.Join(got, ",") != "globex" {
+		t.Errorf("@>: expected globex, got %v", got)
+	}
+
+	got = names([]edamame.ConditionSpec{{Field: "metadata", JSONOp: "?", JSONPath: []string{"plan", "trial"}}}, nil)
+	if strings.Join(got, ",") != "acme" {
+		t.Errorf("?: expected acme, got %v", got)
+	}
+}
+
+// Article is a model with text columns to search.
+type Article struct {
+	ID    int     `db:"id" type:"integer" constraints:"primarykey"`
+	Title string  `db:"title" type:"text"`
+	Body  *string `db:"body" type:"text"`
+}
+
+func TestPostgresIntegration_FullTextCondition(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	_, err = pg.DB().ExecContext(ctx, `
+		CREATE TABLE articles (
+			id SERIAL PRIMARY KEY,
+			title TEXT NOT NULL,
+			body TEXT
+		);
+		INSERT INTO articles (title, body) VALUES
+			('Running databases', 'Notes on tuning postgres'),
+			('Gardening', 'Tomatoes need plenty of sun'),
+			('Untitled', NULL)
+	`)
+	if err != nil {
+		t.Fatalf("failed to create articles table: %v", err)
+	}
+
+	factory, err := edamame.New[Article](pg.DB(), "articles", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+
+	search := edamame.NewQueryStatement("search", "Article search", edamame.QuerySpec{
+		Where:   []edamame.ConditionSpec{{FullText: &edamame.FullTextSpec{Fields: []string{"title", "body"}, Config: "english", Param: "q"}}},
+		OrderBy: []edamame.OrderBySpec{{Field: "id", Direction: "asc"}},
+	})
+	titles := func(q string) []string {
+		t.Helper()
+		articles, err := factory.ExecQuery(ctx, search, map[string]any{"q": q})
+		if err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+		out := make([]string, len(articles))
+		for i, a := range articles {
+			out[i] = a.Title
+		}
+		return out
+	}
+
+	// Stemming matches "run" against the title and "tune" against the body.
+	if got := titles("run tune"); strings.Join(got, ",") != "Running databases" {
+		t.Errorf("expected Running databases, got %v", got)
+	}
+	// A NULL body does not hide a title match.
+	if got := titles("untitled"); strings.Join(got, ",") != "Untitled" {
+		t.Errorf("expected Untitled, got %v", got)
+	}
+	if got := titles("tomato"); strings.Join(got, ",") != "Gardening" {
+		t.Errorf("expected Gardening, got %v", got)
+	}
+}
+
+func TestPostgresIntegration_NotifyOnWrite(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+
+	listener := pq.NewListener(pg.DSN(), time.Second, time.Minute, nil)
+	defer listener.Close()
+	if err := listener.Listen("users_changed"); err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	factory, err := edamame.New[User](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+	if err := factory.SetNotifyOnWrite("users_changed", func(u *User) string { return u.Email }); err != nil {
+		t.Fatalf("failed to set notify on write: %v", err)
+	}
+
+	if _, err := factory.ExecInsert(ctx, &User{Email: "notify@test.com", Name: "Notify"}); err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+
+	select {
+	case n := <-listener.Notify:
+		if n == nil {
+			t.Fatal("listener reconnected before the notification arrived")
+		}
+		if n.Channel != "users_changed" || n.Extra != "notify@test.com" {
+			t.Errorf("unexpected notification: channel %q payload %q", n.Channel, n.Extra)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for notification")
+	}
+
+	// A rolled-back transaction delivers nothing.
+	tx, err := pg.DB().BeginTxx(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	if _, err := factory.ExecInsertTx(ctx, tx, &User{Email: "rolled@test.com", Name: "Rolled"}); err != nil {
+		t.Fatalf("failed to insert user in transaction: %v", err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("failed to roll back: %v", err)
+	}
+	select {
+	case n := <-listener.Notify:
+		t.Errorf("expected no notification after rollback, got %+v", n)
+	case <-time.After(time.Second):
+	}
+}
+
+func TestPostgresIntegration_BulkUpdate(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+
+	factory, err := edamame.New[User](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+
+	const n = 100
+	updates := make([]edamame.RowUpdate, n)
+	for i := range n {
+		id, err := pg.InsertTestUser(ctx, fmt.Sprintf("user%d@test.com", i), "Before", nil)
+		if err != nil {
+			t.Fatalf("failed to insert user: %v", err)
+		}
+		updates[i] = edamame.RowUpdate{Key: id, Set: map[string]any{"name": fmt.Sprintf("After %d", id), "age": id % 90}}
+	}
+
+	updated, err := factory.ExecBulkUpdate(ctx, updates)
+	if err != nil {
+		t.Fatalf("failed to bulk update: %v", err)
+	}
+	if updated != n {
+		t.Errorf("expected %d rows updated, got %d", n, updated)
+	}
+
+	users, err := factory.ExecQuery(ctx, queryAll, nil)
+	if err != nil {
+		t.Fatalf("failed to query users: %v", err)
+	}
+	if len(users) != n {
+		t.Fatalf("expected %d users, got %d", n, len(users))
+	}
+	for _, u := range users {
+		if u.Name != fmt.Sprintf("After %d", u.ID) || u.Age == nil || *u.Age != u.ID%90 {
+			t.Errorf("user %d not updated: name %q, age %v", u.ID, u.Name, u.Age)
+		}
+	}
+}
+
+func TestPostgresIntegration_ExplainCost(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+
+	factory, err := edamame.New[User](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+
+	cost, err := factory.ExplainCost(ctx, queryAll, nil)
+	if err != nil {
+		t.Fatalf("explain failed: %v", err)
+	}
+	if cost <= 0 {
+		t.Errorf("expected a positive cost, got %f", cost)
+	}
+
+	cost, err = factory.ExplainCost(ctx, queryAdults, map[string]any{"min_age": 18})
+	if err != nil {
+		t.Fatalf("explain with params failed: %v", err)
+	}
+	if cost <= 0 {
+		t.Errorf("expected a positive cost, got %f", cost)
+	}
+
+	if _, err := factory.ExplainCost(ctx, deleteByID, map[string]any{"id": 1}); err != nil {
+		t.Fatalf("explain of delete failed: %v", err)
+	}
+}
+
+func TestPostgresIntegration_Diff(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+
+	factory, err := edamame.New[User](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+
+	age := 30
+	if _, err := pg.InsertTestUser(ctx, "same@test.com", "Same", &age); err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+	if _, err := pg.InsertTestUser(ctx, "renamed@test.com", "Before", &age); err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+	if _, err := pg.InsertTestUser(ctx, "aged@test.com", "Aged", nil); err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+
+	incoming := []*User{
+		{Email: "new@test.com", Name: "New", Age: &age},
+		{Email: "same@test.com", Name: "Same", Age: &age},
+		{Email: "renamed@test.com", Name: "After", Age: &age},
+		{Email: "aged@test.com", Name: "Aged", Age: &age},
+	}
+	toInsert, toUpdate, unchanged, err := factory.ExecDiff(ctx, incoming, []string{"email"})
+	if err != nil {
+		t.Fatalf("failed to diff: %v", err)
+	}
+
+	if len(toInsert) != 1 || toInsert[0].Email != "new@test.com" {
+		t.Errorf("expected new@test.com to insert, got %v", toInsert)
+	}
+	if len(toUpdate) != 2 || toUpdate[0].Email != "renamed@test.com" || toUpdate[1].Email != "aged@test.com" {
+		t.Errorf("expected renamed@test.com and aged@test.com to update, got %v", toUpdate)
+	}
+	if len(unchanged) != 1 || unchanged[0].Email != "same@test.com" {
+		t.Errorf("expected same@test.com to be unchanged, got %v", unchanged)
+	}
+}
+
+func TestPostgresIntegration_QueryWithETag(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+
+	factory, err := edamame.New[User](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+
+	age := 30
+	id, err := pg.InsertTestUser(ctx, "alice@test.com", "Alice", &age)
+	if err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+	if _, err := pg.InsertTestUser(ctx, "bob@test.com", "Bob", nil); err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+
+	byID := edamame.NewQueryStatement("all-by-id", "All users by ID", edamame.QuerySpec{
+		OrderBy: []edamame.OrderBySpec{{Field: "id", Direction: "asc"}},
+	})
+	users, etag, err := factory.ExecQueryWithETag(ctx, byID, nil)
+	if err != nil {
+		t.Fatalf("failed to query with ETag: %v", err)
+	}
+	if len(users) != 2 || etag == "" {
+		t.Fatalf("expected 2 users and an ETag, got %d and %q", len(users), etag)
+	}
+
+	_, again, err := factory.ExecQueryWithETag(ctx, byID, nil)
+	if err != nil {
+		t.Fatalf("failed to query with ETag: %v", err)
+	}
+	if again != etag {
+		t.Errorf("expected a stable ETag, got %s then %s", etag, again)
+	}
+
+	if _, err := pg.DB().ExecContext(ctx, `UPDATE users SET name = 'Alicia' WHERE id = $1`, id); err != nil {
+		t.Fatalf("failed to update user: %v", err)
+	}
+	_, changed, err := factory.ExecQueryWithETag(ctx, byID, nil)
+	if err != nil {
+		t.Fatalf("failed to query with ETag: %v", err)
+	}
+	if changed == etag {
+		t.Error("expected the ETag to change after a row changed")
+	}
+}
+
+func TestPostgresIntegration_QueryIter(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+
+	factory, err := edamame.New[User](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+
+	for _, name := range []string{"Alice", "Bob", "Carol"} {
+		if _, err := pg.InsertTestUser(ctx, strings.ToLower(name)+"@test.com", name, nil); err != nil {
+			t.Fatalf("failed to insert user: %v", err)
+		}
+	}
+
+	byName := edamame.NewQueryStatement("all-by-name", "All users by name", edamame.QuerySpec{
+		OrderBy: []edamame.OrderBySpec{{Field: "name", Direction: "asc"}},
+	})
+	seq, err := factory.ExecQueryIter(ctx, byName, nil)
+	if err != nil {
+		t.Fatalf("failed to prepare iterator: %v", err)
+	}
+	var names []string
+	for user, err := range seq {
+		if err != nil {
+			t.Fatalf("iteration failed: %v", err)
+		}
+		names = append(names, user.Name)
+	}
+	if strings.Join(names, ",") != "Alice,Bob,Carol" {
+		t.Errorf("unexpected records: %v", names)
+	}
+
+	// Breaking early closes the rows; the connection is reusable afterwards.
+	for user, err := range seq {
+		if err != nil || user.Name != "Alice" {
+			t.Fatalf("unexpected first record: %v, %v", user, err)
+		}
+		break
+	}
+	if stats := pg.DB().Stats(); stats.InUse != 0 {
+		t.Errorf("expected no connections in use after breaking, got %d", stats.InUse)
+	}
+
+	tx, err := pg.DB().BeginTxx(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	txSeq, err := factory.ExecQueryIterTx(ctx, tx, byName, nil)
+	if err != nil {
+		t.Fatalf("failed to prepare tx iterator: %v", err)
+	}
+	count := 0
+	for _, err := range txSeq {
+		if err != nil {
+			t.Fatalf("tx iteration failed: %v", err)
+		}
+		count++
+	}
+	if count != 3 {
+		t.Errorf("expected 3 records in the transaction, got %d", count)
+	}
+
+	capped := edamame.NewQueryStatement("capped-by-name", "First users by name", edamame.QuerySpec{
+		OrderBy:    []edamame.OrderBySpec{{Field: "name", Direction: "asc"}},
+		MaxResults: 2,
+	})
+	rows := make(chan int, 1)
+	listener := capitan.Hook(edamame.ResultsCapped, func(_ context.Context, e *capitan.Event) {
+		if name, _ := edamame.KeyStatement.From(e); name == capped.Name() {
+			n, _ := edamame.KeyRows.From(e)
+			rows <- n
+		}
+	})
+	defer listener.Close()
+	seq, err = factory.ExecQueryIter(ctx, capped, nil)
+	if err != nil {
+		t.Fatalf("failed to prepare capped iterator: %v", err)
+	}
+	count = 0
+	for _, err := range seq {
+		if err != nil {
+			t.Fatalf("capped iteration failed: %v", err)
+		}
+		count++
+	}
+	if count != 2 {
+		t.Errorf("expected MaxResults to stop at 2 records, got %d", count)
+	}
+	if err := listener.Drain(ctx); err != nil {
+		t.Fatalf("failed to drain ResultsCapped events: %v", err)
+	}
+	select {
+	case n := <-rows:
+		if n != 3 {
+			t.Errorf("expected ResultsCapped to count 3 rows, got %d", n)
+		}
+	default:
+		t.Error("expected ResultsCapped when MaxResults truncates the iteration")
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	seq, err = factory.ExecQueryIter(cancelled, byName, nil)
+	if err != nil {
+		t.Fatalf("failed to prepare iterator: %v", err)
+	}
+	for user, err := range seq {
+		if user != nil || !errors.Is(err, context.Canceled) {
+			t.Errorf("expected a cancellation error, got %v, %v", user, err)
+		}
+	}
+}
+
+func TestPostgresIntegration_ValidateAgainstDB(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+	users, err := edamame.New[User](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+	mismatches, err := users.ValidateAgainstDB(ctx)
+	if err != nil {
+		t.Fatalf("failed to validate users: %v", err)
+	}
+	if len(mismatches) != 0 {
+		t.Errorf("expected the users model to match, got %v", mismatches)
+	}
+
+	// email is nullable, name has the wrong type, age is missing and nickname is extra.
+	_, err = pg.DB().ExecContext(ctx, `
+		CREATE TABLE drifted_users (
+			id SERIAL PRIMARY KEY,
+			email TEXT,
+			name INTEGER,
+			nickname TEXT
+		)
+	`)
+	if err != nil {
+		t.Fatalf("failed to create drifted_users table: %v", err)
+	}
+	drifted, err := edamame.New[User](pg.DB(), "drifted_users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+	mismatches, err = drifted.ValidateAgainstDB(ctx)
+	if err != nil {
+		t.Fatalf("failed to validate drifted_users: %v", err)
+	}
+	want := []edamame.SchemaMismatch{
+		{Column: "email", Kind: edamame.MismatchNullability, Model: "NOT NULL", Table: "NULL"},
+		{Column: "name", Kind: edamame.MismatchType, Model: "text", Table: "integer"},
+		{Column: "age", Kind: edamame.MismatchMissingColumn},
+		{Column: "nickname", Kind: edamame.MismatchExtraColumn},
+	}
+	if fmt.Sprint(mismatches) != fmt.Sprint(want) {
+		t.Errorf("expected %v, got %v", want, mismatches)
+	}
+
+	missing, err := edamame.New[User](pg.DB(), "no_such_table", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+	if _, err := missing.ValidateAgainstDB(ctx); err == nil {
+		t.Error("expected an error for a missing table")
+	}
+}
+
+func TestPostgresIntegration_UpdateCount(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+
+	factory, err := edamame.New[User](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+
+	young, old := 16, 40
+	for i, age := range []*int{&young, &young, &old} {
+		if _, err := pg.InsertTestUser(ctx, fmt.Sprintf("user%d@test.com", i), "User", age); err != nil {
+			t.Fatalf("failed to insert user: %v", err)
+		}
+	}
+
+	rename := edamame.NewUpdateStatement("rename-minors", "Rename users under an age", edamame.UpdateSpec{
+		Set:   map[string]string{"name": "name"},
+		Where: []edamame.ConditionSpec{{Field: "age", Operator: "<", Param: "max_age"}},
+	})
+	count, err := factory.ExecUpdateCount(ctx, rename, map[string]any{"name": "Minor", "max_age": 18})
+	if err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("expected 2 rows updated, got %d", count)
+	}
+
+	var minors int
+	if err := pg.DB().GetContext(ctx, &minors, "SELECT COUNT(*) FROM users WHERE name = 'Minor'"); err != nil {
+		t.Fatalf("failed to count renamed users: %v", err)
+	}
+	if minors != 2 {
+		t.Errorf("expected 2 renamed users, got %d", minors)
+	}
+
+	tx, err := pg.DB().BeginTxx(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	count, err = factory.ExecUpdateCountTx(ctx, tx, rename, map[string]any{"name": "Nobody", "max_age": 0})
+	if err != nil {
+		t.Fatalf("failed to update in transaction: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("expected no rows updated, got %d", count)
+	}
+}
+
+func TestPostgresIntegration_ExportQueryNDJSON(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+
+	factory, err := edamame.New[User](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+
+	age := 30
+	for _, name := range []string{"Alice", "Bob", "Carol"} {
+		if _, err := pg.InsertTestUser(ctx, strings.ToLower(name)+"@test.com", name, &age); err != nil {
+			t.Fatalf("failed to insert user: %v", err)
+		}
+	}
+
+	byName := edamame.NewQueryStatement("export-by-name", "All users by name", edamame.QuerySpec{
+		OrderBy: []edamame.OrderBySpec{{Field: "name", Direction: "asc"}},
+	})
+	var buf bytes.Buffer
+	n, err := factory.ExportQueryNDJSON(ctx, byName, nil, &buf)
+	if err != nil {
+		t.Fatalf("failed to export: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("expected 3 records exported, got %d", n)
+	}
+
+	var exported []User
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var user User
+		if err := json.Unmarshal(scanner.Bytes(), &user); err != nil {
+			t.Fatalf("failed to parse line %q: %v", scanner.Text(), err)
+		}
+		exported = append(exported, user)
+	}
+	if len(exported) != 3 {
+		t.Fatalf("expected 3 lines, got %d", len(exported))
+	}
+	if exported[0].Name != "Alice" || exported[2].Email != "carol@test.com" || exported[1].Age == nil || *exported[1].Age != 30 {
+		t.Errorf("unexpected exported records: %+v", exported)
+	}
+}
+
+func TestPostgresIntegration_ExecExists(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+
+	factory, err := edamame.New[User](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+	if _, err := pg.InsertTestUser(ctx, "alice@test.com", "Alice", nil); err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+
+	byEmail := edamame.NewSelectStatement("exists-by-email", "User by email", edamame.SelectSpec{
+		Where: []edamame.ConditionSpec{{Field: "email", Operator: "=", Param: "email"}},
+	})
+	found, err := factory.ExecExists(ctx, byEmail, map[string]any{"email": "alice@test.com"})
+	if err != nil {
+		t.Fatalf("ExecExists failed: %v", err)
+	}
+	if !found {
+		t.Error("expected alice to exist")
+	}
+
+	tx, err := pg.DB().BeginTxx(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	byName := edamame.NewQueryStatement("exists-by-name", "Users by name", edamame.QuerySpec{
+		Where: []edamame.ConditionSpec{{Field: "name", Operator: "=", Param: "name"}},
+	})
+	found, err = factory.ExecExistsTx(ctx, tx, byName, map[string]any{"name": "Bob"})
+	if err != nil {
+		t.Fatalf("ExecExistsTx failed: %v", err)
+	}
+	if found {
+		t.Error("expected no user named Bob")
+	}
+}
+
+func TestPostgresIntegration_QueryCountDistinct(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+
+	factory, err := edamame.New[User](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+	for i, age := range []int{20, 30, 30, 40, 50} {
+		if _, err := pg.InsertTestUser(ctx, fmt.Sprintf("user%d@test.com", i), fmt.Sprintf("User %d", i), &age); err != nil {
+			t.Fatalf("failed to insert user: %v", err)
+		}
+	}
+	if _, err := pg.InsertTestUser(ctx, "ageless@test.com", "Ageless", nil); err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+
+	// Ages 20 and 50 are filtered out and the NULL age is not counted.
+	between := edamame.NewQueryStatement("ages-between", "Users within an age range", edamame.QuerySpec{
+		Where: []edamame.ConditionSpec{{Field: "age", Between: true, LowParam: "low", HighParam: "high"}},
+	})
+	count, err := factory.ExecQueryCountDistinct(ctx, between, "age", map[string]any{"low": 25, "high": 45})
+	if err != nil {
+		t.Fatalf("ExecQueryCountDistinct failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("expected 2 distinct ages, got %d", count)
+	}
+
+	all := edamame.NewQueryStatement("all-users", "All users", edamame.QuerySpec{})
+	count, err = factory.ExecQueryCountDistinct(ctx, all, "age", nil)
+	if err != nil {
+		t.Fatalf("ExecQueryCountDistinct failed: %v", err)
+	}
+	if count != 4 {
+		t.Errorf("expected 4 distinct ages, got %d", count)
+	}
+}
+
+func TestPostgresIntegration_AggregateCancellation(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+	if _, err := pg.InsertTestUser(ctx, "alice@test.com", "Alice", nil); err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+	// Reading the view sleeps for 30 seconds before returning the users.
+	_, err = pg.DB().ExecContext(ctx, `CREATE VIEW slow_users AS SELECT users.* FROM users, pg_sleep(30)`)
+	if err != nil {
+		t.Fatalf("failed to create slow_users view: %v", err)
+	}
+
+	factory, err := edamame.New[User](pg.DB(), "slow_users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+	countSlow := edamame.NewAggregateStatement("count-slow", "Count users slowly", edamame.AggCount, edamame.AggregateSpec{})
+
+	deadline, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = factory.ExecAggregate(deadline, countSlow, nil)
+	elapsed := time.Since(start)
+	if err == nil {
+		t.Fatal("expected the aggregate to be cancelled")
+	}
+	if elapsed > 5*time.Second {
+		t.Errorf("expected prompt cancellation, aggregate returned after %v", elapsed)
+	}
+
+	// The cancelled statement must not hold the connection.
+	var one int
+	if err := pg.DB().GetContext(ctx, &one, "SELECT 1"); err != nil {
+		t.Errorf("expected the database to be usable after cancellation: %v", err)
+	}
+	// The server may take a moment to process the cancel request.
+	var running int
+	for wait := time.Now().Add(2 * time.Second); ; time.Sleep(50 * time.Millisecond) {
+		err = pg.DB().GetContext(ctx, &running, `SELECT count(*) FROM pg_stat_activity WHERE query LIKE '%slow_users%' AND state = 'active' AND pid <> pg_backend_pid()`)
+		if err != nil {
+			t.Fatalf("failed to read pg_stat_activity: %v", err)
+		}
+		if running == 0 || time.Now().After(wait) {
+			break
+		}
+	}
+	if running != 0 {
+		t.Errorf("expected the aggregate to stop on the server, %d still running", running)
+	}
+}
+
+func TestPostgresIntegration_WithSchema(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	for _, schema := range []string{"tenant_a", "tenant_b"} {
+		if _, err := pg.DB().ExecContext(ctx, fmt.Sprintf(`
+			CREATE SCHEMA %[1]s;
+			CREATE TABLE %[1]s.users (
+				id SERIAL PRIMARY KEY,
+				email TEXT NOT NULL UNIQUE,
+				name TEXT,
+				age INTEGER
+			);
+			INSERT INTO %[1]s.users (email, name) VALUES ('owner@%[1]s.test', '%[1]s owner')
+		`, schema)); err != nil {
+			t.Fatalf("failed to setup %s.users table: %v", schema, err)
+		}
+	}
+
+	factory, err := edamame.New[User](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+	for _, schema := range []string{"tenant_a", "tenant_b"} {
+		tenant, err := factory.WithSchema(schema)
+		if err != nil {
+			t.Fatalf("WithSchema(%q) failed: %v", schema, err)
+		}
+		users, err := tenant.ExecQuery(ctx, queryAll, nil)
+		if err != nil {
+			t.Fatalf("query in %s failed: %v", schema, err)
+		}
+		if len(users) != 1 || users[0].Name != schema+" owner" {
+			t.Errorf("expected the %s owner, got %+v", schema, users)
+		}
+	}
+
+	// The unqualified executor resolves through search_path, which has no users table.
+	if _, err := factory.ExecQuery(ctx, queryAll, nil); err == nil {
+		t.Error("expected the unqualified table to be missing")
+	}
+}
+
+func TestPostgresIntegration_SelectMany(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+
+	factory, err := edamame.New[User](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+	ids := make(map[string]int)
+	for _, name := range []string{"Alice", "Bob", "Carol"} {
+		id, err := pg.InsertTestUser(ctx, strings.ToLower(name)+"@test.com", name, nil)
+		if err != nil {
+			t.Fatalf("failed to insert user: %v", err)
+		}
+		ids[name] = id
+	}
+
+	users, err := factory.ExecSelectMany(ctx, []any{ids["Carol"], 999, ids["Alice"], ids["Carol"]})
+	if err != nil {
+		t.Fatalf("ExecSelectMany failed: %v", err)
+	}
+	var names []string
+	for _, u := range users {
+		names = append(names, u.Name)
+	}
+	if strings.Join(names, ",") != "Carol,Alice" {
+		t.Errorf("expected Carol,Alice in input order, got %v", names)
+	}
+
+	tx, err := pg.DB().BeginTxx(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	users, err = factory.ExecSelectManyTx(ctx, tx, []any{ids["Bob"]})
+	if err != nil {
+		t.Fatalf("ExecSelectManyTx failed: %v", err)
+	}
+	if len(users) != 1 || users[0].Name != "Bob" {
+		t.Errorf("expected Bob, got %+v", users)
+	}
+}
+
+func TestPostgresIntegration_QueryReduce(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+	_, err = pg.DB().ExecContext(ctx, `
+		INSERT INTO users (email, name, age)
+		SELECT 'user' || n || '@test.com', 'User' || n, NULLIF(n % 80, 0)
+		FROM generate_series(1, 1000) AS n
+	`)
+	if err != nil {
+		t.Fatalf("failed to insert users: %v", err)
+	}
+
+	factory, err := edamame.New[User](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+
+	sumAges := func(sum int64, u *User) int64 {
+		if u.Age != nil {
+			sum += int64(*u.Age)
+		}
+		return sum
+	}
+	reduced, err := edamame.ExecQueryReduce(ctx, factory, queryAll, nil, int64(0), sumAges)
+	if err != nil {
+		t.Fatalf("ExecQueryReduce failed: %v", err)
+	}
+	sumAge := edamame.NewAggregateStatement("sum-age", "Sum of ages", edamame.AggSum, edamame.AggregateSpec{Field: "age"})
+	summed, err := factory.ExecAggregateInt(ctx, sumAge, nil)
+	if err != nil {
+		t.Fatalf("ExecAggregateInt failed: %v", err)
+	}
+	if reduced != summed {
+		t.Errorf("expected the reduced sum %d to equal SUM(age) %d", reduced, summed)
+	}
+
+	tx, err := pg.DB().BeginTxx(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	count, err := edamame.ExecQueryReduceTx(ctx, factory, tx, queryAll, nil, 0, func(n int, _ *User) int { return n + 1 })
+	if err != nil {
+		t.Fatalf("ExecQueryReduceTx failed: %v", err)
+	}
+	if count != 1000 {
+		t.Errorf("expected 1000 records, got %d", count)
+	}
+}
+
+func TestPostgresIntegration_RowExprs(t *testing.T) {
+	ctx := context.Background()
+
+	pg, err := NewPostgresContainer(ctx)
+	if err != nil {
+		t.Fatalf("failed to create postgres container: %v", err)
+	}
+	defer pg.Close(ctx)
+
+	if err := pg.SetupUsersTable(ctx); err != nil {
+		t.Fatalf("failed to setup users table: %v", err)
+	}
+
+	factory, err := edamame.New[User](pg.DB(), "users", postgres.New())
+	if err != nil {
+		t.Fatalf("failed to create factory: %v", err)
+	}
+	for i, age := range []int{10, 1} {
+		if _, err := pg.InsertTestUser(ctx, fmt.Sprintf("user%d@test.com", i), fmt.Sprintf("User %d", i), &age); err != nil {
+			t.Fatalf("failed to insert user: %v", err)
+		}
+	}
+	if _, err := pg.InsertTestUser(ctx, "ageless@test.com", "Ageless", nil); err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+
+	type bounds struct {
+		ID   int `db:"id"`
+		Low  int `db:"low"`
+		High int `db:"high"`
+	}
+	stmt := edamame.NewQueryStatement("id-age-bounds", "Lower and higher of id and age", edamame.QuerySpec{
+		Fields: []string{"id"},
+		SelectExprs: []edamame.SelectExprSpec{
+			{Func: "row_min", Fields: []string{"id", "age"}, Alias: "low"},
+			{Func: "row_max", Fields: []string{"id", "age"}, Alias: "high"},
+		},
+		OrderBy: []edamame.OrderBySpec{{Field: "id", Direction: "asc"}},
+	})
+	rows, err := edamame.ExecQueryProjection[User, bounds](ctx, factory, stmt, nil)
+	if err != nil {
+		t.Fatalf("ExecQueryProjection failed: %v", err)
+	}
+	// PostgreSQL's LEAST and GREATEST skip the NULL age.
+	want := []bounds{{1, 1, 10}, {2, 1, 2}, {3, 3, 3}}
+	if len(rows) != len(want) {
+		t.Fatalf("expected %d rows, got %+v", len(want), rows)
+	}
+	for i := range want {
+		if rows[i] != want[i] {
+			t.Errorf("row %d: expected %+v, got %+v", i, want[i], rows[i])
+		}
+	}
+}
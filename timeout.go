@@ -0,0 +1,58 @@
+package edamame
+
+import (
+	"context"
+	"time"
+)
+
+// SetDefaultTimeout bounds every Exec method with d: the context each call runs with
+// is cancelled d after the call starts, unless the caller's context ends sooner. A
+// timeout set with SetStatementTimeout overrides the default for that statement.
+// Zero, the default, applies no timeout.
+//
+// The streaming methods ExecQueryCursor and ExecQueryChan and the ExecInTx callback are
+// not bounded, as they outlive the call that starts them; the Exec methods called
+// within ExecInTx are. ExecQueryIter bounds the iteration rather than the call.
+//
+// Example:
+//
+//	exec.SetDefaultTimeout(5 * time.Second)
+func (e *Executor[T]) SetDefaultTimeout(d time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.defaultTimeout = d
+}
+
+// SetStatementTimeout bounds the Exec methods running the statement named name with d,
+// overriding the default set by SetDefaultTimeout. Zero removes the override.
+//
+// Example:
+//
+//	exec.SetStatementTimeout(MonthlyReport.Name(), time.Minute)
+func (e *Executor[T]) SetStatementTimeout(name string, d time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if d == 0 {
+		delete(e.timeouts, name)
+		return
+	}
+	if e.timeouts == nil {
+		e.timeouts = make(map[string]time.Duration)
+	}
+	e.timeouts[name] = d
+}
+
+// withTimeout returns ctx bounded by the timeout of the statement named name, or the
+// default timeout. Statement-less calls pass an empty name and get the default.
+func (e *Executor[T]) withTimeout(ctx context.Context, name string) (context.Context, context.CancelFunc) {
+	e.mu.RLock()
+	d, ok := e.timeouts[name]
+	if !ok || name == "" {
+		d = e.defaultTimeout
+	}
+	e.mu.RUnlock()
+	if d <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, d)
+}
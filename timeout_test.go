@@ -0,0 +1,131 @@
+package edamame
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+// deadlineDB records the time left before the deadline of each query's context.
+type deadlineDB struct {
+	recordingDB
+	remaining []time.Duration // -1 when the context has no deadline
+}
+
+func (d *deadlineDB) observe(ctx context.Context) {
+	left := time.Duration(-1)
+	if deadline, ok := ctx.Deadline(); ok {
+		left = time.Until(deadline)
+	}
+	d.remaining = append(d.remaining, left)
+}
+
+func (d *deadlineDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	d.observe(ctx)
+	return d.recordingDB.QueryContext(ctx, query, args...)
+}
+
+func (d *deadlineDB) QueryxContext(ctx context.Context, query string, args ...any) (*sqlx.Rows, error) {
+	d.observe(ctx)
+	return d.recordingDB.QueryxContext(ctx, query, args...)
+}
+
+func TestDefaultTimeout(t *testing.T) {
+	db := &deadlineDB{}
+	exec, err := New[User](db, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ctx := context.Background()
+	all := NewQueryStatement("all", "All users", QuerySpec{})
+	report := NewQueryStatement("report", "Slow report", QuerySpec{})
+
+	last := func() time.Duration {
+		t.Helper()
+		if len(db.remaining) == 0 {
+			t.Fatal("expected a query")
+		}
+		return db.remaining[len(db.remaining)-1]
+	}
+
+	_, _ = exec.ExecQuery(ctx, all, nil)
+	if got := last(); got != -1 {
+		t.Errorf("expected no deadline by default, got %v", got)
+	}
+
+	exec.SetDefaultTimeout(time.Hour)
+	_, _ = exec.ExecQuery(ctx, all, nil)
+	if got := last(); got <= 59*time.Minute || got > time.Hour {
+		t.Errorf("expected the default timeout to apply, got %v", got)
+	}
+
+	exec.SetStatementTimeout(report.Name(), time.Minute)
+	_, _ = exec.ExecQuery(ctx, report, nil)
+	if got := last(); got <= 59*time.Second || got > time.Minute {
+		t.Errorf("expected the statement timeout to override the default, got %v", got)
+	}
+	_, _ = exec.ExecQuery(ctx, all, nil)
+	if got := last(); got <= 59*time.Minute {
+		t.Errorf("expected other statements to keep the default, got %v", got)
+	}
+
+	short, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	_, _ = exec.ExecQuery(short, all, nil)
+	if got := last(); got > time.Second {
+		t.Errorf("expected an earlier caller deadline to win, got %v", got)
+	}
+
+	exec.SetStatementTimeout(report.Name(), 0)
+	exec.SetDefaultTimeout(0)
+	_, _ = exec.ExecQuery(ctx, report, nil)
+	if got := last(); got != -1 {
+		t.Errorf("expected no deadline after clearing timeouts, got %v", got)
+	}
+}
+
+func TestDefaultTimeout_HelperMethods(t *testing.T) {
+	db := &deadlineDB{}
+	exec, err := New[User](db, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	exec.SetDefaultTimeout(time.Hour)
+	ctx := context.Background()
+	all := NewQueryStatement("all", "All users", QuerySpec{})
+	names := NewQueryStatement("names", "User names", QuerySpec{Fields: []string{"id", "name"}})
+	count := NewAggregateStatement("count", "User count", AggCount, AggregateSpec{})
+
+	calls := map[string]func(){
+		"ExecAggregateScalar": func() { _, _ = ExecAggregateScalar[User, int64](ctx, exec, count, nil) },
+		"ExecInsertReturningInto": func() {
+			_ = ExecInsertReturningInto(ctx, exec, &User{Email: "a@example.com"}, []string{"id"}, &struct {
+				ID int `db:"id"`
+			}{})
+		},
+		"ExecQueryProjection": func() { _, _ = ExecQueryProjection[User, userName](ctx, exec, names, nil) },
+		"ExplainCost":         func() { _, _ = exec.ExplainCost(ctx, all, nil) },
+		"ExecQueryFields":     func() { _, _ = exec.ExecQueryFields(ctx, all, []string{"id"}, nil) },
+		"ExecQueryReduce": func() {
+			_, _ = ExecQueryReduce(ctx, exec, all, nil, 0, func(n int, _ *User) int { return n + 1 })
+		},
+		"ExportQueryNDJSON": func() { _, _ = exec.ExportQueryNDJSON(ctx, all, nil, io.Discard) },
+	}
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			db.remaining = nil
+			call()
+			if len(db.remaining) != 1 {
+				t.Fatalf("expected one query, got %d", len(db.remaining))
+			}
+			if got := db.remaining[0]; got <= 59*time.Minute || got > time.Hour {
+				t.Errorf("expected the default timeout to apply, got %v", got)
+			}
+		})
+	}
+}
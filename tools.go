@@ -0,0 +1,142 @@
+package edamame
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// toolNamePattern is the function name format OpenAI function calling accepts.
+var toolNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// ToolDefinition is an OpenAI function-calling tool, one per statement. It marshals to
+// the shape the chat completions "tools" array expects.
+type ToolDefinition struct {
+	Type     string       `json:"type"` // always "function"
+	Function ToolFunction `json:"function"`
+}
+
+// ToolFunction describes a statement as a callable function.
+type ToolFunction struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Parameters  ToolParameters `json:"parameters"`
+}
+
+// ToolParameters is the JSON schema object of a function's arguments.
+type ToolParameters struct {
+	Type       string                  `json:"type"` // always "object"
+	Properties map[string]ToolProperty `json:"properties"`
+	Required   []string                `json:"required"`
+}
+
+// ToolProperty is the JSON schema of one argument. Type is empty when the param
+// accepts any JSON value.
+type ToolProperty struct {
+	Type        string        `json:"type,omitempty"`
+	Format      string        `json:"format,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Items       *ToolProperty `json:"items,omitempty"`
+	Default     any           `json:"default,omitempty"`
+}
+
+// ToolSchema returns an OpenAI function-calling tool definition for each statement,
+// so a model can pick a statement by name and supply its params as arguments. The
+// statement's name and description become the function's, and each param becomes a
+// property. A param's JSON schema type comes from the SQL type tag of the column it
+// is compared with or assigned to, falling back to its ParamSpec Type; list params
+// are arrays of that type. Params with a Default are optional; the rest are required.
+// Statement names must be valid function names: letters, digits, '_' and '-', at
+// most 64 characters.
+//
+// Example:
+//
+//	tools, err := exec.ToolSchema(QueryAll, ByRole, SelectByID)
+//	body, _ := json.Marshal(map[string]any{"model": model, "messages": messages, "tools": tools})
+func (e *Executor[T]) ToolSchema(stmts ...Statement) ([]ToolDefinition, error) {
+	tools := make([]ToolDefinition, 0, len(stmts))
+	for _, stmt := range stmts {
+		if !toolNamePattern.MatchString(stmt.Name()) {
+			return nil, fmt.Errorf("edamame: statement name %q is not a valid tool function name", stmt.Name())
+		}
+		bindings, err := e.paramBindings(stmt)
+		if err != nil {
+			return nil, err
+		}
+		params, err := e.StatementParams(stmt)
+		if err != nil {
+			return nil, err
+		}
+
+		parameters := ToolParameters{
+			Type:       "object",
+			Properties: make(map[string]ToolProperty, len(params)),
+			Required:   []string{},
+		}
+		for _, p := range params {
+			typ := p.Type
+			b, bound := bindings[p.Name]
+			if bound {
+				if colType, err := e.columnType(e.column(b.field)); err == nil {
+					typ = colType
+				}
+			}
+			prop := jsonSchemaProperty(typ)
+			if bound && b.list {
+				items := prop
+				prop = ToolProperty{Type: "array", Items: &items}
+			}
+			prop.Description = p.Description
+			if prop.Description == "" && bound {
+				prop.Description = fmt.Sprintf("Value for the %s field", b.field)
+			}
+			prop.Default = p.Default
+			parameters.Properties[p.Name] = prop
+			if p.Required && p.Default == nil {
+				parameters.Required = append(parameters.Required, p.Name)
+			}
+		}
+
+		tools = append(tools, ToolDefinition{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        stmt.Name(),
+				Description: stmt.Description(),
+				Parameters:  parameters,
+			},
+		})
+	}
+	return tools, nil
+}
+
+// jsonSchemaProperty returns the JSON schema of a value of SQL or ParamSpec type typ.
+// Unknown types, including "any", accept any JSON value.
+func jsonSchemaProperty(typ string) ToolProperty {
+	typ = strings.ToLower(strings.TrimSpace(typ))
+	switch {
+	case typ == "any" || typ == "":
+		return ToolProperty{}
+	case typ == "array":
+		return ToolProperty{Type: "array", Items: &ToolProperty{}}
+	case strings.HasSuffix(typ, "[]"):
+		items := jsonSchemaProperty(strings.TrimSuffix(typ, "[]"))
+		return ToolProperty{Type: "array", Items: &items}
+	case strings.HasPrefix(typ, "int") || strings.HasSuffix(typ, "int") || strings.HasSuffix(typ, "serial"):
+		return ToolProperty{Type: "integer"}
+	case strings.HasPrefix(typ, "numeric") || strings.HasPrefix(typ, "decimal") ||
+		strings.HasPrefix(typ, "real") || strings.HasPrefix(typ, "double") || strings.HasPrefix(typ, "float"):
+		return ToolProperty{Type: "number"}
+	case strings.HasPrefix(typ, "bool"):
+		return ToolProperty{Type: "boolean"}
+	case strings.HasPrefix(typ, "timestamp"):
+		return ToolProperty{Type: "string", Format: "date-time"}
+	case typ == "date":
+		return ToolProperty{Type: "string", Format: "date"}
+	case typ == "uuid":
+		return ToolProperty{Type: "string", Format: "uuid"}
+	case strings.HasPrefix(typ, "json"):
+		return ToolProperty{Type: "object"}
+	default:
+		return ToolProperty{Type: "string"}
+	}
+}
@@ -0,0 +1,92 @@
+package edamame
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestToolSchema(t *testing.T) {
+	exec, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	stmt := NewQueryStatement("search-users", "Search users", QuerySpec{
+		Where: []ConditionSpec{
+			{Field: "name", Match: "contains", Param: "q"},
+			{Field: "age", Operator: ">=", Param: "min_age"},
+			{Field: "id", In: true, Param: "ids"},
+		},
+		LimitParam:    "limit",
+		ParamDefaults: map[string]any{"limit": 20},
+	})
+
+	tools, err := exec.ToolSchema(stmt)
+	if err != nil {
+		t.Fatalf("ToolSchema() failed: %v", err)
+	}
+	if len(tools) != 1 || tools[0].Type != "function" || tools[0].Function.Name != "search-users" || tools[0].Function.Description != "Search users" {
+		t.Fatalf("unexpected tools: %+v", tools)
+	}
+	params := tools[0].Function.Parameters
+	if params.Type != "object" {
+		t.Errorf("expected an object schema, got %q", params.Type)
+	}
+	props := params.Properties
+	if props["q"].Type != "string" || props["min_age"].Type != "integer" || props["limit"].Type != "integer" {
+		t.Errorf("unexpected property types: %+v", props)
+	}
+	if props["ids"].Type != "array" || props["ids"].Items == nil || props["ids"].Items.Type != "integer" {
+		t.Errorf("expected an integer array for ids, got %+v", props["ids"])
+	}
+	if props["min_age"].Description != "Value for the age field" {
+		t.Errorf("unexpected description: %q", props["min_age"].Description)
+	}
+	if props["limit"].Default != 20 {
+		t.Errorf("expected the limit default, got %#v", props["limit"].Default)
+	}
+	if strings.Join(params.Required, ",") != "q,min_age,ids" {
+		t.Errorf("expected q, min_age and ids required, got %v", params.Required)
+	}
+
+	doc, err := json.Marshal(tools)
+	if err != nil {
+		t.Fatalf("Marshal() failed: %v", err)
+	}
+	if !strings.Contains(string(doc), `{"type":"function","function":{"name":"search-users"`) {
+		t.Errorf("unexpected JSON: %s", doc)
+	}
+}
+
+func TestToolSchema_InvalidName(t *testing.T) {
+	exec, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if _, err := exec.ToolSchema(NewQueryStatement("all users", "All users", QuerySpec{})); err == nil {
+		t.Error("expected an error for a name with a space")
+	}
+}
+
+func TestJSONSchemaProperty(t *testing.T) {
+	tests := map[string]ToolProperty{
+		"any":         {},
+		"bigint":      {Type: "integer"},
+		"numeric":     {Type: "number"},
+		"boolean":     {Type: "boolean"},
+		"timestamptz": {Type: "string", Format: "date-time"},
+		"uuid":        {Type: "string", Format: "uuid"},
+		"jsonb":       {Type: "object"},
+		"text":        {Type: "string"},
+	}
+	for typ, want := range tests {
+		if got := jsonSchemaProperty(typ); got != want {
+			t.Errorf("%s: expected %+v, got %+v", typ, want, got)
+		}
+	}
+	if got := jsonSchemaProperty("text[]"); got.Type != "array" || got.Items.Type != "string" {
+		t.Errorf("expected a string array, got %+v", got)
+	}
+}
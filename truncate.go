@@ -0,0 +1,84 @@
+package edamame
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// TruncateOpts controls ExecTruncate.
+type TruncateOpts struct {
+	// Confirm must equal the executor's table name. Truncation discards every row,
+	// so the caller names the table explicitly rather than relying on the executor.
+	Confirm string
+
+	// RestartIdentity resets sequences owned by the table's columns (RESTART IDENTITY).
+	RestartIdentity bool
+
+	// Cascade also truncates tables with foreign keys referencing this one (CASCADE).
+	Cascade bool
+}
+
+// ExecTruncate removes every row from the executor's table with TRUNCATE TABLE.
+// opts.Confirm must equal the table name. PostgreSQL only.
+//
+// Example:
+//
+//	err := exec.ExecTruncate(ctx, edamame.TruncateOpts{Confirm: "users", RestartIdentity: true})
+func (e *Executor[T]) ExecTruncate(ctx context.Context, opts TruncateOpts) error {
+	ctx, cancel := e.withTimeout(ctx, "")
+	defer cancel()
+	if err := e.checkWrite(ctx, "ExecTruncate"); err != nil {
+		return err
+	}
+	return e.execTruncate(ctx, e.execer(), opts)
+}
+
+// ExecTruncateTx truncates the executor's table within a transaction.
+func (e *Executor[T]) ExecTruncateTx(ctx context.Context, tx *sqlx.Tx, opts TruncateOpts) error {
+	ctx, cancel := e.withTimeout(ctx, "")
+	defer cancel()
+	if err := e.checkWrite(ctx, "ExecTruncate"); err != nil {
+		return err
+	}
+	return e.execTruncate(ctx, e.execerFor(tx), opts)
+}
+
+// execTruncate renders and runs the TRUNCATE statement on execer.
+func (e *Executor[T]) execTruncate(ctx context.Context, execer sqlx.ExtContext, opts TruncateOpts) error {
+	sql, err := e.renderTruncate(opts)
+	if err != nil {
+		return err
+	}
+
+	ctx = withStatement(ctx, "truncate", "truncate")
+	e.emitSQL(ctx, "truncate", "truncate", sql, nil)
+	if _, err := execer.ExecContext(ctx, sql); err != nil {
+		return fmt.Errorf("edamame: truncate failed: %w", err)
+	}
+	return nil
+}
+
+// renderTruncate builds the TRUNCATE statement for opts. astql has no TRUNCATE, so
+// it is formatted directly; the table name was validated against the schema by soy.
+func (e *Executor[T]) renderTruncate(opts TruncateOpts) (string, error) {
+	if !e.isPostgres() {
+		return "", fmt.Errorf("edamame: ExecTruncate requires the postgres renderer")
+	}
+	table := e.TableName()
+	if opts.Confirm != table {
+		return "", fmt.Errorf("edamame: truncate requires Confirm to equal the table name %q", table)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `TRUNCATE TABLE %s`, e.quotedTableName())
+	if opts.RestartIdentity {
+		b.WriteString(" RESTART IDENTITY")
+	}
+	if opts.Cascade {
+		b.WriteString(" CASCADE")
+	}
+	return b.String(), nil
+}
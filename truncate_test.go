@@ -0,0 +1,67 @@
+package edamame
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/mariadb"
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestRenderTruncate(t *testing.T) {
+	factory, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	tests := []struct {
+		opts TruncateOpts
+		want string
+	}{
+		{TruncateOpts{Confirm: "users"}, `TRUNCATE TABLE "users"`},
+		{TruncateOpts{Confirm: "users", RestartIdentity: true}, `TRUNCATE TABLE "users" RESTART IDENTITY`},
+		{TruncateOpts{Confirm: "users", RestartIdentity: true, Cascade: true}, `TRUNCATE TABLE "users" RESTART IDENTITY CASCADE`},
+	}
+	for _, tt := range tests {
+		got, err := factory.renderTruncate(tt.opts)
+		if err != nil {
+			t.Fatalf("renderTruncate(%+v) failed: %v", tt.opts, err)
+		}
+		if got != tt.want {
+			t.Errorf("renderTruncate(%+v) = %q, want %q", tt.opts, got, tt.want)
+		}
+	}
+}
+
+func TestExecTruncate_RequiresConfirm(t *testing.T) {
+	db := &recordingDB{}
+	factory, err := New[User](db, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ctx := context.Background()
+
+	for _, confirm := range []string{"", "accounts"} {
+		if err := factory.ExecTruncate(ctx, TruncateOpts{Confirm: confirm}); err == nil {
+			t.Errorf("expected error for Confirm %q", confirm)
+		}
+	}
+	if db.count() != 0 {
+		t.Errorf("expected no statements without confirmation, got %d", db.count())
+	}
+
+	if err := factory.ExecTruncate(ctx, TruncateOpts{Confirm: "users"}); !errors.Is(err, errRecorded) {
+		t.Errorf("expected confirmed truncate to reach the database, got %v", err)
+	}
+}
+
+func TestExecTruncate_RequiresPostgres(t *testing.T) {
+	factory, err := New[User](&recordingDB{}, "users", mariadb.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if err := factory.ExecTruncate(context.Background(), TruncateOpts{Confirm: "users"}); err == nil {
+		t.Error("expected error for non-postgres renderer")
+	}
+}
@@ -0,0 +1,86 @@
+package edamame
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ExecUpdateCount executes an update statement and returns the number of rows it
+// changed, for updates whose WHERE matches many rows, such as status migrations.
+// ExecUpdate stays the method for updates targeting one row. Returning columns are
+// ignored; SetExpr is supported.
+//
+// Example:
+//
+//	var Archive = edamame.NewUpdateStatement("archive-stale", "Archive stale accounts", edamame.UpdateSpec{
+//	    Set:   map[string]string{"status": "archived"},
+//	    Where: []edamame.ConditionSpec{{Field: "last_seen", Operator: "<", Param: "cutoff"}},
+//	})
+//	n, err := exec.ExecUpdateCount(ctx, Archive, map[string]any{"cutoff": cutoff})
+func (e *Executor[T]) ExecUpdateCount(ctx context.Context, stmt UpdateStatement, params map[string]any) (int64, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	if err := e.checkWrite(ctx, fmt.Sprintf("statement %q", stmt.name)); err != nil {
+		return 0, err
+	}
+	ctx = withStatement(ctx, stmt.name, "update")
+	count, err := e.execUpdateCount(ctx, e.execer(), stmt, params)
+	return e.notifyAffected(ctx, e.execer(), count, err)
+}
+
+// ExecUpdateCountTx executes an update statement within a transaction and returns the number of rows it changed.
+func (e *Executor[T]) ExecUpdateCountTx(ctx context.Context, tx *sqlx.Tx, stmt UpdateStatement, params map[string]any) (int64, error) {
+	ctx, cancel := e.withTimeout(ctx, stmt.name)
+	defer cancel()
+	if err := e.checkWrite(ctx, fmt.Sprintf("statement %q", stmt.name)); err != nil {
+		return 0, err
+	}
+	count, err := e.execUpdateCount(ctx, e.execerFor(tx), stmt, params)
+	return e.notifyAffected(ctx, e.execerFor(tx), count, err)
+}
+
+// execUpdateCount runs stmt without its RETURNING clause and returns the rows affected.
+func (e *Executor[T]) execUpdateCount(ctx context.Context, execer sqlx.ExtContext, stmt UpdateStatement, params map[string]any) (int64, error) {
+	params, err := e.prepareParams(stmt, params)
+	if err != nil {
+		return 0, err
+	}
+	sql, err := e.renderUpdateCount(stmt.spec)
+	if err != nil {
+		return 0, err
+	}
+	e.emitSQL(ctx, stmt.name, "update", sql, params)
+
+	result, err := sqlx.NamedExecContext(ctx, execer, sql, params)
+	if err != nil {
+		return 0, fmt.Errorf("edamame: UPDATE failed: %w", err)
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("edamame: failed to get rows affected: %w", err)
+	}
+	return count, nil
+}
+
+// renderUpdateCount renders spec as soy would, with its SET expressions applied and
+// soy's RETURNING clause, when the dialect renders one, removed.
+func (e *Executor[T]) renderUpdateCount(spec UpdateSpec) (string, error) {
+	result, err := e.modifyFromSpec(spec).Render()
+	if err != nil {
+		return "", err
+	}
+	sql := result.SQL
+	if len(spec.SetExpr) > 0 {
+		mapped := e.mapUpdateSpec(spec)
+		if sql, err = e.rewriteSetExprs(sql, mapped.Set, mapped.SetExpr); err != nil {
+			return "", err
+		}
+	}
+	if at := strings.LastIndex(sql, " RETURNING "); at >= 0 {
+		sql = sql[:at]
+	}
+	return sql, nil
+}
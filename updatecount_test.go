@@ -0,0 +1,61 @@
+package edamame
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+func TestRenderUpdateCount(t *testing.T) {
+	exec, err := New[User](nil, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	sql, err := exec.renderUpdateCount(UpdateSpec{
+		Set:   map[string]string{"name": "name"},
+		Where: []ConditionSpec{{Field: "age", Operator: "<", Param: "max_age"}},
+	})
+	if err != nil {
+		t.Fatalf("renderUpdateCount() failed: %v", err)
+	}
+	if want := `UPDATE "users" SET "name" = :name WHERE "age" < :max_age`; sql != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, sql)
+	}
+
+	sql, err = exec.renderUpdateCount(UpdateSpec{
+		SetExpr: map[string]string{"age": "age + :delta"},
+		Where:   []ConditionSpec{{Field: "id", Operator: "=", Param: "id"}},
+	})
+	if err != nil {
+		t.Fatalf("renderUpdateCount() failed: %v", err)
+	}
+	if want := `UPDATE "users" SET "age" = "age" + :delta WHERE "id" = :id`; sql != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, sql)
+	}
+}
+
+func TestExecUpdateCount(t *testing.T) {
+	db := &recordingDB{}
+	exec, err := New[User](db, "users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	rename := NewUpdateStatement("rename-minors", "", UpdateSpec{
+		Set:   map[string]string{"name": "name"},
+		Where: []ConditionSpec{{Field: "age", Operator: "<", Param: "max_age"}},
+	})
+	ctx := context.Background()
+
+	if _, err := exec.ExecUpdateCount(ctx, rename, map[string]any{"name": "minor"}); err == nil || db.count() != 0 {
+		t.Errorf("expected a missing param to fail before executing, got %v", err)
+	}
+	if _, err := exec.ExecUpdateCount(ctx, rename, map[string]any{"name": "minor", "max_age": 18}); !errors.Is(err, errRecorded) {
+		t.Fatalf("expected the update to run, got %v", err)
+	}
+	if want := `UPDATE "users" SET "name" = $1 WHERE "age" < $2`; db.count() != 1 || db.queries[0] != want {
+		t.Errorf("expected %s, got %v", want, db.queries)
+	}
+}
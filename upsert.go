@@ -0,0 +1,288 @@
+package edamame
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/soy"
+)
+
+// upsertInsertedColumn is the RETURNING column reporting whether ExecUpsert inserted.
+const upsertInsertedColumn = "edamame_inserted"
+
+// lastWriteWins configures ExecUpsert.
+type lastWriteWins struct {
+	timestamp string
+	conflict  []string
+}
+
+// SetLastWriteWinsUpsert configures ExecUpsert to insert a record or, when it conflicts
+// on conflictCols, overwrite the stored row only if the incoming timestampCol is newer:
+//
+//	ON CONFLICT (conflictCols) DO UPDATE SET ... WHERE EXCLUDED.timestampCol > table.timestampCol
+//
+// Every non-primary-key column other than conflictCols is updated. PostgreSQL only.
+func (e *Executor[T]) SetLastWriteWinsUpsert(timestampCol string, conflictCols ...string) error {
+	if !e.isPostgres() {
+		return fmt.Errorf("edamame: last-write-wins upsert requires the postgres renderer")
+	}
+	if len(conflictCols) == 0 {
+		return fmt.Errorf("edamame: at least one conflict column is required")
+	}
+	for _, col := range append([]string{timestampCol}, conflictCols...) {
+		if _, ok := e.columns[col]; !ok {
+			return fmt.Errorf("edamame: unknown column %q", col)
+		}
+	}
+	if slices.Contains(conflictCols, timestampCol) {
+		return fmt.Errorf("edamame: timestamp column %q cannot be a conflict column", timestampCol)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.upsert = &lastWriteWins{timestamp: timestampCol, conflict: append([]string(nil), conflictCols...)}
+	return nil
+}
+
+// ExecUpsert inserts record, or updates the conflicting row when record is newer, as
+// configured by SetLastWriteWinsUpsert. It returns the stored row and whether it was
+// inserted: true for a new row, false for an updated one. When a newer stored row was
+// kept it returns nil and false.
+//
+// Inserts are detected with RETURNING (xmax = 0): a freshly inserted row version has
+// no deleting or locking transaction, while ON CONFLICT DO UPDATE sets xmax on the row
+// it updates. xmax is a PostgreSQL system column whose meaning is an implementation
+// detail rather than a documented guarantee; it holds on every PostgreSQL release to
+// date, but may not on forks or future releases that change row versioning.
+func (e *Executor[T]) ExecUpsert(ctx context.Context, record *T) (*T, bool, error) {
+	ctx, cancel := e.withTimeout(ctx, "")
+	defer cancel()
+	if err := e.checkWrite(ctx, "ExecUpsert"); err != nil {
+		return nil, false, err
+	}
+	return e.execUpsert(ctx, e.execer(), record)
+}
+
+// ExecUpsertTx performs ExecUpsert within a transaction.
+func (e *Executor[T]) ExecUpsertTx(ctx context.Context, tx *sqlx.Tx, record *T) (*T, bool, error) {
+	ctx, cancel := e.withTimeout(ctx, "")
+	defer cancel()
+	if err := e.checkWrite(ctx, "ExecUpsert"); err != nil {
+		return nil, false, err
+	}
+	return e.execUpsert(ctx, e.execerFor(tx), record)
+}
+
+// execUpsert runs the upsert and, when it applied, issues the write notification.
+func (e *Executor[T]) execUpsert(ctx context.Context, execer sqlx.ExtContext, record *T) (*T, bool, error) {
+	stored, inserted, err := e.runUpsert(ctx, execer, record)
+	if err != nil || stored == nil {
+		return stored, inserted, err
+	}
+	// The row is returned with a NOTIFY error, as the upsert succeeded.
+	return stored, inserted, e.notifyWrite(ctx, execer, stored)
+}
+
+// runUpsert runs the rendered upsert for record and scans the stored row, if any, and
+// whether it was inserted.
+func (e *Executor[T]) runUpsert(ctx context.Context, execer sqlx.ExtContext, record *T) (*T, bool, error) {
+	sql, err := e.renderUpsert()
+	if err != nil {
+		return nil, false, err
+	}
+	ctx = withStatement(ctx, "", "upsert")
+
+	rows, err := sqlx.NamedQueryContext(ctx, execer, sql, record)
+	if err != nil {
+		return nil, false, fmt.Errorf("edamame: upsert failed: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, false, fmt.Errorf("edamame: upsert failed: %w", err)
+		}
+		return nil, false, nil
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, false, fmt.Errorf("edamame: failed to scan upsert result: %w", err)
+	}
+	var stored T
+	var inserted bool
+	v := reflect.ValueOf(&stored).Elem()
+	dest := make([]any, len(columns))
+	for i, col := range columns {
+		if col == upsertInsertedColumn {
+			dest[i] = &inserted
+			continue
+		}
+		index, ok := e.columns[col]
+		if !ok {
+			return nil, false, fmt.Errorf("edamame: upsert returned unknown column %q", col)
+		}
+		dest[i] = v.FieldByIndex(index).Addr().Interface()
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return nil, false, fmt.Errorf("edamame: failed to scan upsert result: %w", err)
+	}
+	return &stored, inserted, nil
+}
+
+// renderUpsert renders the configured last-write-wins upsert, returning every column
+// and whether the row was inserted. astql has no conflict WHERE or RETURNING
+// expressions, so the guard is spliced in before RETURNING and the xmax check after it.
+func (e *Executor[T]) renderUpsert() (string, error) {
+	e.mu.RLock()
+	cfg := e.upsert
+	e.mu.RUnlock()
+	if cfg == nil {
+		return "", fmt.Errorf("edamame: no upsert configured, call SetLastWriteWinsUpsert first")
+	}
+
+	builder, err := e.insertBuilder()
+	if err != nil {
+		return "", err
+	}
+	instance := e.soy.Instance()
+
+	conflictFields, err := tryEach(cfg.conflict, instance.TryF)
+	if err != nil {
+		return "", err
+	}
+
+	update := builder.OnConflict(conflictFields...).DoUpdate()
+	for _, col := range e.insertColumns() {
+		if slices.Contains(cfg.conflict, col) {
+			continue
+		}
+		f, err := instance.TryF(col)
+		if err != nil {
+			return "", fmt.Errorf("edamame: invalid field %q: %w", col, err)
+		}
+		p, err := instance.TryP(col)
+		if err != nil {
+			return "", fmt.Errorf("edamame: invalid param %q: %w", col, err)
+		}
+		update = update.Set(f, p)
+	}
+
+	builder, err = e.returning(update.Build(), e.schemaColumns())
+	if err != nil {
+		return "", err
+	}
+	result, err := builder.Render(e.renderer)
+	if err != nil {
+		return "", fmt.Errorf("edamame: failed to render upsert: %w", err)
+	}
+
+	i := strings.LastIndex(result.SQL, " RETURNING ")
+	if i < 0 {
+		return "", fmt.Errorf("edamame: rendered upsert has no RETURNING clause")
+	}
+	//nolint:gosec // identifiers come from the validated table name and struct tags
+	guard := fmt.Sprintf(` WHERE EXCLUDED."%s" > %s."%s"`, cfg.timestamp, e.quotedTableName(), cfg.timestamp)
+	inserted := fmt.Sprintf(`, (%s.xmax = 0) AS "%s"`, e.quotedTableName(), upsertInsertedColumn)
+	return result.SQL[:i] + guard + result.SQL[i:] + inserted, nil
+}
+
+// ExecUpsertRecord inserts record or, when it conflicts on conflictCols, updates the
+// conflicting row's updateCols to record's values, returning the stored row:
+//
+//	ON CONFLICT (conflictCols) DO UPDATE SET updateCol = :updateCol, ...
+//
+// It builds a CreateSpec with the update conflict action, taking values from record
+// rather than named params. updateCols must be non-empty and name insertable columns;
+// models with generated columns are not supported.
+//
+// Example:
+//
+//	user, err := exec.ExecUpsertRecord(ctx, user, []string{"email"}, []string{"name", "age"})
+func (e *Executor[T]) ExecUpsertRecord(ctx context.Context, record *T, conflictCols, updateCols []string) (*T, error) {
+	ctx, cancel := e.withTimeout(ctx, "")
+	defer cancel()
+	if err := e.checkWrite(ctx, "ExecUpsertRecord"); err != nil {
+		return nil, err
+	}
+	create, err := e.upsertRecordBuilder(conflictCols, updateCols)
+	if err != nil {
+		return nil, err
+	}
+	ctx = withStatement(ctx, "", "upsert")
+	stored, err := create.Exec(ctx, record)
+	return e.notifyRecord(ctx, e.execer(), stored, err)
+}
+
+// ExecUpsertRecordTx performs ExecUpsertRecord within a transaction.
+func (e *Executor[T]) ExecUpsertRecordTx(ctx context.Context, tx *sqlx.Tx, record *T, conflictCols, updateCols []string) (*T, error) {
+	ctx, cancel := e.withTimeout(ctx, "")
+	defer cancel()
+	if err := e.checkWrite(ctx, "ExecUpsertRecord"); err != nil {
+		return nil, err
+	}
+	create, err := e.upsertRecordBuilder(conflictCols, updateCols)
+	if err != nil {
+		return nil, err
+	}
+	ctx = withStatement(ctx, "", "upsert")
+	stored, err := create.ExecTx(ctx, tx, record)
+	return e.notifyRecord(ctx, e.execerFor(tx), stored, err)
+}
+
+// upsertRecordBuilder validates the columns of ExecUpsertRecord and builds its insert
+// from a CreateSpec, setting each update column to the param of the same name.
+func (e *Executor[T]) upsertRecordBuilder(conflictCols, updateCols []string) (*soy.Create[T], error) {
+	if len(conflictCols) == 0 {
+		return nil, fmt.Errorf("edamame: at least one conflict column is required")
+	}
+	if len(updateCols) == 0 {
+		return nil, fmt.Errorf("edamame: at least one update column is required")
+	}
+	if len(e.GeneratedColumns()) > 0 {
+		return nil, fmt.Errorf("edamame: ExecUpsertRecord does not support models with generated columns")
+	}
+	for _, col := range conflictCols {
+		if _, ok := e.columns[col]; !ok {
+			return nil, fmt.Errorf("edamame: unknown conflict column %q", col)
+		}
+	}
+	insertable := e.insertColumns()
+	set := make(map[string]string, len(updateCols))
+	for _, col := range updateCols {
+		if _, ok := e.columns[col]; !ok {
+			return nil, fmt.Errorf("edamame: unknown update column %q", col)
+		}
+		if !slices.Contains(insertable, col) {
+			return nil, fmt.Errorf("edamame: update column %q is not inserted", col)
+		}
+		set[col] = col
+	}
+
+	create, err := e.insertFromSpec(CreateSpec{
+		OnConflict:     conflictCols,
+		ConflictAction: conflictActionUpdate,
+		ConflictSet:    set,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("edamame: %w", err)
+	}
+	return create, nil
+}
+
+// tryEach resolves every name with try. It lets callers collect astql fields,
+// whose type is internal to astql, into a slice.
+func tryEach[F any](names []string, try func(string) (F, error)) ([]F, error) {
+	out := make([]F, 0, len(names))
+	for _, name := range names {
+		v, err := try(name)
+		if err != nil {
+			return nil, fmt.Errorf("edamame: invalid field %q: %w", name, err)
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
@@ -0,0 +1,110 @@
+package edamame
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/astql/pkg/mariadb"
+	"github.com/zoobzio/astql/pkg/postgres"
+)
+
+// SyncedUser is a test model with a last-modified timestamp.
+type SyncedUser struct {
+	ID        int       `db:"id" type:"integer" constraints:"primarykey"`
+	Email     string    `db:"email" type:"text" constraints:"notnull,unique"`
+	Name      string    `db:"name" type:"text"`
+	UpdatedAt time.Time `db:"updated_at" type:"timestamptz"`
+}
+
+func TestRenderUpsert(t *testing.T) {
+	factory, err := New[SyncedUser](nil, "synced_users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	if _, err := factory.renderUpsert(); err == nil {
+		t.Error("renderUpsert() should fail before SetLastWriteWinsUpsert")
+	}
+
+	if err := factory.SetLastWriteWinsUpsert("updated_at", "email"); err != nil {
+		t.Fatalf("SetLastWriteWinsUpsert() failed: %v", err)
+	}
+	sql, err := factory.renderUpsert()
+	if err != nil {
+		t.Fatalf("renderUpsert() failed: %v", err)
+	}
+
+	want := `INSERT INTO "synced_users" ("email", "name", "updated_at") VALUES (:email, :name, :updated_at)` +
+		` ON CONFLICT ("email") DO UPDATE SET "name" = :name, "updated_at" = :updated_at` +
+		` WHERE EXCLUDED."updated_at" > "synced_users"."updated_at"` +
+		` RETURNING "id", "email", "name", "updated_at", ("synced_users".xmax = 0) AS "edamame_inserted"`
+	if sql != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, sql)
+	}
+}
+
+func TestSetLastWriteWinsUpsert_Validation(t *testing.T) {
+	factory, err := New[SyncedUser](nil, "synced_users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	if err := factory.SetLastWriteWinsUpsert("updated_at"); err == nil {
+		t.Error("should require a conflict column")
+	}
+	if err := factory.SetLastWriteWinsUpsert("modified_at", "email"); err == nil {
+		t.Error("should reject an unknown timestamp column")
+	}
+	if err := factory.SetLastWriteWinsUpsert("updated_at", "updated_at"); err == nil {
+		t.Error("should reject the timestamp as a conflict column")
+	}
+
+	maria, err := New[SyncedUser](nil, "synced_users", mariadb.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if err := maria.SetLastWriteWinsUpsert("updated_at", "email"); err == nil {
+		t.Error("should require the postgres renderer")
+	}
+}
+
+func TestUpsertRecordBuilder(t *testing.T) {
+	factory, err := New[SyncedUser](nil, "synced_users", postgres.New())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	create, err := factory.upsertRecordBuilder([]string{"email"}, []string{"name"})
+	if err != nil {
+		t.Fatalf("upsertRecordBuilder() failed: %v", err)
+	}
+	result, err := create.Render()
+	if err != nil {
+		t.Fatalf("Render() failed: %v", err)
+	}
+	want := `INSERT INTO "synced_users" ("email", "name", "updated_at") VALUES (:email, :name, :updated_at)` +
+		` ON CONFLICT ("email") DO UPDATE SET "name" = :name`
+	if !strings.HasPrefix(result.SQL, want) {
+		t.Errorf("expected prefix:\n%s\ngot:\n%s", want, result.SQL)
+	}
+
+	tests := []struct {
+		name     string
+		conflict []string
+		update   []string
+	}{
+		{"no conflict columns", nil, []string{"name"}},
+		{"no update columns", []string{"email"}, nil},
+		{"unknown conflict column", []string{"mail"}, []string{"name"}},
+		{"unknown update column", []string{"email"}, []string{"nickname"}},
+		{"primary key update column", []string{"email"}, []string{"id"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := factory.upsertRecordBuilder(tt.conflict, tt.update); err == nil {
+				t.Error("upsertRecordBuilder() should fail")
+			}
+		})
+	}
+}